@@ -2,17 +2,22 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
 	"github.com/MattSharp0/transaction-split-go/internal/auth"
 	"github.com/MattSharp0/transaction-split-go/internal/handlers"
 	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/mail"
 	"github.com/MattSharp0/transaction-split-go/internal/server"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
@@ -67,6 +72,34 @@ func main() {
 	}
 	log.Debug("Store created successfully")
 
+	// Load exchange rates from file, rates are looked up locally so conversions work offline
+	if ratesFile := os.Getenv("EXCHANGE_RATES_FILE"); ratesFile != "" {
+		count, err := loadExchangeRates(ctx, store, ratesFile)
+		if err != nil {
+			log.Error("Failed to load exchange rates", "error", err, "file", ratesFile)
+			os.Exit(1)
+		}
+		log.Info("Exchange rates loaded", slog.Int("count", count), slog.String("file", ratesFile))
+	}
+
+	// Purge the trash in the background, deleted transactions & groups can be restored until the retention period ends
+	retention, err := trashRetentionFromEnv()
+	if err != nil {
+		log.Error("Invalid trash retention", "error", err)
+		os.Exit(1)
+	}
+	purgeCtx, stopPurge := context.WithCancel(ctx)
+	defer stopPurge()
+	go purgeTrash(purgeCtx, store, retention, log)
+	log.Info("Trash purge started", slog.Duration("retention", retention))
+
+	// Mail transport for verification emails, logged unless MAIL_TRANSPORT is set
+	mailer, err := mail.NewSenderFromEnv()
+	if err != nil {
+		log.Error("Invalid mail settings", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize server with logger
 	s := server.NewServer(":8080", store, log)
 
@@ -75,7 +108,7 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("Hello, World!"))
 	})
-	s.Mux().Handle("/auth/", http.StripPrefix("/auth", handlers.AuthRoutes(s, store)))
+	s.Mux().Handle("/auth/", http.StripPrefix("/auth", handlers.AuthRoutes(s, store, mailer)))
 
 	// Protected routes - require authentication
 	s.Mux().Handle("/users/", auth.RequireAuth(auth.RequireCSRF(http.StripPrefix("/users", handlers.UserRoutes(s, store)))))
@@ -83,6 +116,7 @@ func main() {
 	s.Mux().Handle("/group_members/", auth.RequireAuth(auth.RequireCSRF(http.StripPrefix("/group_members", handlers.GroupMemberRoutes(s, store)))))
 	s.Mux().Handle("/transactions/", auth.RequireAuth(auth.RequireCSRF(http.StripPrefix("/transactions", handlers.TransactionRoutes(s, store)))))
 	s.Mux().Handle("/splits/", auth.RequireAuth(auth.RequireCSRF(http.StripPrefix("/splits", handlers.SplitRoutes(s, store)))))
+	s.Mux().Handle("/invitations/", auth.RequireAuth(auth.RequireCSRF(http.StripPrefix("/invitations", handlers.InvitationRoutes(s, store)))))
 
 	// Start server in goroutine
 	if err := s.Start(); err != nil {
@@ -98,6 +132,7 @@ func main() {
 	<-quit
 
 	log.Info("Shutdown signal received")
+	stopPurge()
 
 	if err := s.Stop(); err != nil {
 		log.Error("Error during shutdown", "error", err)
@@ -106,3 +141,77 @@ func main() {
 
 	log.Info("Application shutdown gracefully")
 }
+
+// loadExchangeRates reads a CSV rates file (date,from_currency,to_currency,rate) and upserts every rate
+func loadExchangeRates(ctx context.Context, store db.Store, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	rates, err := services.ParseExchangeRates(file)
+	if err != nil {
+		return 0, err
+	}
+
+	params := make([]db.UpsertExchangeRateParams, len(rates))
+	for i, rate := range rates {
+		params[i] = db.UpsertExchangeRateParams{
+			FromCurrency: rate.From,
+			ToCurrency:   rate.To,
+			RateDate:     rate.Date,
+			Rate:         rate.Rate,
+		}
+	}
+
+	result, err := store.LoadExchangeRatesTx(ctx, db.LoadExchangeRatesTxParams{Rates: params})
+	if err != nil {
+		return 0, err
+	}
+	return len(result.Rates), nil
+}
+
+// defaultTrashRetentionDays is how long deleted transactions & groups stay in the trash when TRASH_RETENTION_DAYS is not set
+const defaultTrashRetentionDays = 30
+
+// trashPurgeInterval is how often the trash is checked for items past the retention period
+const trashPurgeInterval = time.Hour
+
+// trashRetentionFromEnv reads the trash retention period in days from TRASH_RETENTION_DAYS
+func trashRetentionFromEnv() (time.Duration, error) {
+	days := defaultTrashRetentionDays
+	if value := os.Getenv("TRASH_RETENTION_DAYS"); value != "" {
+		var err error
+		days, err = strconv.Atoi(value)
+		if err != nil || days < 1 {
+			return 0, fmt.Errorf("TRASH_RETENTION_DAYS must be a positive number of days, got %q", value)
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// purgeTrash permanently deletes transactions & groups that have been in the trash longer than retention
+// Runs at startup and then every trashPurgeInterval until ctx is cancelled
+func purgeTrash(ctx context.Context, store db.Store, retention time.Duration, log *slog.Logger) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		result, err := store.PurgeTrashTx(ctx, db.PurgeTrashTxParams{DeletedBefore: time.Now().Add(-retention)})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Error("Failed to purge trash", "error", err)
+		} else if result.Transactions > 0 || result.Groups > 0 {
+			log.Info("Trash purged", slog.Int64("transactions", result.Transactions), slog.Int64("groups", result.Groups))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
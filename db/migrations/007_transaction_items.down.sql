@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS set_modified_at_transaction_items on "transaction_items";
+
+DROP TABLE IF EXISTS "transaction_item_members";
+DROP TABLE IF EXISTS "transaction_items";
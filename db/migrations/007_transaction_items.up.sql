@@ -0,0 +1,33 @@
+CREATE TABLE "transaction_items" (
+  "id" bigserial PRIMARY KEY,
+  "transaction_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "modified_at" timestamptz NOT NULL DEFAULT (now()),
+
+  CONSTRAINT transaction_items_amount_positive CHECK ("amount" > 0)
+);
+
+-- Group members sharing a line item, the item amount is divided equally between them
+CREATE TABLE "transaction_item_members" (
+  "item_id" bigint NOT NULL,
+  "member_id" bigint NOT NULL,
+
+  PRIMARY KEY ("item_id", "member_id")
+);
+
+CREATE INDEX ON "transaction_items" ("transaction_id");
+
+CREATE INDEX ON "transaction_item_members" ("member_id");
+
+ALTER TABLE "transaction_items" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE; -- Item is deleted if transaction is deleted
+
+ALTER TABLE "transaction_item_members" ADD FOREIGN KEY ("item_id") REFERENCES "transaction_items" ("id") ON DELETE CASCADE; -- Item member is deleted if item is deleted
+
+ALTER TABLE "transaction_item_members" ADD FOREIGN KEY ("member_id") REFERENCES "group_members" ("id") ON DELETE CASCADE; -- Item member is deleted if group member is deleted, derived splits are kept
+
+CREATE TRIGGER set_modified_at_transaction_items
+BEFORE UPDATE ON transaction_items
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_at();
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS set_modified_at_transaction_adjustments on "transaction_adjustments";
+
+DROP TABLE IF EXISTS "transaction_adjustments";
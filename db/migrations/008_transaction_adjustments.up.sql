@@ -0,0 +1,23 @@
+-- Transaction level adjustments (tax, tip, service charges, discounts) applied to itemized transactions
+-- Adjustments are spread across members in proportion to their item subtotals
+CREATE TABLE "transaction_adjustments" (
+  "id" bigserial PRIMARY KEY,
+  "transaction_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "adjustment_type" varchar NOT NULL, -- 'fixed' amount or 'percent' of item subtotal
+  "value" numeric(14,6) NOT NULL, -- Fixed amount or percent (0.0 to 1.0), negative for discounts
+  "amount" numeric(10,2) NOT NULL, -- Resolved amount applied to the transaction
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "modified_at" timestamptz NOT NULL DEFAULT (now()),
+
+  CONSTRAINT transaction_adjustments_type_valid CHECK ("adjustment_type" IN ('fixed', 'percent'))
+);
+
+CREATE INDEX ON "transaction_adjustments" ("transaction_id");
+
+ALTER TABLE "transaction_adjustments" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE; -- Adjustment is deleted if transaction is deleted
+
+CREATE TRIGGER set_modified_at_transaction_adjustments
+BEFORE UPDATE ON transaction_adjustments
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_at();
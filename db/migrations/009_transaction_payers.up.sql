@@ -0,0 +1,107 @@
+-- Group members paying part of a transaction, used when several members pay one bill together
+-- Transactions without payer rows are paid in full by transactions.by_user
+CREATE TABLE "transaction_payers" (
+  "transaction_id" bigint NOT NULL,
+  "member_id" bigint NOT NULL,
+  "amount" numeric(10,2) NOT NULL, -- Amount contributed by the member
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "modified_at" timestamptz NOT NULL DEFAULT (now()),
+
+  PRIMARY KEY ("transaction_id", "member_id"),
+  CONSTRAINT transaction_payers_amount_positive CHECK ("amount" > 0)
+);
+
+CREATE INDEX ON "transaction_payers" ("member_id");
+
+ALTER TABLE "transaction_payers" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE; -- Payer is deleted if transaction is deleted
+
+ALTER TABLE "transaction_payers" ADD FOREIGN KEY ("member_id") REFERENCES "group_members" ("id"); -- Group member can't be deleted while they have paid part of a transaction, payer amounts must match the transaction amount
+
+CREATE TRIGGER set_modified_at_transaction_payers
+BEFORE UPDATE ON transaction_payers
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_at();
+
+-- Amount paid by each member for each transaction
+CREATE VIEW transaction_payments AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        tp.member_id,
+        tp.amount
+    FROM transaction_payers tp
+    JOIN transactions tx ON tx.id = tp.transaction_id
+    UNION ALL
+    -- Single payer transactions are paid in full by by_user
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        tx.by_user AS member_id,
+        tx.amount
+    FROM transactions tx
+    WHERE NOT EXISTS (SELECT 1 FROM transaction_payers tp WHERE tp.transaction_id = tx.id)
+);
+
+-- Amount each split member owes each payer, per transaction
+-- Each split is divided between the payers in proportion to the amount they paid
+CREATE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+);
+
+-- group_balances and group_balances_net are rebuilt on the ledger so each payer is credited for their share
+-- user_balances_net and user_balances_by_member are derived from these views and credit payers the same way
+CREATE OR REPLACE VIEW group_balances AS (
+    SELECT
+        gl.group_id,
+        gl.creditor,
+        gl.debtor,
+        SUM(gl.amount)::numeric(10,2) as total_owed -- Sum returns unconstrained numeric
+    FROM group_ledger gl
+    GROUP BY gl.group_id, gl.creditor, gl.debtor
+    ORDER BY gl.group_id, gl.creditor, gl.debtor
+);
+
+CREATE OR REPLACE VIEW group_balances_net AS (
+    WITH payer_credits AS (
+        -- Payers are listed even when they only paid for themselves
+        SELECT
+            p.group_id,
+            p.member_id as user_id,
+            0 as net_amount
+        FROM transaction_payments p
+        UNION ALL
+        SELECT
+            gl.group_id,
+            gl.creditor as user_id,
+            gl.amount as net_amount
+        FROM group_ledger gl
+    ),
+    split_debits AS (
+        SELECT
+            gl.group_id,
+            gl.debtor as user_id,
+            -gl.amount as net_amount
+        FROM group_ledger gl
+    )
+    SELECT
+        group_id,
+        user_id,
+        SUM(net_amount)::numeric(10, 2) AS net_balance -- Sum returns unconstrained numeric
+    FROM (
+        SELECT * FROM payer_credits
+        UNION ALL
+        SELECT * FROM split_debits
+    ) combined
+    GROUP BY group_id, user_id
+    ORDER BY group_id, user_id
+);
@@ -0,0 +1,18 @@
+-- Restore transaction only ledger
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+);
+
+DROP TRIGGER IF EXISTS set_modified_at_settlements on "settlements";
+
+DROP TABLE IF EXISTS "settlements";
@@ -0,0 +1,61 @@
+-- Settle up payments between group members, "from_member paid to_member amount"
+-- Settlements are kept apart from transactions so spending totals and category reports exclude them
+CREATE TABLE "settlements" (
+  "id" bigserial PRIMARY KEY,
+  "group_id" bigint NOT NULL,
+  "from_member" bigint NOT NULL, -- Member who paid
+  "to_member" bigint NOT NULL, -- Member who was paid
+  "amount" numeric(10,2) NOT NULL,
+  "settled_on" date NOT NULL DEFAULT (CURRENT_DATE),
+  "note" varchar,
+  "created_by" bigint, -- User who recorded the settlement
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "modified_at" timestamptz NOT NULL DEFAULT (now()),
+
+  CONSTRAINT settlements_amount_positive CHECK ("amount" > 0),
+  CONSTRAINT settlements_members_differ CHECK ("from_member" != "to_member")
+);
+
+CREATE INDEX ON "settlements" ("group_id");
+
+CREATE INDEX ON "settlements" ("from_member");
+
+CREATE INDEX ON "settlements" ("to_member");
+
+ALTER TABLE "settlements" ADD FOREIGN KEY ("group_id") REFERENCES "groups" ("id") ON DELETE CASCADE; -- Settlement is deleted if group is deleted
+
+ALTER TABLE "settlements" ADD FOREIGN KEY ("from_member") REFERENCES "group_members" ("id"); -- Group member can't be deleted while they have settled, balances would change
+
+ALTER TABLE "settlements" ADD FOREIGN KEY ("to_member") REFERENCES "group_members" ("id"); -- Group member can't be deleted while they have settled, balances would change
+
+ALTER TABLE "settlements" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL; -- Recorder is set to null if user is deleted
+
+CREATE TRIGGER set_modified_at_settlements
+BEFORE UPDATE ON settlements
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_at();
+
+-- Settlements are added to the ledger, the paying member is credited and the member paid is debited
+-- This reduces group_balances_net, group_balances and user_balances_by_member, which are built on the ledger
+-- Settlement rows have no transaction_id
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+    UNION ALL
+    SELECT
+        NULL::bigint AS transaction_id,
+        st.group_id,
+        st.from_member AS creditor,
+        st.to_member AS debtor,
+        st.amount
+    FROM settlements st
+);
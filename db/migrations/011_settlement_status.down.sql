@@ -0,0 +1,29 @@
+-- Restore ledger counting every settlement
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+    UNION ALL
+    SELECT
+        NULL::bigint AS transaction_id,
+        st.group_id,
+        st.from_member AS creditor,
+        st.to_member AS debtor,
+        st.amount
+    FROM settlements st
+);
+
+-- Settlements that were never confirmed did not change balances
+DELETE FROM "settlements" WHERE "status" != 'confirmed';
+
+ALTER TABLE "settlements" DROP CONSTRAINT IF EXISTS settlements_status_valid;
+
+ALTER TABLE "settlements" DROP COLUMN IF EXISTS "status";
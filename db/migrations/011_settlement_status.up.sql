@@ -0,0 +1,32 @@
+-- Settlements are confirmed by the member who was paid before they change balances
+-- pending: waiting for the recipient, confirmed: counts towards balances, disputed: recipient says it was not received, cancelled: withdrawn
+-- Existing settlements were already counted, so they are confirmed
+ALTER TABLE "settlements" ADD COLUMN "status" varchar NOT NULL DEFAULT 'confirmed';
+
+ALTER TABLE "settlements" ALTER COLUMN "status" SET DEFAULT 'pending';
+
+ALTER TABLE "settlements" ADD CONSTRAINT settlements_status_valid CHECK ("status" IN ('pending', 'confirmed', 'disputed', 'cancelled'));
+
+-- Only confirmed settlements are added to the ledger
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+    UNION ALL
+    SELECT
+        NULL::bigint AS transaction_id,
+        st.group_id,
+        st.from_member AS creditor,
+        st.to_member AS debtor,
+        st.amount
+    FROM settlements st
+    WHERE st.status = 'confirmed'
+);
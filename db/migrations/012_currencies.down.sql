@@ -0,0 +1,35 @@
+-- Restore ledger without currency conversion
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+    UNION ALL
+    SELECT
+        NULL::bigint AS transaction_id,
+        st.group_id,
+        st.from_member AS creditor,
+        st.to_member AS debtor,
+        st.amount
+    FROM settlements st
+    WHERE st.status = 'confirmed'
+);
+
+DROP TRIGGER IF EXISTS set_modified_at_exchange_rates ON exchange_rates;
+
+DROP TABLE IF EXISTS "exchange_rates";
+
+ALTER TABLE "transactions" DROP CONSTRAINT IF EXISTS transactions_exchange_rate_positive;
+
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "exchange_rate";
+
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "currency";
+
+ALTER TABLE "groups" DROP COLUMN IF EXISTS "currency";
@@ -0,0 +1,53 @@
+-- Groups keep balances in a base currency, set when the group is created
+ALTER TABLE "groups" ADD COLUMN "currency" varchar(3) NOT NULL DEFAULT 'USD';
+
+-- Transactions, their splits, payers & items are stored in the transaction currency
+-- exchange_rate converts the transaction currency to the group base currency at transaction_date
+ALTER TABLE "transactions" ADD COLUMN "currency" varchar(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE "transactions" ADD COLUMN "exchange_rate" numeric(18,8) NOT NULL DEFAULT 1;
+
+ALTER TABLE "transactions" ADD CONSTRAINT transactions_exchange_rate_positive CHECK ("exchange_rate" > 0);
+
+-- Local exchange rates, loaded from a file so lookups work offline
+-- 1 from_currency = rate to_currency on rate_date
+CREATE TABLE "exchange_rates" (
+  "from_currency" varchar(3) NOT NULL,
+  "to_currency" varchar(3) NOT NULL,
+  "rate_date" date NOT NULL,
+  "rate" numeric(18,8) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "modified_at" timestamptz NOT NULL DEFAULT (now()),
+
+  PRIMARY KEY ("from_currency", "to_currency", "rate_date"),
+  CONSTRAINT exchange_rates_rate_positive CHECK ("rate" > 0)
+);
+
+CREATE TRIGGER set_modified_at_exchange_rates
+BEFORE UPDATE ON exchange_rates
+FOR EACH ROW
+EXECUTE FUNCTION update_modified_at();
+
+-- Ledger amounts are converted to the group base currency
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount * tx.exchange_rate) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+    UNION ALL
+    SELECT
+        NULL::bigint AS transaction_id,
+        st.group_id,
+        st.from_member AS creditor,
+        st.to_member AS debtor,
+        st.amount
+    FROM settlements st
+    WHERE st.status = 'confirmed'
+);
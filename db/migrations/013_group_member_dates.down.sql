@@ -0,0 +1,5 @@
+ALTER TABLE "group_members" DROP CONSTRAINT IF EXISTS group_members_left_after_joined;
+
+ALTER TABLE "group_members" DROP COLUMN IF EXISTS "left_on";
+
+ALTER TABLE "group_members" DROP COLUMN IF EXISTS "joined_on";
@@ -0,0 +1,8 @@
+-- Dates a member was part of the group, e.g. roommates moving in or out partway through the year
+-- A member can only be split into transactions dated from joined_on through left_on, both inclusive
+-- NULL joined_on means a member since the group started, NULL left_on means still a member
+ALTER TABLE "group_members" ADD COLUMN "joined_on" date;
+
+ALTER TABLE "group_members" ADD COLUMN "left_on" date;
+
+ALTER TABLE "group_members" ADD CONSTRAINT group_members_left_after_joined CHECK ("left_on" IS NULL OR "joined_on" IS NULL OR "left_on" >= "joined_on");
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS group_members_one_owner;
+
+ALTER TABLE "group_members" DROP CONSTRAINT IF EXISTS group_members_role_valid;
+
+ALTER TABLE "group_members" DROP COLUMN IF EXISTS "role";
@@ -0,0 +1,18 @@
+-- Role of each group member, controls who can manage the group, its members and other members' transactions
+-- owner: everything incl. deleting the group, one per group; admin: manages the group & members; member: adds transactions & edits their own; viewer: read only
+-- New members are members unless given another role
+ALTER TABLE "group_members" ADD COLUMN "role" varchar NOT NULL DEFAULT 'member';
+
+ALTER TABLE "group_members" ADD CONSTRAINT group_members_role_valid CHECK ("role" IN ('owner', 'admin', 'member', 'viewer'));
+
+-- The creator is added as the group's first member, existing groups are owned by their first member with an account
+UPDATE "group_members" gm
+SET "role" = 'owner'
+WHERE gm.id = (
+    SELECT MIN(first.id)
+    FROM "group_members" first
+    WHERE first.group_id = gm.group_id
+      AND first.user_id IS NOT NULL
+);
+
+CREATE UNIQUE INDEX group_members_one_owner ON "group_members" ("group_id") WHERE "role" = 'owner';
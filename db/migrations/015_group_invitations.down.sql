@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_group_invitations_token_hash;
+
+DROP TABLE IF EXISTS "group_invitations";
@@ -0,0 +1,27 @@
+-- Invitations to join a group, shared as a link or addressed to an email
+-- Only a hash of the invite token is stored, the token is shown once when the invitation is created
+-- Email invitations can only be accepted by a user with that email, so they can be sent before the person has registered
+CREATE TABLE "group_invitations" (
+  "id" bigserial PRIMARY KEY,
+  "group_id" bigint NOT NULL,
+  "token_hash" varchar NOT NULL,
+  "email" varchar, -- Null for link invitations anyone can accept
+  "role" varchar NOT NULL DEFAULT 'member', -- Role given to the new member
+  "single_use" boolean NOT NULL DEFAULT true,
+  "expires_at" timestamptz, -- Null for single use invitations that don't expire
+  "use_count" integer NOT NULL DEFAULT 0,
+  "created_by" bigint, -- User who sent the invitation
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "revoked_at" timestamptz,
+
+  CONSTRAINT group_invitations_role_valid CHECK ("role" IN ('admin', 'member', 'viewer')),
+  CONSTRAINT group_invitations_single_use_or_expires CHECK ("single_use" OR "expires_at" IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX idx_group_invitations_token_hash ON "group_invitations" ("token_hash");
+
+CREATE INDEX ON "group_invitations" ("group_id");
+
+ALTER TABLE "group_invitations" ADD FOREIGN KEY ("group_id") REFERENCES "groups" ("id") ON DELETE CASCADE; -- Invitation is deleted if group is deleted
+
+ALTER TABLE "group_invitations" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL; -- Sender is set to null if user is deleted
@@ -0,0 +1,3 @@
+ALTER TABLE "group_invitations" DROP CONSTRAINT IF EXISTS group_invitations_claim_single_use;
+
+ALTER TABLE "group_invitations" DROP COLUMN IF EXISTS "group_member_id";
@@ -0,0 +1,7 @@
+-- Invitations to claim a placeholder member, a member with a name but no user
+-- Accepting links the user to the placeholder so its transactions, splits and balances carry over
+ALTER TABLE "group_invitations" ADD COLUMN "group_member_id" bigint; -- Null for invitations that add a new member
+
+ALTER TABLE "group_invitations" ADD CONSTRAINT group_invitations_claim_single_use CHECK ("group_member_id" IS NULL OR "single_use");
+
+ALTER TABLE "group_invitations" ADD FOREIGN KEY ("group_member_id") REFERENCES "group_members" ("id") ON DELETE CASCADE; -- Invitation is deleted if the placeholder is deleted
@@ -0,0 +1,21 @@
+ALTER TABLE "splits" DROP CONSTRAINT IF EXISTS splits_split_user_fkey;
+
+ALTER TABLE "splits" ADD CONSTRAINT splits_split_user_fkey FOREIGN KEY ("split_user") REFERENCES "group_members" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "transactions" DROP CONSTRAINT IF EXISTS transactions_by_user_fkey;
+
+ALTER TABLE "transactions" ADD CONSTRAINT transactions_by_user_fkey FOREIGN KEY ("by_user") REFERENCES "group_members" ("id") ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS group_members_one_owner;
+
+DROP INDEX IF EXISTS group_members_member_name_unique;
+
+DROP INDEX IF EXISTS group_members_user_id_unique;
+
+CREATE UNIQUE INDEX group_members_user_id_unique ON "group_members" ("group_id", "user_id") WHERE "user_id" IS NOT NULL;
+
+CREATE UNIQUE INDEX group_members_member_name_unique ON "group_members" ("group_id", "member_name") WHERE "member_name" IS NOT NULL;
+
+CREATE UNIQUE INDEX group_members_one_owner ON "group_members" ("group_id") WHERE "role" = 'owner';
+
+ALTER TABLE "group_members" DROP COLUMN IF EXISTS "removed_at";
@@ -0,0 +1,26 @@
+-- Removing a member marks them as having left the group instead of deleting them
+-- Their transactions, splits & settlements are kept so group balances don't change
+-- Removed members are hidden from member lists, can't be split into new transactions and lose access to the group
+ALTER TABLE "group_members" ADD COLUMN "removed_at" timestamptz; -- Null while still a member
+
+-- A removed user or placeholder name can be added again as a new member
+DROP INDEX IF EXISTS group_members_user_id_unique;
+
+DROP INDEX IF EXISTS group_members_member_name_unique;
+
+DROP INDEX IF EXISTS group_members_one_owner;
+
+CREATE UNIQUE INDEX group_members_user_id_unique ON "group_members" ("group_id", "user_id") WHERE "user_id" IS NOT NULL AND "removed_at" IS NULL;
+
+CREATE UNIQUE INDEX group_members_member_name_unique ON "group_members" ("group_id", "member_name") WHERE "member_name" IS NOT NULL AND "removed_at" IS NULL;
+
+CREATE UNIQUE INDEX group_members_one_owner ON "group_members" ("group_id") WHERE "role" = 'owner' AND "removed_at" IS NULL;
+
+-- Group members are no longer deleted with their history
+ALTER TABLE "transactions" DROP CONSTRAINT IF EXISTS transactions_by_user_fkey;
+
+ALTER TABLE "transactions" ADD CONSTRAINT transactions_by_user_fkey FOREIGN KEY ("by_user") REFERENCES "group_members" ("id"); -- Group member can't be deleted while they have paid a transaction, remove them instead
+
+ALTER TABLE "splits" DROP CONSTRAINT IF EXISTS splits_split_user_fkey;
+
+ALTER TABLE "splits" ADD CONSTRAINT splits_split_user_fkey FOREIGN KEY ("split_user") REFERENCES "group_members" ("id"); -- Group member can't be deleted while they are split into a transaction, remove them instead
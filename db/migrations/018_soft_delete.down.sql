@@ -0,0 +1,49 @@
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount * tx.exchange_rate) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+    UNION ALL
+    SELECT
+        NULL::bigint AS transaction_id,
+        st.group_id,
+        st.from_member AS creditor,
+        st.to_member AS debtor,
+        st.amount
+    FROM settlements st
+    WHERE st.status = 'confirmed'
+);
+
+CREATE OR REPLACE VIEW transaction_payments AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        tp.member_id,
+        tp.amount
+    FROM transaction_payers tp
+    JOIN transactions tx ON tx.id = tp.transaction_id
+    UNION ALL
+    -- Single payer transactions are paid in full by by_user
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        tx.by_user AS member_id,
+        tx.amount
+    FROM transactions tx
+    WHERE NOT EXISTS (SELECT 1 FROM transaction_payers tp WHERE tp.transaction_id = tx.id)
+);
+
+DROP INDEX IF EXISTS groups_deleted_at;
+
+DROP INDEX IF EXISTS transactions_deleted_at;
+
+ALTER TABLE "groups" DROP COLUMN IF EXISTS "deleted_at";
+
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "deleted_at";
@@ -0,0 +1,63 @@
+-- Deleting a transaction or group moves it to the trash, it can be restored until it is purged after the retention period
+-- Splits, payers, items & members are kept while in the trash and are deleted with the transaction or group when it is purged
+ALTER TABLE "transactions" ADD COLUMN "deleted_at" timestamptz; -- Null unless in the trash
+
+ALTER TABLE "groups" ADD COLUMN "deleted_at" timestamptz; -- Null unless in the trash
+
+CREATE INDEX transactions_deleted_at ON "transactions" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+CREATE INDEX groups_deleted_at ON "groups" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+-- Transactions in the trash, or in a group in the trash, are left out of the ledger & balances
+CREATE OR REPLACE VIEW transaction_payments AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        tp.member_id,
+        tp.amount
+    FROM transaction_payers tp
+    JOIN transactions tx ON tx.id = tp.transaction_id
+    JOIN groups g ON g.id = tx.group_id
+    WHERE tx.deleted_at IS NULL
+      AND g.deleted_at IS NULL
+    UNION ALL
+    -- Single payer transactions are paid in full by by_user
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        tx.by_user AS member_id,
+        tx.amount
+    FROM transactions tx
+    JOIN groups g ON g.id = tx.group_id
+    WHERE NOT EXISTS (SELECT 1 FROM transaction_payers tp WHERE tp.transaction_id = tx.id)
+      AND tx.deleted_at IS NULL
+      AND g.deleted_at IS NULL
+);
+
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount * tx.exchange_rate) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    JOIN groups g ON g.id = tx.group_id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+      AND tx.deleted_at IS NULL
+      AND g.deleted_at IS NULL
+    UNION ALL
+    SELECT
+        NULL::bigint AS transaction_id,
+        st.group_id,
+        st.from_member AS creditor,
+        st.to_member AS debtor,
+        st.amount
+    FROM settlements st
+    JOIN groups g ON g.id = st.group_id
+    WHERE st.status = 'confirmed'
+      AND g.deleted_at IS NULL
+);
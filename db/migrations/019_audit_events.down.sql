@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS audit_groups ON "groups";
+DROP TRIGGER IF EXISTS audit_group_members ON "group_members";
+DROP TRIGGER IF EXISTS audit_transactions ON "transactions";
+DROP TRIGGER IF EXISTS audit_splits ON "splits";
+DROP TRIGGER IF EXISTS audit_events_append_only ON "audit_events";
+
+DROP FUNCTION IF EXISTS record_audit_event();
+DROP FUNCTION IF EXISTS prevent_audit_event_changes();
+
+DROP TABLE IF EXISTS "audit_events";
@@ -0,0 +1,104 @@
+-- Append-only log of every change to groups, group members, transactions & splits
+-- Events are written by triggers in the same DB transaction as the change, so a change can't be saved without its event
+-- The acting user is read from the audit.actor_user_id setting, set by the store at the start of each DB transaction
+CREATE TABLE "audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "group_id" bigint, -- Group of the changed row, no foreign key so events outlive purged groups
+  "actor_user_id" bigint, -- Null for changes made without a user, e.g. purging the trash
+  "entity" varchar NOT NULL, -- group, group_member, transaction or split
+  "entity_id" bigint NOT NULL,
+  "action" varchar NOT NULL, -- create, update, delete, trash, restore or remove
+  "before" jsonb, -- Null on create
+  "after" jsonb, -- Null on delete
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_events" ("group_id", "id");
+
+CREATE OR REPLACE FUNCTION record_audit_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    before_row jsonb;
+    after_row jsonb;
+    audit_action varchar;
+    audit_group_id bigint;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        before_row = to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        after_row = to_jsonb(NEW);
+    END IF;
+
+    IF TG_OP = 'INSERT' THEN
+        audit_action = 'create';
+    ELSIF TG_OP = 'DELETE' THEN
+        audit_action = 'delete';
+    ELSE
+        -- Skip updates that only touch modified_at
+        IF (before_row - 'modified_at') = (after_row - 'modified_at') THEN
+            RETURN NULL;
+        END IF;
+
+        -- Moving to or from the trash and removing members are soft deletes
+        audit_action = CASE
+            WHEN before_row->>'deleted_at' IS NULL AND after_row->>'deleted_at' IS NOT NULL THEN 'trash'
+            WHEN before_row->>'deleted_at' IS NOT NULL AND after_row->>'deleted_at' IS NULL THEN 'restore'
+            WHEN before_row->>'removed_at' IS NULL AND after_row->>'removed_at' IS NOT NULL THEN 'remove'
+            ELSE 'update'
+        END;
+    END IF;
+
+    audit_group_id = CASE TG_TABLE_NAME
+        WHEN 'groups' THEN (COALESCE(after_row, before_row)->>'id')::bigint
+        WHEN 'splits' THEN (SELECT group_id FROM transactions WHERE id = (COALESCE(after_row, before_row)->>'transaction_id')::bigint)
+        ELSE (COALESCE(after_row, before_row)->>'group_id')::bigint
+    END;
+
+    INSERT INTO audit_events (group_id, actor_user_id, entity, entity_id, action, before, after)
+    VALUES (
+        audit_group_id,
+        NULLIF(current_setting('audit.actor_user_id', true), '')::bigint,
+        TG_ARGV[0],
+        (COALESCE(after_row, before_row)->>'id')::bigint,
+        audit_action,
+        before_row,
+        after_row
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_groups
+AFTER INSERT OR UPDATE OR DELETE ON "groups"
+FOR EACH ROW
+EXECUTE FUNCTION record_audit_event('group');
+
+CREATE TRIGGER audit_group_members
+AFTER INSERT OR UPDATE OR DELETE ON "group_members"
+FOR EACH ROW
+EXECUTE FUNCTION record_audit_event('group_member');
+
+CREATE TRIGGER audit_transactions
+AFTER INSERT OR UPDATE OR DELETE ON "transactions"
+FOR EACH ROW
+EXECUTE FUNCTION record_audit_event('transaction');
+
+CREATE TRIGGER audit_splits
+AFTER INSERT OR UPDATE OR DELETE ON "splits"
+FOR EACH ROW
+EXECUTE FUNCTION record_audit_event('split');
+
+-- Events can't be changed or deleted once written
+CREATE OR REPLACE FUNCTION prevent_audit_event_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+BEFORE UPDATE OR DELETE ON "audit_events"
+FOR EACH ROW
+EXECUTE FUNCTION prevent_audit_event_changes();
@@ -0,0 +1,18 @@
+-- Numbered revisions of a transaction with its payers & splits, recorded on every edit
+-- The first edit of a transaction also records its state before the edit as revision 1
+-- Reverting restores an earlier revision and records the result as a new revision
+CREATE TABLE "transaction_revisions" (
+  "id" bigserial PRIMARY KEY,
+  "transaction_id" bigint NOT NULL,
+  "revision" integer NOT NULL, -- Numbered from 1 for each transaction
+  "snapshot" jsonb NOT NULL, -- Transaction fields, payers & splits after the change
+  "reverted_from" integer, -- Revision restored by a revert, null for edits
+  "created_by" bigint, -- User who made the change
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+  CONSTRAINT transaction_revisions_unique UNIQUE ("transaction_id", "revision")
+);
+
+ALTER TABLE "transaction_revisions" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE; -- Revision is deleted if transaction is deleted
+
+ALTER TABLE "transaction_revisions" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL; -- Editor is set to null if user is deleted
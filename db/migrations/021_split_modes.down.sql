@@ -0,0 +1,3 @@
+ALTER TABLE "transactions" DROP CONSTRAINT IF EXISTS transactions_split_mode_check;
+
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "split_mode";
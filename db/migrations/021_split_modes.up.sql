@@ -0,0 +1,7 @@
+-- How a transaction's splits were calculated, so changing its amount can rescale or reject them
+-- Splits in equal, shares & percent mode are rescaled by split_percent when the amount changes
+-- Splits in exact mode, or derived from line items, must be replaced instead
+-- NULL for splits sent without a mode, and transactions split before modes were recorded, which are rescaled
+ALTER TABLE "transactions" ADD COLUMN "split_mode" varchar;
+
+ALTER TABLE "transactions" ADD CONSTRAINT transactions_split_mode_check CHECK ("split_mode" IN ('equal', 'shares', 'exact', 'percent', 'items'));
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_one_time_tokens_user_id;
+DROP INDEX IF EXISTS idx_one_time_tokens_token_hash;
+
+DROP TABLE IF EXISTS "one_time_tokens";
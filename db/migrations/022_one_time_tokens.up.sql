@@ -0,0 +1,17 @@
+-- Single use tokens sent to a user's email, such as password reset links
+-- Only a hash of the token is stored, like refresh tokens, the token itself is only in the email
+CREATE TABLE "one_time_tokens" (
+  "id" bigserial PRIMARY KEY,
+  "purpose" varchar NOT NULL, -- What the token can be used for
+  "token_hash" varchar NOT NULL,
+  "user_id" bigint NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "used_at" timestamptz, -- Set when the token is used, or invalidated by a newer token for the same purpose
+
+  CONSTRAINT one_time_tokens_user_id_fkey FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+  CONSTRAINT one_time_tokens_purpose_check CHECK ("purpose" IN ('password_reset'))
+);
+
+CREATE UNIQUE INDEX idx_one_time_tokens_token_hash ON "one_time_tokens" ("token_hash");
+CREATE INDEX idx_one_time_tokens_user_id ON "one_time_tokens" ("user_id");
@@ -0,0 +1,5 @@
+DELETE FROM "one_time_tokens" WHERE "purpose" = 'magic_link';
+
+ALTER TABLE "one_time_tokens" DROP CONSTRAINT one_time_tokens_purpose_check;
+
+ALTER TABLE "one_time_tokens" ADD CONSTRAINT one_time_tokens_purpose_check CHECK ("purpose" IN ('password_reset'));
@@ -0,0 +1,4 @@
+-- One time tokens can also be emailed as passwordless login links
+ALTER TABLE "one_time_tokens" DROP CONSTRAINT one_time_tokens_purpose_check;
+
+ALTER TABLE "one_time_tokens" ADD CONSTRAINT one_time_tokens_purpose_check CHECK ("purpose" IN ('password_reset', 'magic_link'));
@@ -0,0 +1,6 @@
+-- Refresh tokens are rotated on every refresh, the new token joins the family of the token it replaced
+-- A login starts a new family, so a family is one session
+-- A revoked token presented again means it was stolen, so every token in its family is revoked
+ALTER TABLE "refresh_tokens" ADD COLUMN "family_id" uuid NOT NULL DEFAULT gen_random_uuid();
+
+CREATE INDEX idx_refresh_tokens_family_id ON "refresh_tokens" ("family_id");
@@ -0,0 +1,2 @@
+ALTER TABLE "refresh_tokens" DROP COLUMN IF EXISTS "last_used_at";
+ALTER TABLE "refresh_tokens" DROP COLUMN IF EXISTS "ip_address";
@@ -0,0 +1,5 @@
+-- Records where a refresh token was issued and when its session was last used, so users can review and end their sessions
+ALTER TABLE "refresh_tokens" ADD COLUMN "ip_address" varchar;
+ALTER TABLE "refresh_tokens" ADD COLUMN "last_used_at" timestamptz NOT NULL DEFAULT (now());
+
+UPDATE "refresh_tokens" SET last_used_at = created_at;
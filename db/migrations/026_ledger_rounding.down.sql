@@ -0,0 +1,28 @@
+-- Ledger amounts are unrounded again, nets are rounded after summing
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        tx.id AS transaction_id,
+        tx.group_id,
+        p.member_id AS creditor,
+        s.split_user AS debtor,
+        (s.split_amount * p.amount / tx.amount * tx.exchange_rate) AS amount -- Unrounded, round after summing
+    FROM transactions tx
+    JOIN transaction_payments p ON p.transaction_id = tx.id
+    JOIN splits s ON s.transaction_id = tx.id
+    JOIN groups g ON g.id = tx.group_id
+    WHERE s.split_user != p.member_id
+      AND tx.amount != 0
+      AND tx.deleted_at IS NULL
+      AND g.deleted_at IS NULL
+    UNION ALL
+    SELECT
+        NULL::bigint AS transaction_id,
+        st.group_id,
+        st.from_member AS creditor,
+        st.to_member AS debtor,
+        st.amount
+    FROM settlements st
+    JOIN groups g ON g.id = st.group_id
+    WHERE st.status = 'confirmed'
+      AND g.deleted_at IS NULL
+);
@@ -0,0 +1,42 @@
+-- Ledger amounts are rounded to cents per row so net balances always sum to zero
+-- Converted & multi-payer rows have more decimals, rounding each one would make a transaction's rows add up to more or less
+-- than its rounded total, so the difference goes to its largest row
+CREATE OR REPLACE VIEW group_ledger AS (
+    SELECT
+        transaction_id,
+        group_id,
+        creditor,
+        debtor,
+        ROUND(amount, 2) + CASE
+            WHEN row_number() OVER (PARTITION BY transaction_id ORDER BY amount DESC, creditor, debtor) = 1
+            THEN ROUND(SUM(amount) OVER (PARTITION BY transaction_id), 2) - SUM(ROUND(amount, 2)) OVER (PARTITION BY transaction_id)
+            ELSE 0
+        END AS amount
+    FROM (
+        SELECT
+            tx.id AS transaction_id,
+            tx.group_id,
+            p.member_id AS creditor,
+            s.split_user AS debtor,
+            (s.split_amount * p.amount / tx.amount * tx.exchange_rate) AS amount
+        FROM transactions tx
+        JOIN transaction_payments p ON p.transaction_id = tx.id
+        JOIN splits s ON s.transaction_id = tx.id
+        JOIN groups g ON g.id = tx.group_id
+        WHERE s.split_user != p.member_id
+          AND tx.amount != 0
+          AND tx.deleted_at IS NULL
+          AND g.deleted_at IS NULL
+    ) unrounded
+    UNION ALL
+    SELECT
+        NULL::bigint AS transaction_id,
+        st.group_id,
+        st.from_member AS creditor,
+        st.to_member AS debtor,
+        st.amount
+    FROM settlements st
+    JOIN groups g ON g.id = st.group_id
+    WHERE st.status = 'confirmed'
+      AND g.deleted_at IS NULL
+);
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createGroup = `-- name: CreateGroup :one
@@ -14,53 +15,105 @@ const createGroup = `-- name: CreateGroup :one
 Group queries
 Table structure:
 id bigserial PRIMARY KEY,
-name varchar NOT NULL
+name varchar NOT NULL,
+currency varchar(3) NOT NULL DEFAULT 'USD',
+deleted_at timestamptz
 */
 
-INSERT INTO "groups" (name)
-VALUES ($1)
-RETURNING id, name
+INSERT INTO "groups" (name, currency)
+VALUES ($1, $2)
+RETURNING id, name, currency, deleted_at
 `
 
-func (q *Queries) CreateGroup(ctx context.Context, name string) (Group, error) {
-	row := q.db.QueryRow(ctx, createGroup, name)
+type CreateGroupParams struct {
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) CreateGroup(ctx context.Context, arg CreateGroupParams) (Group, error) {
+	row := q.db.QueryRow(ctx, createGroup, arg.Name, arg.Currency)
 	var i Group
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.DeletedAt,
+	)
 	return i, err
 }
 
 const deleteGroup = `-- name: DeleteGroup :one
-DELETE FROM "groups"
-WHERE id = $1
-RETURNING id, name
+UPDATE "groups"
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, currency, deleted_at
 `
 
+// Moves a group to the trash, it is purged after the retention period
 func (q *Queries) DeleteGroup(ctx context.Context, id int64) (Group, error) {
 	row := q.db.QueryRow(ctx, deleteGroup, id)
 	var i Group
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getDeletedGroupByIDForOwner = `-- name: GetDeletedGroupByIDForOwner :one
+SELECT 
+  g.id, g.name, g.currency, g.deleted_at
+FROM "groups" g
+INNER JOIN group_members gm ON g.id = gm.group_id
+WHERE g.id = $1 AND g.deleted_at IS NOT NULL
+  AND gm.user_id = $2 AND gm.role = 'owner' AND gm.removed_at IS NULL
+LIMIT 1
+`
+
+type GetDeletedGroupByIDForOwnerParams struct {
+	ID     int64  `json:"id"`
+	UserID *int64 `json:"user_id"`
+}
+
+// Returns a group in the trash, only if the user owns it
+func (q *Queries) GetDeletedGroupByIDForOwner(ctx context.Context, arg GetDeletedGroupByIDForOwnerParams) (Group, error) {
+	row := q.db.QueryRow(ctx, getDeletedGroupByIDForOwner, arg.ID, arg.UserID)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.DeletedAt,
+	)
 	return i, err
 }
 
 const getGroupByID = `-- name: GetGroupByID :one
 SELECT 
-  id, name
+  id, name, currency, deleted_at
 FROM "groups"
-WHERE id = $1 LIMIT 1
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetGroupByID(ctx context.Context, id int64) (Group, error) {
 	row := q.db.QueryRow(ctx, getGroupByID, id)
 	var i Group
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.DeletedAt,
+	)
 	return i, err
 }
 
 const getGroupByIDForUpdate = `-- name: GetGroupByIDForUpdate :one
 SELECT 
-  id, name
+  id, name, currency, deleted_at
 FROM "groups"
-WHERE id = $1 
+WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1
 FOR UPDATE
 `
@@ -68,14 +121,62 @@ FOR UPDATE
 func (q *Queries) GetGroupByIDForUpdate(ctx context.Context, id int64) (Group, error) {
 	row := q.db.QueryRow(ctx, getGroupByIDForUpdate, id)
 	var i Group
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.DeletedAt,
+	)
 	return i, err
 }
 
+const listDeletedGroupsByOwner = `-- name: ListDeletedGroupsByOwner :many
+SELECT 
+  g.id, g.name, g.currency, g.deleted_at
+FROM "groups" g
+INNER JOIN group_members gm ON g.id = gm.group_id
+WHERE gm.user_id = $1 AND gm.role = 'owner' AND gm.removed_at IS NULL AND g.deleted_at IS NOT NULL
+ORDER BY g.deleted_at desc
+LIMIT $2
+OFFSET $3
+`
+
+type ListDeletedGroupsByOwnerParams struct {
+	UserID *int64 `json:"user_id"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+func (q *Queries) ListDeletedGroupsByOwner(ctx context.Context, arg ListDeletedGroupsByOwnerParams) ([]Group, error) {
+	rows, err := q.db.Query(ctx, listDeletedGroupsByOwner, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Group{}
+	for rows.Next() {
+		var i Group
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Currency,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listGroups = `-- name: ListGroups :many
 SELECT 
-  id, name
+  id, name, currency, deleted_at
 FROM "groups"
+WHERE deleted_at IS NULL
 ORDER BY name
 LIMIT $1
 OFFSET $2
@@ -95,7 +196,12 @@ func (q *Queries) ListGroups(ctx context.Context, arg ListGroupsParams) ([]Group
 	items := []Group{}
 	for rows.Next() {
 		var i Group
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Currency,
+			&i.DeletedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -108,10 +214,10 @@ func (q *Queries) ListGroups(ctx context.Context, arg ListGroupsParams) ([]Group
 
 const listGroupsByUser = `-- name: ListGroupsByUser :many
 SELECT 
-  g.id, g.name
+  g.id, g.name, g.currency, g.deleted_at
 FROM "groups" g
 INNER JOIN group_members gm ON g.id = gm.group_id
-WHERE gm.user_id = $1
+WHERE gm.user_id = $1 AND gm.removed_at IS NULL AND g.deleted_at IS NULL
 ORDER BY g.name
 LIMIT $2
 OFFSET $3
@@ -132,7 +238,12 @@ func (q *Queries) ListGroupsByUser(ctx context.Context, arg ListGroupsByUserPara
 	items := []Group{}
 	for rows.Next() {
 		var i Group
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Currency,
+			&i.DeletedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -143,11 +254,44 @@ func (q *Queries) ListGroupsByUser(ctx context.Context, arg ListGroupsByUserPara
 	return items, nil
 }
 
+const purgeDeletedGroups = `-- name: PurgeDeletedGroups :execrows
+DELETE FROM "groups"
+WHERE deleted_at < $1::timestamptz
+`
+
+// Permanently deletes groups in the trash since before the given time, with their members, transactions & settlements
+func (q *Queries) PurgeDeletedGroups(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedGroups, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreGroup = `-- name: RestoreGroup :one
+UPDATE "groups"
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, currency, deleted_at
+`
+
+func (q *Queries) RestoreGroup(ctx context.Context, id int64) (Group, error) {
+	row := q.db.QueryRow(ctx, restoreGroup, id)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const updateGroup = `-- name: UpdateGroup :one
 UPDATE "groups"
 SET name = $1
 WHERE id = $2
-RETURNING id, name
+RETURNING id, name, currency, deleted_at
 `
 
 type UpdateGroupParams struct {
@@ -158,6 +302,11 @@ type UpdateGroupParams struct {
 func (q *Queries) UpdateGroup(ctx context.Context, arg UpdateGroupParams) (Group, error) {
 	row := q.db.QueryRow(ctx, updateGroup, arg.Name, arg.ID)
 	var i Group
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Currency,
+		&i.DeletedAt,
+	)
 	return i, err
 }
@@ -8,21 +8,65 @@ package db
 import (
 	"context"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimGroupMember = `-- name: ClaimGroupMember :one
+UPDATE group_members
+SET user_id = $2, role = $3
+WHERE id = $1 AND user_id IS NULL AND removed_at IS NULL
+RETURNING id, group_id, member_name, user_id, created_at, joined_on, left_on, role, removed_at
+`
+
+type ClaimGroupMemberParams struct {
+	ID     int64  `json:"id"`
+	UserID *int64 `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// Links a user to a placeholder member, only if no user has claimed it yet and it hasn't been removed
+func (q *Queries) ClaimGroupMember(ctx context.Context, arg ClaimGroupMemberParams) (GroupMember, error) {
+	row := q.db.QueryRow(ctx, claimGroupMember, arg.ID, arg.UserID, arg.Role)
+	var i GroupMember
+	err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.MemberName,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.JoinedOn,
+		&i.LeftOn,
+		&i.Role,
+		&i.RemovedAt,
+	)
+	return i, err
+}
+
 const createGroupMember = `-- name: CreateGroupMember :one
-INSERT INTO group_members (group_id, user_id)
-VALUES ($1, $2)
-RETURNING id, group_id, member_name, user_id, created_at
+INSERT INTO group_members (group_id, member_name, user_id, joined_on, left_on, role)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, group_id, member_name, user_id, created_at, joined_on, left_on, role, removed_at
 `
 
 type CreateGroupMemberParams struct {
-	GroupID int64  `json:"group_id"`
-	UserID  *int64 `json:"user_id"`
+	GroupID    int64       `json:"group_id"`
+	MemberName *string     `json:"member_name"`
+	UserID     *int64      `json:"user_id"`
+	JoinedOn   pgtype.Date `json:"joined_on"`
+	LeftOn     pgtype.Date `json:"left_on"`
+	Role       string      `json:"role"`
 }
 
 func (q *Queries) CreateGroupMember(ctx context.Context, arg CreateGroupMemberParams) (GroupMember, error) {
-	row := q.db.QueryRow(ctx, createGroupMember, arg.GroupID, arg.UserID)
+	row := q.db.QueryRow(ctx, createGroupMember,
+		arg.GroupID,
+		arg.MemberName,
+		arg.UserID,
+		arg.JoinedOn,
+		arg.LeftOn,
+		arg.Role,
+	)
 	var i GroupMember
 	err := row.Scan(
 		&i.ID,
@@ -30,6 +74,10 @@ func (q *Queries) CreateGroupMember(ctx context.Context, arg CreateGroupMemberPa
 		&i.MemberName,
 		&i.UserID,
 		&i.CreatedAt,
+		&i.JoinedOn,
+		&i.LeftOn,
+		&i.Role,
+		&i.RemovedAt,
 	)
 	return i, err
 }
@@ -37,9 +85,10 @@ func (q *Queries) CreateGroupMember(ctx context.Context, arg CreateGroupMemberPa
 const deleteGroupMember = `-- name: DeleteGroupMember :one
 DELETE FROM group_members
 WHERE id = $1
-RETURNING id, group_id, member_name, user_id, created_at
+RETURNING id, group_id, member_name, user_id, created_at, joined_on, left_on, role, removed_at
 `
 
+// Only members without transactions, splits or settlements can be deleted
 func (q *Queries) DeleteGroupMember(ctx context.Context, id int64) (GroupMember, error) {
 	row := q.db.QueryRow(ctx, deleteGroupMember, id)
 	var i GroupMember
@@ -49,61 +98,37 @@ func (q *Queries) DeleteGroupMember(ctx context.Context, id int64) (GroupMember,
 		&i.MemberName,
 		&i.UserID,
 		&i.CreatedAt,
+		&i.JoinedOn,
+		&i.LeftOn,
+		&i.Role,
+		&i.RemovedAt,
 	)
 	return i, err
 }
 
-const deleteGroupMembersByGroupID = `-- name: DeleteGroupMembersByGroupID :many
-DELETE FROM group_members
-WHERE group_id = $1
-RETURNING id, group_id, member_name, user_id, created_at
-`
-
-func (q *Queries) DeleteGroupMembersByGroupID(ctx context.Context, groupID int64) ([]GroupMember, error) {
-	rows, err := q.db.Query(ctx, deleteGroupMembersByGroupID, groupID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GroupMember{}
-	for rows.Next() {
-		var i GroupMember
-		if err := rows.Scan(
-			&i.ID,
-			&i.GroupID,
-			&i.MemberName,
-			&i.UserID,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const getGroupMemberByID = `-- name: GetGroupMemberByID :one
 SELECT 
-  gm.id, gm.group_id, gm.member_name, gm.user_id, gm.created_at,
+  gm.id, gm.group_id, gm.member_name, gm.user_id, gm.created_at, gm.joined_on, gm.left_on, gm.role, gm.removed_at,
   g.name AS group_name,
   u.name AS user_name
 FROM group_members gm
 JOIN groups g ON gm.group_id = g.id 
-JOIN users u ON gm.user_id = u.id
+LEFT JOIN users u ON gm.user_id = u.id
 WHERE gm.id = $1 LIMIT 1
 `
 
 type GetGroupMemberByIDRow struct {
-	ID         int64     `json:"id"`
-	GroupID    int64     `json:"group_id"`
-	MemberName *string   `json:"member_name"`
-	UserID     *int64    `json:"user_id"`
-	CreatedAt  time.Time `json:"created_at"`
-	GroupName  string    `json:"group_name"`
-	UserName   string    `json:"user_name"`
+	ID         int64              `json:"id"`
+	GroupID    int64              `json:"group_id"`
+	MemberName *string            `json:"member_name"`
+	UserID     *int64             `json:"user_id"`
+	CreatedAt  time.Time          `json:"created_at"`
+	JoinedOn   pgtype.Date        `json:"joined_on"`
+	LeftOn     pgtype.Date        `json:"left_on"`
+	Role       string             `json:"role"`
+	RemovedAt  pgtype.Timestamptz `json:"removed_at"`
+	GroupName  string             `json:"group_name"`
+	UserName   *string            `json:"user_name"`
 }
 
 func (q *Queries) GetGroupMemberByID(ctx context.Context, id int64) (GetGroupMemberByIDRow, error) {
@@ -115,6 +140,10 @@ func (q *Queries) GetGroupMemberByID(ctx context.Context, id int64) (GetGroupMem
 		&i.MemberName,
 		&i.UserID,
 		&i.CreatedAt,
+		&i.JoinedOn,
+		&i.LeftOn,
+		&i.Role,
+		&i.RemovedAt,
 		&i.GroupName,
 		&i.UserName,
 	)
@@ -123,13 +152,13 @@ func (q *Queries) GetGroupMemberByID(ctx context.Context, id int64) (GetGroupMem
 
 const listGroupMembersByGroupID = `-- name: ListGroupMembersByGroupID :many
 SELECT 
-  gm.id, gm.group_id, gm.member_name, gm.user_id, gm.created_at,
+  gm.id, gm.group_id, gm.member_name, gm.user_id, gm.created_at, gm.joined_on, gm.left_on, gm.role, gm.removed_at,
   g.name AS group_name,
   u.name AS user_name
 FROM group_members gm
 JOIN groups g ON gm.group_id = g.id
-JOIN users u ON gm.user_id = u.id
-WHERE gm.group_id = $1
+LEFT JOIN users u ON gm.user_id = u.id
+WHERE gm.group_id = $1 AND gm.removed_at IS NULL AND g.deleted_at IS NULL
 ORDER BY gm.id
 LIMIT $2
 OFFSET $3
@@ -142,15 +171,20 @@ type ListGroupMembersByGroupIDParams struct {
 }
 
 type ListGroupMembersByGroupIDRow struct {
-	ID         int64     `json:"id"`
-	GroupID    int64     `json:"group_id"`
-	MemberName *string   `json:"member_name"`
-	UserID     *int64    `json:"user_id"`
-	CreatedAt  time.Time `json:"created_at"`
-	GroupName  string    `json:"group_name"`
-	UserName   string    `json:"user_name"`
+	ID         int64              `json:"id"`
+	GroupID    int64              `json:"group_id"`
+	MemberName *string            `json:"member_name"`
+	UserID     *int64             `json:"user_id"`
+	CreatedAt  time.Time          `json:"created_at"`
+	JoinedOn   pgtype.Date        `json:"joined_on"`
+	LeftOn     pgtype.Date        `json:"left_on"`
+	Role       string             `json:"role"`
+	RemovedAt  pgtype.Timestamptz `json:"removed_at"`
+	GroupName  string             `json:"group_name"`
+	UserName   *string            `json:"user_name"`
 }
 
+// Removed members and members of deleted groups are not listed
 func (q *Queries) ListGroupMembersByGroupID(ctx context.Context, arg ListGroupMembersByGroupIDParams) ([]ListGroupMembersByGroupIDRow, error) {
 	rows, err := q.db.Query(ctx, listGroupMembersByGroupID, arg.GroupID, arg.Limit, arg.Offset)
 	if err != nil {
@@ -166,6 +200,10 @@ func (q *Queries) ListGroupMembersByGroupID(ctx context.Context, arg ListGroupMe
 			&i.MemberName,
 			&i.UserID,
 			&i.CreatedAt,
+			&i.JoinedOn,
+			&i.LeftOn,
+			&i.Role,
+			&i.RemovedAt,
 			&i.GroupName,
 			&i.UserName,
 		); err != nil {
@@ -179,15 +217,16 @@ func (q *Queries) ListGroupMembersByGroupID(ctx context.Context, arg ListGroupMe
 	return items, nil
 }
 
-const unlinkGroupMember = `-- name: UnlinkGroupMember :one
+const removeGroupMember = `-- name: RemoveGroupMember :one
 UPDATE group_members
-SET user_id = NULL
-WHERE id = $1
-RETURNING id, group_id, member_name, user_id, created_at
+SET removed_at = now()
+WHERE id = $1 AND removed_at IS NULL
+RETURNING id, group_id, member_name, user_id, created_at, joined_on, left_on, role, removed_at
 `
 
-func (q *Queries) UnlinkGroupMember(ctx context.Context, id int64) (GroupMember, error) {
-	row := q.db.QueryRow(ctx, unlinkGroupMember, id)
+// Marks a member as removed, their transactions, splits & settlements are kept
+func (q *Queries) RemoveGroupMember(ctx context.Context, id int64) (GroupMember, error) {
+	row := q.db.QueryRow(ctx, removeGroupMember, id)
 	var i GroupMember
 	err := row.Scan(
 		&i.ID,
@@ -195,25 +234,104 @@ func (q *Queries) UnlinkGroupMember(ctx context.Context, id int64) (GroupMember,
 		&i.MemberName,
 		&i.UserID,
 		&i.CreatedAt,
+		&i.JoinedOn,
+		&i.LeftOn,
+		&i.Role,
+		&i.RemovedAt,
 	)
 	return i, err
 }
 
+const removeGroupMembersByGroupID = `-- name: RemoveGroupMembersByGroupID :many
+UPDATE group_members
+SET removed_at = now()
+WHERE group_id = $1 AND removed_at IS NULL AND role <> 'owner'
+RETURNING id, group_id, member_name, user_id, created_at, joined_on, left_on, role, removed_at
+`
+
+// The owner is kept, a group always has an owner
+func (q *Queries) RemoveGroupMembersByGroupID(ctx context.Context, groupID int64) ([]GroupMember, error) {
+	rows, err := q.db.Query(ctx, removeGroupMembersByGroupID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GroupMember{}
+	for rows.Next() {
+		var i GroupMember
+		if err := rows.Scan(
+			&i.ID,
+			&i.GroupID,
+			&i.MemberName,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.JoinedOn,
+			&i.LeftOn,
+			&i.Role,
+			&i.RemovedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateGroupMember = `-- name: UpdateGroupMember :one
 UPDATE group_members
-SET group_id = $1, user_id = $2
-WHERE id = $3
-RETURNING id, group_id, member_name, user_id, created_at
+SET group_id = $1, user_id = $2, joined_on = $3, left_on = $4
+WHERE id = $5
+RETURNING id, group_id, member_name, user_id, created_at, joined_on, left_on, role, removed_at
 `
 
 type UpdateGroupMemberParams struct {
-	GroupID int64  `json:"group_id"`
-	UserID  *int64 `json:"user_id"`
-	ID      int64  `json:"id"`
+	GroupID  int64       `json:"group_id"`
+	UserID   *int64      `json:"user_id"`
+	JoinedOn pgtype.Date `json:"joined_on"`
+	LeftOn   pgtype.Date `json:"left_on"`
+	ID       int64       `json:"id"`
 }
 
 func (q *Queries) UpdateGroupMember(ctx context.Context, arg UpdateGroupMemberParams) (GroupMember, error) {
-	row := q.db.QueryRow(ctx, updateGroupMember, arg.GroupID, arg.UserID, arg.ID)
+	row := q.db.QueryRow(ctx, updateGroupMember,
+		arg.GroupID,
+		arg.UserID,
+		arg.JoinedOn,
+		arg.LeftOn,
+		arg.ID,
+	)
+	var i GroupMember
+	err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.MemberName,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.JoinedOn,
+		&i.LeftOn,
+		&i.Role,
+		&i.RemovedAt,
+	)
+	return i, err
+}
+
+const updateGroupMemberRole = `-- name: UpdateGroupMemberRole :one
+UPDATE group_members
+SET role = $2
+WHERE id = $1
+RETURNING id, group_id, member_name, user_id, created_at, joined_on, left_on, role, removed_at
+`
+
+type UpdateGroupMemberRoleParams struct {
+	ID   int64  `json:"id"`
+	Role string `json:"role"`
+}
+
+func (q *Queries) UpdateGroupMemberRole(ctx context.Context, arg UpdateGroupMemberRoleParams) (GroupMember, error) {
+	row := q.db.QueryRow(ctx, updateGroupMemberRole, arg.ID, arg.Role)
 	var i GroupMember
 	err := row.Scan(
 		&i.ID,
@@ -221,6 +339,10 @@ func (q *Queries) UpdateGroupMember(ctx context.Context, arg UpdateGroupMemberPa
 		&i.MemberName,
 		&i.UserID,
 		&i.CreatedAt,
+		&i.JoinedOn,
+		&i.LeftOn,
+		&i.Role,
+		&i.RemovedAt,
 	)
 	return i, err
 }
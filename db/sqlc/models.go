@@ -11,9 +11,32 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+type AuditEvent struct {
+	ID          int64     `json:"id"`
+	GroupID     *int64    `json:"group_id"`
+	ActorUserID *int64    `json:"actor_user_id"`
+	Entity      string    `json:"entity"`
+	EntityID    int64     `json:"entity_id"`
+	Action      string    `json:"action"`
+	Before      []byte    `json:"before"`
+	After       []byte    `json:"after"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ExchangeRate struct {
+	FromCurrency string          `json:"from_currency"`
+	ToCurrency   string          `json:"to_currency"`
+	RateDate     time.Time       `json:"rate_date"`
+	Rate         decimal.Decimal `json:"rate"`
+	CreatedAt    time.Time       `json:"created_at"`
+	ModifiedAt   time.Time       `json:"modified_at"`
+}
+
 type Group struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	Currency  string             `json:"currency"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
 
 type GroupBalance struct {
@@ -29,12 +52,49 @@ type GroupBalancesNet struct {
 	NetBalance decimal.Decimal `json:"net_balance"`
 }
 
+type GroupInvitation struct {
+	ID            int64              `json:"id"`
+	GroupID       int64              `json:"group_id"`
+	TokenHash     string             `json:"token_hash"`
+	Email         *string            `json:"email"`
+	Role          string             `json:"role"`
+	SingleUse     bool               `json:"single_use"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+	UseCount      int32              `json:"use_count"`
+	CreatedBy     *int64             `json:"created_by"`
+	CreatedAt     time.Time          `json:"created_at"`
+	RevokedAt     pgtype.Timestamptz `json:"revoked_at"`
+	GroupMemberID *int64             `json:"group_member_id"`
+}
+
+type GroupLedger struct {
+	TransactionID int64           `json:"transaction_id"`
+	GroupID       int64           `json:"group_id"`
+	Creditor      int64           `json:"creditor"`
+	Debtor        *int64          `json:"debtor"`
+	Amount        decimal.Decimal `json:"amount"`
+}
+
 type GroupMember struct {
-	ID         int64     `json:"id"`
-	GroupID    int64     `json:"group_id"`
-	MemberName *string   `json:"member_name"`
-	UserID     *int64    `json:"user_id"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID         int64              `json:"id"`
+	GroupID    int64              `json:"group_id"`
+	MemberName *string            `json:"member_name"`
+	UserID     *int64             `json:"user_id"`
+	CreatedAt  time.Time          `json:"created_at"`
+	JoinedOn   pgtype.Date        `json:"joined_on"`
+	LeftOn     pgtype.Date        `json:"left_on"`
+	Role       string             `json:"role"`
+	RemovedAt  pgtype.Timestamptz `json:"removed_at"`
+}
+
+type OneTimeToken struct {
+	ID        int64              `json:"id"`
+	Purpose   string             `json:"purpose"`
+	TokenHash string             `json:"token_hash"`
+	UserID    int64              `json:"user_id"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
 }
 
 type RefreshToken struct {
@@ -45,6 +105,23 @@ type RefreshToken struct {
 	CreatedAt  time.Time          `json:"created_at"`
 	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
 	DeviceInfo *string            `json:"device_info"`
+	FamilyID   pgtype.UUID        `json:"family_id"`
+	IpAddress  *string            `json:"ip_address"`
+	LastUsedAt time.Time          `json:"last_used_at"`
+}
+
+type Settlement struct {
+	ID         int64           `json:"id"`
+	GroupID    int64           `json:"group_id"`
+	FromMember int64           `json:"from_member"`
+	ToMember   int64           `json:"to_member"`
+	Amount     decimal.Decimal `json:"amount"`
+	SettledOn  time.Time       `json:"settled_on"`
+	Note       *string         `json:"note"`
+	CreatedBy  *int64          `json:"created_by"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ModifiedAt time.Time       `json:"modified_at"`
+	Status     string          `json:"status"`
 }
 
 type Split struct {
@@ -59,16 +136,70 @@ type Split struct {
 }
 
 type Transaction struct {
-	ID              int64           `json:"id"`
-	GroupID         int64           `json:"group_id"`
-	Name            string          `json:"name"`
-	TransactionDate time.Time       `json:"transaction_date"`
-	Amount          decimal.Decimal `json:"amount"`
-	Category        *string         `json:"category"`
-	Note            *string         `json:"note"`
-	ByUser          int64           `json:"by_user"`
-	CreatedAt       time.Time       `json:"created_at"`
-	ModifiedAt      time.Time       `json:"modified_at"`
+	ID              int64              `json:"id"`
+	GroupID         int64              `json:"group_id"`
+	Name            string             `json:"name"`
+	TransactionDate time.Time          `json:"transaction_date"`
+	Amount          decimal.Decimal    `json:"amount"`
+	Category        *string            `json:"category"`
+	Note            *string            `json:"note"`
+	ByUser          int64              `json:"by_user"`
+	CreatedAt       time.Time          `json:"created_at"`
+	ModifiedAt      time.Time          `json:"modified_at"`
+	Currency        string             `json:"currency"`
+	ExchangeRate    decimal.Decimal    `json:"exchange_rate"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	SplitMode       *string            `json:"split_mode"`
+}
+
+type TransactionAdjustment struct {
+	ID             int64           `json:"id"`
+	TransactionID  int64           `json:"transaction_id"`
+	Name           string          `json:"name"`
+	AdjustmentType string          `json:"adjustment_type"`
+	Value          decimal.Decimal `json:"value"`
+	Amount         decimal.Decimal `json:"amount"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ModifiedAt     time.Time       `json:"modified_at"`
+}
+
+type TransactionItem struct {
+	ID            int64           `json:"id"`
+	TransactionID int64           `json:"transaction_id"`
+	Name          string          `json:"name"`
+	Amount        decimal.Decimal `json:"amount"`
+	CreatedAt     time.Time       `json:"created_at"`
+	ModifiedAt    time.Time       `json:"modified_at"`
+}
+
+type TransactionItemMember struct {
+	ItemID   int64 `json:"item_id"`
+	MemberID int64 `json:"member_id"`
+}
+
+type TransactionPayer struct {
+	TransactionID int64           `json:"transaction_id"`
+	MemberID      int64           `json:"member_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	CreatedAt     time.Time       `json:"created_at"`
+	ModifiedAt    time.Time       `json:"modified_at"`
+}
+
+type TransactionPayment struct {
+	TransactionID int64           `json:"transaction_id"`
+	GroupID       int64           `json:"group_id"`
+	MemberID      int64           `json:"member_id"`
+	Amount        decimal.Decimal `json:"amount"`
+}
+
+type TransactionRevision struct {
+	ID            int64     `json:"id"`
+	TransactionID int64     `json:"transaction_id"`
+	Revision      int32     `json:"revision"`
+	Snapshot      []byte    `json:"snapshot"`
+	RevertedFrom  *int32    `json:"reverted_from"`
+	CreatedBy     *int64    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 type User struct {
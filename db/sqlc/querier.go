@@ -6,28 +6,70 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
-	CreateGroup(ctx context.Context, name string) (Group, error)
+	// Links a user to a placeholder member, only if no user has claimed it yet and it hasn't been removed
+	ClaimGroupMember(ctx context.Context, arg ClaimGroupMemberParams) (GroupMember, error)
+	CountTransactionRevisions(ctx context.Context, transactionID int64) (int64, error)
+	CreateGroup(ctx context.Context, arg CreateGroupParams) (Group, error)
+	CreateGroupInvitation(ctx context.Context, arg CreateGroupInvitationParams) (GroupInvitation, error)
 	CreateGroupMember(ctx context.Context, arg CreateGroupMemberParams) (GroupMember, error)
+	CreateOneTimeToken(ctx context.Context, arg CreateOneTimeTokenParams) (OneTimeToken, error)
 	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	// Creates the token replacing a rotated token, in the same family
+	CreateRotatedRefreshToken(ctx context.Context, arg CreateRotatedRefreshTokenParams) (RefreshToken, error)
+	CreateSettlement(ctx context.Context, arg CreateSettlementParams) (Settlement, error)
 	CreateSplit(ctx context.Context, arg CreateSplitParams) (Split, error)
 	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
+	CreateTransactionAdjustment(ctx context.Context, arg CreateTransactionAdjustmentParams) (TransactionAdjustment, error)
+	CreateTransactionItem(ctx context.Context, arg CreateTransactionItemParams) (TransactionItem, error)
+	CreateTransactionItemMember(ctx context.Context, arg CreateTransactionItemMemberParams) (TransactionItemMember, error)
+	CreateTransactionPayer(ctx context.Context, arg CreateTransactionPayerParams) (TransactionPayer, error)
+	// Numbers the revision after the latest revision of the transaction
+	CreateTransactionRevision(ctx context.Context, arg CreateTransactionRevisionParams) (TransactionRevision, error)
 	CreateUser(ctx context.Context, name string) (User, error)
 	CreateUserWithAuth(ctx context.Context, arg CreateUserWithAuthParams) (User, error)
 	DeleteExpiredTokens(ctx context.Context) error
+	// Moves a group to the trash, it is purged after the retention period
 	DeleteGroup(ctx context.Context, id int64) (Group, error)
+	// Only members without transactions, splits or settlements can be deleted
 	DeleteGroupMember(ctx context.Context, id int64) (GroupMember, error)
-	DeleteGroupMembersByGroupID(ctx context.Context, groupID int64) ([]GroupMember, error)
+	// Deletes a member's splits of transactions the other member is also split into, run after MergeMemberSplits
+	DeleteMergedMemberSplits(ctx context.Context, arg DeleteMergedMemberSplitsParams) error
+	// Deletes a member's shares of line items the other member also shares
+	DeleteMergedMemberTransactionItemMembers(ctx context.Context, arg DeleteMergedMemberTransactionItemMembersParams) error
+	// Deletes a member's payments of transactions the other member also paid, run after MergeMemberTransactionPayers
+	DeleteMergedMemberTransactionPayers(ctx context.Context, arg DeleteMergedMemberTransactionPayersParams) error
+	DeleteSettlement(ctx context.Context, id int64) (Settlement, error)
+	// Deletes settlements between two members in either direction
+	DeleteSettlementsBetweenMembers(ctx context.Context, arg DeleteSettlementsBetweenMembersParams) error
 	DeleteSplit(ctx context.Context, id int64) (Split, error)
+	// Moves a transaction to the trash, its splits, payers & items are kept until it is purged
 	DeleteTransaction(ctx context.Context, id int64) (Transaction, error)
+	DeleteTransactionAdjustments(ctx context.Context, transactionID int64) ([]TransactionAdjustment, error)
+	DeleteTransactionItems(ctx context.Context, transactionID int64) ([]TransactionItem, error)
+	DeleteTransactionPayers(ctx context.Context, transactionID int64) ([]TransactionPayer, error)
 	DeleteTransactionSplits(ctx context.Context, transactionID int64) ([]Split, error)
 	DeleteUser(ctx context.Context, id int64) (User, error)
+	// Returns a group in the trash, only if the user owns it
+	GetDeletedGroupByIDForOwner(ctx context.Context, arg GetDeletedGroupByIDForOwnerParams) (Group, error)
+	GetDeletedTransactionByID(ctx context.Context, id int64) (Transaction, error)
+	GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRate, error)
 	GetGroupByID(ctx context.Context, id int64) (Group, error)
 	GetGroupByIDForUpdate(ctx context.Context, id int64) (Group, error)
+	GetGroupInvitationByID(ctx context.Context, id int64) (GroupInvitation, error)
+	GetGroupInvitationByTokenHash(ctx context.Context, tokenHash string) (GroupInvitation, error)
+	GetGroupInvitationByTokenHashForUpdate(ctx context.Context, tokenHash string) (GroupInvitation, error)
 	GetGroupMemberByID(ctx context.Context, id int64) (GetGroupMemberByIDRow, error)
+	GetOneTimeTokenByHashForUpdate(ctx context.Context, arg GetOneTimeTokenByHashForUpdateParams) (OneTimeToken, error)
 	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetRefreshTokenByHashForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetSettlementByID(ctx context.Context, id int64) (Settlement, error)
+	GetSettlementByIDForUpdate(ctx context.Context, id int64) (Settlement, error)
 	GetSplitByID(ctx context.Context, id int64) (Split, error)
 	GetSplitByIDForUpdate(ctx context.Context, id int64) (Split, error)
 	GetSplitsByTransactionID(ctx context.Context, transactionID int64) ([]Split, error)
@@ -36,6 +78,7 @@ type Querier interface {
 	GetSplitsByUserFiltered(ctx context.Context, arg GetSplitsByUserFilteredParams) ([]Split, error)
 	GetTransactionByID(ctx context.Context, id int64) (Transaction, error)
 	GetTransactionByIDForUpdate(ctx context.Context, id int64) (Transaction, error)
+	GetTransactionRevision(ctx context.Context, arg GetTransactionRevisionParams) (TransactionRevision, error)
 	GetTransactionsByGroupInPeriod(ctx context.Context, arg GetTransactionsByGroupInPeriodParams) ([]Transaction, error)
 	GetTransactionsByUser(ctx context.Context, arg GetTransactionsByUserParams) ([]Transaction, error)
 	GetTransactionsByUserInPeriod(ctx context.Context, arg GetTransactionsByUserInPeriodParams) ([]Transaction, error)
@@ -44,29 +87,94 @@ type Querier interface {
 	GetUserRefreshTokens(ctx context.Context, userID int64) ([]RefreshToken, error)
 	GroupBalances(ctx context.Context, groupID int64) ([]GroupBalancesRow, error)
 	GroupBalancesNet(ctx context.Context, groupID int64) ([]GroupBalancesNetRow, error)
+	// Uses up every unused token of a purpose for a user, so only the newest token or none works
+	InvalidateUserOneTimeTokens(ctx context.Context, arg InvalidateUserOneTimeTokensParams) error
+	// Newest events first
+	ListAuditEventsByGroupID(ctx context.Context, arg ListAuditEventsByGroupIDParams) ([]ListAuditEventsByGroupIDRow, error)
+	ListDeletedGroupsByOwner(ctx context.Context, arg ListDeletedGroupsByOwnerParams) ([]Group, error)
+	ListDeletedTransactionsByGroupID(ctx context.Context, arg ListDeletedTransactionsByGroupIDParams) ([]Transaction, error)
+	// Transactions whose splits don't sum to the transaction amount, or were split from a different amount
+	// Transactions without splits aren't listed
+	ListInconsistentTransactionsByGroupID(ctx context.Context, arg ListInconsistentTransactionsByGroupIDParams) ([]ListInconsistentTransactionsByGroupIDRow, error)
+	ListGroupInvitationsByGroupID(ctx context.Context, arg ListGroupInvitationsByGroupIDParams) ([]GroupInvitation, error)
+	// Removed members and members of deleted groups are not listed
 	ListGroupMembersByGroupID(ctx context.Context, arg ListGroupMembersByGroupIDParams) ([]ListGroupMembersByGroupIDRow, error)
 	ListGroups(ctx context.Context, arg ListGroupsParams) ([]Group, error)
 	ListGroupsByUser(ctx context.Context, arg ListGroupsByUserParams) ([]Group, error)
+	ListSettlementsByGroupID(ctx context.Context, arg ListSettlementsByGroupIDParams) ([]Settlement, error)
 	ListSplits(ctx context.Context, arg ListSplitsParams) ([]Split, error)
 	ListSplitsByUserGroups(ctx context.Context, arg ListSplitsByUserGroupsParams) ([]Split, error)
 	ListSplitsForTransaction(ctx context.Context, transactionID int64) ([]Split, error)
+	ListTransactionAdjustmentsByTransactionID(ctx context.Context, transactionID int64) ([]TransactionAdjustment, error)
+	ListTransactionItemMembersByTransactionID(ctx context.Context, transactionID int64) ([]TransactionItemMember, error)
+	ListTransactionItemsByTransactionID(ctx context.Context, transactionID int64) ([]TransactionItem, error)
+	ListTransactionPayersByTransactionID(ctx context.Context, transactionID int64) ([]TransactionPayer, error)
+	ListTransactionRevisions(ctx context.Context, arg ListTransactionRevisionsParams) ([]TransactionRevision, error)
 	ListTransactions(ctx context.Context, arg ListTransactionsParams) ([]Transaction, error)
 	ListTransactionsByUserGroups(ctx context.Context, arg ListTransactionsByUserGroupsParams) ([]Transaction, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	// Adds a member's splits to another member's splits of the same transactions
+	MergeMemberSplits(ctx context.Context, arg MergeMemberSplitsParams) error
+	// Adds a member's payments to another member's payments of the same transactions
+	MergeMemberTransactionPayers(ctx context.Context, arg MergeMemberTransactionPayersParams) error
+	// Permanently deletes groups in the trash since before the given time, with their members, transactions & settlements
+	PurgeDeletedGroups(ctx context.Context, deletedBefore time.Time) (int64, error)
+	// Permanently deletes transactions in the trash since before the given time, with their splits, payers & items
+	PurgeDeletedTransactions(ctx context.Context, deletedBefore time.Time) (int64, error)
+	// Moves every settlement paid or received by a group member to another member
+	ReassignMemberSettlements(ctx context.Context, arg ReassignMemberSettlementsParams) error
+	// Moves every split of a group member to another member
+	ReassignMemberSplits(ctx context.Context, arg ReassignMemberSplitsParams) error
+	// Moves every line item share of a group member to another member
+	ReassignMemberTransactionItemMembers(ctx context.Context, arg ReassignMemberTransactionItemMembersParams) error
+	// Moves every payment of a group member to another member
+	ReassignMemberTransactionPayers(ctx context.Context, arg ReassignMemberTransactionPayersParams) error
+	// Moves every transaction paid by a group member to another member
+	ReassignTransactionsByUser(ctx context.Context, arg ReassignTransactionsByUserParams) error
+	// Marks a member as removed, their transactions, splits & settlements are kept
+	RemoveGroupMember(ctx context.Context, id int64) (GroupMember, error)
+	// The owner is kept, a group always has an owner
+	RemoveGroupMembersByGroupID(ctx context.Context, groupID int64) ([]GroupMember, error)
+	// Sets a split's amount for a new transaction amount, split_percent is kept
+	RescaleSplit(ctx context.Context, arg RescaleSplitParams) (Split, error)
+	RestoreGroup(ctx context.Context, id int64) (Group, error)
+	RestoreTransaction(ctx context.Context, id int64) (Transaction, error)
 	RevokeAllUserTokens(ctx context.Context, userID int64) error
+	RevokeGroupInvitation(ctx context.Context, id int64) (GroupInvitation, error)
+	// Revokes every session of a user except the one with the given family
+	RevokeOtherUserRefreshTokens(ctx context.Context, arg RevokeOtherUserRefreshTokensParams) (int64, error)
 	RevokeRefreshToken(ctx context.Context, tokenHash string) error
-	UnlinkGroupMember(ctx context.Context, id int64) (GroupMember, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID pgtype.UUID) (int64, error)
+	// Sets the user recorded on audit events for the rest of the DB transaction
+	SetAuditActor(ctx context.Context, actorUserID int64) error
+	// Returns the net balance of two users in every group they are both members of
+	// Members without ledger entries in a group have a net balance of 0
+	SharedGroupBalancesNet(ctx context.Context, arg SharedGroupBalancesNetParams) ([]SharedGroupBalancesNetRow, error)
+	// Records how the transaction's splits were calculated, NULL for splits sent without a mode
+	SetTransactionSplitMode(ctx context.Context, arg SetTransactionSplitModeParams) error
 	UpdateGroup(ctx context.Context, arg UpdateGroupParams) (Group, error)
 	UpdateGroupMember(ctx context.Context, arg UpdateGroupMemberParams) (GroupMember, error)
+	UpdateGroupMemberRole(ctx context.Context, arg UpdateGroupMemberRoleParams) (GroupMember, error)
+	UpdateSettlementStatus(ctx context.Context, arg UpdateSettlementStatusParams) (Settlement, error)
 	UpdateSplit(ctx context.Context, arg UpdateSplitParams) (Split, error)
 	UpdateTransaction(ctx context.Context, arg UpdateTransactionParams) (Transaction, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UseGroupInvitation(ctx context.Context, id int64) (GroupInvitation, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error)
+	UseOneTimeToken(ctx context.Context, id int64) (OneTimeToken, error)
 	// Returns balances by group for a specific user
 	// Only includes groups where the user is a member (filtered via WHERE gm.user_id = $1)
 	// This is the correct place to filter by user membership for security and performance
 	UserBalancesByGroup(ctx context.Context, userID *int64) ([]UserBalancesByGroupRow, error)
 	UserBalancesByMember(ctx context.Context, userID *int64) ([]UserBalancesByMemberRow, error)
 	UserBalancesSummary(ctx context.Context, userID *int64) (UserBalancesSummaryRow, error)
+	// Returns the balance between every pair of users in a user's network, everyone sharing a group with the user, including the user
+	// Built on user_balances_by_member, so balances are aggregated across all groups the pair shares
+	// Positive values mean member_user_id owes user_id
+	UserNetworkBalances(ctx context.Context, userID *int64) ([]UserNetworkBalancesRow, error)
+	// Marks the user's email verified, only while the email is still the one the verification token was issued for
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -59,6 +59,23 @@ func (q *Queries) CreateSplit(ctx context.Context, arg CreateSplitParams) (Split
 	return i, err
 }
 
+const deleteMergedMemberSplits = `-- name: DeleteMergedMemberSplits :exec
+DELETE FROM splits f
+WHERE f.split_user = $1::bigint
+  AND EXISTS (SELECT 1 FROM splits s WHERE s.transaction_id = f.transaction_id AND s.split_user = $2::bigint)
+`
+
+type DeleteMergedMemberSplitsParams struct {
+	FromMemberID int64 `json:"from_member_id"`
+	IntoMemberID int64 `json:"into_member_id"`
+}
+
+// Deletes a member's splits of transactions the other member is also split into, run after MergeMemberSplits
+func (q *Queries) DeleteMergedMemberSplits(ctx context.Context, arg DeleteMergedMemberSplitsParams) error {
+	_, err := q.db.Exec(ctx, deleteMergedMemberSplits, arg.FromMemberID, arg.IntoMemberID)
+	return err
+}
+
 const deleteSplit = `-- name: DeleteSplit :one
 DELETE FROM "splits" 
 WHERE id = $1
@@ -288,7 +305,8 @@ const getSplitsByUserFiltered = `-- name: GetSplitsByUserFiltered :many
 SELECT s.id, s.transaction_id, s.tx_amount, s.split_percent, s.split_amount, s.split_user, s.created_at, s.modified_at FROM "splits" s
 INNER JOIN transactions t ON s.transaction_id = t.id
 INNER JOIN group_members gm ON t.group_id = gm.group_id
-WHERE s.split_user = $1 AND gm.user_id = $2
+INNER JOIN "groups" g ON t.group_id = g.id
+WHERE s.split_user = $1 AND gm.user_id = $2 AND gm.removed_at IS NULL AND t.deleted_at IS NULL AND g.deleted_at IS NULL
 ORDER BY s.created_at desc
 LIMIT $3
 OFFSET $4
@@ -384,7 +402,8 @@ SELECT
 FROM "splits" s
 INNER JOIN transactions t ON s.transaction_id = t.id
 INNER JOIN group_members gm ON t.group_id = gm.group_id
-WHERE gm.user_id = $1
+INNER JOIN "groups" g ON t.group_id = g.id
+WHERE gm.user_id = $1 AND gm.removed_at IS NULL AND t.deleted_at IS NULL AND g.deleted_at IS NULL
 ORDER BY s.transaction_id, s.created_at desc
 LIMIT $2
 OFFSET $3
@@ -462,6 +481,79 @@ func (q *Queries) ListSplitsForTransaction(ctx context.Context, transactionID in
 	return items, nil
 }
 
+const mergeMemberSplits = `-- name: MergeMemberSplits :exec
+UPDATE splits s
+SET split_amount = s.split_amount + f.split_amount, split_percent = s.split_percent + f.split_percent
+FROM splits f
+WHERE f.transaction_id = s.transaction_id
+  AND s.split_user = $1::bigint
+  AND f.split_user = $2::bigint
+`
+
+type MergeMemberSplitsParams struct {
+	IntoMemberID int64 `json:"into_member_id"`
+	FromMemberID int64 `json:"from_member_id"`
+}
+
+// Adds a member's splits to another member's splits of the same transactions
+func (q *Queries) MergeMemberSplits(ctx context.Context, arg MergeMemberSplitsParams) error {
+	_, err := q.db.Exec(ctx, mergeMemberSplits, arg.IntoMemberID, arg.FromMemberID)
+	return err
+}
+
+const reassignMemberSplits = `-- name: ReassignMemberSplits :exec
+UPDATE splits
+SET split_user = $1::bigint
+WHERE split_user = $2::bigint
+`
+
+type ReassignMemberSplitsParams struct {
+	IntoMemberID int64 `json:"into_member_id"`
+	FromMemberID int64 `json:"from_member_id"`
+}
+
+// Moves every split of a group member to another member
+func (q *Queries) ReassignMemberSplits(ctx context.Context, arg ReassignMemberSplitsParams) error {
+	_, err := q.db.Exec(ctx, reassignMemberSplits, arg.IntoMemberID, arg.FromMemberID)
+	return err
+}
+
+const rescaleSplit = `-- name: RescaleSplit :one
+UPDATE "splits"
+SET tx_amount = $3, split_amount = $4
+WHERE id = $1 AND transaction_id = $2
+RETURNING id, transaction_id, tx_amount, split_percent, split_amount, split_user, created_at, modified_at
+`
+
+type RescaleSplitParams struct {
+	ID            int64           `json:"id"`
+	TransactionID int64           `json:"transaction_id"`
+	TxAmount      decimal.Decimal `json:"tx_amount"`
+	SplitAmount   decimal.Decimal `json:"split_amount"`
+}
+
+// Sets a split's amount for a new transaction amount, split_percent is kept
+func (q *Queries) RescaleSplit(ctx context.Context, arg RescaleSplitParams) (Split, error) {
+	row := q.db.QueryRow(ctx, rescaleSplit,
+		arg.ID,
+		arg.TransactionID,
+		arg.TxAmount,
+		arg.SplitAmount,
+	)
+	var i Split
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.TxAmount,
+		&i.SplitPercent,
+		&i.SplitAmount,
+		&i.SplitUser,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+	)
+	return i, err
+}
+
 const updateSplit = `-- name: UpdateSplit :one
 UPDATE "splits"
 SET split_percent = $2, split_amount = $3, split_user = $4
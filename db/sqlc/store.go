@@ -16,9 +16,26 @@ type Store interface {
 	CreateSplitsTx(ctx context.Context, arg CreateSplitsTxParams) (CreateSplitsTxResult, error)
 	UpdateTransactionSplitsTx(ctx context.Context, arg UpdateTransactionSplitsTxParams) (UpdateTransactionSplitsTxResult, error)
 	DeleteTransactionWithSplitsTx(ctx context.Context, transactionID int64) error
+	CreateTransactionWithPayersTx(ctx context.Context, arg CreateTransactionWithPayersTxParams) (CreateTransactionWithPayersTxResult, error)
+	UpdateTransactionWithPayersTx(ctx context.Context, arg UpdateTransactionWithPayersTxParams) (UpdateTransactionWithPayersTxResult, error)
+	RevertTransactionRevisionTx(ctx context.Context, arg RevertTransactionRevisionTxParams) (RevertTransactionRevisionTxResult, error)
+	ReplaceTransactionItemsTx(ctx context.Context, arg ReplaceTransactionItemsTxParams) (ReplaceTransactionItemsTxResult, error)
+	DeleteTransactionItemsTx(ctx context.Context, transactionID int64) (DeleteTransactionItemsTxResult, error)
 	CreateGroupMembersTx(ctx context.Context, arg CreateGroupMemberTxParams) (CreateGroupMemberTxResult, error)
 	UpdateGroupMembersTx(ctx context.Context, arg UpdateGroupMemberTxParams) (UpdateGroupMemberTxResult, error)
 	DeleteGroupMembersTx(ctx context.Context, groupID int64) error
+	TransferGroupOwnershipTx(ctx context.Context, arg TransferGroupOwnershipTxParams) (TransferGroupOwnershipTxResult, error)
+	MergeGroupMembersTx(ctx context.Context, arg MergeGroupMembersTxParams) (MergeGroupMembersTxResult, error)
+	AcceptGroupInvitationTx(ctx context.Context, arg AcceptGroupInvitationTxParams) (AcceptGroupInvitationTxResult, error)
+	CreateSettlementsTx(ctx context.Context, arg CreateSettlementsTxParams) (CreateSettlementsTxResult, error)
+	CreateCrossGroupSettlementsTx(ctx context.Context, arg CreateCrossGroupSettlementsTxParams) (CreateCrossGroupSettlementsTxResult, error)
+	UpdateSettlementStatusTx(ctx context.Context, arg UpdateSettlementStatusTxParams) (UpdateSettlementStatusTxResult, error)
+	LoadExchangeRatesTx(ctx context.Context, arg LoadExchangeRatesTxParams) (LoadExchangeRatesTxResult, error)
+	PurgeTrashTx(ctx context.Context, arg PurgeTrashTxParams) (PurgeTrashTxResult, error)
+	IssueOneTimeTokenTx(ctx context.Context, arg CreateOneTimeTokenParams) (OneTimeToken, error)
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	UseOneTimeTokenTx(ctx context.Context, arg UseOneTimeTokenTxParams) (OneTimeToken, error)
+	RotateRefreshTokenTx(ctx context.Context, arg RotateRefreshTokenTxParams) (RotateRefreshTokenTxResult, error)
 }
 
 // Implementation of the Store interface
@@ -45,7 +62,10 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	}
 
 	q := New(tx)
-	err = fn(q)
+	err = recordAuditActor(ctx, q)
+	if err == nil {
+		err = fn(q)
+	}
 	if err != nil {
 		if rbErr := tx.Rollback(ctx); rbErr != nil {
 			log.Printf("Transaction error: %v, rollback error: %v", err, rbErr)
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// auditActorKey is the context key for the user recorded on audit events
+type auditActorKey struct{}
+
+// WithAuditActor returns a context whose DB transactions record userID as the actor on audit events
+func WithAuditActor(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, userID)
+}
+
+// AuditActorFromContext returns the user recorded on audit events, if one is set
+func AuditActorFromContext(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(auditActorKey{}).(int64)
+	return userID, ok
+}
+
+// recordAuditActor records the context's actor for the rest of the DB transaction
+// Audit events are written by triggers, so the actor must be set before any change in the transaction
+func recordAuditActor(ctx context.Context, q *Queries) error {
+	userID, ok := AuditActorFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if err := q.SetAuditActor(ctx, userID); err != nil {
+		return fmt.Errorf("failed to set audit actor: %w", err)
+	}
+	return nil
+}
+
+// Single row changes to audited tables run in a DB transaction so their audit event records the actor
+
+func (store *SQLStore) CreateGroup(ctx context.Context, arg CreateGroupParams) (Group, error) {
+	var result Group
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateGroup(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) UpdateGroup(ctx context.Context, arg UpdateGroupParams) (Group, error) {
+	var result Group
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.UpdateGroup(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) DeleteGroup(ctx context.Context, id int64) (Group, error) {
+	var result Group
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.DeleteGroup(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) RestoreGroup(ctx context.Context, id int64) (Group, error) {
+	var result Group
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.RestoreGroup(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) CreateGroupMember(ctx context.Context, arg CreateGroupMemberParams) (GroupMember, error) {
+	var result GroupMember
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateGroupMember(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) UpdateGroupMember(ctx context.Context, arg UpdateGroupMemberParams) (GroupMember, error) {
+	var result GroupMember
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.UpdateGroupMember(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) UpdateGroupMemberRole(ctx context.Context, arg UpdateGroupMemberRoleParams) (GroupMember, error) {
+	var result GroupMember
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.UpdateGroupMemberRole(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) ClaimGroupMember(ctx context.Context, arg ClaimGroupMemberParams) (GroupMember, error) {
+	var result GroupMember
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.ClaimGroupMember(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) RemoveGroupMember(ctx context.Context, id int64) (GroupMember, error) {
+	var result GroupMember
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.RemoveGroupMember(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) DeleteGroupMember(ctx context.Context, id int64) (GroupMember, error) {
+	var result GroupMember
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.DeleteGroupMember(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
+	var result Transaction
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateTransaction(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) DeleteTransaction(ctx context.Context, id int64) (Transaction, error) {
+	var result Transaction
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.DeleteTransaction(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) RestoreTransaction(ctx context.Context, id int64) (Transaction, error) {
+	var result Transaction
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.RestoreTransaction(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) CreateSplit(ctx context.Context, arg CreateSplitParams) (Split, error) {
+	var result Split
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateSplit(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) UpdateSplit(ctx context.Context, arg UpdateSplitParams) (Split, error) {
+	var result Split
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.UpdateSplit(ctx, arg)
+		return err
+	})
+	return result, err
+}
+
+func (store *SQLStore) DeleteSplit(ctx context.Context, id int64) (Split, error) {
+	var result Split
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.DeleteSplit(ctx, id)
+		return err
+	})
+	return result, err
+}
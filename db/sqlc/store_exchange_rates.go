@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// LoadExchangeRatesTxParams contains the exchange rates to load, usually read from a rates file
+type LoadExchangeRatesTxParams struct {
+	Rates []UpsertExchangeRateParams
+}
+
+// LoadExchangeRatesTxResult is the result of the LoadExchangeRatesTx operation
+type LoadExchangeRatesTxResult struct {
+	Rates []ExchangeRate
+}
+
+// LoadExchangeRatesTx inserts or replaces exchange rates atomically
+// Either the whole file is loaded or nothing is changed
+func (store *SQLStore) LoadExchangeRatesTx(ctx context.Context, arg LoadExchangeRatesTxParams) (LoadExchangeRatesTxResult, error) {
+	var result LoadExchangeRatesTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Upsert each rate, a rate for the same currencies & date replaces the existing one
+		result.Rates = make([]ExchangeRate, 0, len(arg.Rates))
+		for _, rateParam := range arg.Rates {
+			rate, err := q.UpsertExchangeRate(ctx, rateParam)
+			if err != nil {
+				return fmt.Errorf("failed to load exchange rate %s/%s on %s: %w", rateParam.FromCurrency, rateParam.ToCurrency, rateParam.RateDate.Format("2006-01-02"), err)
+			}
+			result.Rates = append(result.Rates, rate)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AcceptGroupInvitationTxParams contains the invite token hash and the user accepting the invitation
+type AcceptGroupInvitationTxParams struct {
+	TokenHash string
+	UserID    int64
+	UserEmail string // Must match the invitation's email when it has one
+}
+
+// AcceptGroupInvitationTxResult is the result of the AcceptGroupInvitationTx operation
+type AcceptGroupInvitationTxResult struct {
+	Invitation  GroupInvitation
+	GroupMember GroupMember
+}
+
+// AcceptGroupInvitationTx adds a user to a group with the role from an invitation and counts the use
+// Invitations for a placeholder member link the user to that member instead of adding a new one, so its history carries over
+// The invitation row is locked so a single use invitation can't be accepted twice at once
+func (store *SQLStore) AcceptGroupInvitationTx(ctx context.Context, arg AcceptGroupInvitationTxParams) (AcceptGroupInvitationTxResult, error) {
+	var result AcceptGroupInvitationTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Lock the invitation row to prevent concurrent use
+		invitation, err := q.GetGroupInvitationByTokenHashForUpdate(ctx, arg.TokenHash)
+		if err != nil {
+			return fmt.Errorf("failed to get invitation: %w", err)
+		}
+
+		// 2. Check the invitation can still be used by this user
+		if invitation.RevokedAt.Valid {
+			return fmt.Errorf("invitation has been revoked")
+		}
+		if invitation.ExpiresAt.Valid && time.Now().After(invitation.ExpiresAt.Time) {
+			return fmt.Errorf("invitation has expired")
+		}
+		if invitation.SingleUse && invitation.UseCount > 0 {
+			return fmt.Errorf("invitation has already been used")
+		}
+		if invitation.Email != nil && !strings.EqualFold(*invitation.Email, arg.UserEmail) {
+			return fmt.Errorf("invitation was sent to a different email")
+		}
+
+		// 3. Add the user to the group, or link them to the placeholder they were invited to claim
+		if invitation.GroupMemberID != nil {
+			result.GroupMember, err = q.ClaimGroupMember(ctx, ClaimGroupMemberParams{
+				ID:     *invitation.GroupMemberID,
+				UserID: &arg.UserID,
+				Role:   invitation.Role,
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("member has already been claimed")
+			}
+			if err != nil {
+				return fmt.Errorf("failed to claim group member: %w", err)
+			}
+		} else {
+			result.GroupMember, err = q.CreateGroupMember(ctx, CreateGroupMemberParams{
+				GroupID: invitation.GroupID,
+				UserID:  &arg.UserID,
+				Role:    invitation.Role,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create group member: %w", err)
+			}
+		}
+
+		// 4. Count the use
+		result.Invitation, err = q.UseGroupInvitation(ctx, invitation.ID)
+		if err != nil {
+			return fmt.Errorf("failed to use invitation: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
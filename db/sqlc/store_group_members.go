@@ -2,7 +2,10 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // CreateGroupMemberTxParams contains parameters for creating group members
@@ -47,17 +50,19 @@ func (store *SQLStore) CreateGroupMembersTx(ctx context.Context, arg CreateGroup
 // UpdateGroupMemberTxParams contains parameters for updating all group members for a group
 type UpdateGroupMemberTxParams struct {
 	GroupID      int64
-	GroupMembers []CreateGroupMemberParams // New group members to replace existing ones
+	GroupMembers []CreateGroupMemberParams // Members the group has after the update
 }
 
 // UpdateGroupMemberTxResult is the result of the update operation
 type UpdateGroupMemberTxResult struct {
 	Group          Group
-	DeletedMembers []GroupMember
+	KeptMembers    []GroupMember // Existing members that are in the update, their dates and role are updated
+	DeletedMembers []GroupMember // Existing members that aren't in the update, removed but kept with their transactions
 	NewMembers     []GroupMember
 }
 
-// UpdateGroupMembersTx atomically replaces all group members for a group
+// UpdateGroupMembersTx atomically replaces the members of a group with the given set
+// Existing members that are in the set keep their row, members that aren't are marked as removed rather than deleted so their transactions stay
 // This ensures the group is never left in an invalid state
 func (store *SQLStore) UpdateGroupMembersTx(ctx context.Context, arg UpdateGroupMemberTxParams) (UpdateGroupMemberTxResult, error) {
 	var result UpdateGroupMemberTxResult
@@ -71,20 +76,68 @@ func (store *SQLStore) UpdateGroupMembersTx(ctx context.Context, arg UpdateGroup
 			return fmt.Errorf("failed to get group: %w", err)
 		}
 
-		// 2. Delete existing group members
-		result.DeletedMembers, err = q.DeleteGroupMembersByGroupID(ctx, arg.GroupID)
+		existing, err := q.ListGroupMembersByGroupID(ctx, ListGroupMembersByGroupIDParams{GroupID: arg.GroupID, Limit: 1000, Offset: 0})
 		if err != nil {
-			return fmt.Errorf("failed to delete existing group members: %w", err)
+			return fmt.Errorf("failed to list existing group members: %w", err)
 		}
 
-		// 3. Create new group members
-		result.NewMembers = make([]GroupMember, 0, len(arg.GroupMembers))
-		for _, groupParam := range arg.GroupMembers {
-			groupMember, err := q.CreateGroupMember(ctx, groupParam)
+		// 2. Match the members to existing ones, each existing member is matched once
+		kept := make(map[int64]bool, len(existing))
+		matches := make([]*ListGroupMembersByGroupIDRow, len(arg.GroupMembers))
+		for i, groupParam := range arg.GroupMembers {
+			for j := range existing {
+				if !kept[existing[j].ID] && IsSameGroupMember(existing[j].UserID, existing[j].MemberName, groupParam) {
+					kept[existing[j].ID] = true
+					matches[i] = &existing[j]
+					break
+				}
+			}
+		}
+
+		// 3. Remove existing members that aren't in the update, before new members take their users
+		result.DeletedMembers = make([]GroupMember, 0, len(existing)-len(kept))
+		for _, member := range existing {
+			if kept[member.ID] {
+				continue
+			}
+			removed, err := q.RemoveGroupMember(ctx, member.ID)
 			if err != nil {
-				return fmt.Errorf("failed to create group member: %w", err)
+				return fmt.Errorf("failed to remove group member %d: %w", member.ID, err)
 			}
-			result.NewMembers = append(result.NewMembers, groupMember)
+			result.DeletedMembers = append(result.DeletedMembers, removed)
+		}
+
+		// 4. Update kept members and create new ones
+		result.KeptMembers = make([]GroupMember, 0, len(kept))
+		result.NewMembers = make([]GroupMember, 0, len(arg.GroupMembers)-len(kept))
+		for i, groupParam := range arg.GroupMembers {
+			match := matches[i]
+			if match == nil {
+				groupMember, err := q.CreateGroupMember(ctx, groupParam)
+				if err != nil {
+					return fmt.Errorf("failed to create group member: %w", err)
+				}
+				result.NewMembers = append(result.NewMembers, groupMember)
+				continue
+			}
+
+			groupMember, err := q.UpdateGroupMember(ctx, UpdateGroupMemberParams{
+				ID:       match.ID,
+				GroupID:  match.GroupID,
+				UserID:   match.UserID,
+				JoinedOn: groupParam.JoinedOn,
+				LeftOn:   groupParam.LeftOn,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update group member %d: %w", match.ID, err)
+			}
+			if groupMember.Role != groupParam.Role {
+				groupMember, err = q.UpdateGroupMemberRole(ctx, UpdateGroupMemberRoleParams{ID: match.ID, Role: groupParam.Role})
+				if err != nil {
+					return fmt.Errorf("failed to update role of group member %d: %w", match.ID, err)
+				}
+			}
+			result.KeptMembers = append(result.KeptMembers, groupMember)
 		}
 
 		return nil
@@ -93,7 +146,16 @@ func (store *SQLStore) UpdateGroupMembersTx(ctx context.Context, arg UpdateGroup
 	return result, err
 }
 
-// DeleteGroupMembersTx deletes all group members for a group atomically
+// IsSameGroupMember reports whether an existing member with the given user and name is the member described by arg
+// Members linked to a user are matched by user, placeholder members by name
+func IsSameGroupMember(userID *int64, memberName *string, arg CreateGroupMemberParams) bool {
+	if arg.UserID != nil || userID != nil {
+		return arg.UserID != nil && userID != nil && *arg.UserID == *userID
+	}
+	return arg.MemberName != nil && memberName != nil && *arg.MemberName == *memberName
+}
+
+// DeleteGroupMembersTx removes all group members except the owner for a group atomically, their transactions are kept
 func (store *SQLStore) DeleteGroupMembersTx(ctx context.Context, groupID int64) error {
 	return store.execTx(ctx, func(q *Queries) error {
 		// Lock the group
@@ -102,12 +164,258 @@ func (store *SQLStore) DeleteGroupMembersTx(ctx context.Context, groupID int64)
 			return fmt.Errorf("failed to get group: %w", err)
 		}
 
-		// Delete all group members
-		_, err = q.DeleteGroupMembersByGroupID(ctx, groupID)
+		// Remove all group members, the owner is kept
+		_, err = q.RemoveGroupMembersByGroupID(ctx, groupID)
 		if err != nil {
-			return fmt.Errorf("failed to delete group members: %w", err)
+			return fmt.Errorf("failed to remove group members: %w", err)
 		}
 
 		return nil
 	})
 }
+
+// TransferGroupOwnershipTxParams contains parameters for transferring ownership of a group to another member
+type TransferGroupOwnershipTxParams struct {
+	GroupID      int64
+	FromMemberID int64 // Current owner, becomes an admin
+	ToMemberID   int64 // New owner, must be linked to a user
+}
+
+// TransferGroupOwnershipTxResult is the result of the TransferGroupOwnershipTx operation
+type TransferGroupOwnershipTxResult struct {
+	PreviousOwner GroupMember
+	NewOwner      GroupMember
+}
+
+// TransferGroupOwnershipTx makes another member the owner of a group and the current owner an admin
+// The group row is locked so concurrent transfers can't leave a group with two owners or none
+func (store *SQLStore) TransferGroupOwnershipTx(ctx context.Context, arg TransferGroupOwnershipTxParams) (TransferGroupOwnershipTxResult, error) {
+	var result TransferGroupOwnershipTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Lock the group row to prevent concurrent transfers
+		_, err := q.GetGroupByIDForUpdate(ctx, arg.GroupID)
+		if err != nil {
+			return fmt.Errorf("failed to get group: %w", err)
+		}
+
+		// 2. Check the current owner still owns the group
+		from, err := q.GetGroupMemberByID(ctx, arg.FromMemberID)
+		if err != nil {
+			return fmt.Errorf("failed to get current owner: %w", err)
+		}
+		if from.GroupID != arg.GroupID || from.Role != "owner" {
+			return fmt.Errorf("member %d is not the owner of group %d", arg.FromMemberID, arg.GroupID)
+		}
+
+		// 3. Check the new owner is a member of the group with an account
+		to, err := q.GetGroupMemberByID(ctx, arg.ToMemberID)
+		if err != nil {
+			return fmt.Errorf("failed to get new owner: %w", err)
+		}
+		if to.GroupID != arg.GroupID {
+			return fmt.Errorf("member %d is not in group %d", arg.ToMemberID, arg.GroupID)
+		}
+		if to.RemovedAt.Valid {
+			return fmt.Errorf("member %d has been removed from group %d", arg.ToMemberID, arg.GroupID)
+		}
+		if to.UserID == nil {
+			return fmt.Errorf("member %d must be linked to a user to own the group", arg.ToMemberID)
+		}
+
+		// 4. Demote the current owner first, a group can only have one owner
+		result.PreviousOwner, err = q.UpdateGroupMemberRole(ctx, UpdateGroupMemberRoleParams{ID: arg.FromMemberID, Role: "admin"})
+		if err != nil {
+			return fmt.Errorf("failed to demote current owner: %w", err)
+		}
+
+		// 5. Promote the new owner
+		result.NewOwner, err = q.UpdateGroupMemberRole(ctx, UpdateGroupMemberRoleParams{ID: arg.ToMemberID, Role: "owner"})
+		if err != nil {
+			return fmt.Errorf("failed to promote new owner: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Errors returned when two group members can't be merged
+var (
+	ErrMergeSameMember        = errors.New("a member can't be merged into itself")
+	ErrMergeMemberNotInGroup  = errors.New("members are not both in the group")
+	ErrMergeOwner             = errors.New("the group owner can't be merged into another member")
+	ErrMergeIntoRemovedMember = errors.New("members can't be merged into a removed member")
+	ErrMergeDifferentUsers    = errors.New("members linked to different users can't be merged")
+)
+
+// MergeGroupMembersTxParams contains parameters for merging a duplicate group member into another member
+type MergeGroupMembersTxParams struct {
+	GroupID      int64
+	FromMemberID int64 // Duplicate member, removed once its history is moved
+	IntoMemberID int64 // Member that is kept
+}
+
+// MergeGroupMembersTxResult is the result of the MergeGroupMembersTx operation
+type MergeGroupMembersTxResult struct {
+	Member        GroupMember // Kept member, linked to the duplicate's user if it had none
+	RemovedMember GroupMember
+}
+
+// MergeGroupMembersTx moves every transaction, split, payment, line item share and settlement of a duplicate member to
+// another member of the same group and removes the duplicate, the row is kept so revisions referencing it can still be read. Splits and payments both members have in one transaction are added together
+// and settlements between the two members are deleted, they would be payments to themselves and don't change balances.
+// The kept member takes the duplicate's user and role if it has no user and its dates are widened to cover both members.
+func (store *SQLStore) MergeGroupMembersTx(ctx context.Context, arg MergeGroupMembersTxParams) (MergeGroupMembersTxResult, error) {
+	var result MergeGroupMembersTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Lock the group row to prevent concurrent changes to its members
+		_, err := q.GetGroupByIDForUpdate(ctx, arg.GroupID)
+		if err != nil {
+			return fmt.Errorf("failed to get group: %w", err)
+		}
+
+		// 2. Check both members are in the group and can be merged
+		if arg.FromMemberID == arg.IntoMemberID {
+			return ErrMergeSameMember
+		}
+		from, err := q.GetGroupMemberByID(ctx, arg.FromMemberID)
+		if err != nil {
+			return fmt.Errorf("failed to get member %d: %w", arg.FromMemberID, err)
+		}
+		into, err := q.GetGroupMemberByID(ctx, arg.IntoMemberID)
+		if err != nil {
+			return fmt.Errorf("failed to get member %d: %w", arg.IntoMemberID, err)
+		}
+		if from.GroupID != arg.GroupID || into.GroupID != arg.GroupID {
+			return ErrMergeMemberNotInGroup
+		}
+		if from.Role == "owner" {
+			return ErrMergeOwner
+		}
+		if into.RemovedAt.Valid {
+			return ErrMergeIntoRemovedMember
+		}
+		if from.UserID != nil && into.UserID != nil && *from.UserID != *into.UserID {
+			return ErrMergeDifferentUsers
+		}
+
+		// 3. Move transactions paid by the duplicate
+		err = q.ReassignTransactionsByUser(ctx, ReassignTransactionsByUserParams{IntoMemberID: arg.IntoMemberID, FromMemberID: arg.FromMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to move transactions: %w", err)
+		}
+
+		// 4. Move splits, adding them together where both members are split into a transaction
+		err = q.MergeMemberSplits(ctx, MergeMemberSplitsParams{IntoMemberID: arg.IntoMemberID, FromMemberID: arg.FromMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to merge splits: %w", err)
+		}
+		err = q.DeleteMergedMemberSplits(ctx, DeleteMergedMemberSplitsParams{FromMemberID: arg.FromMemberID, IntoMemberID: arg.IntoMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to delete merged splits: %w", err)
+		}
+		err = q.ReassignMemberSplits(ctx, ReassignMemberSplitsParams{IntoMemberID: arg.IntoMemberID, FromMemberID: arg.FromMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to move splits: %w", err)
+		}
+
+		// 5. Move payments, adding them together where both members paid a transaction
+		err = q.MergeMemberTransactionPayers(ctx, MergeMemberTransactionPayersParams{IntoMemberID: arg.IntoMemberID, FromMemberID: arg.FromMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to merge transaction payers: %w", err)
+		}
+		err = q.DeleteMergedMemberTransactionPayers(ctx, DeleteMergedMemberTransactionPayersParams{FromMemberID: arg.FromMemberID, IntoMemberID: arg.IntoMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to delete merged transaction payers: %w", err)
+		}
+		err = q.ReassignMemberTransactionPayers(ctx, ReassignMemberTransactionPayersParams{IntoMemberID: arg.IntoMemberID, FromMemberID: arg.FromMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to move transaction payers: %w", err)
+		}
+
+		// 6. Move line item shares, items both members share are kept once
+		err = q.DeleteMergedMemberTransactionItemMembers(ctx, DeleteMergedMemberTransactionItemMembersParams{FromMemberID: arg.FromMemberID, IntoMemberID: arg.IntoMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to delete merged transaction item members: %w", err)
+		}
+		err = q.ReassignMemberTransactionItemMembers(ctx, ReassignMemberTransactionItemMembersParams{IntoMemberID: arg.IntoMemberID, FromMemberID: arg.FromMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to move transaction item members: %w", err)
+		}
+
+		// 7. Move settlements, settlements between the two members are deleted
+		err = q.DeleteSettlementsBetweenMembers(ctx, DeleteSettlementsBetweenMembersParams{MemberID: arg.FromMemberID, OtherMemberID: arg.IntoMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to delete settlements between members: %w", err)
+		}
+		err = q.ReassignMemberSettlements(ctx, ReassignMemberSettlementsParams{FromMemberID: arg.FromMemberID, IntoMemberID: arg.IntoMemberID})
+		if err != nil {
+			return fmt.Errorf("failed to move settlements: %w", err)
+		}
+
+		// 8. Remove the duplicate before its user is linked to the kept member, a user can only be in a group once
+		if from.RemovedAt.Valid {
+			result.RemovedMember = GroupMember{
+				ID:         from.ID,
+				GroupID:    from.GroupID,
+				MemberName: from.MemberName,
+				UserID:     from.UserID,
+				CreatedAt:  from.CreatedAt,
+				JoinedOn:   from.JoinedOn,
+				LeftOn:     from.LeftOn,
+				Role:       from.Role,
+				RemovedAt:  from.RemovedAt,
+			}
+		} else {
+			result.RemovedMember, err = q.RemoveGroupMember(ctx, arg.FromMemberID)
+			if err != nil {
+				return fmt.Errorf("failed to remove member %d: %w", arg.FromMemberID, err)
+			}
+		}
+
+		// 9. Update the kept member's user and dates
+		userID := into.UserID
+		if userID == nil {
+			userID = from.UserID
+		}
+		joinedOn, leftOn := mergedMemberDates(from.JoinedOn, from.LeftOn, into.JoinedOn, into.LeftOn)
+		result.Member, err = q.UpdateGroupMember(ctx, UpdateGroupMemberParams{
+			ID:       arg.IntoMemberID,
+			GroupID:  into.GroupID,
+			UserID:   userID,
+			JoinedOn: joinedOn,
+			LeftOn:   leftOn,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update member %d: %w", arg.IntoMemberID, err)
+		}
+
+		// 10. The duplicate's user keeps their role
+		if into.UserID == nil && from.UserID != nil && into.Role != from.Role {
+			result.Member, err = q.UpdateGroupMemberRole(ctx, UpdateGroupMemberRoleParams{ID: arg.IntoMemberID, Role: from.Role})
+			if err != nil {
+				return fmt.Errorf("failed to update role of member %d: %w", arg.IntoMemberID, err)
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// mergedMemberDates returns the dates covering both members, a null date means no limit
+func mergedMemberDates(joinedOnA, leftOnA, joinedOnB, leftOnB pgtype.Date) (pgtype.Date, pgtype.Date) {
+	joinedOn := joinedOnA
+	if !joinedOnB.Valid || (joinedOn.Valid && joinedOnB.Time.Before(joinedOn.Time)) {
+		joinedOn = joinedOnB
+	}
+	leftOn := leftOnA
+	if !leftOnB.Valid || (leftOn.Valid && leftOnB.Time.After(leftOn.Time)) {
+		leftOn = leftOnB
+	}
+	return joinedOn, leftOn
+}
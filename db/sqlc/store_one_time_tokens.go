@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Purposes of one time tokens
+const (
+	TokenPurposePasswordReset = "password_reset" // Reset a forgotten password
+	TokenPurposeMagicLink     = "magic_link"     // Log in without a password
+)
+
+// ErrOneTimeTokenInvalid is returned when a one time token doesn't exist, has expired or has already been used
+var ErrOneTimeTokenInvalid = errors.New("token is invalid, expired or has already been used")
+
+// IssueOneTimeTokenTx stores a new one time token for a user and uses up their older tokens with the same purpose
+// Only the newest token sent to a user works, so an old email can't be used after requesting another
+func (store *SQLStore) IssueOneTimeTokenTx(ctx context.Context, arg CreateOneTimeTokenParams) (OneTimeToken, error) {
+	var result OneTimeToken
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// 1. Use up older tokens
+		err = q.InvalidateUserOneTimeTokens(ctx, InvalidateUserOneTimeTokensParams{UserID: arg.UserID, Purpose: arg.Purpose})
+		if err != nil {
+			return fmt.Errorf("failed to invalidate tokens: %w", err)
+		}
+
+		// 2. Store the new token
+		result, err = q.CreateOneTimeToken(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("failed to create token: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// UseOneTimeTokenTxParams contains the hash and purpose of a one time token
+type UseOneTimeTokenTxParams struct {
+	TokenHash string
+	Purpose   string
+}
+
+// UseOneTimeTokenTx marks a one time token used and returns it, so it can't be used again
+// Returns ErrOneTimeTokenInvalid when the token doesn't exist for the purpose, has expired or has already been used
+func (store *SQLStore) UseOneTimeTokenTx(ctx context.Context, arg UseOneTimeTokenTxParams) (OneTimeToken, error) {
+	var result OneTimeToken
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = consumeOneTimeToken(ctx, q, arg.TokenHash, arg.Purpose)
+		return err
+	})
+
+	return result, err
+}
+
+// ResetPasswordTxParams contains the password reset token hash and the new password hash
+type ResetPasswordTxParams struct {
+	TokenHash    string
+	PasswordHash string
+}
+
+// ResetPasswordTxResult is the result of the ResetPasswordTx operation
+type ResetPasswordTxResult struct {
+	User User
+}
+
+// ResetPasswordTx sets a user's password with a password reset token and uses the token
+// Returns ErrOneTimeTokenInvalid when the token can't be used, the caller is responsible for ending the user's sessions
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Use the token
+		token, err := consumeOneTimeToken(ctx, q, arg.TokenHash, TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		// 2. Set the new password
+		result.User, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{ID: token.UserID, PasswordHash: arg.PasswordHash})
+		if err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// consumeOneTimeToken locks a token and marks it used, returning ErrOneTimeTokenInvalid if it can't be used
+// The row lock stops the same token being used twice at once
+func consumeOneTimeToken(ctx context.Context, q *Queries, tokenHash, purpose string) (OneTimeToken, error) {
+	token, err := q.GetOneTimeTokenByHashForUpdate(ctx, GetOneTimeTokenByHashForUpdateParams{TokenHash: tokenHash, Purpose: purpose})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OneTimeToken{}, ErrOneTimeTokenInvalid
+	}
+	if err != nil {
+		return OneTimeToken{}, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	if token.UsedAt.Valid || time.Now().After(token.ExpiresAt) {
+		return OneTimeToken{}, ErrOneTimeTokenInvalid
+	}
+
+	token, err = q.UseOneTimeToken(ctx, token.ID)
+	if err != nil {
+		return OneTimeToken{}, fmt.Errorf("failed to use token: %w", err)
+	}
+
+	return token, nil
+}
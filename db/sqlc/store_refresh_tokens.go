@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Errors returned when a refresh token can't be rotated
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenExpired  = errors.New("refresh token expired")
+)
+
+// RotateRefreshTokenTxParams contains the hash of the presented refresh token and the new token replacing it
+type RotateRefreshTokenTxParams struct {
+	TokenHash    string
+	NewTokenHash string
+	ExpiresAt    time.Time
+	DeviceInfo   *string
+	IpAddress    *string
+}
+
+// RotateRefreshTokenTxResult is the result of the RotateRefreshTokenTx operation
+type RotateRefreshTokenTxResult struct {
+	OldToken      RefreshToken
+	NewToken      RefreshToken // Empty when Reused is true
+	Reused        bool         // The presented token was already revoked, so its family was revoked
+	RevokedFamily int64        // Number of tokens still active in the family when it was revoked
+}
+
+// RotateRefreshTokenTx revokes a refresh token and stores the token replacing it in the same family
+// A token that was already revoked is being reused, which means it was leaked, so every active token in its family is revoked
+// and Reused is set without an error so the family revocation is committed
+// Returns ErrRefreshTokenNotFound or ErrRefreshTokenExpired when the token can't be rotated
+func (store *SQLStore) RotateRefreshTokenTx(ctx context.Context, arg RotateRefreshTokenTxParams) (RotateRefreshTokenTxResult, error) {
+	var result RotateRefreshTokenTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// 1. Lock the presented token so concurrent refreshes can't both rotate it
+		result.OldToken, err = q.GetRefreshTokenByHashForUpdate(ctx, arg.TokenHash)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRefreshTokenNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get refresh token: %w", err)
+		}
+
+		// 2. Revoke the whole family if the token was already revoked
+		if result.OldToken.RevokedAt.Valid {
+			result.Reused = true
+			result.RevokedFamily, err = q.RevokeRefreshTokenFamily(ctx, result.OldToken.FamilyID)
+			if err != nil {
+				return fmt.Errorf("failed to revoke refresh token family: %w", err)
+			}
+			return nil
+		}
+
+		if time.Now().After(result.OldToken.ExpiresAt) {
+			return ErrRefreshTokenExpired
+		}
+
+		// 3. Revoke the presented token
+		err = q.RevokeRefreshToken(ctx, arg.TokenHash)
+		if err != nil {
+			return fmt.Errorf("failed to revoke refresh token: %w", err)
+		}
+
+		// 4. Store the new token in the same family
+		result.NewToken, err = q.CreateRotatedRefreshToken(ctx, CreateRotatedRefreshTokenParams{
+			TokenHash:  arg.NewTokenHash,
+			UserID:     result.OldToken.UserID,
+			ExpiresAt:  arg.ExpiresAt,
+			DeviceInfo: arg.DeviceInfo,
+			IpAddress:  arg.IpAddress,
+			FamilyID:   result.OldToken.FamilyID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create refresh token: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// CreateSettlementsTxParams contains the settle up payments to record for a group
+type CreateSettlementsTxParams struct {
+	GroupID     int64
+	Settlements []CreateSettlementParams
+}
+
+// CreateSettlementsTxResult is the result of the CreateSettlementsTx operation
+type CreateSettlementsTxResult struct {
+	Settlements []Settlement
+}
+
+// CreateSettlementsTx records several settlements for a group atomically, used to settle up a whole group at once
+// Either every settlement is recorded or none are
+func (store *SQLStore) CreateSettlementsTx(ctx context.Context, arg CreateSettlementsTxParams) (CreateSettlementsTxResult, error) {
+	var result CreateSettlementsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// 1. Lock the group row so concurrent settle ups are recorded one after the other
+		_, err = q.GetGroupByIDForUpdate(ctx, arg.GroupID)
+		if err != nil {
+			return fmt.Errorf("failed to get group: %w", err)
+		}
+
+		// 2. Create settlements, each must belong to the locked group
+		result.Settlements = make([]Settlement, 0, len(arg.Settlements))
+		for _, settlementParam := range arg.Settlements {
+			if settlementParam.GroupID != arg.GroupID {
+				return fmt.Errorf("settlement group %d does not match group %d", settlementParam.GroupID, arg.GroupID)
+			}
+
+			settlement, err := q.CreateSettlement(ctx, settlementParam)
+			if err != nil {
+				return fmt.Errorf("failed to create settlement: %w", err)
+			}
+			result.Settlements = append(result.Settlements, settlement)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// CreateCrossGroupSettlementsTxParams contains the per-group settlements a payment between two users was split into
+type CreateCrossGroupSettlementsTxParams struct {
+	Settlements []CreateSettlementParams
+}
+
+// CreateCrossGroupSettlementsTxResult is the result of the CreateCrossGroupSettlementsTx operation
+type CreateCrossGroupSettlementsTxResult struct {
+	Settlements []Settlement
+}
+
+// CreateCrossGroupSettlementsTx records settlements across several groups atomically, used when one payment settles balances in more than one group
+// Either every settlement is recorded or none are
+func (store *SQLStore) CreateCrossGroupSettlementsTx(ctx context.Context, arg CreateCrossGroupSettlementsTxParams) (CreateCrossGroupSettlementsTxResult, error) {
+	var result CreateCrossGroupSettlementsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Lock every group row, in ID order so concurrent cross-group payments can't deadlock
+		groupIDs := make([]int64, 0, len(arg.Settlements))
+		for _, settlementParam := range arg.Settlements {
+			groupIDs = append(groupIDs, settlementParam.GroupID)
+		}
+		slices.Sort(groupIDs)
+		for _, groupID := range slices.Compact(groupIDs) {
+			if _, err := q.GetGroupByIDForUpdate(ctx, groupID); err != nil {
+				return fmt.Errorf("failed to get group %d: %w", groupID, err)
+			}
+		}
+
+		// 2. Create settlements
+		result.Settlements = make([]Settlement, 0, len(arg.Settlements))
+		for _, settlementParam := range arg.Settlements {
+			settlement, err := q.CreateSettlement(ctx, settlementParam)
+			if err != nil {
+				return fmt.Errorf("failed to create settlement: %w", err)
+			}
+			result.Settlements = append(result.Settlements, settlement)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// UpdateSettlementStatusTxParams contains the new status for a settlement and the statuses it can move from
+type UpdateSettlementStatusTxParams struct {
+	SettlementID int64
+	Status       string
+	FromStatuses []string // Statuses the settlement must currently be in
+}
+
+// UpdateSettlementStatusTxResult is the result of the UpdateSettlementStatusTx operation
+type UpdateSettlementStatusTxResult struct {
+	PreviousStatus string
+	Settlement     Settlement
+}
+
+// UpdateSettlementStatusTx moves a settlement to a new status, e.g. pending to confirmed
+// The settlement row is locked so two members acting at once can't both change it
+func (store *SQLStore) UpdateSettlementStatusTx(ctx context.Context, arg UpdateSettlementStatusTxParams) (UpdateSettlementStatusTxResult, error) {
+	var result UpdateSettlementStatusTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Lock the settlement row to prevent concurrent status changes
+		settlement, err := q.GetSettlementByIDForUpdate(ctx, arg.SettlementID)
+		if err != nil {
+			return fmt.Errorf("failed to get settlement: %w", err)
+		}
+		result.PreviousStatus = settlement.Status
+
+		// 2. Validate the settlement can move to the new status
+		if !slices.Contains(arg.FromStatuses, settlement.Status) {
+			return fmt.Errorf("settlement is %s and can't be changed to %s", settlement.Status, arg.Status)
+		}
+
+		// 3. Update status
+		result.Settlement, err = q.UpdateSettlementStatus(ctx, UpdateSettlementStatusParams{
+			ID:     arg.SettlementID,
+			Status: arg.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update settlement status: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransactionItemTxParams contains a line item and the group members sharing it
+type TransactionItemTxParams struct {
+	Name      string
+	Amount    decimal.Decimal
+	MemberIDs []int64
+}
+
+// TransactionAdjustmentTxParams contains a transaction level adjustment (tax, tip, discount) and its resolved amount
+type TransactionAdjustmentTxParams struct {
+	Name           string
+	AdjustmentType string
+	Value          decimal.Decimal
+	Amount         decimal.Decimal
+}
+
+// TransactionItemWithMembers is a created line item and the group members sharing it
+type TransactionItemWithMembers struct {
+	Item      TransactionItem
+	MemberIDs []int64
+}
+
+// ReplaceTransactionItemsTxParams contains parameters for replacing all items for a transaction
+type ReplaceTransactionItemsTxParams struct {
+	TransactionID int64
+	Items         []TransactionItemTxParams
+	Adjustments   []TransactionAdjustmentTxParams
+	Splits        []CreateSplitParams // Splits derived from items & adjustments, replace existing ones
+}
+
+// ReplaceTransactionItemsTxResult is the result of the ReplaceTransactionItemsTx operation
+type ReplaceTransactionItemsTxResult struct {
+	Transaction   Transaction
+	Items         []TransactionItemWithMembers
+	Adjustments   []TransactionAdjustment
+	DeletedSplits []Split
+	NewSplits     []Split
+}
+
+// ReplaceTransactionItemsTx atomically replaces all line items and adjustments for a transaction along with the splits derived from them
+// It validates that items plus adjustments add up to the transaction amount and splits add up to exactly 100% of the transaction amount
+// The derived splits are recorded as a new revision
+func (store *SQLStore) ReplaceTransactionItemsTx(ctx context.Context, arg ReplaceTransactionItemsTxParams) (ReplaceTransactionItemsTxResult, error) {
+	var result ReplaceTransactionItemsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// 1. Lock the transaction row to prevent concurrent modifications
+		result.Transaction, err = q.GetTransactionByIDForUpdate(ctx, arg.TransactionID)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+
+		// 2. Validate items, adjustments and derived splits add up to the transaction amount
+		totalItems := decimal.NewFromInt(0)
+		for _, item := range arg.Items {
+			totalItems = totalItems.Add(item.Amount)
+		}
+		for _, adjustment := range arg.Adjustments {
+			totalItems = totalItems.Add(adjustment.Amount)
+		}
+		if !totalItems.Equal(result.Transaction.Amount) {
+			return fmt.Errorf("item and adjustment amounts must add up to transaction amount %s, got %s",
+				result.Transaction.Amount.String(), totalItems.String())
+		}
+
+		totalPercent := decimal.NewFromInt(0)
+		totalAmount := decimal.NewFromInt(0)
+		for _, split := range arg.Splits {
+			totalPercent = totalPercent.Add(split.SplitPercent)
+			totalAmount = totalAmount.Add(split.SplitAmount)
+		}
+		if !totalPercent.Equal(decimal.NewFromInt(1)) {
+			return fmt.Errorf("split percentages must add up to 100%%, got %s", totalPercent.String())
+		}
+		if !totalAmount.Equal(result.Transaction.Amount) {
+			return fmt.Errorf("split amounts must add up to transaction amount %s, got %s",
+				result.Transaction.Amount.String(), totalAmount.String())
+		}
+
+		// 3. Record the state before the first edit
+		if err := recordBaselineRevision(ctx, q, arg.TransactionID); err != nil {
+			return err
+		}
+
+		// 4. Delete existing items & adjustments, item members are removed by cascade
+		_, err = q.DeleteTransactionItems(ctx, arg.TransactionID)
+		if err != nil {
+			return fmt.Errorf("failed to delete existing items: %w", err)
+		}
+
+		_, err = q.DeleteTransactionAdjustments(ctx, arg.TransactionID)
+		if err != nil {
+			return fmt.Errorf("failed to delete existing adjustments: %w", err)
+		}
+
+		// 5. Create new items and their members
+		result.Items = make([]TransactionItemWithMembers, 0, len(arg.Items))
+		for _, itemParam := range arg.Items {
+			item, err := q.CreateTransactionItem(ctx, CreateTransactionItemParams{
+				TransactionID: arg.TransactionID,
+				Name:          itemParam.Name,
+				Amount:        itemParam.Amount,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create item: %w", err)
+			}
+
+			memberIDs := make([]int64, 0, len(itemParam.MemberIDs))
+			for _, memberID := range itemParam.MemberIDs {
+				itemMember, err := q.CreateTransactionItemMember(ctx, CreateTransactionItemMemberParams{
+					ItemID:   item.ID,
+					MemberID: memberID,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to create item member: %w", err)
+				}
+				memberIDs = append(memberIDs, itemMember.MemberID)
+			}
+
+			result.Items = append(result.Items, TransactionItemWithMembers{Item: item, MemberIDs: memberIDs})
+		}
+
+		// 6. Create new adjustments
+		result.Adjustments = make([]TransactionAdjustment, 0, len(arg.Adjustments))
+		for _, adjustmentParam := range arg.Adjustments {
+			adjustment, err := q.CreateTransactionAdjustment(ctx, CreateTransactionAdjustmentParams{
+				TransactionID:  arg.TransactionID,
+				Name:           adjustmentParam.Name,
+				AdjustmentType: adjustmentParam.AdjustmentType,
+				Value:          adjustmentParam.Value,
+				Amount:         adjustmentParam.Amount,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create adjustment: %w", err)
+			}
+			result.Adjustments = append(result.Adjustments, adjustment)
+		}
+
+		// 7. Replace splits with those derived from the items
+		result.DeletedSplits, err = q.DeleteTransactionSplits(ctx, arg.TransactionID)
+		if err != nil {
+			return fmt.Errorf("failed to delete existing splits: %w", err)
+		}
+
+		result.NewSplits = make([]Split, 0, len(arg.Splits))
+		for _, splitParam := range arg.Splits {
+			split, err := q.CreateSplit(ctx, splitParam)
+			if err != nil {
+				return fmt.Errorf("failed to create split: %w", err)
+			}
+			result.NewSplits = append(result.NewSplits, split)
+		}
+
+		// 8. Record the splits as derived from items, so changing the amount requires replacing the items
+		splitMode := SplitModeItems
+		err = q.SetTransactionSplitMode(ctx, SetTransactionSplitModeParams{ID: arg.TransactionID, SplitMode: &splitMode})
+		if err != nil {
+			return fmt.Errorf("failed to set split mode: %w", err)
+		}
+
+		// 9. Record the new revision
+		_, err = recordTransactionRevision(ctx, q, arg.TransactionID, nil)
+		return err
+	})
+
+	return result, err
+}
+
+// DeleteTransactionItemsTxResult is the result of the DeleteTransactionItemsTx operation
+type DeleteTransactionItemsTxResult struct {
+	DeletedItems       []TransactionItem
+	DeletedAdjustments []TransactionAdjustment
+}
+
+// DeleteTransactionItemsTx removes all line items and adjustments from a transaction atomically
+// Splits previously derived from the items are kept as exact amounts, the change of split mode is recorded as a new revision
+func (store *SQLStore) DeleteTransactionItemsTx(ctx context.Context, transactionID int64) (DeleteTransactionItemsTxResult, error) {
+	var result DeleteTransactionItemsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Record the state before the first edit
+		if err := recordBaselineRevision(ctx, q, transactionID); err != nil {
+			return err
+		}
+
+		result.DeletedItems, err = q.DeleteTransactionItems(ctx, transactionID)
+		if err != nil {
+			return fmt.Errorf("failed to delete items: %w", err)
+		}
+
+		result.DeletedAdjustments, err = q.DeleteTransactionAdjustments(ctx, transactionID)
+		if err != nil {
+			return fmt.Errorf("failed to delete adjustments: %w", err)
+		}
+
+		// Splits derived from the items are kept as exact amounts, there are no items left to rescale them from
+		if len(result.DeletedItems) > 0 {
+			splitMode := SplitModeExact
+			err = q.SetTransactionSplitMode(ctx, SetTransactionSplitModeParams{ID: transactionID, SplitMode: &splitMode})
+			if err != nil {
+				return fmt.Errorf("failed to set split mode: %w", err)
+			}
+
+			_, err = recordTransactionRevision(ctx, q, transactionID, nil)
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
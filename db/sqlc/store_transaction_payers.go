@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransactionPayerTxParams contains a group member paying part of a transaction
+type TransactionPayerTxParams struct {
+	MemberID int64
+	Amount   decimal.Decimal
+}
+
+// CreateTransactionWithPayersTxParams contains parameters for creating a transaction paid by several members
+type CreateTransactionWithPayersTxParams struct {
+	Transaction CreateTransactionParams
+	Payers      []TransactionPayerTxParams
+}
+
+// CreateTransactionWithPayersTxResult is the result of the CreateTransactionWithPayersTx operation
+type CreateTransactionWithPayersTxResult struct {
+	Transaction Transaction
+	Payers      []TransactionPayer
+}
+
+// CreateTransactionWithPayersTx creates a transaction and its payers atomically
+// It validates that the payer amounts add up to exactly the transaction amount
+func (store *SQLStore) CreateTransactionWithPayersTx(ctx context.Context, arg CreateTransactionWithPayersTxParams) (CreateTransactionWithPayersTxResult, error) {
+	var result CreateTransactionWithPayersTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// 1. Validate payers add up to the transaction amount
+		if err := validatePayerTotal(arg.Payers, arg.Transaction.Amount); err != nil {
+			return err
+		}
+
+		// 2. Create the transaction
+		result.Transaction, err = q.CreateTransaction(ctx, arg.Transaction)
+		if err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		// 3. Create the payers
+		result.Payers, err = createTransactionPayers(ctx, q, result.Transaction.ID, arg.Payers)
+		return err
+	})
+
+	return result, err
+}
+
+// UpdateTransactionWithPayersTxParams contains parameters for updating a transaction and replacing its payers
+type UpdateTransactionWithPayersTxParams struct {
+	Transaction UpdateTransactionParams
+	Payers      []TransactionPayerTxParams // Empty for a transaction paid in full by by_user
+	Splits      []RescaleSplitTxParams     // Every split's amount for a new transaction amount, empty when the amount is unchanged
+}
+
+// UpdateTransactionWithPayersTxResult is the result of the UpdateTransactionWithPayersTx operation
+type UpdateTransactionWithPayersTxResult struct {
+	Transaction    Transaction
+	DeletedPayers  []TransactionPayer
+	Payers         []TransactionPayer
+	RescaledSplits []Split
+}
+
+// UpdateTransactionWithPayersTx atomically updates a transaction, replaces all of its payers, rescales its splits and records a revision
+// It validates that the payer amounts add up to exactly the transaction amount
+// When the amount changes the splits are set to the rescaled amounts, exact and item-derived splits can't be rescaled
+func (store *SQLStore) UpdateTransactionWithPayersTx(ctx context.Context, arg UpdateTransactionWithPayersTxParams) (UpdateTransactionWithPayersTxResult, error) {
+	var result UpdateTransactionWithPayersTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// 1. Lock the transaction row to prevent concurrent modifications
+		tx, err := q.GetTransactionByIDForUpdate(ctx, arg.Transaction.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+
+		// 2. Validate payers add up to the new transaction amount
+		if len(arg.Payers) > 0 {
+			if err := validatePayerTotal(arg.Payers, arg.Transaction.Amount); err != nil {
+				return err
+			}
+		}
+
+		// 3. Record the state before the first edit
+		if err := recordBaselineRevision(ctx, q, arg.Transaction.ID); err != nil {
+			return err
+		}
+
+		// 4. Update the transaction
+		result.Transaction, err = q.UpdateTransaction(ctx, arg.Transaction)
+		if err != nil {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+
+		// 5. Rescale the splits to a new amount
+		result.RescaledSplits, err = rescaleTransactionSplits(ctx, q, tx, arg.Transaction.Amount, arg.Splits)
+		if err != nil {
+			return err
+		}
+
+		// 6. Replace the payers
+		result.DeletedPayers, err = q.DeleteTransactionPayers(ctx, arg.Transaction.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete existing payers: %w", err)
+		}
+
+		result.Payers, err = createTransactionPayers(ctx, q, arg.Transaction.ID, arg.Payers)
+		if err != nil {
+			return err
+		}
+
+		// 7. Record the new revision
+		_, err = recordTransactionRevision(ctx, q, arg.Transaction.ID, nil)
+		return err
+	})
+
+	return result, err
+}
+
+// validatePayerTotal checks payer amounts add up to exactly the transaction amount
+func validatePayerTotal(payers []TransactionPayerTxParams, amount decimal.Decimal) error {
+	total := decimal.NewFromInt(0)
+	for _, payer := range payers {
+		total = total.Add(payer.Amount)
+	}
+	if !total.Equal(amount) {
+		return fmt.Errorf("payer amounts must add up to transaction amount %s, got %s",
+			amount.String(), total.String())
+	}
+	return nil
+}
+
+// createTransactionPayers creates the payer rows for a transaction
+func createTransactionPayers(ctx context.Context, q *Queries, transactionID int64, payers []TransactionPayerTxParams) ([]TransactionPayer, error) {
+	created := make([]TransactionPayer, 0, len(payers))
+	for _, payerParam := range payers {
+		payer, err := q.CreateTransactionPayer(ctx, CreateTransactionPayerParams{
+			TransactionID: transactionID,
+			MemberID:      payerParam.MemberID,
+			Amount:        payerParam.Amount,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create payer: %w", err)
+		}
+		created = append(created, payer)
+	}
+	return created, nil
+}
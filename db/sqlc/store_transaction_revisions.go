@@ -0,0 +1,291 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransactionRevisionSnapshot is the state of a transaction with its payers & splits, stored as a revision's snapshot
+type TransactionRevisionSnapshot struct {
+	GroupID         int64                      `json:"group_id"`
+	Name            string                     `json:"name"`
+	TransactionDate time.Time                  `json:"transaction_date"`
+	Amount          decimal.Decimal            `json:"amount"`
+	Category        *string                    `json:"category"`
+	Note            *string                    `json:"note"`
+	ByUser          int64                      `json:"by_user"`
+	Currency        string                     `json:"currency"`
+	ExchangeRate    decimal.Decimal            `json:"exchange_rate"`
+	Payers          []TransactionRevisionPayer `json:"payers"` // Empty when paid in full by by_user
+	Splits          []TransactionRevisionSplit `json:"splits"`
+	SplitMode       *string                    `json:"split_mode"`
+}
+
+// TransactionRevisionPayer is a member paying part of a transaction in a revision
+type TransactionRevisionPayer struct {
+	MemberID int64           `json:"member_id"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// TransactionRevisionSplit is a member's split of a transaction in a revision
+type TransactionRevisionSplit struct {
+	SplitUser    *int64          `json:"split_user"`
+	SplitPercent decimal.Decimal `json:"split_percent"`
+	SplitAmount  decimal.Decimal `json:"split_amount"`
+}
+
+// ParseTransactionRevisionSnapshot decodes a revision's snapshot
+func ParseTransactionRevisionSnapshot(revision TransactionRevision) (TransactionRevisionSnapshot, error) {
+	var snapshot TransactionRevisionSnapshot
+	if err := json.Unmarshal(revision.Snapshot, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("failed to decode revision %d of transaction %d: %w", revision.Revision, revision.TransactionID, err)
+	}
+	return snapshot, nil
+}
+
+// UpdateTransaction updates a transaction and records a revision
+// Callers replacing payers or rescaling splits use UpdateTransactionWithPayersTx, which also records a revision
+// Changing the amount of a transaction with splits fails, its splits would no longer add up
+func (store *SQLStore) UpdateTransaction(ctx context.Context, arg UpdateTransactionParams) (Transaction, error) {
+	var result Transaction
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Lock the transaction row so revisions are numbered in order
+		tx, err := q.GetTransactionByIDForUpdate(ctx, arg.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+
+		// 2. Record the state before the first edit
+		if err := recordBaselineRevision(ctx, q, arg.ID); err != nil {
+			return err
+		}
+
+		// 3. Update the transaction
+		result, err = q.UpdateTransaction(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+
+		// 4. Check there are no splits to rescale
+		if _, err := rescaleTransactionSplits(ctx, q, tx, arg.Amount, nil); err != nil {
+			return err
+		}
+
+		// 5. Record the new revision
+		_, err = recordTransactionRevision(ctx, q, arg.ID, nil)
+		return err
+	})
+	return result, err
+}
+
+// ErrRevertItemsMode is returned when reverting a transaction to or from splits derived from line items,
+// revisions don't record items so they can't be restored
+var ErrRevertItemsMode = errors.New("transactions split by line items can't be reverted, edit the items instead")
+
+// RevertTransactionRevisionTxParams contains the revision to restore
+type RevertTransactionRevisionTxParams struct {
+	TransactionID int64
+	Revision      int32
+}
+
+// RevertTransactionRevisionTxResult is the result of the RevertTransactionRevisionTx operation
+type RevertTransactionRevisionTxResult struct {
+	Transaction Transaction
+	Payers      []TransactionPayer
+	Splits      []Split
+	Revision    TransactionRevision // New revision recording the revert
+}
+
+// RevertTransactionRevisionTx atomically restores a transaction, its payers & splits to an earlier revision
+// The restored state is recorded as a new revision, so a revert can be reverted too
+func (store *SQLStore) RevertTransactionRevisionTx(ctx context.Context, arg RevertTransactionRevisionTxParams) (RevertTransactionRevisionTxResult, error) {
+	var result RevertTransactionRevisionTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Lock the transaction row to prevent concurrent modifications
+		tx, err := q.GetTransactionByIDForUpdate(ctx, arg.TransactionID)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+
+		// 2. Get the revision to restore
+		revision, err := q.GetTransactionRevision(ctx, GetTransactionRevisionParams{
+			TransactionID: arg.TransactionID,
+			Revision:      arg.Revision,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get revision: %w", err)
+		}
+
+		snapshot, err := ParseTransactionRevisionSnapshot(revision)
+		if err != nil {
+			return err
+		}
+		if isItemsSplitMode(tx.SplitMode) || isItemsSplitMode(snapshot.SplitMode) {
+			return ErrRevertItemsMode
+		}
+
+		// 3. Restore the transaction fields
+		result.Transaction, err = q.UpdateTransaction(ctx, UpdateTransactionParams{
+			ID:              tx.ID,
+			GroupID:         snapshot.GroupID,
+			Name:            snapshot.Name,
+			TransactionDate: snapshot.TransactionDate,
+			Amount:          snapshot.Amount,
+			Category:        snapshot.Category,
+			Note:            snapshot.Note,
+			ByUser:          snapshot.ByUser,
+			Currency:        snapshot.Currency,
+			ExchangeRate:    snapshot.ExchangeRate,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to restore transaction: %w", err)
+		}
+
+		// 4. Restore the payers
+		if _, err := q.DeleteTransactionPayers(ctx, tx.ID); err != nil {
+			return fmt.Errorf("failed to delete existing payers: %w", err)
+		}
+
+		payers := make([]TransactionPayerTxParams, len(snapshot.Payers))
+		for i, payer := range snapshot.Payers {
+			payers[i] = TransactionPayerTxParams{MemberID: payer.MemberID, Amount: payer.Amount}
+		}
+		result.Payers, err = createTransactionPayers(ctx, q, tx.ID, payers)
+		if err != nil {
+			return err
+		}
+
+		// 5. Restore the splits
+		if _, err := q.DeleteTransactionSplits(ctx, tx.ID); err != nil {
+			return fmt.Errorf("failed to delete existing splits: %w", err)
+		}
+
+		result.Splits = make([]Split, 0, len(snapshot.Splits))
+		for _, snapshotSplit := range snapshot.Splits {
+			split, err := q.CreateSplit(ctx, CreateSplitParams{
+				TransactionID: tx.ID,
+				SplitPercent:  snapshotSplit.SplitPercent,
+				SplitAmount:   snapshotSplit.SplitAmount,
+				SplitUser:     snapshotSplit.SplitUser,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to restore split: %w", err)
+			}
+			result.Splits = append(result.Splits, split)
+		}
+
+		err = q.SetTransactionSplitMode(ctx, SetTransactionSplitModeParams{ID: tx.ID, SplitMode: snapshot.SplitMode})
+		if err != nil {
+			return fmt.Errorf("failed to restore split mode: %w", err)
+		}
+		result.Transaction.SplitMode = snapshot.SplitMode
+
+		// 6. Record the restored state as a new revision
+		result.Revision, err = recordTransactionRevision(ctx, q, tx.ID, &arg.Revision)
+		return err
+	})
+
+	return result, err
+}
+
+// isItemsSplitMode reports whether splits are derived from line items
+func isItemsSplitMode(splitMode *string) bool {
+	return splitMode != nil && *splitMode == SplitModeItems
+}
+
+// recordBaselineRevision records a transaction's current state as revision 1 if it has no revisions yet
+// Called before the first edit, so the first edit can be diffed and reverted
+func recordBaselineRevision(ctx context.Context, q *Queries, transactionID int64) error {
+	count, err := q.CountTransactionRevisions(ctx, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to count revisions: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	_, err = recordTransactionRevision(ctx, q, transactionID, nil)
+	return err
+}
+
+// recordTransactionRevision records a transaction's current state, with its payers & splits, as its next revision
+// revertedFrom is the restored revision when recording a revert
+func recordTransactionRevision(ctx context.Context, q *Queries, transactionID int64, revertedFrom *int32) (TransactionRevision, error) {
+	tx, err := q.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		return TransactionRevision{}, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	payers, err := q.ListTransactionPayersByTransactionID(ctx, transactionID)
+	if err != nil {
+		return TransactionRevision{}, fmt.Errorf("failed to get payers: %w", err)
+	}
+
+	splits, err := q.GetSplitsByTransactionID(ctx, transactionID)
+	if err != nil {
+		return TransactionRevision{}, fmt.Errorf("failed to get splits: %w", err)
+	}
+
+	snapshot, err := json.Marshal(transactionRevisionSnapshot(tx, payers, splits))
+	if err != nil {
+		return TransactionRevision{}, fmt.Errorf("failed to encode revision: %w", err)
+	}
+
+	params := CreateTransactionRevisionParams{
+		TransactionID: transactionID,
+		Snapshot:      snapshot,
+		RevertedFrom:  revertedFrom,
+	}
+	if userID, ok := AuditActorFromContext(ctx); ok {
+		params.CreatedBy = &userID
+	}
+
+	revision, err := q.CreateTransactionRevision(ctx, params)
+	if err != nil {
+		return TransactionRevision{}, fmt.Errorf("failed to create revision: %w", err)
+	}
+	return revision, nil
+}
+
+// transactionRevisionSnapshot builds a revision snapshot, payers & splits are sorted by member so snapshots compare in order
+func transactionRevisionSnapshot(tx Transaction, payers []TransactionPayer, splits []Split) TransactionRevisionSnapshot {
+	snapshot := TransactionRevisionSnapshot{
+		GroupID:         tx.GroupID,
+		Name:            tx.Name,
+		TransactionDate: tx.TransactionDate,
+		Amount:          tx.Amount,
+		Category:        tx.Category,
+		Note:            tx.Note,
+		ByUser:          tx.ByUser,
+		Currency:        tx.Currency,
+		ExchangeRate:    tx.ExchangeRate,
+		SplitMode:       tx.SplitMode,
+		Payers:          make([]TransactionRevisionPayer, len(payers)),
+		Splits:          make([]TransactionRevisionSplit, len(splits)),
+	}
+
+	for i, payer := range payers {
+		snapshot.Payers[i] = TransactionRevisionPayer{MemberID: payer.MemberID, Amount: payer.Amount}
+	}
+	sort.Slice(snapshot.Payers, func(i, j int) bool { return snapshot.Payers[i].MemberID < snapshot.Payers[j].MemberID })
+
+	for i, split := range splits {
+		snapshot.Splits[i] = TransactionRevisionSplit{SplitUser: split.SplitUser, SplitPercent: split.SplitPercent, SplitAmount: split.SplitAmount}
+	}
+	sort.Slice(snapshot.Splits, func(i, j int) bool {
+		a, b := snapshot.Splits[i].SplitUser, snapshot.Splits[j].SplitUser
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return *a < *b
+	})
+
+	return snapshot
+}
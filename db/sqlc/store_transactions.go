@@ -2,15 +2,29 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/shopspring/decimal"
 )
 
+// Split modes recorded on a transaction that aren't rescaled when the transaction amount changes
+const (
+	SplitModeExact = "exact" // Splits sent as exact amounts
+	SplitModeItems = "items" // Splits derived from line items
+)
+
+// ErrSplitAmountsFixed is returned when changing the amount of a transaction with exact or item-derived splits
+var ErrSplitAmountsFixed = errors.New("split amounts are fixed, replace the splits to change the transaction amount")
+
+// ErrSplitsChanged is returned when the splits to rescale don't match the transaction's splits
+var ErrSplitsChanged = errors.New("transaction splits changed, retry the update")
+
 // CreateSplitsTxParams contains the input parameters for creating splits
 type CreateSplitsTxParams struct {
 	TransactionID int64
 	Splits        []CreateSplitParams
+	SplitMode     *string // How the splits were calculated, nil for splits sent without a mode
 }
 
 // CreateSplitsTxResult is the result of the CreateSplitsTx operation
@@ -20,7 +34,7 @@ type CreateSplitsTxResult struct {
 }
 
 // CreateSplitsTx creates multiple splits for a transaction atomically
-// It validates that the splits add up to 100% of the transaction amount
+// It validates that the splits add up to exactly 100% of the transaction amount
 func (store *SQLStore) CreateSplitsTx(ctx context.Context, arg CreateSplitsTxParams) (CreateSplitsTxResult, error) {
 	var result CreateSplitsTxResult
 
@@ -46,10 +60,9 @@ func (store *SQLStore) CreateSplitsTx(ctx context.Context, arg CreateSplitsTxPar
 			return fmt.Errorf("split percentages must add up to 100%%, got %s", totalPercent.String())
 		}
 
-		// Optional: Check if total amount equals transaction amount (with small tolerance for rounding)
-		tolerance := decimal.NewFromFloat(0.01) // 1 cent tolerance
-		amountDiff := totalAmount.Sub(result.Transaction.Amount).Abs()
-		if amountDiff.GreaterThan(tolerance) {
+		// Check total amount equals transaction amount exactly, rounding is allocated before splits are stored
+		if !totalAmount.Equal(result.Transaction.Amount) {
+			amountDiff := totalAmount.Sub(result.Transaction.Amount).Abs()
 			return fmt.Errorf("split amounts must add up to transaction amount %s, got %s (diff: %s)",
 				result.Transaction.Amount.String(), totalAmount.String(), amountDiff.String())
 		}
@@ -64,6 +77,13 @@ func (store *SQLStore) CreateSplitsTx(ctx context.Context, arg CreateSplitsTxPar
 			result.Splits = append(result.Splits, split)
 		}
 
+		// 4. Record how the splits were calculated
+		err = q.SetTransactionSplitMode(ctx, SetTransactionSplitModeParams{ID: arg.TransactionID, SplitMode: arg.SplitMode})
+		if err != nil {
+			return fmt.Errorf("failed to set split mode: %w", err)
+		}
+		result.Transaction.SplitMode = arg.SplitMode
+
 		return nil
 	})
 
@@ -74,6 +94,7 @@ func (store *SQLStore) CreateSplitsTx(ctx context.Context, arg CreateSplitsTxPar
 type UpdateTransactionSplitsTxParams struct {
 	TransactionID int64
 	Splits        []CreateSplitParams // New splits to replace existing ones
+	SplitMode     *string             // How the splits were calculated, nil for splits sent without a mode
 }
 
 // UpdateTransactionSplitsTxResult is the result of the update operation
@@ -82,7 +103,7 @@ type UpdateTransactionSplitsTxResult struct {
 	NewSplits     []Split
 }
 
-// UpdateTransactionSplitsTx atomically replaces all splits for a transaction
+// UpdateTransactionSplitsTx atomically replaces all splits for a transaction and records a revision
 // This ensures the transaction is never left in an invalid state
 func (store *SQLStore) UpdateTransactionSplitsTx(ctx context.Context, arg UpdateTransactionSplitsTxParams) (UpdateTransactionSplitsTxResult, error) {
 	var result UpdateTransactionSplitsTxResult
@@ -108,20 +129,23 @@ func (store *SQLStore) UpdateTransactionSplitsTx(ctx context.Context, arg Update
 			return fmt.Errorf("split percentages must add up to 100%%, got %s", totalPercent.String())
 		}
 
-		tolerance := decimal.NewFromFloat(0.01)
-		amountDiff := totalAmount.Sub(tx.Amount).Abs()
-		if amountDiff.GreaterThan(tolerance) {
+		if !totalAmount.Equal(tx.Amount) {
 			return fmt.Errorf("split amounts must add up to transaction amount %s, got %s",
 				tx.Amount.String(), totalAmount.String())
 		}
 
-		// 3. Delete existing splits
+		// 3. Record the state before the first edit
+		if err := recordBaselineRevision(ctx, q, arg.TransactionID); err != nil {
+			return err
+		}
+
+		// 4. Delete existing splits
 		result.DeletedSplits, err = q.DeleteTransactionSplits(ctx, arg.TransactionID)
 		if err != nil {
 			return fmt.Errorf("failed to delete existing splits: %w", err)
 		}
 
-		// 4. Create new splits
+		// 5. Create new splits
 		result.NewSplits = make([]Split, 0, len(arg.Splits))
 		for _, splitParam := range arg.Splits {
 			split, err := q.CreateSplit(ctx, splitParam)
@@ -131,14 +155,22 @@ func (store *SQLStore) UpdateTransactionSplitsTx(ctx context.Context, arg Update
 			result.NewSplits = append(result.NewSplits, split)
 		}
 
-		return nil
+		// 6. Record how the splits were calculated
+		err = q.SetTransactionSplitMode(ctx, SetTransactionSplitModeParams{ID: arg.TransactionID, SplitMode: arg.SplitMode})
+		if err != nil {
+			return fmt.Errorf("failed to set split mode: %w", err)
+		}
+
+		// 7. Record the new revision
+		_, err = recordTransactionRevision(ctx, q, arg.TransactionID, nil)
+		return err
 	})
 
 	return result, err
 }
 
-// DeleteTransactionWithSplitsTx deletes a transaction and its splits atomically
-// (Note: CASCADE handles this automatically, but this shows explicit control)
+// DeleteTransactionWithSplitsTx moves a transaction to the trash atomically
+// Its splits are kept so the transaction can be restored, they are deleted when it is purged
 func (store *SQLStore) DeleteTransactionWithSplitsTx(ctx context.Context, transactionID int64) error {
 	return store.execTx(ctx, func(q *Queries) error {
 		// Lock the transaction
@@ -147,13 +179,7 @@ func (store *SQLStore) DeleteTransactionWithSplitsTx(ctx context.Context, transa
 			return fmt.Errorf("failed to get transaction: %w", err)
 		}
 
-		// Delete splits first
-		_, err = q.DeleteTransactionSplits(ctx, transactionID)
-		if err != nil {
-			return fmt.Errorf("failed to delete splits: %w", err)
-		}
-
-		// Delete transaction
+		// Move transaction to the trash
 		_, err = q.DeleteTransaction(ctx, transactionID)
 		if err != nil {
 			return fmt.Errorf("failed to delete transaction: %w", err)
@@ -162,3 +188,65 @@ func (store *SQLStore) DeleteTransactionWithSplitsTx(ctx context.Context, transa
 		return nil
 	})
 }
+
+// RescaleSplitTxParams contains a split's amount for a new transaction amount
+type RescaleSplitTxParams struct {
+	ID          int64
+	SplitAmount decimal.Decimal
+}
+
+// rescaleTransactionSplits sets the split amounts for a transaction's new amount, split percentages are kept
+// The rescaled amounts are calculated by the caller and must cover every split of the transaction and sum to the new amount
+// Does nothing when the amount is unchanged, a transaction without splits has nothing to rescale
+func rescaleTransactionSplits(ctx context.Context, q *Queries, tx Transaction, amount decimal.Decimal, rescaled []RescaleSplitTxParams) ([]Split, error) {
+	if amount.Equal(tx.Amount) {
+		return nil, nil
+	}
+
+	splits, err := q.GetSplitsByTransactionIDForUpdate(ctx, tx.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get splits: %w", err)
+	}
+	if len(splits) == 0 && len(rescaled) == 0 {
+		return nil, nil
+	}
+
+	if tx.SplitMode != nil && (*tx.SplitMode == SplitModeExact || *tx.SplitMode == SplitModeItems) {
+		return nil, fmt.Errorf("%w (split mode %s)", ErrSplitAmountsFixed, *tx.SplitMode)
+	}
+
+	// Validate the rescaled amounts cover every split & sum to the new amount
+	amounts := make(map[int64]decimal.Decimal, len(rescaled))
+	totalAmount := decimal.NewFromInt(0)
+	for _, split := range rescaled {
+		amounts[split.ID] = split.SplitAmount
+		totalAmount = totalAmount.Add(split.SplitAmount)
+	}
+	if len(amounts) != len(splits) {
+		return nil, ErrSplitsChanged
+	}
+	for _, split := range splits {
+		if _, ok := amounts[split.ID]; !ok {
+			return nil, ErrSplitsChanged
+		}
+	}
+	if !totalAmount.Equal(amount) {
+		return nil, fmt.Errorf("split amounts must add up to transaction amount %s, got %s", amount.String(), totalAmount.String())
+	}
+
+	result := make([]Split, 0, len(splits))
+	for _, split := range splits {
+		updated, err := q.RescaleSplit(ctx, RescaleSplitParams{
+			ID:            split.ID,
+			TransactionID: tx.ID,
+			TxAmount:      amount,
+			SplitAmount:   amounts[split.ID],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to rescale split: %w", err)
+		}
+		result = append(result, updated)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PurgeTrashTxParams contains the cutoff for purging the trash
+type PurgeTrashTxParams struct {
+	DeletedBefore time.Time // Transactions & groups moved to the trash before this time are purged
+}
+
+// PurgeTrashTxResult is the result of the PurgeTrashTx operation
+type PurgeTrashTxResult struct {
+	Transactions int64
+	Groups       int64
+}
+
+// PurgeTrashTx permanently deletes transactions and groups that have been in the trash since before the cutoff
+// Splits, payers, items, members & settlements are deleted with them by cascade
+func (store *SQLStore) PurgeTrashTx(ctx context.Context, arg PurgeTrashTxParams) (PurgeTrashTxResult, error) {
+	var result PurgeTrashTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// 1. Purge transactions in the trash
+		result.Transactions, err = q.PurgeDeletedTransactions(ctx, arg.DeletedBefore)
+		if err != nil {
+			return fmt.Errorf("failed to purge transactions: %w", err)
+		}
+
+		// 2. Purge groups in the trash, with every transaction still in them
+		result.Groups, err = q.PurgeDeletedGroups(ctx, arg.DeletedBefore)
+		if err != nil {
+			return fmt.Errorf("failed to purge groups: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
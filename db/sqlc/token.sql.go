@@ -8,6 +8,8 @@ package db
 import (
 	"context"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createRefreshToken = `-- name: CreateRefreshToken :one
@@ -22,13 +24,16 @@ CREATE TABLE "refresh_tokens" (
   "created_at" timestamptz NOT NULL DEFAULT (now()),
   "revoked_at" timestamptz,
   "device_info" varchar,
+  "family_id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "ip_address" varchar,
+  "last_used_at" timestamptz NOT NULL DEFAULT (now()),
   CONSTRAINT refresh_tokens_user_id_fkey FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
 );
 */
 
-INSERT INTO "refresh_tokens" (token_hash, user_id, expires_at, device_info)
-VALUES ($1, $2, $3, $4)
-RETURNING id, token_hash, user_id, expires_at, created_at, revoked_at, device_info
+INSERT INTO "refresh_tokens" (token_hash, user_id, expires_at, device_info, ip_address)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, token_hash, user_id, expires_at, created_at, revoked_at, device_info, family_id, ip_address, last_used_at
 `
 
 type CreateRefreshTokenParams struct {
@@ -36,6 +41,7 @@ type CreateRefreshTokenParams struct {
 	UserID     int64     `json:"user_id"`
 	ExpiresAt  time.Time `json:"expires_at"`
 	DeviceInfo *string   `json:"device_info"`
+	IpAddress  *string   `json:"ip_address"`
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error) {
@@ -44,6 +50,7 @@ func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshToken
 		arg.UserID,
 		arg.ExpiresAt,
 		arg.DeviceInfo,
+		arg.IpAddress,
 	)
 	var i RefreshToken
 	err := row.Scan(
@@ -54,6 +61,50 @@ func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshToken
 		&i.CreatedAt,
 		&i.RevokedAt,
 		&i.DeviceInfo,
+		&i.FamilyID,
+		&i.IpAddress,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const createRotatedRefreshToken = `-- name: CreateRotatedRefreshToken :one
+INSERT INTO "refresh_tokens" (token_hash, user_id, expires_at, device_info, ip_address, family_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, token_hash, user_id, expires_at, created_at, revoked_at, device_info, family_id, ip_address, last_used_at
+`
+
+type CreateRotatedRefreshTokenParams struct {
+	TokenHash  string      `json:"token_hash"`
+	UserID     int64       `json:"user_id"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+	DeviceInfo *string     `json:"device_info"`
+	IpAddress  *string     `json:"ip_address"`
+	FamilyID   pgtype.UUID `json:"family_id"`
+}
+
+// Creates the token replacing a rotated token, in the same family
+func (q *Queries) CreateRotatedRefreshToken(ctx context.Context, arg CreateRotatedRefreshTokenParams) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, createRotatedRefreshToken,
+		arg.TokenHash,
+		arg.UserID,
+		arg.ExpiresAt,
+		arg.DeviceInfo,
+		arg.IpAddress,
+		arg.FamilyID,
+	)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.TokenHash,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.DeviceInfo,
+		&i.FamilyID,
+		&i.IpAddress,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
@@ -69,7 +120,7 @@ func (q *Queries) DeleteExpiredTokens(ctx context.Context) error {
 }
 
 const getRefreshTokenByHash = `-- name: GetRefreshTokenByHash :one
-SELECT id, token_hash, user_id, expires_at, created_at, revoked_at, device_info FROM "refresh_tokens"
+SELECT id, token_hash, user_id, expires_at, created_at, revoked_at, device_info, family_id, ip_address, last_used_at FROM "refresh_tokens"
 WHERE token_hash = $1
 LIMIT 1
 `
@@ -85,16 +136,44 @@ func (q *Queries) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (
 		&i.CreatedAt,
 		&i.RevokedAt,
 		&i.DeviceInfo,
+		&i.FamilyID,
+		&i.IpAddress,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getRefreshTokenByHashForUpdate = `-- name: GetRefreshTokenByHashForUpdate :one
+SELECT id, token_hash, user_id, expires_at, created_at, revoked_at, device_info, family_id, ip_address, last_used_at FROM "refresh_tokens"
+WHERE token_hash = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetRefreshTokenByHashForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenByHashForUpdate, tokenHash)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.TokenHash,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.DeviceInfo,
+		&i.FamilyID,
+		&i.IpAddress,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
 const getUserRefreshTokens = `-- name: GetUserRefreshTokens :many
-SELECT id, token_hash, user_id, expires_at, created_at, revoked_at, device_info FROM "refresh_tokens"
+SELECT id, token_hash, user_id, expires_at, created_at, revoked_at, device_info, family_id, ip_address, last_used_at FROM "refresh_tokens"
 WHERE user_id = $1
   AND revoked_at IS NULL
   AND expires_at > now()
-ORDER BY created_at DESC
+ORDER BY last_used_at DESC
 `
 
 func (q *Queries) GetUserRefreshTokens(ctx context.Context, userID int64) ([]RefreshToken, error) {
@@ -114,6 +193,9 @@ func (q *Queries) GetUserRefreshTokens(ctx context.Context, userID int64) ([]Ref
 			&i.CreatedAt,
 			&i.RevokedAt,
 			&i.DeviceInfo,
+			&i.FamilyID,
+			&i.IpAddress,
+			&i.LastUsedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -125,6 +207,26 @@ func (q *Queries) GetUserRefreshTokens(ctx context.Context, userID int64) ([]Ref
 	return items, nil
 }
 
+const revokeOtherUserRefreshTokens = `-- name: RevokeOtherUserRefreshTokens :execrows
+UPDATE "refresh_tokens"
+SET revoked_at = now()
+WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL
+`
+
+type RevokeOtherUserRefreshTokensParams struct {
+	UserID   int64       `json:"user_id"`
+	FamilyID pgtype.UUID `json:"family_id"`
+}
+
+// Revokes every session of a user except the one with the given family
+func (q *Queries) RevokeOtherUserRefreshTokens(ctx context.Context, arg RevokeOtherUserRefreshTokensParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeOtherUserRefreshTokens, arg.UserID, arg.FamilyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const revokeAllUserTokens = `-- name: RevokeAllUserTokens :exec
 UPDATE "refresh_tokens"
 SET revoked_at = now()
@@ -146,3 +248,17 @@ func (q *Queries) RevokeRefreshToken(ctx context.Context, tokenHash string) erro
 	_, err := q.db.Exec(ctx, revokeRefreshToken, tokenHash)
 	return err
 }
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :execrows
+UPDATE "refresh_tokens"
+SET revoked_at = now()
+WHERE family_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeRefreshTokenFamily, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
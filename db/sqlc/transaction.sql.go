@@ -25,13 +25,17 @@ category varchar,
 note varchar,
 by_user bigint NOT NULL,
 created_at timestamptz NOT NULL DEFAULT (now()),
-modified_at timestamptz NOT NULL DEFAULT (now())
+modified_at timestamptz NOT NULL DEFAULT (now()),
+currency varchar(3) NOT NULL DEFAULT 'USD',
+exchange_rate numeric(18,8) NOT NULL DEFAULT 1,
+deleted_at timestamptz,
+split_mode varchar
 */
 
 
-INSERT INTO "transactions" (group_id, name, transaction_date, amount, category, note, by_user)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at
+INSERT INTO "transactions" (group_id, name, transaction_date, amount, category, note, by_user, currency, exchange_rate)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode
 `
 
 type CreateTransactionParams struct {
@@ -42,6 +46,8 @@ type CreateTransactionParams struct {
 	Category        *string         `json:"category"`
 	Note            *string         `json:"note"`
 	ByUser          int64           `json:"by_user"`
+	Currency        string          `json:"currency"`
+	ExchangeRate    decimal.Decimal `json:"exchange_rate"`
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -53,6 +59,8 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.Category,
 		arg.Note,
 		arg.ByUser,
+		arg.Currency,
+		arg.ExchangeRate,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -66,16 +74,22 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.ByUser,
 		&i.CreatedAt,
 		&i.ModifiedAt,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.DeletedAt,
+		&i.SplitMode,
 	)
 	return i, err
 }
 
 const deleteTransaction = `-- name: DeleteTransaction :one
-DELETE FROM "transactions" 
-WHERE id = $1
-RETURNING id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at
+UPDATE "transactions"
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode
 `
 
+// Moves a transaction to the trash, its splits, payers & items are kept until it is purged
 func (q *Queries) DeleteTransaction(ctx context.Context, id int64) (Transaction, error) {
 	row := q.db.QueryRow(ctx, deleteTransaction, id)
 	var i Transaction
@@ -90,15 +104,49 @@ func (q *Queries) DeleteTransaction(ctx context.Context, id int64) (Transaction,
 		&i.ByUser,
 		&i.CreatedAt,
 		&i.ModifiedAt,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.DeletedAt,
+		&i.SplitMode,
+	)
+	return i, err
+}
+
+const getDeletedTransactionByID = `-- name: GetDeletedTransactionByID :one
+SELECT 
+    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode 
+FROM "transactions"
+WHERE id = $1 AND deleted_at IS NOT NULL
+LIMIT 1
+`
+
+func (q *Queries) GetDeletedTransactionByID(ctx context.Context, id int64) (Transaction, error) {
+	row := q.db.QueryRow(ctx, getDeletedTransactionByID, id)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.Name,
+		&i.TransactionDate,
+		&i.Amount,
+		&i.Category,
+		&i.Note,
+		&i.ByUser,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.DeletedAt,
+		&i.SplitMode,
 	)
 	return i, err
 }
 
 const getTransactionByID = `-- name: GetTransactionByID :one
 SELECT 
-    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at 
+    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode 
 FROM "transactions"
-WHERE id = $1 
+WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1
 `
 
@@ -116,15 +164,19 @@ func (q *Queries) GetTransactionByID(ctx context.Context, id int64) (Transaction
 		&i.ByUser,
 		&i.CreatedAt,
 		&i.ModifiedAt,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.DeletedAt,
+		&i.SplitMode,
 	)
 	return i, err
 }
 
 const getTransactionByIDForUpdate = `-- name: GetTransactionByIDForUpdate :one
 SELECT 
-    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at 
+    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode 
 FROM "transactions"
-WHERE id = $1 
+WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1
 FOR UPDATE
 `
@@ -143,17 +195,22 @@ func (q *Queries) GetTransactionByIDForUpdate(ctx context.Context, id int64) (Tr
 		&i.ByUser,
 		&i.CreatedAt,
 		&i.ModifiedAt,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.DeletedAt,
+		&i.SplitMode,
 	)
 	return i, err
 }
 
 const getTransactionsByGroupInPeriod = `-- name: GetTransactionsByGroupInPeriod :many
 SELECT 
-    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at
+    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode
 FROM "transactions"
 WHERE 
     group_id = $1
     and transaction_date between $4::date and $5::date
+    and deleted_at IS NULL
 ORDER BY transaction_date desc
 LIMIT $2
 OFFSET $3
@@ -193,6 +250,10 @@ func (q *Queries) GetTransactionsByGroupInPeriod(ctx context.Context, arg GetTra
 			&i.ByUser,
 			&i.CreatedAt,
 			&i.ModifiedAt,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.DeletedAt,
+			&i.SplitMode,
 		); err != nil {
 			return nil, err
 		}
@@ -206,9 +267,9 @@ func (q *Queries) GetTransactionsByGroupInPeriod(ctx context.Context, arg GetTra
 
 const getTransactionsByUser = `-- name: GetTransactionsByUser :many
 SELECT 
-    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at 
+    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode 
 FROM "transactions"
-WHERE by_user = $1
+WHERE by_user = $1 AND deleted_at IS NULL
 ORDER BY transaction_date desc
 LIMIT $2
 OFFSET $3
@@ -240,6 +301,10 @@ func (q *Queries) GetTransactionsByUser(ctx context.Context, arg GetTransactions
 			&i.ByUser,
 			&i.CreatedAt,
 			&i.ModifiedAt,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.DeletedAt,
+			&i.SplitMode,
 		); err != nil {
 			return nil, err
 		}
@@ -252,10 +317,11 @@ func (q *Queries) GetTransactionsByUser(ctx context.Context, arg GetTransactions
 }
 
 const getTransactionsByUserInPeriod = `-- name: GetTransactionsByUserInPeriod :many
-SELECT id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at FROM "transactions"
+SELECT id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode FROM "transactions"
 WHERE 
     by_user = $1 
     AND transaction_date between $4::date and $5::date
+    AND deleted_at IS NULL
 ORDER BY transaction_date desc
 LIMIT $2
 OFFSET $3
@@ -295,6 +361,129 @@ func (q *Queries) GetTransactionsByUserInPeriod(ctx context.Context, arg GetTran
 			&i.ByUser,
 			&i.CreatedAt,
 			&i.ModifiedAt,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.DeletedAt,
+			&i.SplitMode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeletedTransactionsByGroupID = `-- name: ListDeletedTransactionsByGroupID :many
+SELECT 
+    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode 
+FROM "transactions"
+WHERE group_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at desc
+LIMIT $2
+OFFSET $3
+`
+
+type ListDeletedTransactionsByGroupIDParams struct {
+	GroupID int64 `json:"group_id"`
+	Limit   int32 `json:"limit"`
+	Offset  int32 `json:"offset"`
+}
+
+func (q *Queries) ListDeletedTransactionsByGroupID(ctx context.Context, arg ListDeletedTransactionsByGroupIDParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, listDeletedTransactionsByGroupID, arg.GroupID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transaction{}
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.GroupID,
+			&i.Name,
+			&i.TransactionDate,
+			&i.Amount,
+			&i.Category,
+			&i.Note,
+			&i.ByUser,
+			&i.CreatedAt,
+			&i.ModifiedAt,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.DeletedAt,
+			&i.SplitMode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInconsistentTransactionsByGroupID = `-- name: ListInconsistentTransactionsByGroupID :many
+SELECT
+    t.id,
+    t.name,
+    t.transaction_date,
+    t.amount,
+    t.split_mode,
+    COUNT(s.id) AS split_count,
+    SUM(s.split_amount)::numeric AS split_total,
+    COUNT(s.id) FILTER (WHERE s.tx_amount != t.amount) AS stale_split_count
+FROM "transactions" t
+INNER JOIN splits s ON s.transaction_id = t.id
+WHERE t.group_id = $1 AND t.deleted_at IS NULL
+GROUP BY t.id
+HAVING SUM(s.split_amount) != t.amount OR COUNT(s.id) FILTER (WHERE s.tx_amount != t.amount) > 0
+ORDER BY t.transaction_date desc, t.id
+LIMIT $2
+OFFSET $3
+`
+
+type ListInconsistentTransactionsByGroupIDParams struct {
+	GroupID int64 `json:"group_id"`
+	Limit   int32 `json:"limit"`
+	Offset  int32 `json:"offset"`
+}
+
+type ListInconsistentTransactionsByGroupIDRow struct {
+	ID              int64           `json:"id"`
+	Name            string          `json:"name"`
+	TransactionDate time.Time       `json:"transaction_date"`
+	Amount          decimal.Decimal `json:"amount"`
+	SplitMode       *string         `json:"split_mode"`
+	SplitCount      int64           `json:"split_count"`
+	SplitTotal      decimal.Decimal `json:"split_total"`
+	StaleSplitCount int64           `json:"stale_split_count"`
+}
+
+// Transactions whose splits don't sum to the transaction amount, or were split from a different amount
+// Transactions without splits aren't listed
+func (q *Queries) ListInconsistentTransactionsByGroupID(ctx context.Context, arg ListInconsistentTransactionsByGroupIDParams) ([]ListInconsistentTransactionsByGroupIDRow, error) {
+	rows, err := q.db.Query(ctx, listInconsistentTransactionsByGroupID, arg.GroupID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInconsistentTransactionsByGroupIDRow{}
+	for rows.Next() {
+		var i ListInconsistentTransactionsByGroupIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.TransactionDate,
+			&i.Amount,
+			&i.SplitMode,
+			&i.SplitCount,
+			&i.SplitTotal,
+			&i.StaleSplitCount,
 		); err != nil {
 			return nil, err
 		}
@@ -308,8 +497,9 @@ func (q *Queries) GetTransactionsByUserInPeriod(ctx context.Context, arg GetTran
 
 const listTransactions = `-- name: ListTransactions :many
 SELECT 
-    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at 
+    id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode 
 FROM "transactions"
+WHERE deleted_at IS NULL
 ORDER BY transaction_date desc
 LIMIT $1
 OFFSET $2
@@ -340,6 +530,10 @@ func (q *Queries) ListTransactions(ctx context.Context, arg ListTransactionsPara
 			&i.ByUser,
 			&i.CreatedAt,
 			&i.ModifiedAt,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.DeletedAt,
+			&i.SplitMode,
 		); err != nil {
 			return nil, err
 		}
@@ -353,10 +547,11 @@ func (q *Queries) ListTransactions(ctx context.Context, arg ListTransactionsPara
 
 const listTransactionsByUserGroups = `-- name: ListTransactionsByUserGroups :many
 SELECT 
-    t.id, t.group_id, t.name, t.transaction_date, t.amount, t.category, t.note, t.by_user, t.created_at, t.modified_at 
+    t.id, t.group_id, t.name, t.transaction_date, t.amount, t.category, t.note, t.by_user, t.created_at, t.modified_at, t.currency, t.exchange_rate, t.deleted_at, t.split_mode 
 FROM "transactions" t
 INNER JOIN group_members gm ON t.group_id = gm.group_id
-WHERE gm.user_id = $1
+INNER JOIN "groups" g ON t.group_id = g.id
+WHERE gm.user_id = $1 AND gm.removed_at IS NULL AND t.deleted_at IS NULL AND g.deleted_at IS NULL
 ORDER BY t.transaction_date desc
 LIMIT $2
 OFFSET $3
@@ -388,6 +583,10 @@ func (q *Queries) ListTransactionsByUserGroups(ctx context.Context, arg ListTran
 			&i.ByUser,
 			&i.CreatedAt,
 			&i.ModifiedAt,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.DeletedAt,
+			&i.SplitMode,
 		); err != nil {
 			return nil, err
 		}
@@ -399,6 +598,83 @@ func (q *Queries) ListTransactionsByUserGroups(ctx context.Context, arg ListTran
 	return items, nil
 }
 
+const purgeDeletedTransactions = `-- name: PurgeDeletedTransactions :execrows
+DELETE FROM "transactions"
+WHERE deleted_at < $1::timestamptz
+`
+
+// Permanently deletes transactions in the trash since before the given time, with their splits, payers & items
+func (q *Queries) PurgeDeletedTransactions(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedTransactions, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reassignTransactionsByUser = `-- name: ReassignTransactionsByUser :exec
+UPDATE transactions
+SET by_user = $1::bigint
+WHERE by_user = $2::bigint
+`
+
+type ReassignTransactionsByUserParams struct {
+	IntoMemberID int64 `json:"into_member_id"`
+	FromMemberID int64 `json:"from_member_id"`
+}
+
+// Moves every transaction paid by a group member to another member
+func (q *Queries) ReassignTransactionsByUser(ctx context.Context, arg ReassignTransactionsByUserParams) error {
+	_, err := q.db.Exec(ctx, reassignTransactionsByUser, arg.IntoMemberID, arg.FromMemberID)
+	return err
+}
+
+const restoreTransaction = `-- name: RestoreTransaction :one
+UPDATE "transactions"
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode
+`
+
+func (q *Queries) RestoreTransaction(ctx context.Context, id int64) (Transaction, error) {
+	row := q.db.QueryRow(ctx, restoreTransaction, id)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.Name,
+		&i.TransactionDate,
+		&i.Amount,
+		&i.Category,
+		&i.Note,
+		&i.ByUser,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.DeletedAt,
+		&i.SplitMode,
+	)
+	return i, err
+}
+
+const setTransactionSplitMode = `-- name: SetTransactionSplitMode :exec
+UPDATE "transactions"
+SET split_mode = $2
+WHERE id = $1
+`
+
+type SetTransactionSplitModeParams struct {
+	ID        int64   `json:"id"`
+	SplitMode *string `json:"split_mode"`
+}
+
+// Records how the transaction's splits were calculated, NULL for splits sent without a mode
+func (q *Queries) SetTransactionSplitMode(ctx context.Context, arg SetTransactionSplitModeParams) error {
+	_, err := q.db.Exec(ctx, setTransactionSplitMode, arg.ID, arg.SplitMode)
+	return err
+}
+
 const updateTransaction = `-- name: UpdateTransaction :one
 UPDATE "transactions"
 SET
@@ -408,9 +684,11 @@ SET
     amount = $5,
     category = $6,
     note = $7,
-    by_user = $8
+    by_user = $8,
+    currency = $9,
+    exchange_rate = $10
 WHERE id = $1
-RETURNING id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at
+RETURNING id, group_id, name, transaction_date, amount, category, note, by_user, created_at, modified_at, currency, exchange_rate, deleted_at, split_mode
 `
 
 type UpdateTransactionParams struct {
@@ -422,6 +700,8 @@ type UpdateTransactionParams struct {
 	Category        *string         `json:"category"`
 	Note            *string         `json:"note"`
 	ByUser          int64           `json:"by_user"`
+	Currency        string          `json:"currency"`
+	ExchangeRate    decimal.Decimal `json:"exchange_rate"`
 }
 
 func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionParams) (Transaction, error) {
@@ -434,6 +714,8 @@ func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionPa
 		arg.Category,
 		arg.Note,
 		arg.ByUser,
+		arg.Currency,
+		arg.ExchangeRate,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -447,6 +729,10 @@ func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionPa
 		&i.ByUser,
 		&i.CreatedAt,
 		&i.ModifiedAt,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.DeletedAt,
+		&i.SplitMode,
 	)
 	return i, err
 }
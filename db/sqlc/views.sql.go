@@ -61,6 +61,7 @@ func (q *Queries) GroupBalances(ctx context.Context, groupID int64) ([]GroupBala
 
 const groupBalancesNet = `-- name: GroupBalancesNet :many
 SELECT
+    gm.id as member_id,
     gm.user_id as user_id,
     gm.member_name as user_name,
     gbn.net_balance::numeric(10,2) as net_balance -- sum returns unconstrained numeric
@@ -71,6 +72,7 @@ ORDER BY gm.member_name
 `
 
 type GroupBalancesNetRow struct {
+	MemberID   int64           `json:"member_id"`
 	UserID     *int64          `json:"user_id"`
 	UserName   *string         `json:"user_name"`
 	NetBalance decimal.Decimal `json:"net_balance"`
@@ -85,7 +87,73 @@ func (q *Queries) GroupBalancesNet(ctx context.Context, groupID int64) ([]GroupB
 	items := []GroupBalancesNetRow{}
 	for rows.Next() {
 		var i GroupBalancesNetRow
-		if err := rows.Scan(&i.UserID, &i.UserName, &i.NetBalance); err != nil {
+		if err := rows.Scan(
+			&i.MemberID,
+			&i.UserID,
+			&i.UserName,
+			&i.NetBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sharedGroupBalancesNet = `-- name: SharedGroupBalancesNet :many
+SELECT
+    gm.group_id as group_id,
+    g.currency as currency,
+    gm.id as member_id,
+    gm.user_id as user_id,
+    gm.role as role,
+    COALESCE(gbn.net_balance, 0)::numeric(10,2) as net_balance
+FROM group_members gm
+JOIN groups g on g.id = gm.group_id
+LEFT JOIN group_balances_net gbn on gbn.user_id = gm.id
+WHERE gm.user_id IN ($1::bigint, $2::bigint)
+  AND g.deleted_at IS NULL
+  AND gm.group_id IN (SELECT group_id FROM group_members WHERE user_id = $1::bigint)
+  AND gm.group_id IN (SELECT group_id FROM group_members WHERE user_id = $2::bigint)
+ORDER BY gm.group_id, gm.id
+`
+
+type SharedGroupBalancesNetParams struct {
+	UserID      int64 `json:"user_id"`
+	OtherUserID int64 `json:"other_user_id"`
+}
+
+type SharedGroupBalancesNetRow struct {
+	GroupID    int64           `json:"group_id"`
+	Currency   string          `json:"currency"`
+	MemberID   int64           `json:"member_id"`
+	UserID     *int64          `json:"user_id"`
+	Role       string          `json:"role"`
+	NetBalance decimal.Decimal `json:"net_balance"`
+}
+
+// Returns the net balance of two users in every group they are both members of
+// Members without ledger entries in a group have a net balance of 0
+func (q *Queries) SharedGroupBalancesNet(ctx context.Context, arg SharedGroupBalancesNetParams) ([]SharedGroupBalancesNetRow, error) {
+	rows, err := q.db.Query(ctx, sharedGroupBalancesNet, arg.UserID, arg.OtherUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SharedGroupBalancesNetRow{}
+	for rows.Next() {
+		var i SharedGroupBalancesNetRow
+		if err := rows.Scan(
+			&i.GroupID,
+			&i.Currency,
+			&i.MemberID,
+			&i.UserID,
+			&i.Role,
+			&i.NetBalance,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -100,6 +168,7 @@ const userBalancesByGroup = `-- name: UserBalancesByGroup :many
 SELECT
     g.id as group_id,
     g.name as group_name,
+    g.currency as currency,
     gbn.net_balance::numeric(10,2) as net_balance
 FROM group_balances_net gbn
 JOIN group_members gm on gm.id = gbn.user_id
@@ -111,6 +180,7 @@ ORDER BY g.name
 type UserBalancesByGroupRow struct {
 	GroupID    int64           `json:"group_id"`
 	GroupName  string          `json:"group_name"`
+	Currency   string          `json:"currency"`
 	NetBalance decimal.Decimal `json:"net_balance"`
 }
 
@@ -126,7 +196,12 @@ func (q *Queries) UserBalancesByGroup(ctx context.Context, userID *int64) ([]Use
 	items := []UserBalancesByGroupRow{}
 	for rows.Next() {
 		var i UserBalancesByGroupRow
-		if err := rows.Scan(&i.GroupID, &i.GroupName, &i.NetBalance); err != nil {
+		if err := rows.Scan(
+			&i.GroupID,
+			&i.GroupName,
+			&i.Currency,
+			&i.NetBalance,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -202,3 +277,58 @@ func (q *Queries) UserBalancesSummary(ctx context.Context, userID *int64) (UserB
 	err := row.Scan(&i.NetBalance, &i.TotalOwed, &i.TotalOwedToUser)
 	return i, err
 }
+
+const userNetworkBalances = `-- name: UserNetworkBalances :many
+WITH network AS (
+    SELECT DISTINCT gm.user_id
+    FROM group_members gm
+    WHERE gm.user_id IS NOT NULL
+      AND gm.group_id IN (SELECT group_id FROM group_members WHERE user_id = $1)
+)
+SELECT
+    ubm.user_id as user_id,
+    u.name as user_name,
+    ubm.member_user_id as member_user_id,
+    ubm.net_balance::numeric(10,2) as net_balance
+FROM user_balances_by_member ubm
+JOIN users u on u.id = ubm.user_id
+WHERE ubm.user_id IN (SELECT user_id FROM network)
+  AND ubm.member_user_id IN (SELECT user_id FROM network)
+  AND ubm.user_id <> ubm.member_user_id
+ORDER BY ubm.user_id, ubm.member_user_id
+`
+
+type UserNetworkBalancesRow struct {
+	UserID       *int64          `json:"user_id"`
+	UserName     string          `json:"user_name"`
+	MemberUserID *int64          `json:"member_user_id"`
+	NetBalance   decimal.Decimal `json:"net_balance"`
+}
+
+// Returns the balance between every pair of users in a user's network, everyone sharing a group with the user, including the user
+// Built on user_balances_by_member, so balances are aggregated across all groups the pair shares
+// Positive values mean member_user_id owes user_id
+func (q *Queries) UserNetworkBalances(ctx context.Context, userID *int64) ([]UserNetworkBalancesRow, error) {
+	rows, err := q.db.Query(ctx, userNetworkBalances, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []UserNetworkBalancesRow{}
+	for rows.Next() {
+		var i UserNetworkBalancesRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.UserName,
+			&i.MemberUserID,
+			&i.NetBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -161,7 +162,9 @@ func ExtractTokenFromHeader(authHeader string) (string, error) {
 }
 
 // SetUserID sets the user ID in the context
+// The user is also recorded as the actor on audit events for changes made with the context
 func SetUserID(ctx context.Context, userID int64) context.Context {
+	ctx = db.WithAuditActor(ctx, userID)
 	return context.WithValue(ctx, UserIDKey, userID)
 }
 
@@ -195,6 +198,39 @@ func ValidateRefreshToken(ctx context.Context, querier db.Querier, token string)
 	return refreshToken.UserID, nil
 }
 
+// RotateRefreshToken revokes a refresh token and stores newToken in its family, returning the user ID
+// The device and IP address of the client refreshing are recorded on the new token
+// Presenting a token that was already revoked means it was stolen, so its whole family is revoked and ErrRevokedToken is returned
+func RotateRefreshToken(ctx context.Context, store db.Store, token, newToken string, expiresAt time.Time, deviceInfo, ipAddress *string) (int64, error) {
+	result, err := store.RotateRefreshTokenTx(ctx, db.RotateRefreshTokenTxParams{
+		TokenHash:    HashRefreshToken(token),
+		NewTokenHash: HashRefreshToken(newToken),
+		ExpiresAt:    expiresAt,
+		DeviceInfo:   deviceInfo,
+		IpAddress:    ipAddress,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRefreshTokenNotFound) {
+			return 0, ErrInvalidToken
+		}
+		if errors.Is(err, db.ErrRefreshTokenExpired) {
+			return 0, ErrExpiredToken
+		}
+		return 0, err
+	}
+
+	if result.Reused {
+		logger.Warn("Revoked refresh token reused, revoked token family",
+			"user_id", result.OldToken.UserID,
+			"family_id", result.OldToken.FamilyID.String(),
+			"revoked_tokens", result.RevokedFamily,
+		)
+		return 0, ErrRevokedToken
+	}
+
+	return result.NewToken.UserID, nil
+}
+
 // RevokeRefreshToken revokes a refresh token in the database
 func RevokeRefreshToken(ctx context.Context, querier db.Querier, token string) error {
 	tokenHash := HashRefreshToken(token)
@@ -47,6 +47,49 @@ func CheckGroupMembership(ctx context.Context, store db.Store, groupID, userID i
 	return nil
 }
 
+// CheckActsForMember verifies that a user may act for a group member, e.g. to confirm a payment the member received
+// Members linked to a user can only be acted for by that user, members without an account by any current group member
+// Returns error if the user may not act for the member, nil otherwise
+func CheckActsForMember(ctx context.Context, store db.Store, groupID, memberID, userID int64) error {
+	members, err := store.ListGroupMembersByGroupID(ctx, db.ListGroupMembersByGroupIDParams{
+		GroupID: groupID,
+		Limit:   1000, // reasonable limit
+		Offset:  0,
+	})
+	if err != nil {
+		logger.Error("Failed to check group membership", "error", err, "group_id", groupID, "user_id", userID)
+		return errors.New("failed to verify group membership")
+	}
+
+	var isMember bool
+	var member *db.ListGroupMembersByGroupIDRow
+	for i := range members {
+		if members[i].UserID != nil && *members[i].UserID == userID {
+			isMember = true
+		}
+		if members[i].ID == memberID {
+			member = &members[i]
+		}
+	}
+
+	if !isMember {
+		logger.Warn("User is not a member of group", "group_id", groupID, "user_id", userID)
+		return errors.New("user is not a member of this group")
+	}
+
+	if member == nil {
+		logger.Warn("Member is not in group", "group_id", groupID, "member_id", memberID)
+		return errors.New("member is not in this group")
+	}
+
+	if member.UserID != nil && *member.UserID != userID {
+		logger.Warn("User attempted to act for another member", "group_id", groupID, "member_id", memberID, "user_id", userID)
+		return errors.New("user may not act for this member")
+	}
+
+	return nil
+}
+
 // CheckOwnUser verifies that the path user_id matches the authenticated user
 func CheckOwnUser(authenticatedUserID, pathUserID int64) error {
 	if authenticatedUserID != pathUserID {
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenTypeEmailVerification is the token type of email verification tokens
+const TokenTypeEmailVerification = "email_verification"
+
+// EmailVerificationClaims are the claims of an email verification token
+// The email is included so a token stops working if the user's email changes
+type EmailVerificationClaims struct {
+	UserID    int64  `json:"user_id"`
+	Email     string `json:"email"`
+	TokenType string `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+// GenerateEmailVerificationToken generates a signed token confirming a user owns an email address
+// Tokens expire after EMAIL_VERIFICATION_EXPIRATION_HOURS, 24 hours by default
+func GenerateEmailVerificationToken(userID int64, email string) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", errors.New("JWT_SECRET environment variable is not set")
+	}
+
+	expirationHours := 24 // default 24 hours
+	if expStr := os.Getenv("EMAIL_VERIFICATION_EXPIRATION_HOURS"); expStr != "" {
+		if parsed, err := strconv.Atoi(expStr); err == nil {
+			expirationHours = parsed
+		}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(expirationHours) * time.Hour)
+
+	claims := EmailVerificationClaims{
+		UserID:    userID,
+		Email:     email,
+		TokenType: TokenTypeEmailVerification,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign email verification token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// ValidateEmailVerificationToken validates an email verification token and returns the user ID & email it was issued for
+func ValidateEmailVerificationToken(tokenString string) (int64, string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return 0, "", errors.New("JWT_SECRET environment variable is not set")
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &EmailVerificationClaims{}, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return 0, "", ErrExpiredToken
+		}
+		return 0, "", fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	claims, ok := token.Claims.(*EmailVerificationClaims)
+	if !ok || !token.Valid {
+		return 0, "", ErrInvalidToken
+	}
+
+	// Ensure this is an email verification token, access & CSRF tokens are signed with the same secret
+	if claims.TokenType != TokenTypeEmailVerification {
+		return 0, "", ErrInvalidToken
+	}
+
+	return claims.UserID, claims.Email, nil
+}
+
+// EmailVerificationRequired reports whether users must verify their email before creating groups
+// Set with REQUIRE_EMAIL_VERIFICATION
+func EmailVerificationRequired() bool {
+	required := os.Getenv("REQUIRE_EMAIL_VERIFICATION")
+	return required == "true" || required == "1"
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+)
+
+// GroupRole is a member's role in a group, it decides which group permissions they have
+type GroupRole string
+
+const (
+	GroupRoleOwner  GroupRole = "owner"  // Created the group or was given ownership, one per group
+	GroupRoleAdmin  GroupRole = "admin"  // Manages the group, its members and every transaction
+	GroupRoleMember GroupRole = "member" // Adds transactions & settlements and edits the transactions they paid
+	GroupRoleViewer GroupRole = "viewer" // Read only
+)
+
+// GroupPermission is an action in a group that only some roles may take
+// Every role may view the group, its members, transactions and balances
+type GroupPermission string
+
+const (
+	PermissionEditGroup          GroupPermission = "edit_group"           // Rename the group
+	PermissionDeleteGroup        GroupPermission = "delete_group"         // Delete the group
+	PermissionManageMembers      GroupPermission = "manage_members"       // Add, edit & remove members and change their roles
+	PermissionReplaceMembers     GroupPermission = "replace_members"      // Replace or remove every member at once
+	PermissionTransferOwnership  GroupPermission = "transfer_ownership"   // Make another member the owner
+	PermissionAddTransactions    GroupPermission = "add_transactions"     // Add transactions & settlements, edit the transactions the user paid
+	PermissionEditAnyTransaction GroupPermission = "edit_any_transaction" // Edit & delete transactions and settlements of other members
+)
+
+var (
+	ErrNotGroupMember   = errors.New("user is not a member of this group")
+	ErrPermissionDenied = errors.New("user's group role does not allow this action")
+)
+
+// rolePermissions lists the permissions of each role
+var rolePermissions = map[GroupRole][]GroupPermission{
+	GroupRoleOwner: {
+		PermissionEditGroup,
+		PermissionDeleteGroup,
+		PermissionManageMembers,
+		PermissionReplaceMembers,
+		PermissionTransferOwnership,
+		PermissionAddTransactions,
+		PermissionEditAnyTransaction,
+	},
+	GroupRoleAdmin: {
+		PermissionEditGroup,
+		PermissionManageMembers,
+		PermissionAddTransactions,
+		PermissionEditAnyTransaction,
+	},
+	GroupRoleMember: {
+		PermissionAddTransactions,
+	},
+}
+
+// ParseGroupRole validates a role from an API request
+func ParseGroupRole(role string) (GroupRole, error) {
+	switch GroupRole(role) {
+	case GroupRoleOwner, GroupRoleAdmin, GroupRoleMember, GroupRoleViewer:
+		return GroupRole(role), nil
+	}
+	return "", fmt.Errorf("role must be owner, admin, member or viewer, got %q", role)
+}
+
+// HasPermission reports whether a role includes a permission
+func HasPermission(role GroupRole, permission GroupPermission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// GetGroupMemberForUser returns the group member linked to a user
+// Returns ErrNotGroupMember if the user is not a member of the group
+func GetGroupMemberForUser(ctx context.Context, store db.Store, groupID, userID int64) (db.ListGroupMembersByGroupIDRow, error) {
+	members, err := store.ListGroupMembersByGroupID(ctx, db.ListGroupMembersByGroupIDParams{
+		GroupID: groupID,
+		Limit:   1000, // reasonable limit
+		Offset:  0,
+	})
+	if err != nil {
+		logger.Error("Failed to check group membership", "error", err, "group_id", groupID, "user_id", userID)
+		return db.ListGroupMembersByGroupIDRow{}, errors.New("failed to verify group membership")
+	}
+
+	for _, member := range members {
+		if member.UserID != nil && *member.UserID == userID {
+			return member, nil
+		}
+	}
+
+	logger.Warn("User is not a member of group", "group_id", groupID, "user_id", userID)
+	return db.ListGroupMembersByGroupIDRow{}, ErrNotGroupMember
+}
+
+// CheckGroupPermission verifies that a user is a group member whose role includes permission
+// Returns the user's group member, or ErrNotGroupMember / ErrPermissionDenied
+func CheckGroupPermission(ctx context.Context, store db.Store, groupID, userID int64, permission GroupPermission) (db.ListGroupMembersByGroupIDRow, error) {
+	member, err := GetGroupMemberForUser(ctx, store, groupID, userID)
+	if err != nil {
+		return member, err
+	}
+
+	if !HasPermission(GroupRole(member.Role), permission) {
+		logger.Warn("User's group role does not allow action", "group_id", groupID, "user_id", userID, "role", member.Role, "permission", permission)
+		return member, ErrPermissionDenied
+	}
+
+	return member, nil
+}
+
+// CheckTransactionPermission verifies that a user may edit or delete a transaction
+// Members may edit the transactions they paid (by_user or one of its payers), other members' transactions need PermissionEditAnyTransaction
+func CheckTransactionPermission(ctx context.Context, store db.Store, transaction db.Transaction, userID int64) error {
+	member, err := CheckGroupPermission(ctx, store, transaction.GroupID, userID, PermissionAddTransactions)
+	if err != nil {
+		return err
+	}
+
+	if member.ID == transaction.ByUser || HasPermission(GroupRole(member.Role), PermissionEditAnyTransaction) {
+		return nil
+	}
+
+	payers, err := store.ListTransactionPayersByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		logger.Error("Failed to check transaction payers", "error", err, "transaction_id", transaction.ID, "user_id", userID)
+		return errors.New("failed to verify transaction payers")
+	}
+	for _, payer := range payers {
+		if payer.MemberID == member.ID {
+			return nil
+		}
+	}
+
+	logger.Warn("User attempted to edit another member's transaction", "transaction_id", transaction.ID, "group_id", transaction.GroupID, "user_id", userID, "role", member.Role)
+	return ErrPermissionDenied
+}
+
+// CanAssignRole reports whether a member with role assigner may give another member role
+// Owners assign admins, members and viewers, admins assign members and viewers
+// Nobody is assigned owner directly, ownership is transferred instead
+func CanAssignRole(assigner, role GroupRole) bool {
+	switch role {
+	case GroupRoleAdmin:
+		return assigner == GroupRoleOwner
+	case GroupRoleMember, GroupRoleViewer:
+		return HasPermission(assigner, PermissionManageMembers)
+	}
+	return false
+}
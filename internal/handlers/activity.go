@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+)
+
+// List audit events for group, newest first
+// GET /groups/{group_id}/activity
+func getGroupActivity(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} from path parameter
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user is a member of the group
+		if err := auth.CheckGroupMembership(r.Context(), store, groupID, userID); err != nil {
+			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+			return
+		}
+
+		// Parse query parameters
+		limit, offset, err := ParseLimitOffset(r)
+		if err != nil {
+			http.Error(w, "Invalid parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		listParams := db.ListAuditEventsByGroupIDParams{
+			GroupID: &groupID,
+			Limit:   limit,
+			Offset:  offset,
+		}
+
+		logger.Debug("Listing group activity", "group_id", groupID, "limit", limit, "offset", offset)
+
+		events, err := store.ListAuditEventsByGroupID(r.Context(), listParams)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list group activity", "group_id", groupID) {
+			return
+		}
+
+		eventResponses := make([]models.AuditEventResponse, len(events))
+		for i, event := range events {
+			eventResponses[i] = models.AuditEventResponse{
+				ID:          event.ID,
+				ActorUserID: event.ActorUserID,
+				ActorName:   event.ActorName,
+				Entity:      event.Entity,
+				EntityID:    event.EntityID,
+				Action:      event.Action,
+				Before:      auditRow(event.Before),
+				After:       auditRow(event.After),
+				CreatedAt:   event.CreatedAt,
+			}
+		}
+
+		listEventResponse := models.ListAuditEventResponse{
+			Events: eventResponses,
+			Count:  int32(len(eventResponses)),
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		if err := WriteJSONResponseOK(w, listEventResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// auditRow converts a JSONB row snapshot to a raw JSON response value, a missing snapshot is returned as null
+func auditRow(row []byte) json.RawMessage {
+	if len(row) == 0 {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(row)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGroupActivity(t *testing.T) {
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "viewer"},
+	}
+	actorName := "Alice"
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestURL     string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				events := []db.ListAuditEventsByGroupIDRow{
+					{
+						ID:          2,
+						GroupID:     int64Ptr(1),
+						ActorUserID: int64Ptr(1),
+						Entity:      "transaction",
+						EntityID:    5,
+						Action:      "update",
+						Before:      []byte(`{"id": 5, "amount": 10.00}`),
+						After:       []byte(`{"id": 5, "amount": 12.00}`),
+						CreatedAt:   time.Now(),
+						ActorName:   &actorName,
+					},
+					{
+						ID:        1,
+						GroupID:   int64Ptr(1),
+						Entity:    "transaction",
+						EntityID:  5,
+						Action:    "create",
+						After:     []byte(`{"id": 5, "amount": 10.00}`),
+						CreatedAt: time.Now(),
+					},
+				}
+				ms.On("ListAuditEventsByGroupID", mock.Anything, db.ListAuditEventsByGroupIDParams{GroupID: int64Ptr(1), Limit: 20, Offset: 0}).Return(events, nil)
+			},
+			requestURL:     "/groups/1/activity?limit=20",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not a group member",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return([]db.ListGroupMembersByGroupIDRow{}, nil)
+			},
+			requestURL:     "/groups/1/activity",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "invalid offset parameter",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestURL:     "/groups/1/activity?offset=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListAuditEventsByGroupID", mock.Anything, db.ListAuditEventsByGroupIDParams{GroupID: int64Ptr(1), Limit: 100, Offset: 0}).Return(nil, errors.New("database error"))
+			},
+			requestURL:     "/groups/1/activity",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", tt.requestURL, nil, 1)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := getGroupActivity(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Events []map[string]interface{} `json:"events"`
+					Count  int32                    `json:"count"`
+					Limit  int32                    `json:"limit"`
+				}
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Len(t, response.Events, 2)
+				assert.Equal(t, int32(2), response.Count)
+				assert.Equal(t, int32(20), response.Limit)
+
+				assert.Equal(t, "update", response.Events[0]["action"])
+				assert.Equal(t, "Alice", response.Events[0]["actor_name"])
+				assert.Equal(t, map[string]interface{}{"id": float64(5), "amount": float64(10)}, response.Events[0]["before"])
+
+				// Events without a snapshot or actor are returned with nulls
+				assert.Nil(t, response.Events[1]["before"])
+				assert.Contains(t, response.Events[1], "before")
+				assert.Nil(t, response.Events[1]["actor_user_id"])
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
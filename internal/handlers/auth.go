@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
@@ -10,27 +11,38 @@ import (
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
 	"github.com/MattSharp0/transaction-split-go/internal/auth"
 	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/mail"
 	"github.com/MattSharp0/transaction-split-go/internal/models"
 	"github.com/MattSharp0/transaction-split-go/internal/server"
 )
 
-func AuthRoutes(s *server.Server, store db.Store) *http.ServeMux {
+func AuthRoutes(s *server.Server, store db.Store, mailer mail.Sender) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// Public routes
-	mux.HandleFunc("POST /register", register(store)) // POST auth/register: Register new user
-	mux.HandleFunc("POST /login", login(store))       // POST auth/login: Login
-	mux.HandleFunc("POST /refresh", refresh(store))   // POST auth/refresh: Refresh tokens
+	mux.HandleFunc("POST /register", register(store, mailer))              // POST auth/register: Register new user
+	mux.HandleFunc("POST /login", login(store))                            // POST auth/login: Login
+	mux.HandleFunc("POST /refresh", refresh(store))                        // POST auth/refresh: Refresh tokens
+	mux.HandleFunc("POST /verify-email", verifyEmail(store))               // POST auth/verify-email: Verify email with token
+	mux.HandleFunc("POST /password/forgot", forgotPassword(store, mailer)) // POST auth/password/forgot: Email a password reset token
+	mux.HandleFunc("POST /password/reset", resetPassword(store))           // POST auth/password/reset: Set a new password with a reset token
+	mux.HandleFunc("POST /magic-link", requestMagicLink(store, mailer))    // POST auth/magic-link: Email a login link
+	mux.HandleFunc("GET /magic-link/callback", magicLinkCallback(store))   // GET auth/magic-link/callback: Log in with a login link token
 
 	// Protected routes
-	mux.HandleFunc("GET /me", auth.RequireAuth(http.HandlerFunc(getMe(store))).ServeHTTP)                         // GET auth/me: Get current user
-	mux.HandleFunc("POST /logout", auth.RequireAuth(auth.RequireCSRF(http.HandlerFunc(logout(store)))).ServeHTTP) // POST auth/logout: Logout
-	mux.HandleFunc("GET /csrf-token", auth.RequireAuth(http.HandlerFunc(getCSRFToken())).ServeHTTP)               // GET auth/csrf-token: Get CSRF token
+	mux.HandleFunc("GET /me", auth.RequireAuth(http.HandlerFunc(getMe(store))).ServeHTTP)                                                               // GET auth/me: Get current user
+	mux.HandleFunc("POST /logout", auth.RequireAuth(auth.RequireCSRF(http.HandlerFunc(logout(store)))).ServeHTTP)                                       // POST auth/logout: Logout
+	mux.HandleFunc("GET /csrf-token", auth.RequireAuth(http.HandlerFunc(getCSRFToken())).ServeHTTP)                                                     // GET auth/csrf-token: Get CSRF token
+	mux.HandleFunc("POST /verify-email/resend", auth.RequireAuth(auth.RequireCSRF(http.HandlerFunc(resendVerificationEmail(store, mailer)))).ServeHTTP) // POST auth/verify-email/resend: Resend verification email
+	mux.HandleFunc("POST /password/change", auth.RequireAuth(auth.RequireCSRF(http.HandlerFunc(changePassword(store)))).ServeHTTP)                      // POST auth/password/change: Change password, ending other sessions
+	mux.HandleFunc("GET /sessions", auth.RequireAuth(http.HandlerFunc(listSessions(store))).ServeHTTP)                                                  // GET auth/sessions: List active sessions
+	mux.HandleFunc("DELETE /sessions/others", auth.RequireAuth(auth.RequireCSRF(http.HandlerFunc(revokeOtherSessions(store)))).ServeHTTP)               // DELETE auth/sessions/others: Log out everywhere else
+	mux.HandleFunc("DELETE /sessions/{id}", auth.RequireAuth(auth.RequireCSRF(http.HandlerFunc(revokeSession(store)))).ServeHTTP)                       // DELETE auth/sessions/{id}: Log out a session
 
 	return mux
 }
 
-func register(store db.Store) http.HandlerFunc {
+func register(store db.Store, mailer mail.Sender) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req models.RegisterRequest
 		if err := DecodeJSONBody(r, &req); err != nil {
@@ -86,76 +98,19 @@ func register(store db.Store) http.HandlerFunc {
 
 		logger.Debug("User registered successfully", slog.Int64("user_id", user.ID), slog.String("email", user.Email))
 
-		// Generate access and refresh tokens
-		accessToken, err := auth.GenerateAccessToken(user.ID)
-		if err != nil {
-			logger.Error("Failed to generate access token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
-			return
-		}
-
-		refreshToken, err := auth.GenerateRefreshToken()
-		if err != nil {
-			logger.Error("Failed to generate refresh token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
-			return
-		}
-
-		// Store refresh token in database
-		refreshTokenHash := auth.HashRefreshToken(refreshToken)
-		expirationDays := 7 // default 7 days
-		if expStr := os.Getenv("REFRESH_TOKEN_EXPIRATION_DAYS"); expStr != "" {
-			if parsed, err := strconv.Atoi(expStr); err == nil {
-				expirationDays = parsed
-			}
-		}
-		expiresAt := time.Now().Add(time.Duration(expirationDays) * 24 * time.Hour)
-
-		_, err = store.CreateRefreshToken(r.Context(), db.CreateRefreshTokenParams{
-			TokenHash:  refreshTokenHash,
-			UserID:     user.ID,
-			ExpiresAt:  expiresAt,
-			DeviceInfo: nil, // Could extract from User-Agent header
-		})
-		if err != nil {
-			logger.Error("Failed to store refresh token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
-			return
+		// Send verification email, registration still succeeds if it fails since the user can request another
+		if err := sendVerificationEmail(r.Context(), mailer, user); err != nil {
+			logger.Error("Failed to send verification email", "error", err, "user_id", user.ID)
 		}
 
-		// Generate CSRF token
-		csrfToken, err := auth.GenerateCSRFToken(user.ID)
-		if err != nil {
-			logger.Error("Failed to generate CSRF token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+		// Issue tokens & set cookies
+		accessToken, csrfToken, ok := startSession(w, r, store, user.ID)
+		if !ok {
 			return
 		}
 
-		// Set cookies
-		accessTokenMaxAge := 30 * 60 // 30 minutes in seconds
-		refreshTokenMaxAge := expirationDays * 24 * 60 * 60
-		csrfTokenMaxAge := 24 * 60 * 60 // 24 hours
-
-		auth.SetAuthCookie(w, auth.AccessTokenCookieName, accessToken, accessTokenMaxAge)
-		auth.SetAuthCookie(w, auth.RefreshTokenCookieName, refreshToken, refreshTokenMaxAge)
-		auth.SetCSRFCookie(w, csrfToken, csrfTokenMaxAge)
-
-		// Convert to response format
-		userResponse := models.UserResponse{
-			ID:         user.ID,
-			Name:       user.Name,
-			CreatedAt:  user.CreatedAt,
-			ModifiedAt: user.ModifiedAt,
-		}
-
-		loginResponse := models.LoginResponse{
-			Token:     accessToken, // Still return access token in JSON for header-based clients
-			User:      userResponse,
-			CSRFToken: csrfToken, // Include CSRF token in response
-		}
-
 		// Send response with 201 Created status
-		if err := WriteJSONResponseCreated(w, loginResponse); err != nil {
+		if err := WriteJSONResponseCreated(w, loginResponse(user, accessToken, csrfToken)); err != nil {
 			http.Error(w, "An error has occurred", http.StatusInternalServerError)
 			return
 		}
@@ -199,78 +154,16 @@ func login(store db.Store) http.HandlerFunc {
 
 		logger.Debug("Password verified successfully", slog.Int64("user_id", user.ID))
 
-		// Generate access and refresh tokens
-		accessToken, err := auth.GenerateAccessToken(user.ID)
-		if err != nil {
-			logger.Error("Failed to generate access token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
-			return
-		}
-
-		refreshToken, err := auth.GenerateRefreshToken()
-		if err != nil {
-			logger.Error("Failed to generate refresh token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
-			return
-		}
-
-		// Store refresh token in database
-		refreshTokenHash := auth.HashRefreshToken(refreshToken)
-		expirationDays := 7 // default 7 days
-		if expStr := os.Getenv("REFRESH_TOKEN_EXPIRATION_DAYS"); expStr != "" {
-			if parsed, err := strconv.Atoi(expStr); err == nil {
-				expirationDays = parsed
-			}
-		}
-		expiresAt := time.Now().Add(time.Duration(expirationDays) * 24 * time.Hour)
-
-		_, err = store.CreateRefreshToken(r.Context(), db.CreateRefreshTokenParams{
-			TokenHash:  refreshTokenHash,
-			UserID:     user.ID,
-			ExpiresAt:  expiresAt,
-			DeviceInfo: nil, // TODO: extract from User-Agent header
-		})
-		if err != nil {
-			logger.Error("Failed to store refresh token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
-			return
-		}
-
-		// Generate CSRF token
-		csrfToken, err := auth.GenerateCSRFToken(user.ID)
-		if err != nil {
-			logger.Error("Failed to generate CSRF token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+		// Issue tokens & set cookies
+		accessToken, csrfToken, ok := startSession(w, r, store, user.ID)
+		if !ok {
 			return
 		}
 
-		// Set cookies
-		accessTokenMaxAge := 30 * 60 // 30 minutes in seconds
-		refreshTokenMaxAge := expirationDays * 24 * 60 * 60
-		csrfTokenMaxAge := 24 * 60 * 60 // 24 hours
-
-		auth.SetAuthCookie(w, auth.AccessTokenCookieName, accessToken, accessTokenMaxAge)
-		auth.SetAuthCookie(w, auth.RefreshTokenCookieName, refreshToken, refreshTokenMaxAge)
-		auth.SetCSRFCookie(w, csrfToken, csrfTokenMaxAge)
-
-		// Convert to response format
-		userResponse := models.UserResponse{
-			ID:         user.ID,
-			Name:       user.Name,
-			CreatedAt:  user.CreatedAt,
-			ModifiedAt: user.ModifiedAt,
-		}
-
-		loginResponse := models.LoginResponse{
-			Token:     accessToken, // Still return access token in JSON for header-based clients
-			User:      userResponse,
-			CSRFToken: csrfToken, // Include CSRF token in response
-		}
-
 		logger.Debug("Login successful", slog.Int64("user_id", user.ID))
 
 		// Send response
-		if err := WriteJSONResponseOK(w, loginResponse); err != nil {
+		if err := WriteJSONResponseOK(w, loginResponse(user, accessToken, csrfToken)); err != nil {
 			http.Error(w, "An error has occurred", http.StatusInternalServerError)
 			return
 		}
@@ -292,16 +185,8 @@ func getMe(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Convert to response format
-		userResponse := models.UserResponse{
-			ID:         user.ID,
-			Name:       user.Name,
-			CreatedAt:  user.CreatedAt,
-			ModifiedAt: user.ModifiedAt,
-		}
-
 		// Send response
-		if err := WriteJSONResponseOK(w, userResponse); err != nil {
+		if err := WriteJSONResponseOK(w, currentUserResponse(user)); err != nil {
 			http.Error(w, "An error has occurred", http.StatusInternalServerError)
 			return
 		}
@@ -328,84 +213,44 @@ func refresh(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Validate refresh token
-		userID, err := auth.ValidateRefreshToken(r.Context(), store, refreshToken)
+		newRefreshToken, err := auth.GenerateRefreshToken()
+		if err != nil {
+			logger.Error("Failed to generate refresh token", "error", err)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		// Rotate refresh token, a reused token revokes every token issued from the same login
+		expiresAt, refreshTokenMaxAge := refreshTokenExpiration()
+		userID, err := auth.RotateRefreshToken(r.Context(), store, refreshToken, newRefreshToken, expiresAt, clientDeviceInfo(r), clientIPAddress(r))
 		if err != nil {
-			if err == auth.ErrRevokedToken {
+			if errors.Is(err, auth.ErrRevokedToken) {
 				logger.Warn("Refresh token revoked")
+				auth.ClearAuthCookies(w)
 				http.Error(w, "Token revoked", http.StatusUnauthorized)
 				return
 			}
-			if err == auth.ErrExpiredToken {
+			if errors.Is(err, auth.ErrExpiredToken) {
 				logger.Warn("Refresh token expired")
 				http.Error(w, "Token expired", http.StatusUnauthorized)
 				return
 			}
-			logger.Warn("Invalid refresh token", "error", err)
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
-		}
-
-		// Revoke old refresh token (token rotation)
-		if err := auth.RevokeRefreshToken(r.Context(), store, refreshToken); err != nil {
-			logger.Warn("Failed to revoke old refresh token", "error", err)
-			// Continue anyway - token rotation is best practice but not critical
-		}
-
-		// Generate new access and refresh tokens
-		accessToken, err := auth.GenerateAccessToken(userID)
-		if err != nil {
-			logger.Error("Failed to generate access token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
-			return
-		}
-
-		newRefreshToken, err := auth.GenerateRefreshToken()
-		if err != nil {
-			logger.Error("Failed to generate refresh token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
-			return
-		}
-
-		// Store new refresh token in database
-		refreshTokenHash := auth.HashRefreshToken(newRefreshToken)
-		expirationDays := 7 // default 7 days
-		if expStr := os.Getenv("REFRESH_TOKEN_EXPIRATION_DAYS"); expStr != "" {
-			if parsed, err := strconv.Atoi(expStr); err == nil {
-				expirationDays = parsed
+			if errors.Is(err, auth.ErrInvalidToken) {
+				logger.Warn("Invalid refresh token", "error", err)
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
 			}
-		}
-		expiresAt := time.Now().Add(time.Duration(expirationDays) * 24 * time.Hour)
-
-		_, err = store.CreateRefreshToken(r.Context(), db.CreateRefreshTokenParams{
-			TokenHash:  refreshTokenHash,
-			UserID:     userID,
-			ExpiresAt:  expiresAt,
-			DeviceInfo: nil,
-		})
-		if err != nil {
-			logger.Error("Failed to store refresh token", "error", err)
+			logger.Error("Failed to rotate refresh token", "error", err)
 			http.Error(w, "An error has occurred", http.StatusInternalServerError)
 			return
 		}
 
-		// Generate new CSRF token
-		csrfToken, err := auth.GenerateCSRFToken(userID)
-		if err != nil {
-			logger.Error("Failed to generate CSRF token", "error", err)
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+		// Issue tokens & set cookies
+		accessToken, csrfToken, ok := setSessionCookies(w, userID, newRefreshToken, refreshTokenMaxAge)
+		if !ok {
 			return
 		}
 
-		// Set new cookies
-		accessTokenMaxAge := 30 * 60 // 30 minutes in seconds
-		refreshTokenMaxAge := expirationDays * 24 * 60 * 60
-		csrfTokenMaxAge := 24 * 60 * 60 // 24 hours
-
-		auth.SetAuthCookie(w, auth.AccessTokenCookieName, accessToken, accessTokenMaxAge)
-		auth.SetAuthCookie(w, auth.RefreshTokenCookieName, newRefreshToken, refreshTokenMaxAge)
-		auth.SetCSRFCookie(w, csrfToken, csrfTokenMaxAge)
-
 		refreshResponse := models.RefreshResponse{
 			Token:     accessToken,
 			CSRFToken: csrfToken,
@@ -496,3 +341,87 @@ func getCSRFToken() http.HandlerFunc {
 		}
 	}
 }
+
+// loginResponse converts a user and the tokens of their new session to response format
+func loginResponse(user db.User, accessToken, csrfToken string) models.LoginResponse {
+	return models.LoginResponse{
+		Token: accessToken, // Still return access token in JSON for header-based clients
+		User: models.UserResponse{
+			ID:         user.ID,
+			Name:       user.Name,
+			CreatedAt:  user.CreatedAt,
+			ModifiedAt: user.ModifiedAt,
+		},
+		CSRFToken: csrfToken, // Include CSRF token in response
+	}
+}
+
+// startSession issues an access, refresh & CSRF token for the user, stores the refresh token and sets the auth cookies
+// The refresh token starts a new token family, refreshing rotates it within the family
+// Writes an error response and returns false if the session can't be started
+func startSession(w http.ResponseWriter, r *http.Request, store db.Store, userID int64) (string, string, bool) {
+	refreshToken, err := auth.GenerateRefreshToken()
+	if err != nil {
+		logger.Error("Failed to generate refresh token", "error", err)
+		http.Error(w, "An error has occurred", http.StatusInternalServerError)
+		return "", "", false
+	}
+
+	// Store refresh token in database
+	expiresAt, refreshTokenMaxAge := refreshTokenExpiration()
+	_, err = store.CreateRefreshToken(r.Context(), db.CreateRefreshTokenParams{
+		TokenHash:  auth.HashRefreshToken(refreshToken),
+		UserID:     userID,
+		ExpiresAt:  expiresAt,
+		DeviceInfo: clientDeviceInfo(r),
+		IpAddress:  clientIPAddress(r),
+	})
+	if err != nil {
+		logger.Error("Failed to store refresh token", "error", err)
+		http.Error(w, "An error has occurred", http.StatusInternalServerError)
+		return "", "", false
+	}
+
+	return setSessionCookies(w, userID, refreshToken, refreshTokenMaxAge)
+}
+
+// refreshTokenExpiration returns when a refresh token issued now expires and its cookie max age in seconds
+func refreshTokenExpiration() (time.Time, int) {
+	expirationDays := 7 // default 7 days
+	if expStr := os.Getenv("REFRESH_TOKEN_EXPIRATION_DAYS"); expStr != "" {
+		if parsed, err := strconv.Atoi(expStr); err == nil {
+			expirationDays = parsed
+		}
+	}
+	return time.Now().Add(time.Duration(expirationDays) * 24 * time.Hour), expirationDays * 24 * 60 * 60
+}
+
+// setSessionCookies issues an access & CSRF token for the user and sets them in cookies with an already stored refresh token
+// Writes an error response and returns false if the tokens can't be issued
+func setSessionCookies(w http.ResponseWriter, userID int64, refreshToken string, refreshTokenMaxAge int) (string, string, bool) {
+	// Generate access token
+	accessToken, err := auth.GenerateAccessToken(userID)
+	if err != nil {
+		logger.Error("Failed to generate access token", "error", err)
+		http.Error(w, "An error has occurred", http.StatusInternalServerError)
+		return "", "", false
+	}
+
+	// Generate CSRF token
+	csrfToken, err := auth.GenerateCSRFToken(userID)
+	if err != nil {
+		logger.Error("Failed to generate CSRF token", "error", err)
+		http.Error(w, "An error has occurred", http.StatusInternalServerError)
+		return "", "", false
+	}
+
+	// Set cookies
+	accessTokenMaxAge := 30 * 60    // 30 minutes in seconds
+	csrfTokenMaxAge := 24 * 60 * 60 // 24 hours
+
+	auth.SetAuthCookie(w, auth.AccessTokenCookieName, accessToken, accessTokenMaxAge)
+	auth.SetAuthCookie(w, auth.RefreshTokenCookieName, refreshToken, refreshTokenMaxAge)
+	auth.SetCSRFCookie(w, csrfToken, csrfTokenMaxAge)
+
+	return accessToken, csrfToken, true
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+)
+
+// List group transactions whose splits don't sum to the transaction amount, or were split from a different amount
+// GET /groups/{group_id}/transactions/inconsistent
+func listInconsistentGroupTransactions(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} from path parameter
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user is a member of the group
+		if err := auth.CheckGroupMembership(r.Context(), store, groupID, userID); err != nil {
+			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+			return
+		}
+
+		// Parse query parameters
+		limit, offset, err := ParseLimitOffset(r)
+		if err != nil {
+			http.Error(w, "Invalid parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		listParams := db.ListInconsistentTransactionsByGroupIDParams{
+			GroupID: groupID,
+			Limit:   limit,
+			Offset:  offset,
+		}
+
+		logger.Debug("Listing inconsistent transactions for group", "group_id", groupID, "limit", limit, "offset", offset)
+
+		transactions, err := store.ListInconsistentTransactionsByGroupID(r.Context(), listParams)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list inconsistent transactions", "group_id", groupID) {
+			return
+		}
+
+		if len(transactions) > 0 {
+			logger.Warn("Group has transactions whose splits don't sum to their amount", "group_id", groupID, "count", len(transactions))
+		}
+
+		transactionResponses := make([]models.InconsistentTransactionResponse, len(transactions))
+		for i, tx := range transactions {
+			transactionResponses[i] = models.InconsistentTransactionResponse{
+				TransactionID:   tx.ID,
+				Name:            tx.Name,
+				TransactionDate: tx.TransactionDate,
+				Amount:          tx.Amount,
+				SplitMode:       tx.SplitMode,
+				SplitCount:      tx.SplitCount,
+				SplitTotal:      tx.SplitTotal,
+				Difference:      tx.Amount.Sub(tx.SplitTotal),
+				StaleSplitCount: tx.StaleSplitCount,
+			}
+		}
+
+		listTransactionResponse := models.ListInconsistentTransactionResponse{
+			Transactions: transactionResponses,
+			Count:        int32(len(transactionResponses)),
+			Limit:        limit,
+			Offset:       offset,
+		}
+
+		if err := WriteJSONResponseOK(w, listTransactionResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
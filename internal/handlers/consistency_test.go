@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListInconsistentGroupTransactions(t *testing.T) {
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "viewer"},
+	}
+	percentMode := "percent"
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestURL     string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				rows := []db.ListInconsistentTransactionsByGroupIDRow{
+					{
+						ID:              5,
+						Name:            "Dinner",
+						TransactionDate: time.Now(),
+						Amount:          decimal.NewFromFloat(120.00),
+						SplitMode:       &percentMode,
+						SplitCount:      2,
+						SplitTotal:      decimal.NewFromFloat(100.00),
+						StaleSplitCount: 2,
+					},
+				}
+				ms.On("ListInconsistentTransactionsByGroupID", mock.Anything, db.ListInconsistentTransactionsByGroupIDParams{GroupID: 1, Limit: 20, Offset: 0}).Return(rows, nil)
+			},
+			requestURL:     "/groups/1/transactions/inconsistent?limit=20",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "not a group member",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return([]db.ListGroupMembersByGroupIDRow{}, nil)
+			},
+			requestURL:     "/groups/1/transactions/inconsistent",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "invalid limit parameter",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestURL:     "/groups/1/transactions/inconsistent?limit=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListInconsistentTransactionsByGroupID", mock.Anything, db.ListInconsistentTransactionsByGroupIDParams{GroupID: 1, Limit: 100, Offset: 0}).Return(nil, errors.New("database error"))
+			},
+			requestURL:     "/groups/1/transactions/inconsistent",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", tt.requestURL, nil, 1)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := listInconsistentGroupTransactions(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.ListInconsistentTransactionResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Len(t, response.Transactions, 1)
+				assert.Equal(t, int32(1), response.Count)
+				assert.Equal(t, int64(5), response.Transactions[0].TransactionID)
+				assert.True(t, decimal.NewFromFloat(20.00).Equal(response.Transactions[0].Difference))
+				assert.Equal(t, int64(2), response.Transactions[0].StaleSplitCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/mail"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// Verify a user's email with the token from their verification email
+// POST /auth/verify-email
+func verifyEmail(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.VerifyEmailRequest
+		if err := DecodeJSONBody(r, &req); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if req.Token == "" {
+			http.Error(w, "Token is required", http.StatusBadRequest)
+			return
+		}
+
+		userID, email, err := auth.ValidateEmailVerificationToken(req.Token)
+		if err != nil {
+			if errors.Is(err, auth.ErrExpiredToken) {
+				logger.Warn("Email verification token expired")
+				http.Error(w, "Verification token expired, request a new verification email", http.StatusBadRequest)
+				return
+			}
+			logger.Warn("Invalid email verification token", "error", err)
+			http.Error(w, "Invalid verification token", http.StatusBadRequest)
+			return
+		}
+
+		// The token only verifies the email it was issued for
+		user, err := store.VerifyUserEmail(r.Context(), db.VerifyUserEmailParams{ID: userID, Email: email})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				logger.Warn("Email verification token for a changed email or deleted user", "user_id", userID)
+				http.Error(w, "Invalid verification token", http.StatusBadRequest)
+				return
+			}
+			logger.Error("Failed to verify email", "error", err, "user_id", userID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("Email verified", slog.Int64("user_id", user.ID))
+
+		if err := WriteJSONResponseOK(w, currentUserResponse(user)); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Send the authenticated user a new verification email
+// POST /auth/verify-email/resend
+func resendVerificationEmail(store db.Store, mailer mail.Sender) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		user, err := store.GetUserByID(r.Context(), userID)
+		if HandleDBError(w, err, "User not found", "An error has occurred", "Failed to get user by ID", "user_id", userID) {
+			return
+		}
+
+		if user.EmailVerified {
+			http.Error(w, "Email already verified", http.StatusConflict)
+			return
+		}
+
+		if err := sendVerificationEmail(r.Context(), mailer, user); err != nil {
+			logger.Error("Failed to send verification email", "error", err, "user_id", userID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		if err := WriteJSONResponseOK(w, map[string]string{"message": "Verification email sent"}); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// sendVerificationEmail emails the user a token to verify their email address
+func sendVerificationEmail(ctx context.Context, mailer mail.Sender, user db.User) error {
+	token, err := auth.GenerateEmailVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return err
+	}
+
+	err = mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body:    tokenEmailBody(user.Name, "confirm your email address", os.Getenv("APP_URL"), "/verify-email", token, "If you didn't create an account, you can ignore this email."),
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Verification email sent", slog.Int64("user_id", user.ID))
+	return nil
+}
+
+// tokenEmailBody builds the body of an email carrying a token for the user to act on
+// The email links to baseURL followed by path when baseURL is set, otherwise it contains only the token
+func tokenEmailBody(name, action, baseURL, path, token, footer string) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", name)
+	if baseURL != "" {
+		fmt.Fprintf(&body, "To %s, open this link:\n\n%s%s?token=%s\n", action, strings.TrimSuffix(baseURL, "/"), path, url.QueryEscape(token))
+	} else {
+		fmt.Fprintf(&body, "To %s, use this token:\n\n%s\n", action, token)
+	}
+	fmt.Fprintf(&body, "\n%s\n", footer)
+	return body.String()
+}
+
+// currentUserResponse converts a user to the response returned to the user themselves
+func currentUserResponse(user db.User) models.CurrentUserResponse {
+	return models.CurrentUserResponse{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		CreatedAt:     user.CreatedAt,
+		ModifiedAt:    user.ModifiedAt,
+	}
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEmail(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	validToken, err := auth.GenerateEmailVerificationToken(1, "alice@example.com")
+	require.NoError(t, err)
+	accessToken, err := auth.GenerateAccessToken(1)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestBody    string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("VerifyUserEmail", mock.Anything, db.VerifyUserEmailParams{ID: 1, Email: "alice@example.com"}).Return(db.User{ID: 1, Name: "Alice", Email: "alice@example.com", EmailVerified: true}, nil)
+			},
+			requestBody:    `{"token": "` + validToken + `"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "email changed since the token was issued",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("VerifyUserEmail", mock.Anything, db.VerifyUserEmailParams{ID: 1, Email: "alice@example.com"}).Return(db.User{}, pgx.ErrNoRows)
+			},
+			requestBody:    `{"token": "` + validToken + `"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "access token is not a verification token",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    `{"token": "` + accessToken + `"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid token",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    `{"token": "not-a-token"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing token",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("VerifyUserEmail", mock.Anything, db.VerifyUserEmailParams{ID: 1, Email: "alice@example.com"}).Return(db.User{}, errors.New("database error"))
+			},
+			requestBody:    `{"token": "` + validToken + `"}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequest("POST", "/auth/verify-email", []byte(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			handler := verifyEmail(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, true, response["email_verified"])
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("APP_URL", "https://split.example.com/")
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		sendErr        error
+		expectedStatus int
+		expectSent     bool
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(db.User{ID: 1, Name: "Alice", Email: "alice@example.com"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectSent:     true,
+		},
+		{
+			name: "already verified",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(db.User{ID: 1, Name: "Alice", Email: "alice@example.com", EmailVerified: true}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "user not found",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(db.User{}, pgx.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "mail sender error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(db.User{ID: 1, Name: "Alice", Email: "alice@example.com"}, nil)
+			},
+			sendErr:        errors.New("smtp unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+			mailer := &fakeMailSender{err: tt.sendErr}
+
+			req := createRequestWithUserID("POST", "/auth/verify-email/resend", nil, 1)
+			rr := httptest.NewRecorder()
+
+			handler := resendVerificationEmail(mockStore, mailer)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectSent {
+				require.Len(t, mailer.messages, 1)
+				assert.Equal(t, "alice@example.com", mailer.messages[0].To)
+				assert.Contains(t, mailer.messages[0].Body, "https://split.example.com/verify-email?token=")
+
+				// The emailed token verifies the user's current email
+				body := mailer.messages[0].Body
+				start := strings.Index(body, "token=") + len("token=")
+				token := strings.Fields(body[start:])[0]
+				userID, email, err := auth.ValidateEmailVerificationToken(token)
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), userID)
+				assert.Equal(t, "alice@example.com", email)
+			} else {
+				assert.Empty(t, mailer.messages)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
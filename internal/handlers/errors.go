@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
 	"github.com/MattSharp0/transaction-split-go/internal/logger"
 	"github.com/jackc/pgx/v5"
 )
@@ -64,3 +66,62 @@ func HandleDBListError(w http.ResponseWriter, err error, errorMessage, logMessag
 	http.Error(w, errorMessage, http.StatusInternalServerError)
 	return true
 }
+
+// HandleGroupPermissionError handles errors from group permission checks and writes a 403 Forbidden response.
+// Users who are not group members get the usual membership message, members whose role doesn't allow the action get deniedMessage.
+//
+// Parameters:
+//   - w: HTTP response writer
+//   - err: The error returned by auth.CheckGroupPermission or auth.CheckTransactionPermission
+//   - deniedMessage: Message to send when the role doesn't allow the action (e.g., "Forbidden: only the group owner can delete the group")
+//
+// Returns true if an error response was written (caller should return), false otherwise.
+func HandleGroupPermissionError(w http.ResponseWriter, err error, deniedMessage string) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, auth.ErrPermissionDenied) {
+		http.Error(w, deniedMessage, http.StatusForbidden)
+		return true
+	}
+
+	http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+	return true
+}
+
+// HandleSplitRescaleError handles errors from updating a transaction amount whose splits can't be rescaled and writes a 409 Conflict response.
+// Splits sent as exact amounts or derived from line items must be replaced, splits changed during the update must be retried.
+//
+// Returns true if an error response was written (caller should return), false otherwise.
+func HandleSplitRescaleError(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, db.ErrSplitAmountsFixed) || errors.Is(err, db.ErrSplitsChanged) {
+		logger.Debug("Transaction splits can't be rescaled", "error", err)
+		http.Error(w, "Conflict: "+err.Error(), http.StatusConflict)
+		return true
+	}
+	return false
+}
+
+// HandleMergeGroupMembersError handles errors from merging two group members and writes the appropriate HTTP response.
+// Members that can't be merged get 400 Bad Request, a missing member 404 Not Found and all other errors 500.
+//
+// Returns true if an error response was written (caller should return), false otherwise.
+func HandleMergeGroupMembersError(w http.ResponseWriter, err error, logFields ...interface{}) bool {
+	if err == nil {
+		return false
+	}
+
+	switch {
+	case errors.Is(err, db.ErrMergeSameMember),
+		errors.Is(err, db.ErrMergeMemberNotInGroup),
+		errors.Is(err, db.ErrMergeOwner),
+		errors.Is(err, db.ErrMergeIntoRemovedMember),
+		errors.Is(err, db.ErrMergeDifferentUsers):
+		logger.Debug("Group members can't be merged", append([]interface{}{"error", err}, logFields...)...)
+		http.Error(w, "Bad request: "+err.Error(), http.StatusBadRequest)
+		return true
+	}
+
+	return HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to merge group members", logFields...)
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
@@ -30,18 +33,47 @@ func GroupRoutes(s *server.Server, q db.Store) *http.ServeMux {
 	mux.HandleFunc("GET /{id}", getGroupByID(q))   // GET: Get group by ID
 	mux.HandleFunc("PUT /{id}", updateGroup(q))    // PUT: Update group
 	mux.HandleFunc("PATCH /{id}", updateGroup(q))  // PATCH: Update group
-	mux.HandleFunc("DELETE /{id}", deleteGroup(q)) // DELETE: Delete group
+	mux.HandleFunc("DELETE /{id}", deleteGroup(q)) // DELETE: Move group to the trash
+
+	// Trash Handlers
+	mux.HandleFunc("GET /trash", listDeletedGroups(q))                       // GET: List groups in the trash owned by user
+	mux.HandleFunc("POST /{id}/restore", restoreGroup(q))                    // POST: Restore group from the trash
+	mux.HandleFunc("GET /{group_id}/trash", listDeletedGroupTransactions(q)) // GET: List group transactions in the trash
 
 	// Nested resource handlers
-	mux.HandleFunc("GET /{group_id}/members", listGroupMembers(q))         // GET: List group members
-	mux.HandleFunc("POST /{group_id}/members", createGroupMemberNested(q)) // POST: Create group member
+	mux.HandleFunc("GET /{group_id}/members", listGroupMembers(q))              // GET: List group members
+	mux.HandleFunc("POST /{group_id}/members", createGroupMemberNested(q))      // POST: Create group member
+	mux.HandleFunc("POST /{group_id}/members/{id}/merge", mergeGroupMembers(q)) // POST: Merge a duplicate member into this member
+
+	// Role Handlers
+	mux.HandleFunc("PUT /{group_id}/members/{id}/role", updateGroupMemberRole(q))    // PUT: Change group member role
+	mux.HandleFunc("POST /{group_id}/transfer-ownership", transferGroupOwnership(q)) // POST: Make another member the owner
+
+	// Invitation Handlers
+	mux.HandleFunc("GET /{group_id}/invitations", listGroupInvitations(q))          // GET: List group invitations
+	mux.HandleFunc("POST /{group_id}/invitations", createGroupInvitation(q))        // POST: Invite by link or email
+	mux.HandleFunc("DELETE /{group_id}/invitations/{id}", revokeGroupInvitation(q)) // DELETE: Revoke invitation
 
-	mux.HandleFunc("GET /{group_id}/transactions", getTransactionsByGroupNested(q)) // GET: List group transactions
-	mux.HandleFunc("POST /{group_id}/transactions", createTransactionNested(q))     // POST: Create transaction in group
+	mux.HandleFunc("GET /{group_id}/transactions", getTransactionsByGroupNested(q))                   // GET: List group transactions
+	mux.HandleFunc("POST /{group_id}/transactions", createTransactionNested(q))                       // POST: Create transaction in group
+	mux.HandleFunc("GET /{group_id}/transactions/inconsistent", listInconsistentGroupTransactions(q)) // GET: List transactions whose splits don't add up
+
+	// Activity Handlers
+	mux.HandleFunc("GET /{group_id}/activity", getGroupActivity(q)) // GET: List changes to the group, newest first
 
 	// Balance Handlers
 	mux.HandleFunc("GET /{group_id}/balances", getGroupBalances(q)) // GET: Get group balances
 
+	// Settlement Handlers
+	mux.HandleFunc("GET /{group_id}/settlements", listGroupSettlements(q))                                                                     // GET: List group settlements
+	mux.HandleFunc("POST /{group_id}/settlements", createGroupSettlement(q))                                                                   // POST: Record settlement between members
+	mux.HandleFunc("POST /{group_id}/settlements/settle-all", settleAllGroupBalances(q))                                                       // POST: Record all simplified payments
+	mux.HandleFunc("GET /{group_id}/settlements/{settlement_id}", getGroupSettlementByID(q))                                                   // GET: Get settlement by ID
+	mux.HandleFunc("DELETE /{group_id}/settlements/{settlement_id}", deleteGroupSettlement(q))                                                 // DELETE: Delete settlement
+	mux.HandleFunc("POST /{group_id}/settlements/{settlement_id}/confirm", updateGroupSettlementStatus(q, services.SettlementStatusConfirmed)) // POST: Recipient confirms settlement
+	mux.HandleFunc("POST /{group_id}/settlements/{settlement_id}/dispute", updateGroupSettlementStatus(q, services.SettlementStatusDisputed))  // POST: Recipient disputes settlement
+	mux.HandleFunc("POST /{group_id}/settlements/{settlement_id}/cancel", updateGroupSettlementStatus(q, services.SettlementStatusCancelled))  // POST: Payer cancels settlement
+
 	return mux
 }
 
@@ -75,8 +107,9 @@ func listGroups(store db.Store) http.HandlerFunc {
 		groupResponses := make([]models.GroupResponse, len(groups))
 		for i, group := range groups {
 			groupResponses[i] = models.GroupResponse{
-				ID:   group.ID,
-				Name: group.Name,
+				ID:       group.ID,
+				Name:     group.Name,
+				Currency: group.Currency,
 			}
 		}
 
@@ -126,8 +159,9 @@ func getGroupByID(store db.Store) http.HandlerFunc {
 
 		// Convert to response format
 		groupResponse := models.GroupResponse{
-			ID:   group.ID,
-			Name: group.Name,
+			ID:       group.ID,
+			Name:     group.Name,
+			Currency: group.Currency,
 		}
 
 		// Send response
@@ -159,20 +193,48 @@ func createGroup(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		logger.Debug("Creating group", slog.String("name", createGroupReq.Name), slog.Int64("user_id", userID))
+		// Unverified users can't create groups when REQUIRE_EMAIL_VERIFICATION is set
+		if auth.EmailVerificationRequired() {
+			user, err := store.GetUserByID(r.Context(), userID)
+			if HandleDBError(w, err, "User not found", "An error has occurred", "Failed to get user by ID", "user_id", userID) {
+				return
+			}
+			if !user.EmailVerified {
+				logger.Warn("Unverified user tried to create a group", "user_id", userID)
+				http.Error(w, "Forbidden: verify your email address before creating groups", http.StatusForbidden)
+				return
+			}
+		}
+
+		// Base currency for balances, fixed once the group is created
+		currency := services.DefaultCurrency
+		if createGroupReq.Currency != "" {
+			var err error
+			currency, err = services.NormalizeCurrency(createGroupReq.Currency)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		logger.Debug("Creating group", slog.String("name", createGroupReq.Name), slog.String("currency", currency), slog.Int64("user_id", userID))
 
 		// Create group in database
-		group, err := store.CreateGroup(r.Context(), createGroupReq.Name)
+		group, err := store.CreateGroup(r.Context(), db.CreateGroupParams{
+			Name:     createGroupReq.Name,
+			Currency: currency,
+		})
 		if HandleDBListError(w, err, "An error has occurred", "Failed to create group", "name", createGroupReq.Name) {
 			return
 		}
 		logger.Debug("Group created successfully", slog.Int64("group_id", group.ID), slog.String("name", group.Name))
 
-		// Automatically add creator as group member
+		// Automatically add creator as group owner
 		userIDPtr := &userID
 		_, err = store.CreateGroupMember(r.Context(), db.CreateGroupMemberParams{
 			GroupID: group.ID,
 			UserID:  userIDPtr,
+			Role:    string(auth.GroupRoleOwner),
 		})
 		if err != nil {
 			logger.Error("Failed to add creator as group member", "error", err, "group_id", group.ID, "user_id", userID)
@@ -183,8 +245,9 @@ func createGroup(store db.Store) http.HandlerFunc {
 
 		// Convert to response format
 		groupResponse := models.GroupResponse{
-			ID:   group.ID,
-			Name: group.Name,
+			ID:       group.ID,
+			Name:     group.Name,
+			Currency: group.Currency,
 		}
 
 		// Send response with 201 Created status
@@ -209,9 +272,9 @@ func updateGroup(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, id, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Verify user is the group owner or an admin
+		_, err := auth.CheckGroupPermission(r.Context(), store, id, userID, auth.PermissionEditGroup)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can edit the group") {
 			return
 		}
 
@@ -241,8 +304,9 @@ func updateGroup(store db.Store) http.HandlerFunc {
 
 		// Convert to response format
 		groupResponse := models.GroupResponse{
-			ID:   group.ID,
-			Name: group.Name,
+			ID:       group.ID,
+			Name:     group.Name,
+			Currency: group.Currency,
 		}
 
 		// Send response
@@ -267,15 +331,15 @@ func deleteGroup(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, id, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Verify user is the group owner
+		_, err := auth.CheckGroupPermission(r.Context(), store, id, userID, auth.PermissionDeleteGroup)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner can delete the group") {
 			return
 		}
 
 		logger.Debug("Deleting group", "group_id", id, "user_id", userID)
 
-		// Delete group from database
+		// Move group to the trash, it can be restored until it is purged
 		group, err := store.DeleteGroup(r.Context(), id)
 		if HandleDBError(w, err, "Group not found", "An error has occurred", "Failed to delete group", "group_id", id) {
 			return
@@ -283,8 +347,10 @@ func deleteGroup(store db.Store) http.HandlerFunc {
 
 		// Convert to response format
 		groupResponse := models.GroupResponse{
-			ID:   group.ID,
-			Name: group.Name,
+			ID:        group.ID,
+			Name:      group.Name,
+			Currency:  group.Currency,
+			DeletedAt: TimestamptzPtr(group.DeletedAt),
 		}
 
 		// Send response with deleted group data
@@ -347,6 +413,10 @@ func listGroupMembers(store db.Store) http.HandlerFunc {
 				MemberName: gm.MemberName,
 				UserID:     gm.UserID,
 				UserName:   gm.UserName,
+				JoinedOn:   DatePtr(gm.JoinedOn),
+				LeftOn:     DatePtr(gm.LeftOn),
+				Role:       gm.Role,
+				RemovedAt:  TimestamptzPtr(gm.RemovedAt),
 				CreatedAt:  gm.CreatedAt,
 			}
 		}
@@ -383,9 +453,9 @@ func createGroupMemberNested(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, groupID, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Verify user may manage the group's members
+		requester, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionManageMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can add members") {
 			return
 		}
 
@@ -399,12 +469,36 @@ func createGroupMemberNested(store db.Store) http.HandlerFunc {
 		// Override group_id from URL
 		createGroupMemberReq.GroupID = groupID
 
+		if err := ValidateMemberDates(createGroupMemberReq.JoinedOn, createGroupMemberReq.LeftOn); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		memberName, err := ValidateNewMemberName(createGroupMemberReq.UserID, createGroupMemberReq.MemberName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		role, err := ValidateNewMemberRole(createGroupMemberReq.Role)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !auth.CanAssignRole(auth.GroupRole(requester.Role), role) {
+			http.Error(w, "Forbidden: only the group owner can add admins", http.StatusForbidden)
+			return
+		}
+
 		logger.Debug("Creating group member", "group_id", createGroupMemberReq.GroupID, "user_id", createGroupMemberReq.UserID, "requester_user_id", userID)
 
 		// Create group member in database
 		groupMember, err := store.CreateGroupMember(r.Context(), db.CreateGroupMemberParams{
-			GroupID: createGroupMemberReq.GroupID,
-			UserID:  createGroupMemberReq.UserID,
+			GroupID:    createGroupMemberReq.GroupID,
+			MemberName: memberName,
+			UserID:     createGroupMemberReq.UserID,
+			JoinedOn:   DateFromPtr(createGroupMemberReq.JoinedOn),
+			LeftOn:     DateFromPtr(createGroupMemberReq.LeftOn),
+			Role:       string(role),
 		})
 		if HandleDBListError(w, err, "An error has occurred", "Failed to create group member", "group_id", createGroupMemberReq.GroupID, "user_id", createGroupMemberReq.UserID) {
 			return
@@ -417,6 +511,10 @@ func createGroupMemberNested(store db.Store) http.HandlerFunc {
 			GroupID:    groupMember.GroupID,
 			MemberName: groupMember.MemberName,
 			UserID:     groupMember.UserID,
+			JoinedOn:   DatePtr(groupMember.JoinedOn),
+			LeftOn:     DatePtr(groupMember.LeftOn),
+			Role:       groupMember.Role,
+			RemovedAt:  TimestamptzPtr(groupMember.RemovedAt),
 			CreatedAt:  groupMember.CreatedAt,
 		}
 
@@ -498,9 +596,13 @@ func getTransactionsByGroupNested(store db.Store) http.HandlerFunc {
 				Name:            tx.Name,
 				TransactionDate: tx.TransactionDate,
 				Amount:          tx.Amount,
+				Currency:        tx.Currency,
+				ExchangeRate:    tx.ExchangeRate,
+				BaseAmount:      services.ConvertAmount(tx.Amount, tx.ExchangeRate),
 				Category:        tx.Category,
 				Note:            tx.Note,
 				ByUser:          tx.ByUser,
+				SplitMode:       tx.SplitMode,
 				CreatedAt:       tx.CreatedAt,
 				ModifiedAt:      tx.ModifiedAt,
 			}
@@ -538,9 +640,9 @@ func createTransactionNested(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, groupID, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Verify user may add transactions to the group
+		_, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionAddTransactions)
+		if HandleGroupPermissionError(w, err, "Forbidden: viewers can't add transactions") {
 			return
 		}
 
@@ -559,30 +661,67 @@ func createTransactionNested(store db.Store) http.HandlerFunc {
 			http.Error(w, "Name is required", http.StatusBadRequest)
 			return
 		}
-		if createTransactionReq.ByUser == 0 {
+		if createTransactionReq.ByUser == 0 && len(createTransactionReq.Payers) == 0 {
 			http.Error(w, "ByUser is required", http.StatusBadRequest)
 			return
 		}
 
-		// Verify ByUser is a group member (it should be a group_member ID, but we should verify it's in this group)
-		// Note: ByUser is a group_member ID, not a user ID
-		// We'll need to verify that the group_member belongs to this group
-		groupMember, err := store.GetGroupMemberByID(r.Context(), createTransactionReq.ByUser)
-		if err != nil {
-			logger.Warn("Group member not found for ByUser", "by_user", createTransactionReq.ByUser)
-			http.Error(w, "Group member not found", http.StatusBadRequest)
+		var payers []models.TransactionPayerRequest
+		if len(createTransactionReq.Payers) > 0 {
+			// Validate payers, ByUser is one of the payers and a single payer is stored as ByUser only
+			byUser, resolvedPayers, err := services.ResolvePayers(createTransactionReq.Amount, createTransactionReq.ByUser, createTransactionReq.Payers)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			createTransactionReq.ByUser = byUser
+			payers = resolvedPayers
+
+			// Verify all payers are group members
+			groupMembers, err := store.ListGroupMembersByGroupID(r.Context(), db.ListGroupMembersByGroupIDParams{GroupID: groupID, Limit: 1000, Offset: 0})
+			if HandleDBError(w, err, "Group members not found", "An error has occurred", "Failed to get group members by group ID", "group id", groupID) {
+				return
+			}
+			if err := ValidatePayerMembersInGroup(createTransactionReq.Payers, groupMembers, groupID); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			// Verify ByUser is a group member (it should be a group_member ID, but we should verify it's in this group)
+			// Note: ByUser is a group_member ID, not a user ID
+			// We'll need to verify that the group_member belongs to this group
+			groupMember, err := store.GetGroupMemberByID(r.Context(), createTransactionReq.ByUser)
+			if err != nil {
+				logger.Warn("Group member not found for ByUser", "by_user", createTransactionReq.ByUser)
+				http.Error(w, "Group member not found", http.StatusBadRequest)
+				return
+			}
+			if groupMember.GroupID != groupID {
+				logger.Warn("Group member does not belong to this group", "by_user", createTransactionReq.ByUser, "group_id", groupID)
+				http.Error(w, "Group member does not belong to this group", http.StatusBadRequest)
+				return
+			}
+			if groupMember.RemovedAt.Valid {
+				http.Error(w, "Group member has been removed from the group", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Get group for its base currency
+		group, err := store.GetGroupByID(r.Context(), groupID)
+		if HandleDBError(w, err, "Group not found", "An error has occurred", "Failed to get group by ID", "group_id", groupID) {
 			return
 		}
-		if groupMember.GroupID != groupID {
-			logger.Warn("Group member does not belong to this group", "by_user", createTransactionReq.ByUser, "group_id", groupID)
-			http.Error(w, "Group member does not belong to this group", http.StatusBadRequest)
+
+		// Resolve transaction currency & exchange rate to the group base currency
+		currency, exchangeRate, ok := resolveTransactionCurrency(w, r, store, group, createTransactionReq.Currency, createTransactionReq.TransactionDate)
+		if !ok {
 			return
 		}
 
-		logger.Debug("Creating transaction", slog.String("name", createTransactionReq.Name), slog.Int64("group_id", createTransactionReq.GroupID), slog.Int64("user_id", userID))
+		logger.Debug("Creating transaction", slog.String("name", createTransactionReq.Name), slog.Int64("group_id", createTransactionReq.GroupID), slog.Int64("user_id", userID), slog.Int("payer_count", len(payers)), slog.String("currency", currency))
 
-		// Create transaction in database
-		transaction, err := store.CreateTransaction(r.Context(), db.CreateTransactionParams{
+		createTransactionParams := db.CreateTransactionParams{
 			GroupID:         createTransactionReq.GroupID,
 			Name:            createTransactionReq.Name,
 			TransactionDate: createTransactionReq.TransactionDate,
@@ -590,9 +729,28 @@ func createTransactionNested(store db.Store) http.HandlerFunc {
 			Category:        createTransactionReq.Category,
 			Note:            createTransactionReq.Note,
 			ByUser:          createTransactionReq.ByUser,
-		})
-		if HandleDBListError(w, err, "An error has occurred", "Failed to create transaction", "group_id", createTransactionReq.GroupID) {
-			return
+			Currency:        currency,
+			ExchangeRate:    exchangeRate,
+		}
+
+		// Create transaction in database, with its payers when paid by several members
+		var transaction db.Transaction
+		var createdPayers []db.TransactionPayer
+		if len(payers) > 0 {
+			result, err := store.CreateTransactionWithPayersTx(r.Context(), db.CreateTransactionWithPayersTxParams{
+				Transaction: createTransactionParams,
+				Payers:      transactionPayerTxParams(payers),
+			})
+			if HandleDBListError(w, err, "An error has occurred", "Failed to create transaction with payers", "group_id", createTransactionReq.GroupID) {
+				return
+			}
+			transaction, createdPayers = result.Transaction, result.Payers
+		} else {
+			var err error
+			transaction, err = store.CreateTransaction(r.Context(), createTransactionParams)
+			if HandleDBListError(w, err, "An error has occurred", "Failed to create transaction", "group_id", createTransactionReq.GroupID) {
+				return
+			}
 		}
 		logger.Debug("Transaction created successfully", slog.Int64("transaction_id", transaction.ID), slog.String("name", transaction.Name))
 
@@ -603,9 +761,14 @@ func createTransactionNested(store db.Store) http.HandlerFunc {
 			Name:            transaction.Name,
 			TransactionDate: transaction.TransactionDate,
 			Amount:          transaction.Amount,
+			Currency:        transaction.Currency,
+			ExchangeRate:    transaction.ExchangeRate,
+			BaseAmount:      services.ConvertAmount(transaction.Amount, transaction.ExchangeRate),
 			Category:        transaction.Category,
 			Note:            transaction.Note,
 			ByUser:          transaction.ByUser,
+			SplitMode:       transaction.SplitMode,
+			Payers:          transactionPayerResponses(transaction, createdPayers),
 			CreatedAt:       transaction.CreatedAt,
 			ModifiedAt:      transaction.ModifiedAt,
 		}
@@ -642,7 +805,19 @@ func getGroupBalances(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		logger.Debug("Getting balances for group", "group_id", groupID)
+		simplify, err := services.ParseSimplifyMethod(r.URL.Query().Get("simplify"))
+		if err != nil {
+			http.Error(w, "Invalid parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.Debug("Getting balances for group", "group_id", groupID, "simplify", simplify)
+
+		// Get group for its base currency, balances are converted to it by the ledger
+		group, err := store.GetGroupByID(r.Context(), groupID)
+		if HandleDBError(w, err, "Group not found", "An error has occurred", "Failed to get group by ID", "group_id", groupID) {
+			return
+		}
 
 		balances, err := store.GroupBalances(r.Context(), groupID)
 		if HandleDBListError(w, err, "An error has occurred", "Failed to get group balances", "group_id", groupID) {
@@ -654,94 +829,123 @@ func getGroupBalances(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		netBalancesForSimplification := make([]*models.NetBalance, len(netBalances))
-		for i, nb := range netBalances {
-			userID := int64(0)
-			if nb.UserID != nil {
-				userID = *nb.UserID
-			}
-			netBalancesForSimplification[i] = &models.NetBalance{
-				UserID:     userID,
-				NetBalance: nb.NetBalance,
-			}
+		response, err := groupBalancesResponse(group, simplify, balances, netBalances)
+		if errors.Is(err, services.ErrTooManyMembersToOptimize) {
+			http.Error(w, "Invalid parameter: "+err.Error()+", use simplify=greedy", http.StatusBadRequest)
+			return
 		}
-
-		simplifiedBalances, err := services.SimplifyDebts(netBalancesForSimplification)
 		if err != nil {
-			logger.Error("Failed to simplify debts", "error", err, "group_id", groupID)
+			logger.Error("Failed to simplify debts", "error", err, "group_id", groupID, "simplify", simplify)
 			http.Error(w, "An error has occurred", http.StatusInternalServerError)
 			return
 		}
 
-		balanceResponses := make([]models.BalanceResponse, len(balances))
-		for i, b := range balances {
-			creditor := ""
-			if b.Creditor != nil {
-				creditor = *b.Creditor
-			}
-			debtor := ""
-			if b.Debtor != nil {
-				debtor = *b.Debtor
-			}
-			creditorID := int64(0)
-			if b.CreditorID != nil {
-				creditorID = *b.CreditorID
-			}
-			debtorID := int64(0)
-			if b.DebtorID != nil {
-				debtorID = *b.DebtorID
-			}
-			balanceResponses[i] = models.BalanceResponse{
-				CreditorID: creditorID,
-				Creditor:   creditor,
-				DebtorID:   debtorID,
-				Debtor:     debtor,
-				TotalOwed:  b.TotalOwed,
-			}
+		if err := WriteJSONResponseOK(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
 		}
+	}
+}
 
-		netBalanceResponses := make([]models.NetBalanceResponse, len(netBalances))
-		for i, nb := range netBalances {
-			userID := int64(0)
-			if nb.UserID != nil {
-				userID = *nb.UserID
-			}
+// groupBalancesResponse builds a group's balance report, simplifying its net balances with the given method
+func groupBalancesResponse(group db.Group, simplify services.SimplifyMethod, balances []db.GroupBalancesRow, netBalances []db.GroupBalancesNetRow) (models.GroupBalancesResponse, error) {
+	var simplifiedBalances []models.SimplifiedPaymentsResponse
+	var err error
+	if simplify == services.SimplifyOptimal {
+		simplifiedBalances, err = services.SimplifyDebtsOptimal(netBalancesForSimplification(netBalances, false))
+	} else {
+		simplifiedBalances, err = services.SimplifyDebts(netBalancesForSimplification(netBalances, false))
+	}
+	if err != nil {
+		return models.GroupBalancesResponse{}, err
+	}
 
-			memberName := ""
-			if nb.UserName != nil {
-				memberName = *nb.UserName
-			}
-			netBalanceResponses[i] = models.NetBalanceResponse{
-				UserID:     userID,
-				MemberName: memberName,
-				NetBalance: nb.NetBalance,
-			}
+	balanceResponses := make([]models.BalanceResponse, len(balances))
+	for i, b := range balances {
+		creditor := ""
+		if b.Creditor != nil {
+			creditor = *b.Creditor
+		}
+		debtor := ""
+		if b.Debtor != nil {
+			debtor = *b.Debtor
 		}
+		creditorID := int64(0)
+		if b.CreditorID != nil {
+			creditorID = *b.CreditorID
+		}
+		debtorID := int64(0)
+		if b.DebtorID != nil {
+			debtorID = *b.DebtorID
+		}
+		balanceResponses[i] = models.BalanceResponse{
+			CreditorID: creditorID,
+			Creditor:   creditor,
+			DebtorID:   debtorID,
+			Debtor:     debtor,
+			TotalOwed:  b.TotalOwed,
+		}
+	}
 
-		simplifiedResponses := make([]models.SimplifiedPaymentsResponse, len(simplifiedBalances))
-		for i, sb := range simplifiedBalances {
-			simplifiedResponses[i] = models.SimplifiedPaymentsResponse{
-				FromUserID: sb.FromUserID,
-				ToUserID:   sb.ToUserID,
-				Amount:     sb.Amount,
-			}
+	netBalanceResponses := make([]models.NetBalanceResponse, len(netBalances))
+	for i, nb := range netBalances {
+		userID := int64(0)
+		if nb.UserID != nil {
+			userID = *nb.UserID
 		}
 
-		response := models.GroupBalancesResponse{
-			GroupID:                 groupID,
-			Balances:                balanceResponses,
-			NetBalances:             netBalanceResponses,
-			SimplifiedPayments:      simplifiedResponses,
-			Count:                   int32(len(balanceResponses)),
-			NetCount:                int32(len(netBalanceResponses)),
-			SimplifiedPaymentsCount: int32(len(simplifiedResponses)),
+		memberName := ""
+		if nb.UserName != nil {
+			memberName = *nb.UserName
+		}
+		netBalanceResponses[i] = models.NetBalanceResponse{
+			UserID:     userID,
+			MemberName: memberName,
+			NetBalance: nb.NetBalance,
 		}
+	}
 
-		if err := WriteJSONResponseOK(w, response); err != nil {
-			http.Error(w, "An error has occurred", http.StatusInternalServerError)
-			return
+	simplifiedResponses := make([]models.SimplifiedPaymentsResponse, len(simplifiedBalances))
+	for i, sb := range simplifiedBalances {
+		simplifiedResponses[i] = models.SimplifiedPaymentsResponse{
+			FromUserID: sb.FromUserID,
+			ToUserID:   sb.ToUserID,
+			Amount:     sb.Amount,
 		}
 	}
+
+	return models.GroupBalancesResponse{
+		GroupID:                 group.ID,
+		Currency:                group.Currency,
+		Simplify:                string(simplify),
+		Balances:                balanceResponses,
+		NetBalances:             netBalanceResponses,
+		SimplifiedPayments:      simplifiedResponses,
+		Count:                   int32(len(balanceResponses)),
+		NetCount:                int32(len(netBalanceResponses)),
+		SimplifiedPaymentsCount: int32(len(simplifiedResponses)),
+	}, nil
+}
+
+// netBalancesForSimplification converts group net balances for SimplifyDebts, keyed by group member ID when byMemberID is set, otherwise by user ID.
+// Group net balances are positive when a member is owed while SimplifyDebts pays from positive balances,
+// so balances are negated to make the members who owe pay the members who are owed.
+func netBalancesForSimplification(netBalances []db.GroupBalancesNetRow, byMemberID bool) []*models.NetBalance {
+	balances := make([]*models.NetBalance, len(netBalances))
+	for i, nb := range netBalances {
+		id := nb.MemberID
+		if !byMemberID {
+			id = 0
+			if nb.UserID != nil {
+				id = *nb.UserID
+			}
+		}
+		balances[i] = &models.NetBalance{
+			UserID:     id,
+			NetBalance: nb.NetBalance.Neg(),
+		}
+	}
+	return balances
 }
 
 // Batch operation handlers
@@ -750,12 +954,24 @@ func getGroupBalances(store db.Store) http.HandlerFunc {
 // POST /groups/{group_id}/members/batch
 func createGroupMembersForGroup(store db.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
 		// Extract {group_id} from path parameter
 		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
 		if !ok {
 			return
 		}
 
+		// Verify user may manage the group's members
+		requester, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionManageMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can add members") {
+			return
+		}
+
 		// Decode request body
 		var batchReq models.BatchCreateGroupMemberRequest
 		if err := DecodeJSONBody(r, &batchReq); err != nil {
@@ -772,9 +988,31 @@ func createGroupMembersForGroup(store db.Store) http.HandlerFunc {
 		// Convert request to transaction params
 		groupMembers := make([]db.CreateGroupMemberParams, len(batchReq.Members))
 		for i, member := range batchReq.Members {
+			if err := ValidateMemberDates(member.JoinedOn, member.LeftOn); err != nil {
+				http.Error(w, fmt.Sprintf("members[%d]: %v", i, err), http.StatusBadRequest)
+				return
+			}
+			memberName, err := ValidateNewMemberName(member.UserID, member.MemberName)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("members[%d]: %v", i, err), http.StatusBadRequest)
+				return
+			}
+			role, err := ValidateNewMemberRole(member.Role)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("members[%d]: %v", i, err), http.StatusBadRequest)
+				return
+			}
+			if !auth.CanAssignRole(auth.GroupRole(requester.Role), role) {
+				http.Error(w, "Forbidden: only the group owner can add admins", http.StatusForbidden)
+				return
+			}
 			groupMembers[i] = db.CreateGroupMemberParams{
-				GroupID: groupID,
-				UserID:  member.UserID,
+				GroupID:    groupID,
+				MemberName: memberName,
+				UserID:     member.UserID,
+				JoinedOn:   DateFromPtr(member.JoinedOn),
+				LeftOn:     DateFromPtr(member.LeftOn),
+				Role:       string(role),
 			}
 		}
 
@@ -801,14 +1039,19 @@ func createGroupMembersForGroup(store db.Store) http.HandlerFunc {
 				GroupID:    gm.GroupID,
 				MemberName: gm.MemberName,
 				UserID:     gm.UserID,
+				JoinedOn:   DatePtr(gm.JoinedOn),
+				LeftOn:     DatePtr(gm.LeftOn),
+				Role:       gm.Role,
+				RemovedAt:  TimestamptzPtr(gm.RemovedAt),
 				CreatedAt:  gm.CreatedAt,
 			}
 		}
 
 		response := models.BatchCreateGroupMemberResponse{
 			Group: models.GroupResponse{
-				ID:   result.Group.ID,
-				Name: result.Group.Name,
+				ID:       result.Group.ID,
+				Name:     result.Group.Name,
+				Currency: result.Group.Currency,
 			},
 			GroupMembers: groupMemberResponses,
 			Count:        int32(len(groupMemberResponses)),
@@ -822,16 +1065,34 @@ func createGroupMembersForGroup(store db.Store) http.HandlerFunc {
 	}
 }
 
-// Update group members for group (batch) - replaces all members
+// Update group members for group (batch) - replaces all members, existing members that aren't kept are removed but keep their transactions
 // PUT/PATCH /groups/{group_id}/members/batch
 func updateGroupMembersForGroup(store db.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
 		// Extract {group_id} from path parameter
 		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
 		if !ok {
 			return
 		}
 
+		force, err := ParseQueryBool(r, "force", false)
+		if err != nil {
+			http.Error(w, "Invalid parameter: force: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Verify user is the group owner, replacing every member can't be undone
+		_, err = auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionReplaceMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner can replace all members") {
+			return
+		}
+
 		// Decode request body
 		var batchReq models.BatchUpdateGroupMemberRequest
 		if err := DecodeJSONBody(r, &batchReq); err != nil {
@@ -839,16 +1100,69 @@ func updateGroupMembersForGroup(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Convert request to transaction params
+		// Convert request to transaction params, the owner keeps ownership of the group
 		groupMembers := make([]db.CreateGroupMemberParams, len(batchReq.Members))
+		ownerIncluded := false
 		for i, member := range batchReq.Members {
+			if err := ValidateMemberDates(member.JoinedOn, member.LeftOn); err != nil {
+				http.Error(w, fmt.Sprintf("members[%d]: %v", i, err), http.StatusBadRequest)
+				return
+			}
+			memberName, err := ValidateNewMemberName(member.UserID, member.MemberName)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("members[%d]: %v", i, err), http.StatusBadRequest)
+				return
+			}
+			role, err := ValidateNewMemberRole(member.Role)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("members[%d]: %v", i, err), http.StatusBadRequest)
+				return
+			}
+			if member.UserID != nil && *member.UserID == userID && !ownerIncluded {
+				role = auth.GroupRoleOwner
+				ownerIncluded = true
+			}
 			groupMembers[i] = db.CreateGroupMemberParams{
-				GroupID: groupID,
-				UserID:  member.UserID,
+				GroupID:    groupID,
+				MemberName: memberName,
+				UserID:     member.UserID,
+				JoinedOn:   DateFromPtr(member.JoinedOn),
+				LeftOn:     DateFromPtr(member.LeftOn),
+				Role:       string(role),
 			}
 		}
+		if !ownerIncluded {
+			http.Error(w, "The group owner must be one of the members, transfer ownership first", http.StatusBadRequest)
+			return
+		}
 
-		logger.Debug("Updating group members in batch", "group_id", groupID, "new_count", len(groupMembers))
+		// Existing members that aren't kept are removed, their balances stay in the group so they should settle up first
+		existing, err := store.ListGroupMembersByGroupID(r.Context(), db.ListGroupMembersByGroupIDParams{GroupID: groupID, Limit: 1000, Offset: 0})
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list group members", "group_id", groupID) {
+			return
+		}
+		var removedMemberIDs []int64
+		for _, member := range existing {
+			if !slices.ContainsFunc(groupMembers, func(arg db.CreateGroupMemberParams) bool {
+				return db.IsSameGroupMember(member.UserID, member.MemberName, arg)
+			}) {
+				removedMemberIDs = append(removedMemberIDs, member.ID)
+			}
+		}
+		if len(removedMemberIDs) > 0 {
+			unsettled, err := unsettledMembers(r.Context(), store, groupID, removedMemberIDs...)
+			if err != nil {
+				logger.Error("Failed to get group net balances", "error", err, "group_id", groupID)
+				http.Error(w, "An error has occurred", http.StatusInternalServerError)
+				return
+			}
+			if len(unsettled) > 0 && !force {
+				http.Error(w, fmt.Sprintf("%d removed group members have a non-zero net balance, settle up first or remove them with force=true", len(unsettled)), http.StatusConflict)
+				return
+			}
+		}
+
+		logger.Debug("Updating group members in batch", "group_id", groupID, "count", len(groupMembers), "removed_count", len(removedMemberIDs))
 
 		// Update group members using transaction (replaces all)
 		result, err := store.UpdateGroupMembersTx(r.Context(), db.UpdateGroupMemberTxParams{
@@ -861,7 +1175,13 @@ func updateGroupMembersForGroup(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		logger.Debug("Group members updated successfully", "group_id", groupID, "deleted_count", len(result.DeletedMembers), "new_count", len(result.NewMembers))
+		logger.Debug("Group members updated successfully", "group_id", groupID, "kept_count", len(result.KeptMembers), "deleted_count", len(result.DeletedMembers), "new_count", len(result.NewMembers))
+
+		// Convert kept members to response format
+		keptResponses := make([]models.GroupMemberResponse, len(result.KeptMembers))
+		for i, gm := range result.KeptMembers {
+			keptResponses[i] = groupMemberResponse(gm)
+		}
 
 		// Convert deleted members to response format
 		deletedResponses := make([]models.GroupMemberResponse, len(result.DeletedMembers))
@@ -871,6 +1191,10 @@ func updateGroupMembersForGroup(store db.Store) http.HandlerFunc {
 				GroupID:    gm.GroupID,
 				MemberName: gm.MemberName,
 				UserID:     gm.UserID,
+				JoinedOn:   DatePtr(gm.JoinedOn),
+				LeftOn:     DatePtr(gm.LeftOn),
+				Role:       gm.Role,
+				RemovedAt:  TimestamptzPtr(gm.RemovedAt),
 				CreatedAt:  gm.CreatedAt,
 			}
 		}
@@ -883,17 +1207,24 @@ func updateGroupMembersForGroup(store db.Store) http.HandlerFunc {
 				GroupID:    gm.GroupID,
 				MemberName: gm.MemberName,
 				UserID:     gm.UserID,
+				JoinedOn:   DatePtr(gm.JoinedOn),
+				LeftOn:     DatePtr(gm.LeftOn),
+				Role:       gm.Role,
+				RemovedAt:  TimestamptzPtr(gm.RemovedAt),
 				CreatedAt:  gm.CreatedAt,
 			}
 		}
 
 		response := models.BatchUpdateGroupMemberResponse{
 			Group: models.GroupResponse{
-				ID:   result.Group.ID,
-				Name: result.Group.Name,
+				ID:       result.Group.ID,
+				Name:     result.Group.Name,
+				Currency: result.Group.Currency,
 			},
+			KeptMembers:    keptResponses,
 			DeletedMembers: deletedResponses,
 			NewMembers:     newResponses,
+			KeptCount:      int32(len(keptResponses)),
 			DeletedCount:   int32(len(deletedResponses)),
 			NewCount:       int32(len(newResponses)),
 		}
@@ -906,31 +1237,61 @@ func updateGroupMembersForGroup(store db.Store) http.HandlerFunc {
 	}
 }
 
-// Delete group members for group (batch) - deletes all members
+// Delete group members for group (batch) - removes all members except the owner, their transactions are kept
 // DELETE /groups/{group_id}/members/batch
 func deleteGroupMembersForGroup(store db.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
 		// Extract {group_id} from path parameter
 		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
 		if !ok {
 			return
 		}
 
-		logger.Debug("Deleting group members in batch", "group_id", groupID)
+		force, err := ParseQueryBool(r, "force", false)
+		if err != nil {
+			http.Error(w, "Invalid parameter: force: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Verify user is the group owner
+		_, err = auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionReplaceMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner can remove all members") {
+			return
+		}
+
+		// Every member but the owner is removed, their balances stay in the group so they should settle up first
+		unsettled, err := unsettledMembers(r.Context(), store, groupID)
+		if err != nil {
+			logger.Error("Failed to get group net balances", "error", err, "group_id", groupID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+		if len(unsettled) > 0 && !force {
+			http.Error(w, fmt.Sprintf("%d group members have a non-zero net balance, settle up first or remove them with force=true", len(unsettled)), http.StatusConflict)
+			return
+		}
+
+		logger.Debug("Removing group members in batch", "group_id", groupID, "user_id", userID, "force", force)
 
-		// Delete all group members using transaction
-		err := store.DeleteGroupMembersTx(r.Context(), groupID)
+		// Remove all group members except the owner using transaction, their transactions are kept
+		err = store.DeleteGroupMembersTx(r.Context(), groupID)
 		if err != nil {
-			logger.Error("Failed to delete group members", "error", err, "group_id", groupID)
+			logger.Error("Failed to remove group members", "error", err, "group_id", groupID)
 			http.Error(w, "An error has occurred", http.StatusInternalServerError)
 			return
 		}
 
-		logger.Debug("Group members deleted successfully", "group_id", groupID)
+		logger.Debug("Group members removed successfully", "group_id", groupID)
 
 		response := models.BatchDeleteGroupMemberResponse{
 			GroupID: groupID,
-			Message: "All group members deleted successfully",
+			Message: "All group members except the owner removed successfully",
 		}
 
 		// Send response
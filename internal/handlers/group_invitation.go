@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/MattSharp0/transaction-split-go/internal/server"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Invitations expire after 7 days unless the request asks otherwise
+const defaultInvitationExpiryHours = 7 * 24
+
+// Invitation statuses, derived from the invitation row
+const (
+	invitationStatusPending  = "pending"
+	invitationStatusAccepted = "accepted" // Single use invitation that has been used
+	invitationStatusExpired  = "expired"
+	invitationStatusRevoked  = "revoked"
+)
+
+func InvitationRoutes(s *server.Server, q db.Store) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /accept", acceptGroupInvitation(q)) // POST: Join a group with an invite token
+
+	return mux
+}
+
+// Invite someone to a group by link or email
+// POST /groups/{group_id}/invitations
+func createGroupInvitation(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} from path parameter
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user may manage the group's members
+		requester, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionManageMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can invite members") {
+			return
+		}
+
+		// Decode request body
+		var invitationReq models.CreateGroupInvitationRequest
+		if err := DecodeJSONBody(r, &invitationReq); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		// Invitations to claim a placeholder member keep its role unless another is given
+		var memberID *int64
+		if invitationReq.MemberID != nil {
+			member, err := store.GetGroupMemberByID(r.Context(), *invitationReq.MemberID)
+			if HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to get group member by ID", "group_member_id", *invitationReq.MemberID) {
+				return
+			}
+			if member.GroupID != groupID {
+				http.Error(w, "Group member not found", http.StatusNotFound)
+				return
+			}
+			if member.UserID != nil {
+				http.Error(w, "Only placeholder members without a user can be claimed", http.StatusBadRequest)
+				return
+			}
+			if member.RemovedAt.Valid {
+				http.Error(w, "Removed members can't be claimed", http.StatusBadRequest)
+				return
+			}
+			if invitationReq.Role == "" {
+				invitationReq.Role = member.Role
+			}
+			memberID = &member.ID
+		}
+
+		// Validate input
+		role, err := ValidateNewMemberRole(invitationReq.Role)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !auth.CanAssignRole(auth.GroupRole(requester.Role), role) {
+			http.Error(w, "Forbidden: only the group owner can invite admins", http.StatusForbidden)
+			return
+		}
+
+		singleUse := true
+		if invitationReq.SingleUse != nil {
+			singleUse = *invitationReq.SingleUse
+		}
+		if memberID != nil && !singleUse {
+			http.Error(w, "Invitations to claim a member are single use", http.StatusBadRequest)
+			return
+		}
+
+		var email *string
+		if invitationReq.Email != nil {
+			validEmail, err := ValidateInvitationEmail(*invitationReq.Email)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if !singleUse {
+				http.Error(w, "Email invitations are single use", http.StatusBadRequest)
+				return
+			}
+			email = &validEmail
+		}
+
+		expiresInHours := defaultInvitationExpiryHours
+		if invitationReq.ExpiresInHours != nil {
+			expiresInHours = *invitationReq.ExpiresInHours
+		}
+		if expiresInHours < 0 {
+			http.Error(w, "expires_in_hours must not be negative", http.StatusBadRequest)
+			return
+		}
+		if expiresInHours == 0 && !singleUse {
+			http.Error(w, "Invitations that can be used more than once must expire", http.StatusBadRequest)
+			return
+		}
+		var expiresAt pgtype.Timestamptz
+		if expiresInHours > 0 {
+			expiresAt = pgtype.Timestamptz{Time: time.Now().Add(time.Duration(expiresInHours) * time.Hour), Valid: true}
+		}
+
+		// Invite tokens are generated and hashed like refresh tokens, only the hash is stored
+		token, err := auth.GenerateRefreshToken()
+		if err != nil {
+			logger.Error("Failed to generate invite token", "error", err)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Debug("Creating group invitation", "group_id", groupID, "role", role, "single_use", singleUse, "by_email", email != nil, "group_member_id", memberID, "user_id", userID)
+
+		invitation, err := store.CreateGroupInvitation(r.Context(), db.CreateGroupInvitationParams{
+			GroupID:       groupID,
+			TokenHash:     auth.HashRefreshToken(token),
+			Email:         email,
+			Role:          string(role),
+			SingleUse:     singleUse,
+			ExpiresAt:     expiresAt,
+			CreatedBy:     &userID,
+			GroupMemberID: memberID,
+		})
+		if HandleDBError(w, err, "Group not found", "An error has occurred", "Failed to create group invitation", "group_id", groupID) {
+			return
+		}
+
+		response := models.CreateGroupInvitationResponse{
+			Invitation: groupInvitationResponse(invitation),
+			Token:      token,
+		}
+
+		if err := WriteJSONResponseCreated(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// List a group's invitations, newest first
+// GET /groups/{group_id}/invitations
+func listGroupInvitations(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} from path parameter
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user may manage the group's members, invitations include email addresses
+		_, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionManageMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can view invitations") {
+			return
+		}
+
+		// Parse query parameters
+		limit, offset, err := ParseLimitOffset(r)
+		if err != nil {
+			http.Error(w, "Invalid parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.Debug("Listing invitations for group", "group_id", groupID, "limit", limit, "offset", offset)
+
+		invitations, err := store.ListGroupInvitationsByGroupID(r.Context(), db.ListGroupInvitationsByGroupIDParams{
+			GroupID: groupID,
+			Limit:   limit,
+			Offset:  offset,
+		})
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list invitations by group", "group_id", groupID) {
+			return
+		}
+
+		invitationResponses := make([]models.GroupInvitationResponse, len(invitations))
+		for i, invitation := range invitations {
+			invitationResponses[i] = groupInvitationResponse(invitation)
+		}
+
+		listInvitationResponse := models.ListGroupInvitationResponse{
+			Invitations: invitationResponses,
+			Count:       int32(len(invitationResponses)),
+			Limit:       limit,
+			Offset:      offset,
+		}
+
+		if err := WriteJSONResponseOK(w, listInvitationResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Revoke an invitation so its token can no longer be used
+// DELETE /groups/{group_id}/invitations/{id}
+func revokeGroupInvitation(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} and {id} from path parameters
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		id, ok := ParsePathInt64(w, r, "id", "Invitation ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user may manage the group's members
+		_, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionManageMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can revoke invitations") {
+			return
+		}
+
+		invitation, err := store.GetGroupInvitationByID(r.Context(), id)
+		if HandleDBError(w, err, "Invitation not found", "An error has occurred", "Failed to get invitation by ID", "invitation_id", id) {
+			return
+		}
+		if invitation.GroupID != groupID {
+			http.Error(w, "Invitation not found", http.StatusNotFound)
+			return
+		}
+		if invitation.RevokedAt.Valid {
+			http.Error(w, "Invitation has already been revoked", http.StatusBadRequest)
+			return
+		}
+
+		logger.Debug("Revoking group invitation", "invitation_id", id, "group_id", groupID, "user_id", userID)
+
+		invitation, err = store.RevokeGroupInvitation(r.Context(), id)
+		if HandleDBError(w, err, "Invitation not found", "An error has occurred", "Failed to revoke invitation", "invitation_id", id) {
+			return
+		}
+
+		if err := WriteJSONResponseOK(w, groupInvitationResponse(invitation)); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Join a group with an invite token
+// POST /invitations/accept
+func acceptGroupInvitation(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Decode request body
+		var acceptReq models.AcceptGroupInvitationRequest
+		if err := DecodeJSONBody(r, &acceptReq); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if acceptReq.Token == "" {
+			http.Error(w, "Token is required", http.StatusBadRequest)
+			return
+		}
+		tokenHash := auth.HashRefreshToken(acceptReq.Token)
+
+		invitation, err := store.GetGroupInvitationByTokenHash(r.Context(), tokenHash)
+		if HandleDBError(w, err, "Invitation not found", "An error has occurred", "Failed to get invitation by token", "user_id", userID) {
+			return
+		}
+
+		// Check the user is not already in the group
+		_, err = auth.GetGroupMemberForUser(r.Context(), store, invitation.GroupID, userID)
+		if err == nil {
+			http.Error(w, "You are already a member of this group", http.StatusConflict)
+			return
+		}
+		if !errors.Is(err, auth.ErrNotGroupMember) {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		// Email invitations are checked against the user's email
+		user, err := store.GetUserByID(r.Context(), userID)
+		if HandleDBError(w, err, "User not found", "An error has occurred", "Failed to get user by ID", "user_id", userID) {
+			return
+		}
+
+		logger.Debug("Accepting group invitation", "invitation_id", invitation.ID, "group_id", invitation.GroupID, "user_id", userID)
+
+		result, err := store.AcceptGroupInvitationTx(r.Context(), db.AcceptGroupInvitationTxParams{
+			TokenHash: tokenHash,
+			UserID:    userID,
+			UserEmail: user.Email,
+		})
+		if err != nil {
+			logger.Error("Failed to accept group invitation", "error", err, "invitation_id", invitation.ID, "user_id", userID)
+			http.Error(w, fmt.Sprintf("Failed to accept invitation: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if err := WriteJSONResponseCreated(w, groupMemberResponse(result.GroupMember)); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// groupInvitationResponse converts a group invitation to response format, the token hash is never returned
+func groupInvitationResponse(invitation db.GroupInvitation) models.GroupInvitationResponse {
+	return models.GroupInvitationResponse{
+		ID:        invitation.ID,
+		GroupID:   invitation.GroupID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		SingleUse: invitation.SingleUse,
+		ExpiresAt: TimestamptzPtr(invitation.ExpiresAt),
+		UseCount:  invitation.UseCount,
+		Status:    invitationStatus(invitation, time.Now()),
+		CreatedBy: invitation.CreatedBy,
+		CreatedAt: invitation.CreatedAt,
+		RevokedAt: TimestamptzPtr(invitation.RevokedAt),
+		MemberID:  invitation.GroupMemberID,
+	}
+}
+
+// invitationStatus reports whether an invitation can still be accepted at now
+func invitationStatus(invitation db.GroupInvitation, now time.Time) string {
+	switch {
+	case invitation.RevokedAt.Valid:
+		return invitationStatusRevoked
+	case invitation.SingleUse && invitation.UseCount > 0:
+		return invitationStatusAccepted
+	case invitation.ExpiresAt.Valid && now.After(invitation.ExpiresAt.Time):
+		return invitationStatusExpired
+	}
+	return invitationStatusPending
+}
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateGroupInvitation(t *testing.T) {
+	// User 1 owns the group, user 2 is an admin & user 3 is a member
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+		{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "admin"},
+		{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "member"},
+	}
+	listParams := db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}
+	expiresAt := pgtype.Timestamptz{Time: time.Now().Add(7 * 24 * time.Hour), Valid: true}
+
+	tests := []struct {
+		name           string
+		userID         int64
+		body           interface{}
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name:   "link invitation with defaults",
+			userID: 1,
+			body:   map[string]interface{}{},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("CreateGroupInvitation", mock.Anything, mock.MatchedBy(func(arg db.CreateGroupInvitationParams) bool {
+					week := time.Now().Add(7 * 24 * time.Hour)
+					return arg.GroupID == 1 && arg.Email == nil && arg.Role == "member" && arg.SingleUse &&
+						arg.ExpiresAt.Valid && arg.ExpiresAt.Time.Sub(week).Abs() < time.Minute &&
+						arg.TokenHash != "" && *arg.CreatedBy == 1
+				})).Return(db.GroupInvitation{ID: 1, GroupID: 1, Role: "member", SingleUse: true, ExpiresAt: expiresAt, CreatedBy: int64Ptr(1)}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "email invitation by admin",
+			userID: 2,
+			body:   models.CreateGroupInvitationRequest{Email: stringPtr(" jane@example.com "), Role: "viewer"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("CreateGroupInvitation", mock.Anything, mock.MatchedBy(func(arg db.CreateGroupInvitationParams) bool {
+					return arg.Email != nil && *arg.Email == "jane@example.com" && arg.Role == "viewer" && arg.SingleUse
+				})).Return(db.GroupInvitation{ID: 1, GroupID: 1, Email: stringPtr("jane@example.com"), Role: "viewer", SingleUse: true}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "invitation to claim a placeholder keeps its role",
+			userID: 1,
+			body:   models.CreateGroupInvitationRequest{MemberID: int64Ptr(4)},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(4)).Return(db.GetGroupMemberByIDRow{ID: 4, GroupID: 1, MemberName: stringPtr("Sam"), Role: "viewer"}, nil)
+				ms.On("CreateGroupInvitation", mock.Anything, mock.MatchedBy(func(arg db.CreateGroupInvitationParams) bool {
+					return arg.GroupMemberID != nil && *arg.GroupMemberID == 4 && arg.Role == "viewer" && arg.SingleUse
+				})).Return(db.GroupInvitation{ID: 2, GroupID: 1, Role: "viewer", SingleUse: true, GroupMemberID: int64Ptr(4)}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "member with a user can't be claimed",
+			userID: 1,
+			body:   models.CreateGroupInvitationRequest{MemberID: int64Ptr(3)},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(db.GetGroupMemberByIDRow{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "member"}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "placeholder in another group",
+			userID: 1,
+			body:   models.CreateGroupInvitationRequest{MemberID: int64Ptr(9)},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(9)).Return(db.GetGroupMemberByIDRow{ID: 9, GroupID: 2, MemberName: stringPtr("Sam"), Role: "member"}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "reusable invitation to claim a placeholder",
+			userID: 1,
+			body:   map[string]interface{}{"member_id": 4, "single_use": false},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(4)).Return(db.GetGroupMemberByIDRow{ID: 4, GroupID: 1, MemberName: stringPtr("Sam"), Role: "member"}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "reusable link that never expires",
+			userID: 1,
+			body:   map[string]interface{}{"single_use": false, "expires_in_hours": 0},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "reusable email invitation",
+			userID: 1,
+			body:   map[string]interface{}{"email": "jane@example.com", "single_use": false},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid email",
+			userID: 1,
+			body:   models.CreateGroupInvitationRequest{Email: stringPtr("jane")},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "admin can't invite admins",
+			userID: 2,
+			body:   models.CreateGroupInvitationRequest{Role: "admin"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "member can't invite",
+			userID: 3,
+			body:   map[string]interface{}{},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			body, _ := json.Marshal(tt.body)
+			req := createRequestWithUserID("POST", "/groups/1/invitations", body, tt.userID)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := createGroupInvitation(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var response models.CreateGroupInvitationResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.NotEmpty(t, response.Token)
+				assert.Equal(t, "pending", response.Invitation.Status)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeGroupInvitation(t *testing.T) {
+	members := createTestGroupMembers([]int64{1, 2}, 1)
+	listParams := db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}
+	revokedAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	tests := []struct {
+		name           string
+		userID         int64
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupInvitationByID", mock.Anything, int64(5)).Return(db.GroupInvitation{ID: 5, GroupID: 1, SingleUse: true}, nil)
+				ms.On("RevokeGroupInvitation", mock.Anything, int64(5)).Return(db.GroupInvitation{ID: 5, GroupID: 1, SingleUse: true, RevokedAt: revokedAt}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "already revoked",
+			userID: 1,
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupInvitationByID", mock.Anything, int64(5)).Return(db.GroupInvitation{ID: 5, GroupID: 1, RevokedAt: revokedAt}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invitation to another group",
+			userID: 1,
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupInvitationByID", mock.Anything, int64(5)).Return(db.GroupInvitation{ID: 5, GroupID: 2}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "member can't revoke",
+			userID: 2,
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("DELETE", "/groups/1/invitations/5", nil, tt.userID)
+			req.SetPathValue("group_id", "1")
+			req.SetPathValue("id", "5")
+			rr := httptest.NewRecorder()
+
+			handler := revokeGroupInvitation(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.GroupInvitationResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, "revoked", response.Status)
+				assert.NotNil(t, response.RevokedAt)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAcceptGroupInvitation(t *testing.T) {
+	token := "invite-token"
+	tokenHash := auth.HashRefreshToken(token)
+	invitation := db.GroupInvitation{ID: 5, GroupID: 1, TokenHash: tokenHash, Role: "member", SingleUse: true}
+	listParams := db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}
+	user := db.User{ID: 4, Name: "Jane", Email: "jane@example.com"}
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: models.AcceptGroupInvitationRequest{Token: token},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupInvitationByTokenHash", mock.Anything, tokenHash).Return(invitation, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				ms.On("GetUserByID", mock.Anything, int64(4)).Return(user, nil)
+				ms.On("AcceptGroupInvitationTx", mock.Anything, db.AcceptGroupInvitationTxParams{TokenHash: tokenHash, UserID: 4, UserEmail: "jane@example.com"}).Return(db.AcceptGroupInvitationTxResult{
+					Invitation:  db.GroupInvitation{ID: 5, GroupID: 1, Role: "member", SingleUse: true, UseCount: 1},
+					GroupMember: db.GroupMember{ID: 3, GroupID: 1, UserID: int64Ptr(4), Role: "member"},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "claims a placeholder",
+			body: models.AcceptGroupInvitationRequest{Token: token},
+			setupMock: func(ms *mocks.MockStore) {
+				claim := invitation
+				claim.GroupMemberID = int64Ptr(2)
+				ms.On("GetGroupInvitationByTokenHash", mock.Anything, tokenHash).Return(claim, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(createTestGroupMembers([]int64{1}, 1), nil)
+				ms.On("GetUserByID", mock.Anything, int64(4)).Return(user, nil)
+				ms.On("AcceptGroupInvitationTx", mock.Anything, db.AcceptGroupInvitationTxParams{TokenHash: tokenHash, UserID: 4, UserEmail: "jane@example.com"}).Return(db.AcceptGroupInvitationTxResult{
+					Invitation:  db.GroupInvitation{ID: 5, GroupID: 1, Role: "member", SingleUse: true, UseCount: 1, GroupMemberID: int64Ptr(2)},
+					GroupMember: db.GroupMember{ID: 2, GroupID: 1, MemberName: stringPtr("Sam"), UserID: int64Ptr(4), Role: "member"},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "already a member",
+			body: models.AcceptGroupInvitationRequest{Token: token},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupInvitationByTokenHash", mock.Anything, tokenHash).Return(invitation, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(createTestGroupMembers([]int64{1, 4}, 1), nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "unknown token",
+			body: models.AcceptGroupInvitationRequest{Token: token},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupInvitationByTokenHash", mock.Anything, tokenHash).Return(db.GroupInvitation{}, pgx.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "invitation can't be used",
+			body: models.AcceptGroupInvitationRequest{Token: token},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupInvitationByTokenHash", mock.Anything, tokenHash).Return(invitation, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				ms.On("GetUserByID", mock.Anything, int64(4)).Return(user, nil)
+				ms.On("AcceptGroupInvitationTx", mock.Anything, mock.Anything).Return(db.AcceptGroupInvitationTxResult{}, errors.New("invitation has expired"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing token",
+			body:           models.AcceptGroupInvitationRequest{},
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			body, _ := json.Marshal(tt.body)
+			req := createRequestWithUserID("POST", "/invitations/accept", body, 4)
+			rr := httptest.NewRecorder()
+
+			handler := acceptGroupInvitation(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var response models.GroupMemberResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int64(4), *response.UserID)
+				assert.Equal(t, "member", response.Role)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInvitationStatus(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	past := pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true}
+	future := pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true}
+
+	tests := []struct {
+		name       string
+		invitation db.GroupInvitation
+		expected   string
+	}{
+		{name: "unused", invitation: db.GroupInvitation{SingleUse: true, ExpiresAt: future}, expected: "pending"},
+		{name: "no expiry", invitation: db.GroupInvitation{SingleUse: true}, expected: "pending"},
+		{name: "used single use", invitation: db.GroupInvitation{SingleUse: true, UseCount: 1, ExpiresAt: future}, expected: "accepted"},
+		{name: "used reusable link", invitation: db.GroupInvitation{UseCount: 3, ExpiresAt: future}, expected: "pending"},
+		{name: "expired", invitation: db.GroupInvitation{UseCount: 3, ExpiresAt: past}, expected: "expired"},
+		{name: "revoked", invitation: db.GroupInvitation{SingleUse: true, ExpiresAt: future, RevokedAt: past}, expected: "revoked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, invitationStatus(tt.invitation, now))
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"slices"
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
 	"github.com/MattSharp0/transaction-split-go/internal/auth"
@@ -17,7 +20,7 @@ func GroupMemberRoutes(s *server.Server, q db.Store) *http.ServeMux {
 	mux.HandleFunc("GET /{id}", getGroupMemberByID(q))   // GET: Get group member by ID
 	mux.HandleFunc("PUT /{id}", updateGroupMember(q))    // PUT: Update group member
 	mux.HandleFunc("PATCH /{id}", updateGroupMember(q))  // PATCH: Update group member
-	mux.HandleFunc("DELETE /{id}", deleteGroupMember(q)) // DELETE: Delete group member
+	mux.HandleFunc("DELETE /{id}", deleteGroupMember(q)) // DELETE: Remove group member, kept with their transactions
 
 	return mux
 }
@@ -71,6 +74,10 @@ func listGroupMembersByGroupID(store db.Store) http.HandlerFunc {
 				MemberName: gm.MemberName,
 				UserID:     gm.UserID,
 				UserName:   gm.UserName,
+				JoinedOn:   DatePtr(gm.JoinedOn),
+				LeftOn:     DatePtr(gm.LeftOn),
+				Role:       gm.Role,
+				RemovedAt:  TimestamptzPtr(gm.RemovedAt),
 				CreatedAt:  gm.CreatedAt,
 			}
 		}
@@ -127,6 +134,10 @@ func getGroupMemberByID(store db.Store) http.HandlerFunc {
 			MemberName: groupMember.MemberName,
 			UserID:     groupMember.UserID,
 			UserName:   groupMember.UserName,
+			JoinedOn:   DatePtr(groupMember.JoinedOn),
+			LeftOn:     DatePtr(groupMember.LeftOn),
+			Role:       groupMember.Role,
+			RemovedAt:  TimestamptzPtr(groupMember.RemovedAt),
 			CreatedAt:  groupMember.CreatedAt,
 		}
 
@@ -159,10 +170,28 @@ func createGroupMember(store db.Store) http.HandlerFunc {
 			http.Error(w, "Group ID is required", http.StatusBadRequest)
 			return
 		}
+		if err := ValidateMemberDates(createGroupMemberReq.JoinedOn, createGroupMemberReq.LeftOn); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		memberName, err := ValidateNewMemberName(createGroupMemberReq.UserID, createGroupMemberReq.MemberName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		role, err := ValidateNewMemberRole(createGroupMemberReq.Role)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, createGroupMemberReq.GroupID, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Verify user may manage the group's members
+		requester, err := auth.CheckGroupPermission(r.Context(), store, createGroupMemberReq.GroupID, userID, auth.PermissionManageMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can add members") {
+			return
+		}
+		if !auth.CanAssignRole(auth.GroupRole(requester.Role), role) {
+			http.Error(w, "Forbidden: only the group owner can add admins", http.StatusForbidden)
 			return
 		}
 
@@ -170,8 +199,12 @@ func createGroupMember(store db.Store) http.HandlerFunc {
 
 		// Create group member in database
 		groupMember, err := store.CreateGroupMember(r.Context(), db.CreateGroupMemberParams{
-			GroupID: createGroupMemberReq.GroupID,
-			UserID:  createGroupMemberReq.UserID,
+			GroupID:    createGroupMemberReq.GroupID,
+			MemberName: memberName,
+			UserID:     createGroupMemberReq.UserID,
+			JoinedOn:   DateFromPtr(createGroupMemberReq.JoinedOn),
+			LeftOn:     DateFromPtr(createGroupMemberReq.LeftOn),
+			Role:       string(role),
 		})
 		if HandleDBListError(w, err, "An error has occurred", "Failed to create group member", "group_id", createGroupMemberReq.GroupID, "user_id", createGroupMemberReq.UserID) {
 			return
@@ -184,6 +217,10 @@ func createGroupMember(store db.Store) http.HandlerFunc {
 			GroupID:    groupMember.GroupID,
 			MemberName: groupMember.MemberName,
 			UserID:     groupMember.UserID,
+			JoinedOn:   DatePtr(groupMember.JoinedOn),
+			LeftOn:     DatePtr(groupMember.LeftOn),
+			Role:       groupMember.Role,
+			RemovedAt:  TimestamptzPtr(groupMember.RemovedAt),
 			CreatedAt:  groupMember.CreatedAt,
 		}
 
@@ -215,9 +252,13 @@ func updateGroupMember(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, groupMemberRow.GroupID, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Verify user may manage the group's members
+		requester, err := auth.CheckGroupPermission(r.Context(), store, groupMemberRow.GroupID, userID, auth.PermissionManageMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can edit members") {
+			return
+		}
+		if groupMemberRow.RemovedAt.Valid {
+			http.Error(w, "Removed group members can't be edited", http.StatusBadRequest)
 			return
 		}
 
@@ -233,14 +274,46 @@ func updateGroupMember(store db.Store) http.HandlerFunc {
 			http.Error(w, "Group ID is required", http.StatusBadRequest)
 			return
 		}
+		if err := ValidateMemberDates(updateGroupMemberReq.JoinedOn, updateGroupMemberReq.LeftOn); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The owner stays linked to their user in their group until ownership is transferred
+		if auth.GroupRole(groupMemberRow.Role) == auth.GroupRoleOwner {
+			if groupMemberRow.UserID == nil || *groupMemberRow.UserID != userID {
+				http.Error(w, "Forbidden: only the group owner can edit the owner", http.StatusForbidden)
+				return
+			}
+			if updateGroupMemberReq.GroupID != groupMemberRow.GroupID || updateGroupMemberReq.UserID == nil || *updateGroupMemberReq.UserID != userID {
+				http.Error(w, "The group owner can't be moved to another group or user, transfer ownership first", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Only the owner edits admins
+		if auth.GroupRole(groupMemberRow.Role) == auth.GroupRoleAdmin && !auth.CanAssignRole(auth.GroupRole(requester.Role), auth.GroupRoleAdmin) {
+			http.Error(w, "Forbidden: only the group owner can edit admins", http.StatusForbidden)
+			return
+		}
+
+		// Moving a member to another group also needs permission in that group
+		if updateGroupMemberReq.GroupID != groupMemberRow.GroupID {
+			_, err = auth.CheckGroupPermission(r.Context(), store, updateGroupMemberReq.GroupID, userID, auth.PermissionManageMembers)
+			if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can add members") {
+				return
+			}
+		}
 
 		logger.Debug("Updating group member", "group_member_id", id, "group_id", updateGroupMemberReq.GroupID, "user_id", updateGroupMemberReq.UserID, "requester_user_id", userID)
 
 		// Update group member in database
 		groupMember, err := store.UpdateGroupMember(r.Context(), db.UpdateGroupMemberParams{
-			ID:      id,
-			GroupID: updateGroupMemberReq.GroupID,
-			UserID:  updateGroupMemberReq.UserID,
+			ID:       id,
+			GroupID:  updateGroupMemberReq.GroupID,
+			UserID:   updateGroupMemberReq.UserID,
+			JoinedOn: DateFromPtr(updateGroupMemberReq.JoinedOn),
+			LeftOn:   DateFromPtr(updateGroupMemberReq.LeftOn),
 		})
 		if HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to update group member", "group_member_id", id) {
 			return
@@ -252,6 +325,10 @@ func updateGroupMember(store db.Store) http.HandlerFunc {
 			GroupID:    groupMember.GroupID,
 			MemberName: groupMember.MemberName,
 			UserID:     groupMember.UserID,
+			JoinedOn:   DatePtr(groupMember.JoinedOn),
+			LeftOn:     DatePtr(groupMember.LeftOn),
+			Role:       groupMember.Role,
+			RemovedAt:  TimestamptzPtr(groupMember.RemovedAt),
 			CreatedAt:  groupMember.CreatedAt,
 		}
 
@@ -277,40 +354,91 @@ func deleteGroupMember(store db.Store) http.HandlerFunc {
 			return
 		}
 
+		force, err := ParseQueryBool(r, "force", false)
+		if err != nil {
+			http.Error(w, "Invalid parameter: force: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// Get group member to find its group
 		groupMemberRow, err := store.GetGroupMemberByID(r.Context(), id)
 		if HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to get group member by ID", "group_member_id", id) {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, groupMemberRow.GroupID, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Members may leave the group themselves, removing anyone else needs permission to manage members
+		leaving := groupMemberRow.UserID != nil && *groupMemberRow.UserID == userID
+		var requester db.ListGroupMembersByGroupIDRow
+		if leaving {
+			err = auth.CheckGroupMembership(r.Context(), store, groupMemberRow.GroupID, userID)
+		} else {
+			requester, err = auth.CheckGroupPermission(r.Context(), store, groupMemberRow.GroupID, userID, auth.PermissionManageMembers)
+		}
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can remove members") {
 			return
 		}
 
-		logger.Debug("Unlinking group member", "group_member_id", id, "user_id", userID)
+		if groupMemberRow.RemovedAt.Valid {
+			http.Error(w, "Group member has already been removed", http.StatusBadRequest)
+			return
+		}
 
-		// Unlink group member (set user_id to NULL instead of deleting)
-		// This preserves the group_member record and member_name
-		groupMember, err := store.UnlinkGroupMember(r.Context(), id)
-		if HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to unlink group member", "group_member_id", id) {
+		// A group always has an owner
+		if auth.GroupRole(groupMemberRow.Role) == auth.GroupRoleOwner {
+			http.Error(w, "The group owner can't be removed, transfer ownership first", http.StatusBadRequest)
 			return
 		}
 
-		// Convert to response format
-		groupMemberResponse := models.GroupMemberResponse{
-			ID:         groupMember.ID,
-			GroupID:    groupMember.GroupID,
-			MemberName: groupMember.MemberName,
-			UserID:     groupMember.UserID, // This will be NULL after unlinking
-			CreatedAt:  groupMember.CreatedAt,
+		// Only the owner removes admins, admins may still leave themselves
+		if !leaving && auth.GroupRole(groupMemberRow.Role) == auth.GroupRoleAdmin && !auth.CanAssignRole(auth.GroupRole(requester.Role), auth.GroupRoleAdmin) {
+			http.Error(w, "Forbidden: only the group owner can remove admins", http.StatusForbidden)
+			return
 		}
 
-		// Send response with unlinked group member data
-		if err := WriteJSONResponseOK(w, groupMemberResponse); err != nil {
+		// Balances of removed members stay in the group, so they should settle up first
+		unsettled, err := unsettledMembers(r.Context(), store, groupMemberRow.GroupID, id)
+		if err != nil {
+			logger.Error("Failed to get group net balances", "error", err, "group_id", groupMemberRow.GroupID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+		if len(unsettled) > 0 && !force {
+			http.Error(w, fmt.Sprintf("Group member has a net balance of %s, settle up first or remove them with force=true", unsettled[0].NetBalance.StringFixed(2)), http.StatusConflict)
+			return
+		}
+
+		logger.Debug("Removing group member", "group_member_id", id, "user_id", userID, "force", force)
+
+		groupMember, err := store.RemoveGroupMember(r.Context(), id)
+		if HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to remove group member", "group_member_id", id) {
+			return
+		}
+
+		// Send response with removed group member data
+		if err := WriteJSONResponseOK(w, groupMemberResponse(groupMember)); err != nil {
 			http.Error(w, "An error has occurred", http.StatusInternalServerError)
 			return
 		}
 	}
 }
+
+// unsettledMembers returns the net balances of a group's members that aren't zero, of every member when no member IDs are given
+func unsettledMembers(ctx context.Context, store db.Store, groupID int64, memberIDs ...int64) ([]db.GroupBalancesNetRow, error) {
+	netBalances, err := store.GroupBalancesNet(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var unsettled []db.GroupBalancesNetRow
+	for _, netBalance := range netBalances {
+		if netBalance.NetBalance.IsZero() {
+			continue
+		}
+		if len(memberIDs) > 0 && !slices.Contains(memberIDs, netBalance.MemberID) {
+			continue
+		}
+		unsettled = append(unsettled, netBalance)
+	}
+
+	return unsettled, nil
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
+)
+
+// Merge a duplicate member into another member of the group, moving its history and removing it
+// POST /groups/{group_id}/members/{id}/merge
+func mergeGroupMembers(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} and {id} from path parameters
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		id, ok := ParsePathInt64(w, r, "id", "Group Member ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user may manage the group's members
+		requester, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionManageMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can merge members") {
+			return
+		}
+
+		// Decode request body
+		var mergeReq models.MergeGroupMembersRequest
+		if err := DecodeJSONBody(r, &mergeReq); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		// Validate input
+		if mergeReq.MemberID == 0 {
+			http.Error(w, "member_id is required", http.StatusBadRequest)
+			return
+		}
+		if mergeReq.MemberID == id {
+			http.Error(w, "A member can't be merged into itself", http.StatusBadRequest)
+			return
+		}
+
+		into, err := store.GetGroupMemberByID(r.Context(), id)
+		if HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to get group member by ID", "group_member_id", id) {
+			return
+		}
+		from, err := store.GetGroupMemberByID(r.Context(), mergeReq.MemberID)
+		if HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to get group member by ID", "group_member_id", mergeReq.MemberID) {
+			return
+		}
+		if into.GroupID != groupID || from.GroupID != groupID {
+			http.Error(w, "Group member not found", http.StatusNotFound)
+			return
+		}
+
+		// The duplicate is removed, so the same rules apply as removing a member
+		fromRole := auth.GroupRole(from.Role)
+		if fromRole == auth.GroupRoleOwner {
+			http.Error(w, "The group owner can't be merged into another member, transfer ownership first", http.StatusBadRequest)
+			return
+		}
+		if fromRole == auth.GroupRoleAdmin && auth.GroupRole(requester.Role) != auth.GroupRoleOwner {
+			http.Error(w, "Forbidden: only the group owner can remove admins", http.StatusForbidden)
+			return
+		}
+		if into.RemovedAt.Valid {
+			http.Error(w, "Members can't be merged into a removed member", http.StatusBadRequest)
+			return
+		}
+		if from.UserID != nil && into.UserID != nil && *from.UserID != *into.UserID {
+			http.Error(w, "Members linked to different users can't be merged", http.StatusBadRequest)
+			return
+		}
+
+		logger.Debug("Merging group members", "group_id", groupID, "from_member_id", from.ID, "into_member_id", into.ID, "user_id", userID)
+
+		result, err := store.MergeGroupMembersTx(r.Context(), db.MergeGroupMembersTxParams{
+			GroupID:      groupID,
+			FromMemberID: from.ID,
+			IntoMemberID: into.ID,
+		})
+		if HandleMergeGroupMembersError(w, err, "group_id", groupID, "from_member_id", from.ID, "into_member_id", into.ID) {
+			return
+		}
+
+		// Return the group's balances after the merge
+		group, err := store.GetGroupByID(r.Context(), groupID)
+		if HandleDBError(w, err, "Group not found", "An error has occurred", "Failed to get group by ID", "group_id", groupID) {
+			return
+		}
+
+		balances, err := store.GroupBalances(r.Context(), groupID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get group balances", "group_id", groupID) {
+			return
+		}
+
+		netBalances, err := store.GroupBalancesNet(r.Context(), groupID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get group net balances", "group_id", groupID) {
+			return
+		}
+
+		balancesResponse, err := groupBalancesResponse(group, services.SimplifyGreedy, balances, netBalances)
+		if err != nil {
+			logger.Error("Failed to simplify debts", "error", err, "group_id", groupID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		response := models.MergeGroupMembersResponse{
+			Member:        groupMemberResponse(result.Member),
+			RemovedMember: groupMemberResponse(result.RemovedMember),
+			Balances:      balancesResponse,
+		}
+
+		if err := WriteJSONResponseOK(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
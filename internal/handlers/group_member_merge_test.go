@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeGroupMembers(t *testing.T) {
+	// User 1 owns the group, user 2 is an admin, user 3 is a member & member 4 is a placeholder for user 3
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+		{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "admin"},
+		{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "member"},
+		{ID: 4, GroupID: 1, MemberName: stringPtr("Sam"), Role: "member"},
+	}
+	listParams := db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}
+	memberRow := func(id int64) db.GetGroupMemberByIDRow {
+		m := members[id-1]
+		return db.GetGroupMemberByIDRow{ID: m.ID, GroupID: m.GroupID, MemberName: m.MemberName, UserID: m.UserID, Role: m.Role}
+	}
+
+	tests := []struct {
+		name           string
+		userID         int64
+		memberID       string
+		body           interface{}
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name:     "placeholder merged into member",
+			userID:   2,
+			memberID: "3",
+			body:     models.MergeGroupMembersRequest{MemberID: 4},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(memberRow(3), nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(4)).Return(memberRow(4), nil)
+				ms.On("MergeGroupMembersTx", mock.Anything, db.MergeGroupMembersTxParams{GroupID: 1, FromMemberID: 4, IntoMemberID: 3}).Return(db.MergeGroupMembersTxResult{
+					Member:        db.GroupMember{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "member"},
+					RemovedMember: db.GroupMember{ID: 4, GroupID: 1, MemberName: stringPtr("Sam"), Role: "member"},
+				}, nil)
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group 1", Currency: "USD"}, nil)
+				ms.On("GroupBalances", mock.Anything, int64(1)).Return([]db.GroupBalancesRow{
+					{CreditorID: int64Ptr(1), Creditor: stringPtr("User 1"), DebtorID: int64Ptr(3), Debtor: stringPtr("User 3"), TotalOwed: decimal.NewFromInt(30)},
+				}, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return([]db.GroupBalancesNetRow{
+					{MemberID: 1, UserID: int64Ptr(1), UserName: stringPtr("User 1"), NetBalance: decimal.NewFromInt(30)},
+					{MemberID: 3, UserID: int64Ptr(3), UserName: stringPtr("User 3"), NetBalance: decimal.NewFromInt(-30)},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "owner can't be merged away",
+			userID:   1,
+			memberID: "4",
+			body:     models.MergeGroupMembersRequest{MemberID: 1},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(4)).Return(memberRow(4), nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(memberRow(1), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "admin can't merge away admins",
+			userID:   2,
+			memberID: "4",
+			body:     models.MergeGroupMembersRequest{MemberID: 2},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(4)).Return(memberRow(4), nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(2)).Return(memberRow(2), nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:     "members linked to different users",
+			userID:   1,
+			memberID: "2",
+			body:     models.MergeGroupMembersRequest{MemberID: 3},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(2)).Return(memberRow(2), nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(memberRow(3), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "removed member merged into member of the same user",
+			userID:   1,
+			memberID: "3",
+			body:     models.MergeGroupMembersRequest{MemberID: 5},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(memberRow(3), nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(5)).Return(db.GetGroupMemberByIDRow{ID: 5, GroupID: 1, UserID: int64Ptr(3), Role: "member", RemovedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+				ms.On("MergeGroupMembersTx", mock.Anything, db.MergeGroupMembersTxParams{GroupID: 1, FromMemberID: 5, IntoMemberID: 3}).Return(db.MergeGroupMembersTxResult{
+					Member:        db.GroupMember{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "member"},
+					RemovedMember: db.GroupMember{ID: 5, GroupID: 1, UserID: int64Ptr(3), Role: "member", RemovedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+				}, nil)
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group 1", Currency: "USD"}, nil)
+				ms.On("GroupBalances", mock.Anything, int64(1)).Return([]db.GroupBalancesRow{
+					{CreditorID: int64Ptr(1), Creditor: stringPtr("User 1"), DebtorID: int64Ptr(3), Debtor: stringPtr("User 3"), TotalOwed: decimal.NewFromInt(30)},
+				}, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return([]db.GroupBalancesNetRow{
+					{MemberID: 1, UserID: int64Ptr(1), UserName: stringPtr("User 1"), NetBalance: decimal.NewFromInt(30)},
+					{MemberID: 3, UserID: int64Ptr(3), UserName: stringPtr("User 3"), NetBalance: decimal.NewFromInt(-30)},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "merge into removed member",
+			userID:   1,
+			memberID: "5",
+			body:     models.MergeGroupMembersRequest{MemberID: 4},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(5)).Return(db.GetGroupMemberByIDRow{ID: 5, GroupID: 1, UserID: int64Ptr(3), Role: "member", RemovedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(4)).Return(memberRow(4), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "duplicate in another group",
+			userID:   1,
+			memberID: "3",
+			body:     models.MergeGroupMembersRequest{MemberID: 9},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(memberRow(3), nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(9)).Return(db.GetGroupMemberByIDRow{ID: 9, GroupID: 2, MemberName: stringPtr("Sam"), Role: "member"}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "merge into itself",
+			userID:   1,
+			memberID: "3",
+			body:     models.MergeGroupMembersRequest{MemberID: 3},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "missing member_id",
+			userID:   1,
+			memberID: "3",
+			body:     map[string]interface{}{},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "member can't merge",
+			userID:   3,
+			memberID: "3",
+			body:     models.MergeGroupMembersRequest{MemberID: 4},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:     "transaction error",
+			userID:   1,
+			memberID: "3",
+			body:     models.MergeGroupMembersRequest{MemberID: 4},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(memberRow(3), nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(4)).Return(memberRow(4), nil)
+				ms.On("MergeGroupMembersTx", mock.Anything, mock.Anything).Return(db.MergeGroupMembersTxResult{}, errors.New("failed to move splits"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:     "members changed during merge",
+			userID:   1,
+			memberID: "3",
+			body:     models.MergeGroupMembersRequest{MemberID: 4},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(memberRow(3), nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(4)).Return(memberRow(4), nil)
+				ms.On("MergeGroupMembersTx", mock.Anything, mock.Anything).Return(db.MergeGroupMembersTxResult{}, db.ErrMergeIntoRemovedMember)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			body, _ := json.Marshal(tt.body)
+			req := createRequestWithUserID("POST", "/groups/1/members/"+tt.memberID+"/merge", body, tt.userID)
+			req.SetPathValue("group_id", "1")
+			req.SetPathValue("id", tt.memberID)
+			rr := httptest.NewRecorder()
+
+			handler := mergeGroupMembers(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.MergeGroupMembersResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int64(3), response.Member.ID)
+				assert.Equal(t, tt.body.(models.MergeGroupMembersRequest).MemberID, response.RemovedMember.ID)
+				assert.Equal(t, int32(2), response.Balances.NetCount)
+				require.Len(t, response.Balances.SimplifiedPayments, 1)
+				assert.Equal(t, int64(3), response.Balances.SimplifiedPayments[0].FromUserID)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -10,7 +10,10 @@ import (
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
 	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -37,7 +40,7 @@ func TestListGroupMembersByGroupID(t *testing.T) {
 						GroupName:  "Group 1",
 						MemberName: stringPtr("Member 1"),
 						UserID:     userID,
-						UserName:   "User 1",
+						UserName:   stringPtr("User 1"),
 						CreatedAt:  time.Now(),
 					},
 				}
@@ -49,13 +52,28 @@ func TestListGroupMembersByGroupID(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedCount:  1,
 		},
+		{
+			name: "placeholder members without a user are listed",
+			setupMock: func(ms *mocks.MockStore) {
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, MemberName: stringPtr("Member 1"), UserID: int64Ptr(1), UserName: stringPtr("User 1"), Role: "owner"},
+					{ID: 2, GroupID: 1, MemberName: stringPtr("Sam"), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 100, Offset: 0}).Return(members, nil)
+			},
+			pathValue:      "1",
+			queryParams:    "",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
 		{
 			name: "success with empty members",
 			setupMock: func(ms *mocks.MockStore) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 100, Offset: 0}).Return([]db.ListGroupMembersByGroupIDRow{}, nil)
@@ -79,7 +97,7 @@ func TestListGroupMembersByGroupID(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 100, Offset: 0}).Return(nil, errors.New("database error"))
@@ -136,14 +154,14 @@ func TestGetGroupMemberByID(t *testing.T) {
 					GroupName:  "Group 1",
 					MemberName: stringPtr("Member 1"),
 					UserID:     int64Ptr(1),
-					UserName:   "User 1",
+					UserName:   stringPtr("User 1"),
 					CreatedAt:  time.Now(),
 				}
 				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(member, nil)
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 			},
@@ -216,7 +234,7 @@ func TestCreateGroupMember(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				userIDVal := int64(1)
@@ -226,7 +244,7 @@ func TestCreateGroupMember(t *testing.T) {
 					UserID:    &userIDVal,
 					CreatedAt: time.Now(),
 				}
-				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, UserID: &userIDVal}).Return(member, nil)
+				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, UserID: &userIDVal, Role: "member"}).Return(member, nil)
 			},
 			requestBody:    map[string]interface{}{"group_id": 1, "user_id": 1},
 			expectedStatus: http.StatusCreated,
@@ -238,21 +256,36 @@ func TestCreateGroupMember(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				member := db.GroupMember{
-					ID:        1,
-					GroupID:   1,
-					UserID:    nil,
-					CreatedAt: time.Now(),
+					ID:         1,
+					GroupID:    1,
+					MemberName: stringPtr("Sam"),
+					UserID:     nil,
+					CreatedAt:  time.Now(),
 				}
-				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, UserID: nil}).Return(member, nil)
+				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, MemberName: stringPtr("Sam"), UserID: nil, Role: "member"}).Return(member, nil)
 			},
-			requestBody:    map[string]interface{}{"group_id": 1, "user_id": nil},
+			requestBody:    map[string]interface{}{"group_id": 1, "user_id": nil, "member_name": " Sam "},
 			expectedStatus: http.StatusCreated,
 			expectMember:   true,
 		},
+		{
+			name:           "missing user_id and member_name",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    map[string]interface{}{"group_id": 1, "user_id": nil},
+			expectedStatus: http.StatusBadRequest,
+			expectMember:   false,
+		},
+		{
+			name:           "blank member_name",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    map[string]interface{}{"group_id": 1, "member_name": "  "},
+			expectedStatus: http.StatusBadRequest,
+			expectMember:   false,
+		},
 		{
 			name:           "missing group_id",
 			setupMock:      func(ms *mocks.MockStore) {},
@@ -273,11 +306,11 @@ func TestCreateGroupMember(t *testing.T) {
 				// Mock group membership check
 				userIDPtr := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userIDPtr},
+					{ID: 1, GroupID: 1, UserID: userIDPtr, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				userID := int64(1)
-				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, UserID: &userID}).Return(db.GroupMember{}, errors.New("database error"))
+				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, UserID: &userID, Role: "member"}).Return(db.GroupMember{}, errors.New("database error"))
 			},
 			requestBody:    map[string]interface{}{"group_id": 1, "user_id": 1},
 			expectedStatus: http.StatusInternalServerError,
@@ -338,7 +371,7 @@ func TestUpdateGroupMember(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				userIDVal := int64(2)
@@ -355,6 +388,38 @@ func TestUpdateGroupMember(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectMember:   true,
 		},
+		{
+			name: "owner edits an admin",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupMemberByID", mock.Anything, int64(2)).Return(db.GetGroupMemberByIDRow{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "admin"}, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "admin"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("UpdateGroupMember", mock.Anything, db.UpdateGroupMemberParams{ID: 2, GroupID: 1, UserID: int64Ptr(3)}).Return(db.GroupMember{ID: 2, GroupID: 1, UserID: int64Ptr(3), Role: "admin"}, nil)
+			},
+			pathValue:      "2",
+			requestBody:    map[string]interface{}{"group_id": 1, "user_id": 3},
+			expectedStatus: http.StatusOK,
+			expectMember:   true,
+		},
+		{
+			name: "admin can't edit another admin",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupMemberByID", mock.Anything, int64(2)).Return(db.GetGroupMemberByIDRow{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "admin"}, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "admin"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "admin"},
+					{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			pathValue:      "2",
+			requestBody:    map[string]interface{}{"group_id": 1, "user_id": 4},
+			expectedStatus: http.StatusForbidden,
+			expectMember:   false,
+		},
 		{
 			name:           "invalid ID format",
 			setupMock:      func(ms *mocks.MockStore) {},
@@ -375,7 +440,7 @@ func TestUpdateGroupMember(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 			},
@@ -406,7 +471,7 @@ func TestUpdateGroupMember(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 			},
@@ -451,41 +516,131 @@ func TestUpdateGroupMember(t *testing.T) {
 }
 
 func TestDeleteGroupMember(t *testing.T) {
+	// User 1 owns the group and removes member 2
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+		{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+	}
+	listParams := db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}
+	groupMemberRow := db.GetGroupMemberByIDRow{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"}
+	removedMember := db.GroupMember{
+		ID:        2,
+		GroupID:   1,
+		UserID:    int64Ptr(2),
+		Role:      "member",
+		RemovedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		CreatedAt: time.Now(),
+	}
+	unsettledBalances := []db.GroupBalancesNetRow{
+		{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.NewFromInt(25)},
+		{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.NewFromInt(-25)},
+	}
+
 	tests := []struct {
 		name           string
 		setupMock      func(*mocks.MockStore)
 		pathValue      string
+		query          string
 		expectedStatus int
 		expectMember   bool
 	}{
 		{
 			name: "success",
 			setupMock: func(ms *mocks.MockStore) {
-				// Get group member first
-				groupMemberRow := db.GetGroupMemberByIDRow{
-					ID:      1,
-					GroupID: 1,
-				}
-				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(groupMemberRow, nil)
-				// Mock group membership check
-				userID := int64Ptr(1)
-				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
-				}
-				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
-				userIDVal := int64(1)
-				member := db.GroupMember{
-					ID:        1,
-					GroupID:   1,
-					UserID:    &userIDVal,
-					CreatedAt: time.Now(),
-				}
-				ms.On("UnlinkGroupMember", mock.Anything, int64(1)).Return(member, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(2)).Return(groupMemberRow, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return([]db.GroupBalancesNetRow{
+					{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.Zero},
+					{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.Zero},
+				}, nil)
+				ms.On("RemoveGroupMember", mock.Anything, int64(2)).Return(removedMember, nil)
 			},
-			pathValue:      "1",
+			pathValue:      "2",
+			expectedStatus: http.StatusOK,
+			expectMember:   true,
+		},
+		{
+			name: "unsettled balance",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupMemberByID", mock.Anything, int64(2)).Return(groupMemberRow, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(unsettledBalances, nil)
+			},
+			pathValue:      "2",
+			expectedStatus: http.StatusConflict,
+			expectMember:   false,
+		},
+		{
+			name: "unsettled balance forced",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupMemberByID", mock.Anything, int64(2)).Return(groupMemberRow, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(unsettledBalances, nil)
+				ms.On("RemoveGroupMember", mock.Anything, int64(2)).Return(removedMember, nil)
+			},
+			pathValue:      "2",
+			query:          "?force=true",
 			expectedStatus: http.StatusOK,
 			expectMember:   true,
 		},
+		{
+			name: "already removed",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(db.GetGroupMemberByIDRow{
+					ID:        3,
+					GroupID:   1,
+					UserID:    int64Ptr(3),
+					Role:      "member",
+					RemovedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				}, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			pathValue:      "3",
+			expectedStatus: http.StatusBadRequest,
+			expectMember:   false,
+		},
+		{
+			name: "owner removes an admin",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(db.GetGroupMemberByIDRow{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "admin"}, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return([]db.GroupBalancesNetRow{}, nil)
+				ms.On("RemoveGroupMember", mock.Anything, int64(3)).Return(db.GroupMember{
+					ID:        3,
+					GroupID:   1,
+					UserID:    int64Ptr(3),
+					Role:      "admin",
+					RemovedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				}, nil)
+			},
+			pathValue:      "3",
+			expectedStatus: http.StatusOK,
+			expectMember:   false,
+		},
+		{
+			name: "admin can't remove another admin",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(db.GetGroupMemberByIDRow{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "admin"}, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return([]db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "admin"},
+					{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "admin"},
+					{ID: 4, GroupID: 1, UserID: int64Ptr(4), Role: "owner"},
+				}, nil)
+			},
+			pathValue:      "3",
+			expectedStatus: http.StatusForbidden,
+			expectMember:   false,
+		},
+		{
+			name: "owner can't be removed",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(db.GetGroupMemberByIDRow{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"}, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusBadRequest,
+			expectMember:   false,
+		},
 		{
 			name:           "invalid ID format",
 			setupMock:      func(ms *mocks.MockStore) {},
@@ -493,6 +648,14 @@ func TestDeleteGroupMember(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectMember:   false,
 		},
+		{
+			name:           "invalid force",
+			setupMock:      func(ms *mocks.MockStore) {},
+			pathValue:      "2",
+			query:          "?force=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectMember:   false,
+		},
 		{
 			name: "member not found",
 			setupMock: func(ms *mocks.MockStore) {
@@ -505,20 +668,12 @@ func TestDeleteGroupMember(t *testing.T) {
 		{
 			name: "database error",
 			setupMock: func(ms *mocks.MockStore) {
-				groupMemberRow := db.GetGroupMemberByIDRow{
-					ID:      1,
-					GroupID: 1,
-				}
-				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(groupMemberRow, nil)
-				// Mock group membership check
-				userID := int64Ptr(1)
-				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
-				}
-				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
-				ms.On("UnlinkGroupMember", mock.Anything, int64(1)).Return(db.GroupMember{}, errors.New("database error"))
+				ms.On("GetGroupMemberByID", mock.Anything, int64(2)).Return(groupMemberRow, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return([]db.GroupBalancesNetRow{}, nil)
+				ms.On("RemoveGroupMember", mock.Anything, int64(2)).Return(db.GroupMember{}, errors.New("database error"))
 			},
-			pathValue:      "1",
+			pathValue:      "2",
 			expectedStatus: http.StatusInternalServerError,
 			expectMember:   false,
 		},
@@ -529,7 +684,7 @@ func TestDeleteGroupMember(t *testing.T) {
 			mockStore := mocks.NewMockStore(t)
 			tt.setupMock(mockStore)
 
-			req := createRequestWithUserID("DELETE", "/group_members/"+tt.pathValue, nil, 1)
+			req := createRequestWithUserID("DELETE", "/group_members/"+tt.pathValue+tt.query, nil, 1)
 			req.SetPathValue("id", tt.pathValue)
 			rr := httptest.NewRecorder()
 
@@ -538,10 +693,243 @@ func TestDeleteGroupMember(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 			if tt.expectMember {
-				var memberResponse map[string]interface{}
+				var memberResponse models.GroupMemberResponse
 				err := json.Unmarshal(rr.Body.Bytes(), &memberResponse)
 				require.NoError(t, err)
-				assert.NotNil(t, memberResponse["id"])
+				assert.Equal(t, int64(2), memberResponse.ID)
+				assert.Equal(t, int64Ptr(2), memberResponse.UserID)
+				assert.NotNil(t, memberResponse.RemovedAt)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateGroupMembersForGroup(t *testing.T) {
+	// User 1 owns the group, member 2 leaves, placeholder Sam stays and user 3 joins
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+		{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+		{ID: 3, GroupID: 1, MemberName: stringPtr("Sam"), Role: "member"},
+	}
+	listParams := db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}
+	body := models.BatchUpdateGroupMemberRequest{Members: []models.BatchGroupMemberItem{
+		{UserID: int64Ptr(1)},
+		{MemberName: stringPtr("Sam")},
+		{UserID: int64Ptr(3)},
+	}}
+	updateParams := db.UpdateGroupMemberTxParams{GroupID: 1, GroupMembers: []db.CreateGroupMemberParams{
+		{GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+		{GroupID: 1, MemberName: stringPtr("Sam"), Role: "member"},
+		{GroupID: 1, UserID: int64Ptr(3), Role: "member"},
+	}}
+	result := db.UpdateGroupMemberTxResult{
+		Group: db.Group{ID: 1, Name: "Roommates", Currency: "USD"},
+		KeptMembers: []db.GroupMember{
+			{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+			{ID: 3, GroupID: 1, MemberName: stringPtr("Sam"), Role: "member"},
+		},
+		DeletedMembers: []db.GroupMember{
+			{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member", RemovedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+		},
+		NewMembers: []db.GroupMember{
+			{ID: 4, GroupID: 1, UserID: int64Ptr(3), Role: "member"},
+		},
+	}
+	// Sam owes the owner, only the leaving member's balance matters
+	keptUnsettled := []db.GroupBalancesNetRow{
+		{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.NewFromInt(25)},
+		{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.Zero},
+		{MemberID: 3, NetBalance: decimal.NewFromInt(-25)},
+	}
+	removedUnsettled := []db.GroupBalancesNetRow{
+		{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.NewFromInt(25)},
+		{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.NewFromInt(-25)},
+		{MemberID: 3, NetBalance: decimal.Zero},
+	}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		body           interface{}
+		query          string
+		expectedStatus int
+		expectResult   bool
+	}{
+		{
+			name: "success keeps staying members",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(keptUnsettled, nil)
+				ms.On("UpdateGroupMembersTx", mock.Anything, updateParams).Return(result, nil)
+			},
+			body:           body,
+			expectedStatus: http.StatusOK,
+			expectResult:   true,
+		},
+		{
+			name: "removed member unsettled",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(removedUnsettled, nil)
+			},
+			body:           body,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "removed member unsettled forced",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(removedUnsettled, nil)
+				ms.On("UpdateGroupMembersTx", mock.Anything, updateParams).Return(result, nil)
+			},
+			body:           body,
+			query:          "?force=true",
+			expectedStatus: http.StatusOK,
+			expectResult:   true,
+		},
+		{
+			name: "owner not included",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			body:           models.BatchUpdateGroupMemberRequest{Members: []models.BatchGroupMemberItem{{UserID: int64Ptr(2)}}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(keptUnsettled, nil)
+				ms.On("UpdateGroupMembersTx", mock.Anything, updateParams).Return(db.UpdateGroupMemberTxResult{}, errors.New("database error"))
+			},
+			body:           body,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			bodyBytes, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+			req := createRequestWithUserID("PUT", "/groups/1/members/batch"+tt.query, bodyBytes, 1)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := updateGroupMembersForGroup(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectResult {
+				var response models.BatchUpdateGroupMemberResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int32(2), response.KeptCount)
+				assert.Equal(t, int32(1), response.DeletedCount)
+				assert.Equal(t, int32(1), response.NewCount)
+				assert.Equal(t, int64(1), response.KeptMembers[0].ID)
+				assert.Equal(t, "owner", response.KeptMembers[0].Role)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteGroupMembersForGroup(t *testing.T) {
+	// User 1 owns the group, user 2 is an admin
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+		{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "admin"},
+	}
+	listParams := db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}
+	settled := []db.GroupBalancesNetRow{
+		{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.Zero},
+		{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.Zero},
+	}
+	unsettled := []db.GroupBalancesNetRow{
+		{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.NewFromInt(25)},
+		{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.NewFromInt(-25)},
+	}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		userID         int64
+		query          string
+		expectedStatus int
+	}{
+		{
+			name: "success keeps the owner",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(settled, nil)
+				ms.On("DeleteGroupMembersTx", mock.Anything, int64(1)).Return(nil)
+			},
+			userID:         1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "unsettled balance",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(unsettled, nil)
+			},
+			userID:         1,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "unsettled balance forced",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(unsettled, nil)
+				ms.On("DeleteGroupMembersTx", mock.Anything, int64(1)).Return(nil)
+			},
+			userID:         1,
+			query:          "?force=true",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "admin can't remove all members",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			userID:         2,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(settled, nil)
+				ms.On("DeleteGroupMembersTx", mock.Anything, int64(1)).Return(errors.New("database error"))
+			},
+			userID:         1,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("DELETE", "/groups/1/members/batch"+tt.query, nil, tt.userID)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := deleteGroupMembersForGroup(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.BatchDeleteGroupMemberResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), response.GroupID)
+				assert.Contains(t, response.Message, "except the owner")
 			}
 			mockStore.AssertExpectations(t)
 		})
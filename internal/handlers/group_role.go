@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+)
+
+// Change a group member's role
+// PUT /groups/{group_id}/members/{id}/role
+func updateGroupMemberRole(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} and {id} from path parameters
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		id, ok := ParsePathInt64(w, r, "id", "Group Member ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user may manage the group's members
+		requester, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionManageMembers)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can change member roles") {
+			return
+		}
+
+		// Decode request body
+		var roleReq models.UpdateGroupMemberRoleRequest
+		if err := DecodeJSONBody(r, &roleReq); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		// Validate input
+		if roleReq.Role == "" {
+			http.Error(w, "Role is required", http.StatusBadRequest)
+			return
+		}
+		role, err := ValidateNewMemberRole(roleReq.Role)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		groupMemberRow, err := store.GetGroupMemberByID(r.Context(), id)
+		if HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to get group member by ID", "group_member_id", id) {
+			return
+		}
+		if groupMemberRow.GroupID != groupID {
+			http.Error(w, "Group member not found", http.StatusNotFound)
+			return
+		}
+		if groupMemberRow.RemovedAt.Valid {
+			http.Error(w, "Removed group members can't be given a role", http.StatusBadRequest)
+			return
+		}
+
+		// The owner's role only changes when ownership is transferred
+		currentRole := auth.GroupRole(groupMemberRow.Role)
+		if currentRole == auth.GroupRoleOwner {
+			http.Error(w, "The group owner's role can't be changed, transfer ownership instead", http.StatusBadRequest)
+			return
+		}
+
+		// Only the owner adds or removes admins
+		requesterRole := auth.GroupRole(requester.Role)
+		if !auth.CanAssignRole(requesterRole, role) || (currentRole == auth.GroupRoleAdmin && requesterRole != auth.GroupRoleOwner) {
+			http.Error(w, "Forbidden: only the group owner can add or remove admins", http.StatusForbidden)
+			return
+		}
+
+		logger.Debug("Updating group member role", "group_member_id", id, "group_id", groupID, "from_role", currentRole, "to_role", role, "user_id", userID)
+
+		groupMember, err := store.UpdateGroupMemberRole(r.Context(), db.UpdateGroupMemberRoleParams{
+			ID:   id,
+			Role: string(role),
+		})
+		if HandleDBError(w, err, "Group member not found", "An error has occurred", "Failed to update group member role", "group_member_id", id) {
+			return
+		}
+
+		if err := WriteJSONResponseOK(w, groupMemberResponse(groupMember)); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Make another member the group owner, the current owner becomes an admin
+// POST /groups/{group_id}/transfer-ownership
+func transferGroupOwnership(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} from path parameter
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user is the group owner
+		owner, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionTransferOwnership)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner can transfer ownership") {
+			return
+		}
+
+		// Decode request body
+		var transferReq models.TransferGroupOwnershipRequest
+		if err := DecodeJSONBody(r, &transferReq); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		// Validate input
+		if transferReq.MemberID == 0 {
+			http.Error(w, "Member ID is required", http.StatusBadRequest)
+			return
+		}
+		if transferReq.MemberID == owner.ID {
+			http.Error(w, "You already own this group", http.StatusBadRequest)
+			return
+		}
+
+		logger.Debug("Transferring group ownership", "group_id", groupID, "from_member_id", owner.ID, "to_member_id", transferReq.MemberID, "user_id", userID)
+
+		result, err := store.TransferGroupOwnershipTx(r.Context(), db.TransferGroupOwnershipTxParams{
+			GroupID:      groupID,
+			FromMemberID: owner.ID,
+			ToMemberID:   transferReq.MemberID,
+		})
+		if err != nil {
+			logger.Error("Failed to transfer group ownership", "error", err, "group_id", groupID, "to_member_id", transferReq.MemberID)
+			http.Error(w, fmt.Sprintf("Failed to transfer ownership: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		response := models.TransferGroupOwnershipResponse{
+			PreviousOwner: groupMemberResponse(result.PreviousOwner),
+			NewOwner:      groupMemberResponse(result.NewOwner),
+		}
+
+		if err := WriteJSONResponseOK(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// groupMemberResponse converts a group member to response format
+func groupMemberResponse(groupMember db.GroupMember) models.GroupMemberResponse {
+	return models.GroupMemberResponse{
+		ID:         groupMember.ID,
+		GroupID:    groupMember.GroupID,
+		MemberName: groupMember.MemberName,
+		UserID:     groupMember.UserID,
+		JoinedOn:   DatePtr(groupMember.JoinedOn),
+		LeftOn:     DatePtr(groupMember.LeftOn),
+		Role:       groupMember.Role,
+		RemovedAt:  TimestamptzPtr(groupMember.RemovedAt),
+		CreatedAt:  groupMember.CreatedAt,
+	}
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateGroupMemberRole(t *testing.T) {
+	// User 1 owns the group, user 2 is an admin, user 3 is a member & user 4 is a viewer
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+		{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "admin"},
+		{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "member"},
+		{ID: 4, GroupID: 1, UserID: int64Ptr(4), Role: "viewer"},
+	}
+	listParams := db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}
+	memberRow := func(id int64) db.GetGroupMemberByIDRow {
+		return db.GetGroupMemberByIDRow{ID: id, GroupID: 1, UserID: members[id-1].UserID, Role: members[id-1].Role}
+	}
+
+	tests := []struct {
+		name           string
+		userID         int64
+		memberID       string
+		body           interface{}
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+		expectedRole   string
+	}{
+		{
+			name:     "owner makes a member an admin",
+			userID:   1,
+			memberID: "3",
+			body:     models.UpdateGroupMemberRoleRequest{Role: "admin"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(memberRow(3), nil)
+				ms.On("UpdateGroupMemberRole", mock.Anything, db.UpdateGroupMemberRoleParams{ID: 3, Role: "admin"}).Return(db.GroupMember{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "admin"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedRole:   "admin",
+		},
+		{
+			name:     "admin makes a member a viewer",
+			userID:   2,
+			memberID: "3",
+			body:     models.UpdateGroupMemberRoleRequest{Role: "viewer"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(3)).Return(memberRow(3), nil)
+				ms.On("UpdateGroupMemberRole", mock.Anything, db.UpdateGroupMemberRoleParams{ID: 3, Role: "viewer"}).Return(db.GroupMember{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "viewer"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedRole:   "viewer",
+		},
+		{
+			name:     "admin can't add admins",
+			userID:   2,
+			memberID: "4",
+			body:     models.UpdateGroupMemberRoleRequest{Role: "admin"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(4)).Return(memberRow(4), nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:     "admin can't demote admins",
+			userID:   2,
+			memberID: "2",
+			body:     models.UpdateGroupMemberRoleRequest{Role: "member"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(2)).Return(memberRow(2), nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:     "member can't change roles",
+			userID:   3,
+			memberID: "4",
+			body:     models.UpdateGroupMemberRoleRequest{Role: "member"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:     "owner role can't be changed",
+			userID:   1,
+			memberID: "1",
+			body:     models.UpdateGroupMemberRoleRequest{Role: "admin"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(memberRow(1), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "owner can't be assigned",
+			userID:   1,
+			memberID: "3",
+			body:     models.UpdateGroupMemberRoleRequest{Role: "owner"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid role",
+			userID:   1,
+			memberID: "3",
+			body:     models.UpdateGroupMemberRoleRequest{Role: "superuser"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "member in another group",
+			userID:   1,
+			memberID: "9",
+			body:     models.UpdateGroupMemberRoleRequest{Role: "viewer"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(9)).Return(db.GetGroupMemberByIDRow{ID: 9, GroupID: 2, Role: "member"}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "invalid JSON",
+			userID:   1,
+			memberID: "3",
+			body:     "invalid",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			var body []byte
+			if str, ok := tt.body.(string); ok {
+				body = []byte(str)
+			} else {
+				body, _ = json.Marshal(tt.body)
+			}
+
+			req := createRequestWithUserID("PUT", "/groups/1/members/"+tt.memberID+"/role", body, tt.userID)
+			req.SetPathValue("group_id", "1")
+			req.SetPathValue("id", tt.memberID)
+			rr := httptest.NewRecorder()
+
+			handler := updateGroupMemberRole(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.GroupMemberResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedRole, response.Role)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferGroupOwnership(t *testing.T) {
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+		{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "admin"},
+		{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "member"},
+	}
+	listParams := db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}
+
+	tests := []struct {
+		name           string
+		userID         int64
+		body           interface{}
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name:   "owner transfers to a member",
+			userID: 1,
+			body:   models.TransferGroupOwnershipRequest{MemberID: 3},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("TransferGroupOwnershipTx", mock.Anything, db.TransferGroupOwnershipTxParams{GroupID: 1, FromMemberID: 1, ToMemberID: 3}).Return(db.TransferGroupOwnershipTxResult{
+					PreviousOwner: db.GroupMember{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "admin"},
+					NewOwner:      db.GroupMember{ID: 3, GroupID: 1, UserID: int64Ptr(3), Role: "owner"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "admin can't transfer ownership",
+			userID: 2,
+			body:   models.TransferGroupOwnershipRequest{MemberID: 3},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "not a group member",
+			userID: 9,
+			body:   models.TransferGroupOwnershipRequest{MemberID: 3},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "transfer to self",
+			userID: 1,
+			body:   models.TransferGroupOwnershipRequest{MemberID: 1},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "missing member_id",
+			userID: 1,
+			body:   map[string]interface{}{},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "new owner without a user",
+			userID: 1,
+			body:   models.TransferGroupOwnershipRequest{MemberID: 4},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, listParams).Return(members, nil)
+				ms.On("TransferGroupOwnershipTx", mock.Anything, mock.Anything).Return(db.TransferGroupOwnershipTxResult{}, errors.New("member 4 must be linked to a user to own the group"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			body, _ := json.Marshal(tt.body)
+			req := createRequestWithUserID("POST", "/groups/1/transfer-ownership", body, tt.userID)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := transferGroupOwnership(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.TransferGroupOwnershipResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, "admin", response.PreviousOwner.Role)
+				assert.Equal(t, "owner", response.NewOwner.Role)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -10,7 +10,10 @@ import (
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
 	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -119,7 +122,7 @@ func TestGetGroupByID(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 			},
@@ -180,31 +183,73 @@ func TestGetGroupByID(t *testing.T) {
 
 func TestCreateGroup(t *testing.T) {
 	tests := []struct {
-		name           string
-		setupMock      func(*mocks.MockStore)
-		requestBody    interface{}
-		expectedStatus int
-		expectGroup    bool
+		name                string
+		setupMock           func(*mocks.MockStore)
+		requestBody         interface{}
+		requireVerification bool
+		expectedStatus      int
+		expectGroup         bool
 	}{
 		{
 			name: "success",
 			setupMock: func(ms *mocks.MockStore) {
-				group := db.Group{ID: 1, Name: "New Group"}
-				ms.On("CreateGroup", mock.Anything, "New Group").Return(group, nil)
+				group := db.Group{ID: 1, Name: "New Group", Currency: "USD"}
+				ms.On("CreateGroup", mock.Anything, db.CreateGroupParams{Name: "New Group", Currency: "USD"}).Return(group, nil)
 				// Automatically add creator as group member
 				userID := int64Ptr(1)
 				groupMember := db.GroupMember{
 					ID:        1,
 					GroupID:   1,
 					UserID:    userID,
+					Role:      "owner",
 					CreatedAt: time.Now(),
 				}
-				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, UserID: userID}).Return(groupMember, nil)
+				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, UserID: userID, Role: "owner"}).Return(groupMember, nil)
 			},
 			requestBody:    map[string]string{"name": "New Group"},
 			expectedStatus: http.StatusCreated,
 			expectGroup:    true,
 		},
+		{
+			name: "success with base currency",
+			setupMock: func(ms *mocks.MockStore) {
+				group := db.Group{ID: 1, Name: "Trip", Currency: "EUR"}
+				ms.On("CreateGroup", mock.Anything, db.CreateGroupParams{Name: "Trip", Currency: "EUR"}).Return(group, nil)
+				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, UserID: int64Ptr(1), Role: "owner"}).Return(db.GroupMember{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"}, nil)
+			},
+			requestBody:    map[string]string{"name": "Trip", "currency": "eur"},
+			expectedStatus: http.StatusCreated,
+			expectGroup:    true,
+		},
+		{
+			name: "verified user when verification is required",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(db.User{ID: 1, EmailVerified: true}, nil)
+				ms.On("CreateGroup", mock.Anything, db.CreateGroupParams{Name: "New Group", Currency: "USD"}).Return(db.Group{ID: 1, Name: "New Group", Currency: "USD"}, nil)
+				ms.On("CreateGroupMember", mock.Anything, db.CreateGroupMemberParams{GroupID: 1, UserID: int64Ptr(1), Role: "owner"}).Return(db.GroupMember{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"}, nil)
+			},
+			requestBody:         map[string]string{"name": "New Group"},
+			requireVerification: true,
+			expectedStatus:      http.StatusCreated,
+			expectGroup:         true,
+		},
+		{
+			name: "unverified user when verification is required",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(db.User{ID: 1, EmailVerified: false}, nil)
+			},
+			requestBody:         map[string]string{"name": "New Group"},
+			requireVerification: true,
+			expectedStatus:      http.StatusForbidden,
+			expectGroup:         false,
+		},
+		{
+			name:           "invalid currency",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    map[string]string{"name": "Trip", "currency": "euro"},
+			expectedStatus: http.StatusBadRequest,
+			expectGroup:    false,
+		},
 		{
 			name:           "missing name",
 			setupMock:      func(ms *mocks.MockStore) {},
@@ -229,7 +274,7 @@ func TestCreateGroup(t *testing.T) {
 		{
 			name: "database error",
 			setupMock: func(ms *mocks.MockStore) {
-				ms.On("CreateGroup", mock.Anything, "New Group").Return(db.Group{}, errors.New("database error"))
+				ms.On("CreateGroup", mock.Anything, db.CreateGroupParams{Name: "New Group", Currency: "USD"}).Return(db.Group{}, errors.New("database error"))
 				// Note: CreateGroupMember won't be called if CreateGroup fails
 			},
 			requestBody:    map[string]string{"name": "New Group"},
@@ -252,6 +297,12 @@ func TestCreateGroup(t *testing.T) {
 				require.NoError(t, err)
 			}
 
+			if tt.requireVerification {
+				t.Setenv("REQUIRE_EMAIL_VERIFICATION", "true")
+			} else {
+				t.Setenv("REQUIRE_EMAIL_VERIFICATION", "")
+			}
+
 			req := createRequestWithUserID("POST", "/groups", bodyBytes, 1)
 			rr := httptest.NewRecorder()
 
@@ -287,7 +338,7 @@ func TestUpdateGroup(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 			},
@@ -310,7 +361,7 @@ func TestUpdateGroup(t *testing.T) {
 				// Mock group membership check (handler checks this before decoding body)
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 			},
@@ -325,7 +376,7 @@ func TestUpdateGroup(t *testing.T) {
 				// Mock group membership check first
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 999, UserID: userID},
+					{ID: 1, GroupID: 999, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 999, Limit: 1000, Offset: 0}).Return(members, nil)
 				ms.On("UpdateGroup", mock.Anything, db.UpdateGroupParams{ID: 999, Name: "Updated Group"}).Return(db.Group{}, pgx.ErrNoRows)
@@ -341,7 +392,7 @@ func TestUpdateGroup(t *testing.T) {
 				// Mock group membership check (handler checks this before decoding body)
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 			},
@@ -401,7 +452,7 @@ func TestDeleteGroup(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 			},
@@ -409,6 +460,19 @@ func TestDeleteGroup(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectGroup:    true,
 		},
+		{
+			name: "admin can't delete the group",
+			setupMock: func(ms *mocks.MockStore) {
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "admin"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusForbidden,
+			expectGroup:    false,
+		},
 		{
 			name:           "invalid ID format",
 			setupMock:      func(ms *mocks.MockStore) {},
@@ -422,7 +486,7 @@ func TestDeleteGroup(t *testing.T) {
 				// Mock group membership check first
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 999, UserID: userID},
+					{ID: 1, GroupID: 999, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 999, Limit: 1000, Offset: 0}).Return(members, nil)
 				ms.On("DeleteGroup", mock.Anything, int64(999)).Return(db.Group{}, pgx.ErrNoRows)
@@ -437,7 +501,7 @@ func TestDeleteGroup(t *testing.T) {
 				// Mock group membership check first
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				ms.On("DeleteGroup", mock.Anything, int64(1)).Return(db.Group{}, errors.New("database error"))
@@ -471,3 +535,99 @@ func TestDeleteGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestGetGroupBalances(t *testing.T) {
+	// Members 1-3 are owed 90, 50 and 70, members 4-6 owe 30, 40 and 140
+	netBalances := []db.GroupBalancesNetRow{
+		{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.NewFromInt(90)},
+		{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.NewFromInt(50)},
+		{MemberID: 3, UserID: int64Ptr(3), NetBalance: decimal.NewFromInt(70)},
+		{MemberID: 4, UserID: int64Ptr(4), NetBalance: decimal.NewFromInt(-30)},
+		{MemberID: 5, UserID: int64Ptr(5), NetBalance: decimal.NewFromInt(-40)},
+		{MemberID: 6, UserID: int64Ptr(6), NetBalance: decimal.NewFromInt(-140)},
+	}
+
+	tests := []struct {
+		name             string
+		query            string
+		setupMock        func(*mocks.MockStore)
+		expectedStatus   int
+		expectedSimplify string
+		expectedPayments int
+	}{
+		{
+			name:  "greedy by default",
+			query: "",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Trip", Currency: "USD"}, nil)
+				ms.On("GroupBalances", mock.Anything, int64(1)).Return([]db.GroupBalancesRow{}, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(netBalances, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedSimplify: "greedy",
+			expectedPayments: 5,
+		},
+		{
+			name:  "optimal finds fewer payments",
+			query: "?simplify=optimal",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Trip", Currency: "USD"}, nil)
+				ms.On("GroupBalances", mock.Anything, int64(1)).Return([]db.GroupBalancesRow{}, nil)
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(netBalances, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedSimplify: "optimal",
+			expectedPayments: 4,
+		},
+		{
+			name:           "invalid simplify method",
+			query:          "?simplify=fastest",
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "optimal with too many members",
+			query: "?simplify=optimal",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Trip", Currency: "USD"}, nil)
+				ms.On("GroupBalances", mock.Anything, int64(1)).Return([]db.GroupBalancesRow{}, nil)
+				var manyBalances []db.GroupBalancesNetRow
+				for i := int64(1); i <= services.MaxOptimalSimplifyMembers+1; i++ {
+					balance := decimal.NewFromInt(1)
+					if i == 1 {
+						balance = decimal.NewFromInt(-services.MaxOptimalSimplifyMembers)
+					}
+					manyBalances = append(manyBalances, db.GroupBalancesNetRow{MemberID: i, UserID: int64Ptr(i), NetBalance: balance})
+				}
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(manyBalances, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			mockStore.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2, 3, 4, 5, 6}, 1), nil)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", "/groups/1/balances"+tt.query, nil, 1)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := getGroupBalances(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.GroupBalancesResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedSimplify, response.Simplify)
+				assert.Equal(t, int32(tt.expectedPayments), response.SimplifiedPaymentsCount)
+				assert.Len(t, response.SimplifiedPayments, tt.expectedPayments)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/MattSharp0/transaction-split-go/internal/auth"
 	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // ParsePathInt64 extracts and parses an int64 path parameter from the request.
@@ -67,6 +68,61 @@ func ParseQueryDate(r *http.Request, paramName string, defaultValue time.Time) (
 	return date, nil
 }
 
+// ParseQueryBool extracts and parses a bool query parameter from the request.
+// Accepts the values understood by strconv.ParseBool, e.g. "true", "false", "1" or "0".
+// Returns the parsed value, or defaultValue if the parameter is not present.
+// Returns an error if the parameter is present but invalid.
+func ParseQueryBool(r *http.Request, paramName string, defaultValue bool) (bool, error) {
+	queryParams := r.URL.Query()
+	paramStr := queryParams.Get(paramName)
+	if paramStr == "" {
+		return defaultValue, nil
+	}
+
+	param, err := strconv.ParseBool(paramStr)
+	if err != nil {
+		return defaultValue, err
+	}
+
+	return param, nil
+}
+
+// DateFromPtr converts an optional request date to a nullable database date.
+// A nil date is stored as NULL.
+func DateFromPtr(date *time.Time) pgtype.Date {
+	if date == nil {
+		return pgtype.Date{}
+	}
+	return pgtype.Date{Time: *date, Valid: true}
+}
+
+// DatePtr converts a nullable database date to an optional response date.
+// Returns nil when the date is NULL.
+func DatePtr(date pgtype.Date) *time.Time {
+	if !date.Valid {
+		return nil
+	}
+	return &date.Time
+}
+
+// TimestamptzPtr converts a nullable database timestamp to an optional response timestamp.
+// Returns nil when the timestamp is NULL.
+func TimestamptzPtr(timestamp pgtype.Timestamptz) *time.Time {
+	if !timestamp.Valid {
+		return nil
+	}
+	return &timestamp.Time
+}
+
+// StringPtr converts an optional request string to a nullable database value.
+// An empty string is stored as NULL.
+func StringPtr(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
 // ParseLimitOffset parses limit and offset query parameters from the request.
 // Returns limit and offset with default values of 100 and 0 respectively.
 // Returns an error if either parameter is invalid. The error will indicate which parameter failed.
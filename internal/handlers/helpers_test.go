@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/mail"
 	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -34,6 +37,24 @@ func int64Ptr(i int64) *int64 {
 	return &i
 }
 
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// fakeMailSender records sent messages instead of delivering them
+type fakeMailSender struct {
+	messages []mail.Message
+	err      error
+}
+
+func (f *fakeMailSender) Send(ctx context.Context, msg mail.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.messages = append(f.messages, msg)
+	return nil
+}
+
 // Helper function to create a request with path values
 func createRequestWithPath(method, url, pathParamName, pathParamValue string, body []byte) *http.Request {
 	req := httptest.NewRequest(method, url, bytes.NewBuffer(body))
@@ -199,6 +220,66 @@ func TestParseQueryInt32(t *testing.T) {
 	}
 }
 
+// TestParseQueryBool tests the ParseQueryBool function
+func TestParseQueryBool(t *testing.T) {
+	tests := []struct {
+		name         string
+		queryParam   string
+		defaultValue bool
+		expected     bool
+		expectError  bool
+	}{
+		{
+			name:         "true",
+			queryParam:   "true",
+			defaultValue: false,
+			expected:     true,
+			expectError:  false,
+		},
+		{
+			name:         "numeric false",
+			queryParam:   "0",
+			defaultValue: true,
+			expected:     false,
+			expectError:  false,
+		},
+		{
+			name:         "missing parameter",
+			queryParam:   "",
+			defaultValue: true,
+			expected:     true,
+			expectError:  false,
+		},
+		{
+			name:         "invalid format",
+			queryParam:   "yes",
+			defaultValue: false,
+			expected:     false,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/test"
+			if tt.queryParam != "" {
+				url += "?param=" + tt.queryParam
+			}
+			req := createRequest("GET", url, nil)
+
+			result, err := ParseQueryBool(req, "param", tt.defaultValue)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.defaultValue, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
 // TestParseLimitOffset tests the ParseLimitOffset function
 func TestParseLimitOffset(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/mail"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// magicLinkMessage is returned whether or not an account exists, so the endpoint can't be used to find registered emails
+const magicLinkMessage = "If an account exists for that email, a login link has been sent"
+
+// Email a single use login link
+// POST /auth/magic-link
+func requestMagicLink(store db.Store, mailer mail.Sender) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.MagicLinkRequest
+		if err := DecodeJSONBody(r, &req); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if req.Email == "" {
+			http.Error(w, "Email is required", http.StatusBadRequest)
+			return
+		}
+
+		user, err := store.GetUserByEmail(r.Context(), req.Email)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				logger.Debug("Login link requested for unknown email", "email", req.Email)
+				writeMessageResponse(w, magicLinkMessage)
+				return
+			}
+			logger.Error("Failed to get user by email", "error", err)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		// Failures after the account is found get the generic response too, an error only for registered emails would reveal them
+		token, err := issueOneTimeToken(r.Context(), store, user.ID, db.TokenPurposeMagicLink, "MAGIC_LINK_EXPIRATION_MINUTES", 15)
+		if err != nil {
+			logger.Error("Failed to store login link token", "error", err, "user_id", user.ID)
+			writeMessageResponse(w, magicLinkMessage)
+			return
+		}
+
+		// The link opens the callback on the API, so the session cookies are set for the API
+		err = mailer.Send(r.Context(), mail.Message{
+			To:      user.Email,
+			Subject: "Your login link",
+			Body:    tokenEmailBody(user.Name, "log in", os.Getenv("API_URL"), "/auth/magic-link/callback", token, "If you didn't ask to log in, you can ignore this email."),
+		})
+		if err != nil {
+			logger.Error("Failed to send login link email", "error", err, "user_id", user.ID)
+			writeMessageResponse(w, magicLinkMessage)
+			return
+		}
+
+		logger.Info("Login link email sent", slog.Int64("user_id", user.ID))
+
+		writeMessageResponse(w, magicLinkMessage)
+	}
+}
+
+// Log in with the token from a login link, setting the same cookies as login
+// GET /auth/magic-link/callback?token=
+func magicLinkCallback(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			http.Error(w, "Token is required", http.StatusBadRequest)
+			return
+		}
+
+		// Using the token marks it used, so the link only works once
+		loginToken, err := store.UseOneTimeTokenTx(r.Context(), db.UseOneTimeTokenTxParams{
+			TokenHash: auth.HashRefreshToken(token),
+			Purpose:   db.TokenPurposeMagicLink,
+		})
+		if err != nil {
+			if errors.Is(err, db.ErrOneTimeTokenInvalid) {
+				logger.Warn("Invalid login link token")
+				http.Error(w, "Invalid or expired login link", http.StatusUnauthorized)
+				return
+			}
+			logger.Error("Failed to use login link token", "error", err)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		user, err := store.GetUserByID(r.Context(), loginToken.UserID)
+		if HandleDBError(w, err, "User not found", "An error has occurred", "Failed to get user by ID", "user_id", loginToken.UserID) {
+			return
+		}
+
+		// Issue tokens & set cookies
+		accessToken, csrfToken, ok := startSession(w, r, store, user.ID)
+		if !ok {
+			return
+		}
+
+		logger.Debug("Login link successful", slog.Int64("user_id", user.ID))
+
+		// Send response
+		if err := WriteJSONResponseOK(w, loginResponse(user, accessToken, csrfToken)); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestMagicLink(t *testing.T) {
+	t.Setenv("API_URL", "https://api.split.example.com")
+	user := db.User{ID: 1, Name: "Alice", Email: "alice@example.com"}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestBody    string
+		sendErr        error
+		expectedStatus int
+		expectSent     bool
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+				ms.On("IssueOneTimeTokenTx", mock.Anything, mock.MatchedBy(func(arg db.CreateOneTimeTokenParams) bool {
+					return arg.Purpose == db.TokenPurposeMagicLink && arg.UserID == 1
+				})).Return(db.OneTimeToken{ID: 1}, nil)
+			},
+			requestBody:    `{"email": "alice@example.com"}`,
+			expectedStatus: http.StatusOK,
+			expectSent:     true,
+		},
+		{
+			name: "unknown email gets the same response",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(db.User{}, pgx.ErrNoRows)
+			},
+			requestBody:    `{"email": "nobody@example.com"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing email",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "token store error gets the same response",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+				ms.On("IssueOneTimeTokenTx", mock.Anything, mock.Anything).Return(db.OneTimeToken{}, errors.New("database error"))
+			},
+			requestBody:    `{"email": "alice@example.com"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "mail sender error gets the same response",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+				ms.On("IssueOneTimeTokenTx", mock.Anything, mock.Anything).Return(db.OneTimeToken{ID: 1}, nil)
+			},
+			requestBody:    `{"email": "alice@example.com"}`,
+			sendErr:        errors.New("smtp unavailable"),
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+			mailer := &fakeMailSender{err: tt.sendErr}
+
+			req := createRequest("POST", "/auth/magic-link", []byte(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			handler := requestMagicLink(mockStore, mailer)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]string
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, magicLinkMessage, response["message"])
+			}
+			if tt.expectSent {
+				require.Len(t, mailer.messages, 1)
+				body := mailer.messages[0].Body
+				assert.Contains(t, body, "https://api.split.example.com/auth/magic-link/callback?token=")
+
+				// Only the hash of the emailed token is stored
+				start := strings.Index(body, "token=") + len("token=")
+				token, err := url.QueryUnescape(strings.Fields(body[start:])[0])
+				require.NoError(t, err)
+				issued := mockStore.Calls[1].Arguments.Get(1).(db.CreateOneTimeTokenParams)
+				assert.Equal(t, auth.HashRefreshToken(token), issued.TokenHash)
+			} else {
+				assert.Empty(t, mailer.messages)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMagicLinkCallback(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	useParams := db.UseOneTimeTokenTxParams{TokenHash: auth.HashRefreshToken("login-token"), Purpose: db.TokenPurposeMagicLink}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestURL     string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UseOneTimeTokenTx", mock.Anything, useParams).Return(db.OneTimeToken{ID: 1, UserID: 1}, nil)
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(db.User{ID: 1, Name: "Alice"}, nil)
+				ms.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(arg db.CreateRefreshTokenParams) bool {
+					return arg.UserID == 1
+				})).Return(db.RefreshToken{ID: 1, UserID: 1}, nil)
+			},
+			requestURL:     "/auth/magic-link/callback?token=login-token",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid, expired or used token",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UseOneTimeTokenTx", mock.Anything, useParams).Return(db.OneTimeToken{}, db.ErrOneTimeTokenInvalid)
+			},
+			requestURL:     "/auth/magic-link/callback?token=login-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing token",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestURL:     "/auth/magic-link/callback",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UseOneTimeTokenTx", mock.Anything, useParams).Return(db.OneTimeToken{}, errors.New("database error"))
+			},
+			requestURL:     "/auth/magic-link/callback?token=login-token",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequest("GET", tt.requestURL, nil)
+			rr := httptest.NewRecorder()
+
+			handler := magicLinkCallback(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.LoginResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), response.User.ID)
+				assert.NotEmpty(t, response.Token)
+				assert.NotEmpty(t, response.CSRFToken)
+
+				// The same cookies as login are set
+				cookies := map[string]bool{}
+				for _, cookie := range rr.Result().Cookies() {
+					cookies[cookie.Name] = true
+				}
+				assert.True(t, cookies[auth.AccessTokenCookieName])
+				assert.True(t, cookies[auth.RefreshTokenCookieName])
+				assert.True(t, cookies[auth.CSRFTokenCookieName])
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/mail"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// minPasswordLength is the shortest password accepted when registering or setting a new password
+const minPasswordLength = 8
+
+// forgotPasswordMessage is returned whether or not an account exists, so the endpoint can't be used to find registered emails
+const forgotPasswordMessage = "If an account exists for that email, a password reset email has been sent"
+
+// Email a single use password reset token
+// POST /auth/password/forgot
+func forgotPassword(store db.Store, mailer mail.Sender) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.ForgotPasswordRequest
+		if err := DecodeJSONBody(r, &req); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if req.Email == "" {
+			http.Error(w, "Email is required", http.StatusBadRequest)
+			return
+		}
+
+		user, err := store.GetUserByEmail(r.Context(), req.Email)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				logger.Debug("Password reset requested for unknown email", "email", req.Email)
+				writeMessageResponse(w, forgotPasswordMessage)
+				return
+			}
+			logger.Error("Failed to get user by email", "error", err)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		// Failures after the account is found get the generic response too, an error only for registered emails would reveal them
+		token, err := issueOneTimeToken(r.Context(), store, user.ID, db.TokenPurposePasswordReset, "PASSWORD_RESET_EXPIRATION_MINUTES", 60)
+		if err != nil {
+			logger.Error("Failed to store password reset token", "error", err, "user_id", user.ID)
+			writeMessageResponse(w, forgotPasswordMessage)
+			return
+		}
+
+		err = mailer.Send(r.Context(), mail.Message{
+			To:      user.Email,
+			Subject: "Reset your password",
+			Body:    tokenEmailBody(user.Name, "reset your password", os.Getenv("APP_URL"), "/reset-password", token, "If you didn't ask to reset your password, you can ignore this email."),
+		})
+		if err != nil {
+			logger.Error("Failed to send password reset email", "error", err, "user_id", user.ID)
+			writeMessageResponse(w, forgotPasswordMessage)
+			return
+		}
+
+		logger.Info("Password reset email sent", slog.Int64("user_id", user.ID))
+
+		writeMessageResponse(w, forgotPasswordMessage)
+	}
+}
+
+// Set a new password with a password reset token, ending every session
+// POST /auth/password/reset
+func resetPassword(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.ResetPasswordRequest
+		if err := DecodeJSONBody(r, &req); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if req.Token == "" {
+			http.Error(w, "Token is required", http.StatusBadRequest)
+			return
+		}
+		if !validateNewPassword(w, req.Password) {
+			return
+		}
+
+		passwordHash, err := auth.HashPassword(req.Password)
+		if err != nil {
+			logger.Error("Failed to hash password", "error", err)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		result, err := store.ResetPasswordTx(r.Context(), db.ResetPasswordTxParams{
+			TokenHash:    auth.HashRefreshToken(req.Token),
+			PasswordHash: passwordHash,
+		})
+		if err != nil {
+			if errors.Is(err, db.ErrOneTimeTokenInvalid) {
+				logger.Warn("Invalid password reset token")
+				http.Error(w, "Invalid or expired reset token", http.StatusBadRequest)
+				return
+			}
+			logger.Error("Failed to reset password", "error", err)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		// End every session, including any started with the old password
+		if err := auth.RevokeAllUserTokens(r.Context(), store, result.User.ID); err != nil {
+			logger.Error("Password reset but failed to revoke refresh tokens", "error", err, "user_id", result.User.ID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+		auth.ClearAuthCookies(w)
+
+		logger.Info("Password reset", slog.Int64("user_id", result.User.ID))
+
+		writeMessageResponse(w, "Password reset, log in with your new password")
+	}
+}
+
+// Change the authenticated user's password, ending every other session
+// POST /auth/password/change
+func changePassword(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var req models.ChangePasswordRequest
+		if err := DecodeJSONBody(r, &req); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if req.CurrentPassword == "" {
+			http.Error(w, "Current password is required", http.StatusBadRequest)
+			return
+		}
+		if !validateNewPassword(w, req.NewPassword) {
+			return
+		}
+
+		user, err := store.GetUserByID(r.Context(), userID)
+		if HandleDBError(w, err, "User not found", "An error has occurred", "Failed to get user by ID", "user_id", userID) {
+			return
+		}
+
+		if err := auth.VerifyPassword(user.PasswordHash, req.CurrentPassword); err != nil {
+			logger.Warn("Password change failed: invalid current password", "user_id", userID)
+			http.Error(w, "Forbidden: current password is incorrect", http.StatusForbidden)
+			return
+		}
+
+		passwordHash, err := auth.HashPassword(req.NewPassword)
+		if err != nil {
+			logger.Error("Failed to hash password", "error", err)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		_, err = store.UpdateUserPassword(r.Context(), db.UpdateUserPasswordParams{ID: userID, PasswordHash: passwordHash})
+		if HandleDBError(w, err, "User not found", "An error has occurred", "Failed to update password", "user_id", userID) {
+			return
+		}
+
+		// Reset emails sent before the change can't be used to undo it
+		err = store.InvalidateUserOneTimeTokens(r.Context(), db.InvalidateUserOneTimeTokensParams{UserID: userID, Purpose: db.TokenPurposePasswordReset})
+		if err != nil {
+			logger.Warn("Failed to invalidate password reset tokens", "error", err, "user_id", userID)
+			// Continue anyway - reset tokens expire on their own
+		}
+
+		// End every session, then start a new one for this client
+		if err := auth.RevokeAllUserTokens(r.Context(), store, userID); err != nil {
+			logger.Error("Password changed but failed to revoke refresh tokens", "error", err, "user_id", userID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		accessToken, csrfToken, ok := startSession(w, r, store, userID)
+		if !ok {
+			return
+		}
+
+		logger.Info("Password changed", slog.Int64("user_id", userID))
+
+		refreshResponse := models.RefreshResponse{
+			Token:     accessToken,
+			CSRFToken: csrfToken,
+		}
+
+		if err := WriteJSONResponseOK(w, refreshResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// issueOneTimeToken generates a one time token for a user and stores its hash, returning the token to email to them
+// Tokens are generated and hashed like refresh tokens, they expire after the minutes set in expirationEnv or defaultMinutes
+func issueOneTimeToken(ctx context.Context, store db.Store, userID int64, purpose, expirationEnv string, defaultMinutes int) (string, error) {
+	token, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	expirationMinutes := defaultMinutes
+	if expStr := os.Getenv(expirationEnv); expStr != "" {
+		if parsed, err := strconv.Atoi(expStr); err == nil {
+			expirationMinutes = parsed
+		}
+	}
+
+	_, err = store.IssueOneTimeTokenTx(ctx, db.CreateOneTimeTokenParams{
+		Purpose:   purpose,
+		TokenHash: auth.HashRefreshToken(token),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(time.Duration(expirationMinutes) * time.Minute),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// validateNewPassword checks a new password is long enough
+// Writes an error response and returns false if it isn't
+func validateNewPassword(w http.ResponseWriter, password string) bool {
+	if password == "" {
+		http.Error(w, "Password is required", http.StatusBadRequest)
+		return false
+	}
+	if len(password) < minPasswordLength {
+		http.Error(w, "Password must be at least 8 characters", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// writeMessageResponse writes a JSON response containing only a message
+func writeMessageResponse(w http.ResponseWriter, message string) {
+	if err := WriteJSONResponseOK(w, map[string]string{"message": message}); err != nil {
+		http.Error(w, "An error has occurred", http.StatusInternalServerError)
+	}
+}
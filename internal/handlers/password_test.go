@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForgotPassword(t *testing.T) {
+	t.Setenv("APP_URL", "")
+	user := db.User{ID: 1, Name: "Alice", Email: "alice@example.com"}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestBody    string
+		sendErr        error
+		expectedStatus int
+		expectSent     bool
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+				ms.On("IssueOneTimeTokenTx", mock.Anything, mock.MatchedBy(func(arg db.CreateOneTimeTokenParams) bool {
+					return arg.Purpose == db.TokenPurposePasswordReset && arg.UserID == 1 && arg.TokenHash != ""
+				})).Return(db.OneTimeToken{ID: 1}, nil)
+			},
+			requestBody:    `{"email": "alice@example.com"}`,
+			expectedStatus: http.StatusOK,
+			expectSent:     true,
+		},
+		{
+			name: "unknown email gets the same response",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(db.User{}, pgx.ErrNoRows)
+			},
+			requestBody:    `{"email": "nobody@example.com"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing email",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "token store error gets the same response",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+				ms.On("IssueOneTimeTokenTx", mock.Anything, mock.Anything).Return(db.OneTimeToken{}, errors.New("database error"))
+			},
+			requestBody:    `{"email": "alice@example.com"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "mail sender error gets the same response",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+				ms.On("IssueOneTimeTokenTx", mock.Anything, mock.Anything).Return(db.OneTimeToken{ID: 1}, nil)
+			},
+			requestBody:    `{"email": "alice@example.com"}`,
+			sendErr:        errors.New("smtp unavailable"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(db.User{}, errors.New("database error"))
+			},
+			requestBody:    `{"email": "alice@example.com"}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+			mailer := &fakeMailSender{err: tt.sendErr}
+
+			req := createRequest("POST", "/auth/password/forgot", []byte(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			handler := forgotPassword(mockStore, mailer)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]string
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, forgotPasswordMessage, response["message"])
+			}
+			if tt.expectSent {
+				require.Len(t, mailer.messages, 1)
+				assert.Equal(t, "alice@example.com", mailer.messages[0].To)
+
+				// Only the hash of the emailed token is stored
+				body := mailer.messages[0].Body
+				start := strings.Index(body, "use this token:\n\n") + len("use this token:\n\n")
+				token := strings.Fields(body[start:])[0]
+				issued := mockStore.Calls[1].Arguments.Get(1).(db.CreateOneTimeTokenParams)
+				assert.Equal(t, auth.HashRefreshToken(token), issued.TokenHash)
+			} else {
+				assert.Empty(t, mailer.messages)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	tokenHash := auth.HashRefreshToken("reset-token")
+	passwordMatches := func(arg db.ResetPasswordTxParams) bool {
+		return arg.TokenHash == tokenHash && auth.VerifyPassword(arg.PasswordHash, "newpassword") == nil
+	}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestBody    string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ResetPasswordTx", mock.Anything, mock.MatchedBy(passwordMatches)).Return(db.ResetPasswordTxResult{User: db.User{ID: 1}}, nil)
+				ms.On("RevokeAllUserTokens", mock.Anything, int64(1)).Return(nil)
+			},
+			requestBody:    `{"token": "reset-token", "password": "newpassword"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid or used token",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ResetPasswordTx", mock.Anything, mock.MatchedBy(passwordMatches)).Return(db.ResetPasswordTxResult{}, db.ErrOneTimeTokenInvalid)
+			},
+			requestBody:    `{"token": "reset-token", "password": "newpassword"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing token",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    `{"password": "newpassword"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "password too short",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    `{"token": "reset-token", "password": "short"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "failed to revoke sessions",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ResetPasswordTx", mock.Anything, mock.MatchedBy(passwordMatches)).Return(db.ResetPasswordTxResult{User: db.User{ID: 1}}, nil)
+				ms.On("RevokeAllUserTokens", mock.Anything, int64(1)).Return(errors.New("database error"))
+			},
+			requestBody:    `{"token": "reset-token", "password": "newpassword"}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequest("POST", "/auth/password/reset", []byte(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			handler := resetPassword(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				// Cookies for any existing session are cleared
+				for _, cookie := range rr.Result().Cookies() {
+					assert.Equal(t, -1, cookie.MaxAge)
+				}
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	currentHash, err := auth.HashPassword("oldpassword")
+	require.NoError(t, err)
+	user := db.User{ID: 1, Name: "Alice", Email: "alice@example.com", PasswordHash: currentHash}
+	passwordMatches := func(arg db.UpdateUserPasswordParams) bool {
+		return arg.ID == 1 && auth.VerifyPassword(arg.PasswordHash, "newpassword") == nil
+	}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestBody    string
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(user, nil)
+				ms.On("UpdateUserPassword", mock.Anything, mock.MatchedBy(passwordMatches)).Return(user, nil)
+				ms.On("InvalidateUserOneTimeTokens", mock.Anything, db.InvalidateUserOneTimeTokensParams{UserID: 1, Purpose: db.TokenPurposePasswordReset}).Return(nil)
+				ms.On("RevokeAllUserTokens", mock.Anything, int64(1)).Return(nil)
+				ms.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(arg db.CreateRefreshTokenParams) bool {
+					return arg.UserID == 1
+				})).Return(db.RefreshToken{ID: 2, UserID: 1}, nil)
+			},
+			requestBody:    `{"current_password": "oldpassword", "new_password": "newpassword"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "incorrect current password",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(user, nil)
+			},
+			requestBody:    `{"current_password": "wrongpassword", "new_password": "newpassword"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "missing current password",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    `{"new_password": "newpassword"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "new password too short",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestBody:    `{"current_password": "oldpassword", "new_password": "short"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "failed to revoke sessions",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserByID", mock.Anything, int64(1)).Return(user, nil)
+				ms.On("UpdateUserPassword", mock.Anything, mock.MatchedBy(passwordMatches)).Return(user, nil)
+				ms.On("InvalidateUserOneTimeTokens", mock.Anything, db.InvalidateUserOneTimeTokensParams{UserID: 1, Purpose: db.TokenPurposePasswordReset}).Return(nil)
+				ms.On("RevokeAllUserTokens", mock.Anything, int64(1)).Return(errors.New("database error"))
+			},
+			requestBody:    `{"current_password": "oldpassword", "new_password": "newpassword"}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("POST", "/auth/password/change", []byte(tt.requestBody), 1)
+			rr := httptest.NewRecorder()
+
+			handler := changePassword(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]string
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.NotEmpty(t, response["token"])
+				assert.NotEmpty(t, response["csrf_token"])
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefresh(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	oldTokenHash := auth.HashRefreshToken("old-refresh-token")
+	family := pgtype.UUID{Bytes: [16]byte{1, 2, 3}, Valid: true}
+
+	// The rotated token must replace the presented one, with a new token
+	rotateParams := mock.MatchedBy(func(arg db.RotateRefreshTokenTxParams) bool {
+		return arg.TokenHash == oldTokenHash && arg.NewTokenHash != "" && arg.NewTokenHash != oldTokenHash
+	})
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		refreshToken   string
+		expectedStatus int
+		expectCleared  bool
+	}{
+		{
+			name: "success rotates the token",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("RotateRefreshTokenTx", mock.Anything, rotateParams).Return(db.RotateRefreshTokenTxResult{
+					OldToken: db.RefreshToken{ID: 1, UserID: 1, FamilyID: family},
+					NewToken: db.RefreshToken{ID: 2, UserID: 1, FamilyID: family},
+				}, nil)
+			},
+			refreshToken:   "old-refresh-token",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "reused token revokes the family",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("RotateRefreshTokenTx", mock.Anything, rotateParams).Return(db.RotateRefreshTokenTxResult{
+					OldToken:      db.RefreshToken{ID: 1, UserID: 1, FamilyID: family},
+					Reused:        true,
+					RevokedFamily: 1,
+				}, nil)
+			},
+			refreshToken:   "old-refresh-token",
+			expectedStatus: http.StatusUnauthorized,
+			expectCleared:  true,
+		},
+		{
+			name: "expired token",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("RotateRefreshTokenTx", mock.Anything, rotateParams).Return(db.RotateRefreshTokenTxResult{}, db.ErrRefreshTokenExpired)
+			},
+			refreshToken:   "old-refresh-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "unknown token",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("RotateRefreshTokenTx", mock.Anything, rotateParams).Return(db.RotateRefreshTokenTxResult{}, db.ErrRefreshTokenNotFound)
+			},
+			refreshToken:   "old-refresh-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing token",
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("RotateRefreshTokenTx", mock.Anything, rotateParams).Return(db.RotateRefreshTokenTxResult{}, errors.New("database error"))
+			},
+			refreshToken:   "old-refresh-token",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequest("POST", "/auth/refresh", nil)
+			if tt.refreshToken != "" {
+				req.AddCookie(&http.Cookie{Name: auth.RefreshTokenCookieName, Value: tt.refreshToken})
+			}
+			rr := httptest.NewRecorder()
+
+			handler := refresh(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+
+			cookies := map[string]*http.Cookie{}
+			for _, cookie := range rr.Result().Cookies() {
+				cookies[cookie.Name] = cookie
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response models.RefreshResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.NotEmpty(t, response.Token)
+				assert.NotEmpty(t, response.CSRFToken)
+
+				// The new refresh token is set, not the presented one
+				require.Contains(t, cookies, auth.RefreshTokenCookieName)
+				newToken := cookies[auth.RefreshTokenCookieName].Value
+				assert.NotEqual(t, "old-refresh-token", newToken)
+				mockStore.AssertCalled(t, "RotateRefreshTokenTx", mock.Anything, mock.MatchedBy(func(arg db.RotateRefreshTokenTxParams) bool {
+					return arg.NewTokenHash == auth.HashRefreshToken(newToken)
+				}))
+			}
+			if tt.expectCleared {
+				require.Contains(t, cookies, auth.RefreshTokenCookieName)
+				assert.Empty(t, cookies[auth.RefreshTokenCookieName].Value)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
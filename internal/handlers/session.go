@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxDeviceInfoLength limits how many bytes of the User-Agent header are stored
+const maxDeviceInfoLength = 255
+
+// listSessions lists the user's active sessions, one per login, most recently used first
+func listSessions(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		logger.Debug("Listing sessions", "user_id", userID)
+
+		tokens, err := store.GetUserRefreshTokens(r.Context(), userID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list refresh tokens", "user_id", userID) {
+			return
+		}
+
+		currentHash := currentRefreshTokenHash(r)
+		sessions := make([]models.SessionResponse, len(tokens))
+		for i, token := range tokens {
+			sessions[i] = sessionResponse(token, currentHash)
+		}
+
+		response := models.ListSessionResponse{
+			Sessions: sessions,
+			Count:    int32(len(sessions)),
+		}
+
+		if err := WriteJSONResponseOK(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// revokeSession logs out one of the user's sessions, revoking every refresh token issued to it
+// Revoking the current session also clears the auth cookies
+func revokeSession(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		sessionID := r.PathValue("id")
+
+		logger.Debug("Revoking session", "user_id", userID, "session_id", sessionID)
+
+		// Only the user's own active sessions can be revoked
+		tokens, err := store.GetUserRefreshTokens(r.Context(), userID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list refresh tokens", "user_id", userID) {
+			return
+		}
+
+		var session *db.RefreshToken
+		for i := range tokens {
+			if tokens[i].FamilyID.String() == sessionID {
+				session = &tokens[i]
+				break
+			}
+		}
+		if session == nil {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+
+		if _, err := store.RevokeRefreshTokenFamily(r.Context(), session.FamilyID); err != nil {
+			logger.Error("Failed to revoke session", "error", err, "user_id", userID, "session_id", sessionID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		response := sessionResponse(*session, currentRefreshTokenHash(r))
+		if response.Current {
+			auth.ClearAuthCookies(w)
+		}
+
+		logger.Info("Session revoked", "user_id", userID, "session_id", sessionID)
+
+		if err := WriteJSONResponseOK(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// revokeOtherSessions logs out every session of the user except the one making the request
+// The current session is found from the refresh token cookie
+func revokeOtherSessions(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		logger.Debug("Revoking other sessions", "user_id", userID)
+
+		currentHash := currentRefreshTokenHash(r)
+		if currentHash == "" {
+			http.Error(w, "Refresh token cookie is required to identify the current session", http.StatusBadRequest)
+			return
+		}
+
+		current, err := store.GetRefreshTokenByHash(r.Context(), currentHash)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			logger.Error("Failed to get refresh token", "error", err, "user_id", userID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+		if err != nil || current.UserID != userID || current.RevokedAt.Valid {
+			http.Error(w, "Refresh token cookie is required to identify the current session", http.StatusBadRequest)
+			return
+		}
+
+		revoked, err := store.RevokeOtherUserRefreshTokens(r.Context(), db.RevokeOtherUserRefreshTokensParams{
+			UserID:   userID,
+			FamilyID: current.FamilyID,
+		})
+		if err != nil {
+			logger.Error("Failed to revoke other sessions", "error", err, "user_id", userID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("Other sessions revoked", "user_id", userID, "revoked_tokens", revoked)
+
+		if err := WriteJSONResponseOK(w, models.RevokeOtherSessionsResponse{Revoked: revoked}); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// sessionResponse converts the active refresh token of a session to response format
+func sessionResponse(token db.RefreshToken, currentHash string) models.SessionResponse {
+	return models.SessionResponse{
+		ID:         token.FamilyID.String(),
+		DeviceInfo: token.DeviceInfo,
+		IPAddress:  token.IpAddress,
+		LastUsedAt: token.LastUsedAt,
+		ExpiresAt:  token.ExpiresAt,
+		Current:    currentHash != "" && token.TokenHash == currentHash,
+	}
+}
+
+// currentRefreshTokenHash returns the hash of the refresh token cookie sent with the request, or "" without one
+func currentRefreshTokenHash(r *http.Request) string {
+	refreshToken := auth.GetTokenFromCookie(r, auth.RefreshTokenCookieName)
+	if refreshToken == "" {
+		return ""
+	}
+	return auth.HashRefreshToken(refreshToken)
+}
+
+// clientDeviceInfo returns the User-Agent of the client to describe its device, nil without one
+func clientDeviceInfo(r *http.Request) *string {
+	userAgent := strings.TrimSpace(r.UserAgent())
+	if userAgent == "" {
+		return nil
+	}
+	if len(userAgent) > maxDeviceInfoLength {
+		// Cut on a character boundary so the stored value stays valid UTF-8
+		end := maxDeviceInfoLength
+		for end > 0 && !utf8.RuneStart(userAgent[end]) {
+			end--
+		}
+		userAgent = userAgent[:end]
+	}
+	return StringPtr(userAgent)
+}
+
+// clientIPAddress returns the IP address of the client
+// Behind a reverse proxy set TRUST_PROXY_HEADERS to use the first address in X-Forwarded-For, it is ignored otherwise since clients can set it
+func clientIPAddress(r *http.Request) *string {
+	trustProxy := os.Getenv("TRUST_PROXY_HEADERS")
+	if trustProxy == "true" || trustProxy == "1" {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			ip := strings.TrimSpace(strings.Split(forwarded, ",")[0])
+			if net.ParseIP(ip) != nil {
+				return StringPtr(ip)
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if host == "" {
+		return nil
+	}
+	return StringPtr(host)
+}
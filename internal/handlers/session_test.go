@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	currentFamily = pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	otherFamily   = pgtype.UUID{Bytes: [16]byte{2}, Valid: true}
+)
+
+// testSessionTokens returns the active refresh tokens of the current session and another session of user 1
+func testSessionTokens() []db.RefreshToken {
+	now := time.Now()
+	return []db.RefreshToken{
+		{
+			ID:         1,
+			TokenHash:  auth.HashRefreshToken("current-refresh-token"),
+			UserID:     1,
+			FamilyID:   currentFamily,
+			DeviceInfo: StringPtr("Mozilla/5.0 (Macintosh)"),
+			IpAddress:  StringPtr("203.0.113.1"),
+			LastUsedAt: now,
+			ExpiresAt:  now.Add(7 * 24 * time.Hour),
+		},
+		{
+			ID:         2,
+			TokenHash:  auth.HashRefreshToken("other-refresh-token"),
+			UserID:     1,
+			FamilyID:   otherFamily,
+			LastUsedAt: now.Add(-time.Hour),
+			ExpiresAt:  now.Add(6 * 24 * time.Hour),
+		},
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		refreshToken   string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "success marks the current session",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserRefreshTokens", mock.Anything, int64(1)).Return(testSessionTokens(), nil)
+			},
+			refreshToken:   "current-refresh-token",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserRefreshTokens", mock.Anything, int64(1)).Return([]db.RefreshToken{}, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", "/auth/sessions", nil, 1)
+			if tt.refreshToken != "" {
+				req.AddCookie(&http.Cookie{Name: auth.RefreshTokenCookieName, Value: tt.refreshToken})
+			}
+			rr := httptest.NewRecorder()
+
+			handler := listSessions(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.ListSessionResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Len(t, response.Sessions, tt.expectedCount)
+				assert.Equal(t, currentFamily.String(), response.Sessions[0].ID)
+				assert.True(t, response.Sessions[0].Current)
+				assert.Equal(t, "203.0.113.1", *response.Sessions[0].IPAddress)
+				assert.False(t, response.Sessions[1].Current)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		sessionID      string
+		expectedStatus int
+		expectCleared  bool
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserRefreshTokens", mock.Anything, int64(1)).Return(testSessionTokens(), nil)
+				ms.On("RevokeRefreshTokenFamily", mock.Anything, otherFamily).Return(int64(1), nil)
+			},
+			sessionID:      otherFamily.String(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "revoking the current session clears cookies",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserRefreshTokens", mock.Anything, int64(1)).Return(testSessionTokens(), nil)
+				ms.On("RevokeRefreshTokenFamily", mock.Anything, currentFamily).Return(int64(1), nil)
+			},
+			sessionID:      currentFamily.String(),
+			expectedStatus: http.StatusOK,
+			expectCleared:  true,
+		},
+		{
+			name: "session not found",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserRefreshTokens", mock.Anything, int64(1)).Return(testSessionTokens(), nil)
+			},
+			sessionID:      "not-a-session",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetUserRefreshTokens", mock.Anything, int64(1)).Return(testSessionTokens(), nil)
+				ms.On("RevokeRefreshTokenFamily", mock.Anything, otherFamily).Return(int64(0), errors.New("database error"))
+			},
+			sessionID:      otherFamily.String(),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("DELETE", "/auth/sessions/"+tt.sessionID, nil, 1)
+			req.SetPathValue("id", tt.sessionID)
+			req.AddCookie(&http.Cookie{Name: auth.RefreshTokenCookieName, Value: "current-refresh-token"})
+			rr := httptest.NewRecorder()
+
+			handler := revokeSession(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.SessionResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tt.sessionID, response.ID)
+				assert.Equal(t, tt.expectCleared, response.Current)
+			}
+			assert.Equal(t, tt.expectCleared, len(rr.Result().Cookies()) > 0)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	currentHash := auth.HashRefreshToken("current-refresh-token")
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		refreshToken   string
+		expectedStatus int
+		expectedCount  int64
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetRefreshTokenByHash", mock.Anything, currentHash).Return(testSessionTokens()[0], nil)
+				ms.On("RevokeOtherUserRefreshTokens", mock.Anything, db.RevokeOtherUserRefreshTokensParams{
+					UserID:   1,
+					FamilyID: currentFamily,
+				}).Return(int64(3), nil)
+			},
+			refreshToken:   "current-refresh-token",
+			expectedStatus: http.StatusOK,
+			expectedCount:  3,
+		},
+		{
+			name:           "missing refresh token cookie",
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown refresh token",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetRefreshTokenByHash", mock.Anything, currentHash).Return(db.RefreshToken{}, pgx.ErrNoRows)
+			},
+			refreshToken:   "current-refresh-token",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "refresh token of another user",
+			setupMock: func(ms *mocks.MockStore) {
+				token := testSessionTokens()[0]
+				token.UserID = 2
+				ms.On("GetRefreshTokenByHash", mock.Anything, currentHash).Return(token, nil)
+			},
+			refreshToken:   "current-refresh-token",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetRefreshTokenByHash", mock.Anything, currentHash).Return(testSessionTokens()[0], nil)
+				ms.On("RevokeOtherUserRefreshTokens", mock.Anything, mock.Anything).Return(int64(0), errors.New("database error"))
+			},
+			refreshToken:   "current-refresh-token",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("DELETE", "/auth/sessions/others", nil, 1)
+			if tt.refreshToken != "" {
+				req.AddCookie(&http.Cookie{Name: auth.RefreshTokenCookieName, Value: tt.refreshToken})
+			}
+			rr := httptest.NewRecorder()
+
+			handler := revokeOtherSessions(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.RevokeOtherSessionsResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedCount, response.Revoked)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClientIPAddress(t *testing.T) {
+	tests := []struct {
+		name         string
+		trustProxy   string
+		remoteAddr   string
+		forwardedFor string
+		expectedIP   string
+	}{
+		{
+			name:       "remote address",
+			remoteAddr: "203.0.113.1:54321",
+			expectedIP: "203.0.113.1",
+		},
+		{
+			name:         "forwarded header ignored by default",
+			remoteAddr:   "10.0.0.1:54321",
+			forwardedFor: "198.51.100.7",
+			expectedIP:   "10.0.0.1",
+		},
+		{
+			name:         "first forwarded address behind a trusted proxy",
+			trustProxy:   "true",
+			remoteAddr:   "10.0.0.1:54321",
+			forwardedFor: "198.51.100.7, 10.0.0.2",
+			expectedIP:   "198.51.100.7",
+		},
+		{
+			name:         "invalid forwarded address falls back to remote address",
+			trustProxy:   "true",
+			remoteAddr:   "10.0.0.1:54321",
+			forwardedFor: "not-an-ip",
+			expectedIP:   "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRUST_PROXY_HEADERS", tt.trustProxy)
+
+			req := createRequest("POST", "/auth/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+
+			ip := clientIPAddress(req)
+			require.NotNil(t, ip)
+			assert.Equal(t, tt.expectedIP, *ip)
+		})
+	}
+}
+
+func TestClientDeviceInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		userAgent      string
+		expectNil      bool
+		expectedDevice string
+	}{
+		{
+			name:      "no user agent",
+			userAgent: "",
+			expectNil: true,
+		},
+		{
+			name:           "short user agent",
+			userAgent:      "  Mozilla/5.0 (Macintosh)  ",
+			expectedDevice: "Mozilla/5.0 (Macintosh)",
+		},
+		{
+			name:           "long user agent is truncated",
+			userAgent:      strings.Repeat("a", 300),
+			expectedDevice: strings.Repeat("a", maxDeviceInfoLength),
+		},
+		{
+			name:           "long non-ASCII user agent is cut on a character boundary",
+			userAgent:      "Mozilla/5.0 " + strings.Repeat("é", 200),
+			expectedDevice: "Mozilla/5.0 " + strings.Repeat("é", 121),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createRequest("POST", "/auth/login", nil)
+			req.Header.Set("User-Agent", tt.userAgent)
+
+			device := clientDeviceInfo(req)
+			if tt.expectNil {
+				assert.Nil(t, device)
+				return
+			}
+			require.NotNil(t, device)
+			assert.Equal(t, tt.expectedDevice, *device)
+			assert.True(t, utf8.ValidString(*device))
+			assert.LessOrEqual(t, len(*device), maxDeviceInfoLength)
+		})
+	}
+}
+
+func TestLoginRecordsDevice(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	passwordHash, err := auth.HashPassword("securepassword123")
+	require.NoError(t, err)
+
+	mockStore := mocks.NewMockStore(t)
+	mockStore.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(db.User{ID: 1, Name: "Alice", PasswordHash: passwordHash}, nil)
+	mockStore.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(arg db.CreateRefreshTokenParams) bool {
+		return arg.UserID == 1 &&
+			arg.DeviceInfo != nil && *arg.DeviceInfo == "Mozilla/5.0 (Macintosh)" &&
+			arg.IpAddress != nil && *arg.IpAddress == "203.0.113.1"
+	})).Return(db.RefreshToken{ID: 1, UserID: 1}, nil)
+
+	req := createRequest("POST", "/auth/login", []byte(`{"email": "alice@example.com", "password": "securepassword123"}`))
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh)")
+	req.RemoteAddr = "203.0.113.1:54321"
+	rr := httptest.NewRecorder()
+
+	handler := login(mockStore)
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockStore.AssertExpectations(t)
+}
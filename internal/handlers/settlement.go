@@ -0,0 +1,455 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
+)
+
+// List settlements in group
+// GET /groups/{group_id}/settlements
+func listGroupSettlements(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} from path parameter
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user is a member of the group
+		if err := auth.CheckGroupMembership(r.Context(), store, groupID, userID); err != nil {
+			http.Error(w, "Forbidden: User is not a current group member", http.StatusForbidden)
+			return
+		}
+
+		// Parse query parameters
+		limit, offset, err := ParseLimitOffset(r)
+		if err != nil {
+			http.Error(w, "Invalid parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.Debug("Listing settlements for group", "group_id", groupID, "limit", limit, "offset", offset)
+
+		settlements, err := store.ListSettlementsByGroupID(r.Context(), db.ListSettlementsByGroupIDParams{
+			GroupID: groupID,
+			Limit:   limit,
+			Offset:  offset,
+		})
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list settlements by group", "group_id", groupID) {
+			return
+		}
+
+		settlementResponses := settlementResponses(settlements)
+
+		listSettlementResponse := models.ListSettlementResponse{
+			Settlements: settlementResponses,
+			Count:       int32(len(settlementResponses)),
+			Limit:       limit,
+			Offset:      offset,
+		}
+
+		if err := WriteJSONResponseOK(w, listSettlementResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Record a settle up payment between two group members
+// POST /groups/{group_id}/settlements
+func createGroupSettlement(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} from path parameter
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user may add transactions to the group
+		_, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionAddTransactions)
+		if HandleGroupPermissionError(w, err, "Forbidden: viewers can't add settlements") {
+			return
+		}
+
+		// Decode request body
+		var createSettlementReq models.CreateSettlementRequest
+		if err := DecodeJSONBody(r, &createSettlementReq); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		// Validate input
+		if !createSettlementReq.Amount.IsPositive() {
+			http.Error(w, "Amount must be greater than 0", http.StatusBadRequest)
+			return
+		}
+		if !createSettlementReq.Amount.Equal(createSettlementReq.Amount.Round(2)) {
+			http.Error(w, "Amount must have at most 2 decimal places", http.StatusBadRequest)
+			return
+		}
+
+		// Verify both members are in the group
+		groupMembers, err := store.ListGroupMembersByGroupID(r.Context(), db.ListGroupMembersByGroupIDParams{GroupID: groupID, Limit: 1000, Offset: 0})
+		if HandleDBError(w, err, "Group members not found", "An error has occurred", "Failed to get group members by group ID", "group id", groupID) {
+			return
+		}
+		if err := ValidateSettlementMembersInGroup(createSettlementReq.FromMember, createSettlementReq.ToMember, groupMembers, groupID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		settledOn := createSettlementReq.SettledOn
+		if settledOn.IsZero() {
+			settledOn = time.Now()
+		}
+
+		// Pending until the recipient confirms, unless the recipient is recording it
+		status := initialSettlementStatus(groupMembers, createSettlementReq.ToMember, userID)
+
+		logger.Debug("Creating settlement", "group_id", groupID, "from_member", createSettlementReq.FromMember, "to_member", createSettlementReq.ToMember, "amount", createSettlementReq.Amount, "status", status, "user_id", userID)
+
+		settlement, err := store.CreateSettlement(r.Context(), db.CreateSettlementParams{
+			GroupID:    groupID,
+			FromMember: createSettlementReq.FromMember,
+			ToMember:   createSettlementReq.ToMember,
+			Amount:     createSettlementReq.Amount,
+			SettledOn:  settledOn,
+			Note:       createSettlementReq.Note,
+			CreatedBy:  &userID,
+			Status:     string(status),
+		})
+		if HandleDBListError(w, err, "An error has occurred", "Failed to create settlement", "group_id", groupID) {
+			return
+		}
+
+		if err := WriteJSONResponseCreated(w, settlementResponse(settlement)); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Record every payment suggested by the simplified group balances in one database transaction
+// Each settlement is pending until its recipient confirms it
+// POST /groups/{group_id}/settlements/settle-all
+func settleAllGroupBalances(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} from path parameter
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user may add transactions to the group
+		_, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionAddTransactions)
+		if HandleGroupPermissionError(w, err, "Forbidden: viewers can't add settlements") {
+			return
+		}
+
+		netBalances, err := store.GroupBalancesNet(r.Context(), groupID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get group net balances", "group_id", groupID) {
+			return
+		}
+
+		// Simplify by group member ID, settlements are between members
+		payments, err := services.SimplifyDebts(netBalancesForSimplification(netBalances, true))
+		if err != nil {
+			logger.Error("Failed to simplify debts", "error", err, "group_id", groupID)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		if len(payments) == 0 {
+			logger.Debug("Group is already settled up", "group_id", groupID)
+			response := models.SettleAllResponse{
+				GroupID:     groupID,
+				Settlements: []models.SettlementResponse{},
+				Count:       0,
+			}
+			if err := WriteJSONResponseOK(w, response); err != nil {
+				http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		logger.Debug("Settling all group balances", "group_id", groupID, "payment_count", len(payments), "user_id", userID)
+
+		groupMembers, err := store.ListGroupMembersByGroupID(r.Context(), db.ListGroupMembersByGroupIDParams{GroupID: groupID, Limit: 1000, Offset: 0})
+		if HandleDBError(w, err, "Group members not found", "An error has occurred", "Failed to get group members by group ID", "group id", groupID) {
+			return
+		}
+
+		settledOn := time.Now()
+		settlementParams := make([]db.CreateSettlementParams, len(payments))
+		for i, payment := range payments {
+			settlementParams[i] = db.CreateSettlementParams{
+				GroupID:    groupID,
+				FromMember: payment.FromUserID,
+				ToMember:   payment.ToUserID,
+				Amount:     payment.Amount,
+				SettledOn:  settledOn,
+				CreatedBy:  &userID,
+				Status:     string(initialSettlementStatus(groupMembers, payment.ToUserID, userID)),
+			}
+		}
+
+		result, err := store.CreateSettlementsTx(r.Context(), db.CreateSettlementsTxParams{
+			GroupID:     groupID,
+			Settlements: settlementParams,
+		})
+		if err != nil {
+			logger.Error("Failed to settle all group balances", "error", err, "group_id", groupID)
+			http.Error(w, fmt.Sprintf("Failed to settle all balances: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		settlementResponses := settlementResponses(result.Settlements)
+
+		response := models.SettleAllResponse{
+			GroupID:     groupID,
+			Settlements: settlementResponses,
+			Count:       int32(len(settlementResponses)),
+		}
+
+		if err := WriteJSONResponseCreated(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Confirm, dispute or cancel a settlement
+// Only the member who was paid may confirm or dispute, only the paying member or the recorder may cancel
+// POST /groups/{group_id}/settlements/{settlement_id}/confirm
+// POST /groups/{group_id}/settlements/{settlement_id}/dispute
+// POST /groups/{group_id}/settlements/{settlement_id}/cancel
+func updateGroupSettlementStatus(store db.Store, status services.SettlementStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} and {settlement_id} from path parameters
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		settlementID, ok := ParsePathInt64(w, r, "settlement_id", "Settlement ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user may add transactions to the group
+		_, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionAddTransactions)
+		if HandleGroupPermissionError(w, err, "Forbidden: viewers can't add settlements") {
+			return
+		}
+
+		settlement, err := store.GetSettlementByID(r.Context(), settlementID)
+		if HandleDBError(w, err, "Settlement not found", "An error has occurred", "Failed to get settlement by ID", "settlement_id", settlementID) {
+			return
+		}
+		if settlement.GroupID != groupID {
+			http.Error(w, "Settlement not found", http.StatusNotFound)
+			return
+		}
+
+		// Verify user may act for the member on the right side of the settlement
+		if status == services.SettlementStatusCancelled {
+			isRecorder := settlement.CreatedBy != nil && *settlement.CreatedBy == userID
+			if !isRecorder {
+				if err := auth.CheckActsForMember(r.Context(), store, groupID, settlement.FromMember, userID); err != nil {
+					http.Error(w, "Forbidden: only the paying member can cancel this settlement", http.StatusForbidden)
+					return
+				}
+			}
+		} else {
+			if err := auth.CheckActsForMember(r.Context(), store, groupID, settlement.ToMember, userID); err != nil {
+				http.Error(w, "Forbidden: only the member who was paid can confirm or dispute this settlement", http.StatusForbidden)
+				return
+			}
+		}
+
+		logger.Debug("Updating settlement status", "settlement_id", settlementID, "group_id", groupID, "status", status, "user_id", userID)
+
+		result, err := store.UpdateSettlementStatusTx(r.Context(), db.UpdateSettlementStatusTxParams{
+			SettlementID: settlementID,
+			Status:       string(status),
+			FromStatuses: services.SettlementStatusesBefore(status),
+		})
+		if err != nil {
+			logger.Error("Failed to update settlement status", "error", err, "settlement_id", settlementID, "status", status)
+			http.Error(w, fmt.Sprintf("Failed to update settlement: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if err := WriteJSONResponseOK(w, settlementResponse(result.Settlement)); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Get settlement by ID
+// GET /groups/{group_id}/settlements/{settlement_id}
+func getGroupSettlementByID(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} and {settlement_id} from path parameters
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		settlementID, ok := ParsePathInt64(w, r, "settlement_id", "Settlement ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user is a member of the group
+		if err := auth.CheckGroupMembership(r.Context(), store, groupID, userID); err != nil {
+			http.Error(w, "Forbidden: User is not a current group member", http.StatusForbidden)
+			return
+		}
+
+		settlement, err := store.GetSettlementByID(r.Context(), settlementID)
+		if HandleDBError(w, err, "Settlement not found", "An error has occurred", "Failed to get settlement by ID", "settlement_id", settlementID) {
+			return
+		}
+		if settlement.GroupID != groupID {
+			http.Error(w, "Settlement not found", http.StatusNotFound)
+			return
+		}
+
+		if err := WriteJSONResponseOK(w, settlementResponse(settlement)); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Delete settlement, the settled balances are owed again
+// DELETE /groups/{group_id}/settlements/{settlement_id}
+func deleteGroupSettlement(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} and {settlement_id} from path parameters
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		settlementID, ok := ParsePathInt64(w, r, "settlement_id", "Settlement ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user may record settlements in the group
+		requester, err := auth.CheckGroupPermission(r.Context(), store, groupID, userID, auth.PermissionAddTransactions)
+		if HandleGroupPermissionError(w, err, "Forbidden: viewers can't delete settlements") {
+			return
+		}
+
+		settlement, err := store.GetSettlementByID(r.Context(), settlementID)
+		if HandleDBError(w, err, "Settlement not found", "An error has occurred", "Failed to get settlement by ID", "settlement_id", settlementID) {
+			return
+		}
+		if settlement.GroupID != groupID {
+			http.Error(w, "Settlement not found", http.StatusNotFound)
+			return
+		}
+
+		// Members can only delete the settlements they recorded
+		recordedByUser := settlement.CreatedBy != nil && *settlement.CreatedBy == userID
+		if !recordedByUser && !auth.HasPermission(auth.GroupRole(requester.Role), auth.PermissionEditAnyTransaction) {
+			http.Error(w, "Forbidden: only the group owner or an admin can delete settlements recorded by other members", http.StatusForbidden)
+			return
+		}
+
+		logger.Debug("Deleting settlement", "settlement_id", settlementID, "group_id", groupID, "user_id", userID)
+
+		settlement, err = store.DeleteSettlement(r.Context(), settlementID)
+		if HandleDBError(w, err, "Settlement not found", "An error has occurred", "Failed to delete settlement", "settlement_id", settlementID) {
+			return
+		}
+
+		if err := WriteJSONResponseOK(w, settlementResponse(settlement)); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// initialSettlementStatus returns the status for a new settlement, confirmed when the user recording it is the member who was paid, otherwise pending
+func initialSettlementStatus(groupMembers []db.ListGroupMembersByGroupIDRow, toMember, userID int64) services.SettlementStatus {
+	for _, member := range groupMembers {
+		if member.ID == toMember && member.UserID != nil && *member.UserID == userID {
+			return services.SettlementStatusConfirmed
+		}
+	}
+	return services.SettlementStatusPending
+}
+
+// settlementResponse converts a settlement to response format
+func settlementResponse(settlement db.Settlement) models.SettlementResponse {
+	return models.SettlementResponse{
+		ID:         settlement.ID,
+		GroupID:    settlement.GroupID,
+		FromMember: settlement.FromMember,
+		ToMember:   settlement.ToMember,
+		Amount:     settlement.Amount,
+		SettledOn:  settlement.SettledOn,
+		Note:       settlement.Note,
+		Status:     settlement.Status,
+		CreatedBy:  settlement.CreatedBy,
+		CreatedAt:  settlement.CreatedAt,
+		ModifiedAt: settlement.ModifiedAt,
+	}
+}
+
+// settlementResponses converts settlements to response format
+func settlementResponses(settlements []db.Settlement) []models.SettlementResponse {
+	responses := make([]models.SettlementResponse, len(settlements))
+	for i, settlement := range settlements {
+		responses[i] = settlementResponse(settlement)
+	}
+	return responses
+}
@@ -0,0 +1,507 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListGroupSettlements(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				settlements := []db.Settlement{
+					{ID: 1, GroupID: 1, FromMember: 2, ToMember: 1, Amount: decimal.NewFromInt(20), SettledOn: time.Now()},
+				}
+				ms.On("ListSettlementsByGroupID", mock.Anything, db.ListSettlementsByGroupIDParams{GroupID: 1, Limit: 100, Offset: 0}).Return(settlements, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name: "not a group member",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return([]db.ListGroupMembersByGroupIDRow{}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				ms.On("ListSettlementsByGroupID", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", "/groups/1/settlements", nil, 1)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := listGroupSettlements(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.ListSettlementResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int32(tt.expectedCount), response.Count)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateGroupSettlement(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           interface{}
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: models.CreateSettlementRequest{
+				FromMember: 2,
+				ToMember:   1,
+				Amount:     decimal.RequireFromString("25.50"),
+			},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				ms.On("CreateSettlement", mock.Anything, mock.MatchedBy(func(arg db.CreateSettlementParams) bool {
+					return arg.GroupID == 1 && arg.FromMember == 2 && arg.ToMember == 1 &&
+						arg.Amount.Equal(decimal.RequireFromString("25.50")) &&
+						!arg.SettledOn.IsZero() && arg.CreatedBy != nil && *arg.CreatedBy == 1 &&
+						arg.Status == "confirmed" // Recorded by the recipient
+				})).Return(db.Settlement{ID: 1, GroupID: 1, FromMember: 2, ToMember: 1, Amount: decimal.RequireFromString("25.50"), Status: "confirmed"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "success pending until recipient confirms",
+			body: models.CreateSettlementRequest{
+				FromMember: 1,
+				ToMember:   2,
+				Amount:     decimal.NewFromInt(10),
+			},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				ms.On("CreateSettlement", mock.Anything, mock.MatchedBy(func(arg db.CreateSettlementParams) bool {
+					return arg.FromMember == 1 && arg.ToMember == 2 && arg.Status == "pending"
+				})).Return(db.Settlement{ID: 2, GroupID: 1, FromMember: 1, ToMember: 2, Amount: decimal.NewFromInt(10), Status: "pending"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "same member",
+			body: models.CreateSettlementRequest{
+				FromMember: 1,
+				ToMember:   1,
+				Amount:     decimal.NewFromInt(10),
+			},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "member not in group",
+			body: models.CreateSettlementRequest{
+				FromMember: 3,
+				ToMember:   1,
+				Amount:     decimal.NewFromInt(10),
+			},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "zero amount",
+			body: models.CreateSettlementRequest{
+				FromMember: 2,
+				ToMember:   1,
+				Amount:     decimal.Zero,
+			},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "fractional cents",
+			body: models.CreateSettlementRequest{
+				FromMember: 2,
+				ToMember:   1,
+				Amount:     decimal.RequireFromString("10.005"),
+			},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid JSON",
+			body: "invalid",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not a group member",
+			body: models.CreateSettlementRequest{
+				FromMember: 2,
+				ToMember:   1,
+				Amount:     decimal.NewFromInt(10),
+			},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return([]db.ListGroupMembersByGroupIDRow{}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			var body []byte
+			if str, ok := tt.body.(string); ok {
+				body = []byte(str)
+			} else {
+				body, _ = json.Marshal(tt.body)
+			}
+
+			req := createRequestWithUserID("POST", "/groups/1/settlements", body, 1)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := createGroupSettlement(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSettleAllGroupBalances(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "success records simplified payments",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2, 3}, 1), nil)
+				// Member 1 is owed 30, members 2 and 3 owe 20 and 10
+				netBalances := []db.GroupBalancesNetRow{
+					{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.NewFromInt(30)},
+					{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.NewFromInt(-20)},
+					{MemberID: 3, UserID: int64Ptr(3), NetBalance: decimal.NewFromInt(-10)},
+				}
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(netBalances, nil)
+				ms.On("CreateSettlementsTx", mock.Anything, mock.MatchedBy(func(arg db.CreateSettlementsTxParams) bool {
+					if arg.GroupID != 1 || len(arg.Settlements) != 2 {
+						return false
+					}
+					paid := map[int64]decimal.Decimal{}
+					for _, s := range arg.Settlements {
+						// User 1 is the recipient so the settlements are confirmed
+						if s.ToMember != 1 || s.GroupID != 1 || s.Status != "confirmed" {
+							return false
+						}
+						paid[s.FromMember] = s.Amount
+					}
+					return paid[2].Equal(decimal.NewFromInt(20)) && paid[3].Equal(decimal.NewFromInt(10))
+				})).Return(db.CreateSettlementsTxResult{
+					Settlements: []db.Settlement{
+						{ID: 1, GroupID: 1, FromMember: 2, ToMember: 1, Amount: decimal.NewFromInt(20)},
+						{ID: 2, GroupID: 1, FromMember: 3, ToMember: 1, Amount: decimal.NewFromInt(10)},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedCount:  2,
+		},
+		{
+			name: "already settled up",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				netBalances := []db.GroupBalancesNetRow{
+					{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.Zero},
+					{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.Zero},
+				}
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(netBalances, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  0,
+		},
+		{
+			name: "settlement transaction fails",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				netBalances := []db.GroupBalancesNetRow{
+					{MemberID: 1, UserID: int64Ptr(1), NetBalance: decimal.NewFromInt(15)},
+					{MemberID: 2, UserID: int64Ptr(2), NetBalance: decimal.NewFromInt(-15)},
+				}
+				ms.On("GroupBalancesNet", mock.Anything, int64(1)).Return(netBalances, nil)
+				ms.On("CreateSettlementsTx", mock.Anything, mock.Anything).Return(db.CreateSettlementsTxResult{}, errors.New("failed to create settlement"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not a group member",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return([]db.ListGroupMembersByGroupIDRow{}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("POST", "/groups/1/settlements/settle-all", nil, 1)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := settleAllGroupBalances(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK || tt.expectedStatus == http.StatusCreated {
+				var response models.SettleAllResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int32(tt.expectedCount), response.Count)
+				assert.Len(t, response.Settlements, tt.expectedCount)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteGroupSettlement(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				settlement := db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, Amount: decimal.NewFromInt(10)}
+				ms.On("GetSettlementByID", mock.Anything, int64(5)).Return(settlement, nil)
+				ms.On("DeleteSettlement", mock.Anything, int64(5)).Return(settlement, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "settlement in another group",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				ms.On("GetSettlementByID", mock.Anything, int64(5)).Return(db.Settlement{ID: 5, GroupID: 2}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "settlement not found",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(createTestGroupMembers([]int64{1, 2}, 1), nil)
+				ms.On("GetSettlementByID", mock.Anything, int64(5)).Return(db.Settlement{}, pgx.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("DELETE", "/groups/1/settlements/5", nil, 1)
+			req.SetPathValue("group_id", "1")
+			req.SetPathValue("settlement_id", "5")
+			rr := httptest.NewRecorder()
+
+			handler := deleteGroupSettlement(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateGroupSettlementStatus(t *testing.T) {
+	// Member 3 has no account, any group member may act for them
+	placeholderName := "Placeholder"
+	members := append(createTestGroupMembers([]int64{1, 2}, 1), db.ListGroupMembersByGroupIDRow{ID: 3, GroupID: 1, MemberName: &placeholderName})
+
+	tests := []struct {
+		name           string
+		status         services.SettlementStatus
+		userID         int64
+		settlement     db.Settlement
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name:       "recipient confirms",
+			status:     services.SettlementStatusConfirmed,
+			userID:     1,
+			settlement: db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, CreatedBy: int64Ptr(2), Status: "pending"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UpdateSettlementStatusTx", mock.Anything, db.UpdateSettlementStatusTxParams{
+					SettlementID: 5,
+					Status:       "confirmed",
+					FromStatuses: []string{"pending", "disputed"},
+				}).Return(db.UpdateSettlementStatusTxResult{
+					PreviousStatus: "pending",
+					Settlement:     db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, Status: "confirmed"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "payer cannot confirm",
+			status:         services.SettlementStatusConfirmed,
+			userID:         2,
+			settlement:     db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, CreatedBy: int64Ptr(2), Status: "pending"},
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:       "recipient disputes",
+			status:     services.SettlementStatusDisputed,
+			userID:     1,
+			settlement: db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, CreatedBy: int64Ptr(2), Status: "pending"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UpdateSettlementStatusTx", mock.Anything, db.UpdateSettlementStatusTxParams{
+					SettlementID: 5,
+					Status:       "disputed",
+					FromStatuses: []string{"pending"},
+				}).Return(db.UpdateSettlementStatusTxResult{
+					PreviousStatus: "pending",
+					Settlement:     db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, Status: "disputed"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "payer cancels",
+			status:     services.SettlementStatusCancelled,
+			userID:     2,
+			settlement: db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, CreatedBy: int64Ptr(1), Status: "disputed"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UpdateSettlementStatusTx", mock.Anything, mock.MatchedBy(func(arg db.UpdateSettlementStatusTxParams) bool {
+					return arg.SettlementID == 5 && arg.Status == "cancelled"
+				})).Return(db.UpdateSettlementStatusTxResult{
+					PreviousStatus: "disputed",
+					Settlement:     db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, Status: "cancelled"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "recipient cannot cancel",
+			status:         services.SettlementStatusCancelled,
+			userID:         1,
+			settlement:     db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, CreatedBy: int64Ptr(2), Status: "pending"},
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:       "any member confirms for member without account",
+			status:     services.SettlementStatusConfirmed,
+			userID:     2,
+			settlement: db.Settlement{ID: 5, GroupID: 1, FromMember: 1, ToMember: 3, CreatedBy: int64Ptr(1), Status: "pending"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UpdateSettlementStatusTx", mock.Anything, mock.Anything).Return(db.UpdateSettlementStatusTxResult{
+					PreviousStatus: "pending",
+					Settlement:     db.Settlement{ID: 5, GroupID: 1, FromMember: 1, ToMember: 3, Status: "confirmed"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "already confirmed",
+			status:     services.SettlementStatusDisputed,
+			userID:     1,
+			settlement: db.Settlement{ID: 5, GroupID: 1, FromMember: 2, ToMember: 1, CreatedBy: int64Ptr(2), Status: "confirmed"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UpdateSettlementStatusTx", mock.Anything, mock.Anything).Return(db.UpdateSettlementStatusTxResult{}, errors.New("settlement is confirmed and can't be changed to disputed"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "settlement in another group",
+			status:         services.SettlementStatusConfirmed,
+			userID:         1,
+			settlement:     db.Settlement{ID: 5, GroupID: 2, FromMember: 2, ToMember: 1, Status: "pending"},
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			mockStore.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			mockStore.On("GetSettlementByID", mock.Anything, int64(5)).Return(tt.settlement, nil)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("POST", "/groups/1/settlements/5/"+string(tt.status), nil, tt.userID)
+			req.SetPathValue("group_id", "1")
+			req.SetPathValue("settlement_id", "5")
+			rr := httptest.NewRecorder()
+
+			handler := updateGroupSettlementStatus(mockStore, tt.status)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.SettlementResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, string(tt.status), response.Status)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -121,7 +121,7 @@ func TestGetSplitByID(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 			},
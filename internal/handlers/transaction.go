@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
 	"github.com/MattSharp0/transaction-split-go/internal/auth"
 	"github.com/MattSharp0/transaction-split-go/internal/logger"
 	"github.com/MattSharp0/transaction-split-go/internal/models"
 	"github.com/MattSharp0/transaction-split-go/internal/server"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 )
 
 func TransactionRoutes(s *server.Server, q db.Store) *http.ServeMux {
@@ -23,7 +28,10 @@ func TransactionRoutes(s *server.Server, q db.Store) *http.ServeMux {
 	mux.HandleFunc("GET /{id}", getTransactionByID(q))   // GET: Get transaction by ID
 	mux.HandleFunc("PUT /{id}", updateTransaction(q))    // PUT: Update transaction
 	mux.HandleFunc("PATCH /{id}", updateTransaction(q))  // PATCH: Update transaction
-	mux.HandleFunc("DELETE /{id}", deleteTransaction(q)) // DELETE: Delete transaction
+	mux.HandleFunc("DELETE /{id}", deleteTransaction(q)) // DELETE: Move transaction to the trash
+
+	// Trash Handlers
+	mux.HandleFunc("POST /{id}/restore", restoreTransaction(q)) // POST: Restore transaction from the trash
 
 	// Nested resource handlers - RESTful approach for splits (batch operations only)
 	mux.HandleFunc("GET /{transaction_id}/splits", getSplitsByTransactionNested(q))   // GET: List splits for transaction
@@ -31,6 +39,17 @@ func TransactionRoutes(s *server.Server, q db.Store) *http.ServeMux {
 	mux.HandleFunc("PUT /{transaction_id}/splits", updateTransactionSplitsBatch(q))   // PUT: Replace all splits (batch)
 	mux.HandleFunc("PATCH /{transaction_id}/splits", updateTransactionSplitsBatch(q)) // PATCH: Replace all splits (batch)
 
+	// Nested resource handlers - line items, splits are derived from items
+	mux.HandleFunc("GET /{transaction_id}/items", getTransactionItems(q))       // GET: List items for transaction
+	mux.HandleFunc("POST /{transaction_id}/items", replaceTransactionItems(q))  // POST: Create/replace all items & derive splits
+	mux.HandleFunc("PUT /{transaction_id}/items", replaceTransactionItems(q))   // PUT: Replace all items & derive splits
+	mux.HandleFunc("DELETE /{transaction_id}/items", deleteTransactionItems(q)) // DELETE: Delete all items, splits are kept
+
+	// Nested resource handlers - revision history
+	mux.HandleFunc("GET /{transaction_id}/revisions", listTransactionRevisions(q))                     // GET: List revisions for transaction
+	mux.HandleFunc("GET /{transaction_id}/revisions/{revision}", getTransactionRevision(q))            // GET: Get revision with changes since the previous revision
+	mux.HandleFunc("POST /{transaction_id}/revisions/{revision}/revert", revertTransactionRevision(q)) // POST: Revert transaction to revision
+
 	return mux
 }
 
@@ -72,9 +91,13 @@ func listTransactions(store db.Store) http.HandlerFunc {
 				Name:            tx.Name,
 				TransactionDate: tx.TransactionDate,
 				Amount:          tx.Amount,
+				Currency:        tx.Currency,
+				ExchangeRate:    tx.ExchangeRate,
+				BaseAmount:      services.ConvertAmount(tx.Amount, tx.ExchangeRate),
 				Category:        tx.Category,
 				Note:            tx.Note,
 				ByUser:          tx.ByUser,
+				SplitMode:       tx.SplitMode,
 				CreatedAt:       tx.CreatedAt,
 				ModifiedAt:      tx.ModifiedAt,
 			}
@@ -124,6 +147,12 @@ func getTransactionByID(store db.Store) http.HandlerFunc {
 			return
 		}
 
+		// Get payers, transactions without payer rows are paid in full by by_user
+		payers, err := store.ListTransactionPayersByTransactionID(r.Context(), id)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get payers by transaction ID", "transaction_id", id) {
+			return
+		}
+
 		// Convert to response format
 		transactionResponse := models.TransactionResponse{
 			ID:              transaction.ID,
@@ -131,9 +160,14 @@ func getTransactionByID(store db.Store) http.HandlerFunc {
 			Name:            transaction.Name,
 			TransactionDate: transaction.TransactionDate,
 			Amount:          transaction.Amount,
+			Currency:        transaction.Currency,
+			ExchangeRate:    transaction.ExchangeRate,
+			BaseAmount:      services.ConvertAmount(transaction.Amount, transaction.ExchangeRate),
 			Category:        transaction.Category,
 			Note:            transaction.Note,
 			ByUser:          transaction.ByUser,
+			SplitMode:       transaction.SplitMode,
+			Payers:          transactionPayerResponses(transaction, payers),
 			CreatedAt:       transaction.CreatedAt,
 			ModifiedAt:      transaction.ModifiedAt,
 		}
@@ -170,27 +204,65 @@ func createTransaction(store db.Store) http.HandlerFunc {
 			http.Error(w, "Group ID is required", http.StatusBadRequest)
 			return
 		}
-		if createTransactionReq.ByUser == 0 {
+		if createTransactionReq.ByUser == 0 && len(createTransactionReq.Payers) == 0 {
 			http.Error(w, "ByUser is required", http.StatusBadRequest)
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, createTransactionReq.GroupID, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Verify user may add transactions to the group
+		_, err := auth.CheckGroupPermission(r.Context(), store, createTransactionReq.GroupID, userID, auth.PermissionAddTransactions)
+		if HandleGroupPermissionError(w, err, "Forbidden: viewers can't add transactions") {
 			return
 		}
 
-		// Verify ByUser is a group member (it should be a group_member ID)
-		groupMember, err := store.GetGroupMemberByID(r.Context(), createTransactionReq.ByUser)
-		if err != nil {
-			logger.Warn("Group member not found for ByUser", "by_user", createTransactionReq.ByUser)
-			http.Error(w, "Group member not found", http.StatusBadRequest)
+		var payers []models.TransactionPayerRequest
+		if len(createTransactionReq.Payers) > 0 {
+			// Validate payers, ByUser is one of the payers and a single payer is stored as ByUser only
+			byUser, resolvedPayers, err := services.ResolvePayers(createTransactionReq.Amount, createTransactionReq.ByUser, createTransactionReq.Payers)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			createTransactionReq.ByUser = byUser
+			payers = resolvedPayers
+
+			// Verify all payers are group members
+			groupMembers, err := store.ListGroupMembersByGroupID(r.Context(), db.ListGroupMembersByGroupIDParams{GroupID: createTransactionReq.GroupID, Limit: 1000, Offset: 0})
+			if HandleDBError(w, err, "Group members not found", "An error has occurred", "Failed to get group members by group ID", "group id", createTransactionReq.GroupID) {
+				return
+			}
+			if err := ValidatePayerMembersInGroup(createTransactionReq.Payers, groupMembers, createTransactionReq.GroupID); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			// Verify ByUser is a group member (it should be a group_member ID)
+			groupMember, err := store.GetGroupMemberByID(r.Context(), createTransactionReq.ByUser)
+			if err != nil {
+				logger.Warn("Group member not found for ByUser", "by_user", createTransactionReq.ByUser)
+				http.Error(w, "Group member not found", http.StatusBadRequest)
+				return
+			}
+			if groupMember.GroupID != createTransactionReq.GroupID {
+				logger.Warn("Group member does not belong to this group", "by_user", createTransactionReq.ByUser, "group_id", createTransactionReq.GroupID)
+				http.Error(w, "Group member does not belong to this group", http.StatusBadRequest)
+				return
+			}
+			if groupMember.RemovedAt.Valid {
+				http.Error(w, "Group member has been removed from the group", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Get group for its base currency
+		group, err := store.GetGroupByID(r.Context(), createTransactionReq.GroupID)
+		if HandleDBError(w, err, "Group not found", "An error has occurred", "Failed to get group by ID", "group_id", createTransactionReq.GroupID) {
 			return
 		}
-		if groupMember.GroupID != createTransactionReq.GroupID {
-			logger.Warn("Group member does not belong to this group", "by_user", createTransactionReq.ByUser, "group_id", createTransactionReq.GroupID)
-			http.Error(w, "Group member does not belong to this group", http.StatusBadRequest)
+
+		// Resolve transaction currency & exchange rate to the group base currency
+		currency, exchangeRate, ok := resolveTransactionCurrency(w, r, store, group, createTransactionReq.Currency, createTransactionReq.TransactionDate)
+		if !ok {
 			return
 		}
 
@@ -198,10 +270,11 @@ func createTransaction(store db.Store) http.HandlerFunc {
 			slog.String("name", createTransactionReq.Name),
 			slog.Int64("group_id", createTransactionReq.GroupID),
 			slog.Int64("user_id", userID),
+			slog.Int("payer_count", len(payers)),
+			slog.String("currency", currency),
 		)
 
-		// Create transaction in database
-		transaction, err := store.CreateTransaction(r.Context(), db.CreateTransactionParams{
+		createTransactionParams := db.CreateTransactionParams{
 			GroupID:         createTransactionReq.GroupID,
 			Name:            createTransactionReq.Name,
 			TransactionDate: createTransactionReq.TransactionDate,
@@ -209,9 +282,28 @@ func createTransaction(store db.Store) http.HandlerFunc {
 			Category:        createTransactionReq.Category,
 			Note:            createTransactionReq.Note,
 			ByUser:          createTransactionReq.ByUser,
-		})
-		if HandleDBListError(w, err, "An error has occurred", "Failed to create transaction", "group_id", createTransactionReq.GroupID) {
-			return
+			Currency:        currency,
+			ExchangeRate:    exchangeRate,
+		}
+
+		// Create transaction in database, with its payers when paid by several members
+		var transaction db.Transaction
+		var createdPayers []db.TransactionPayer
+		if len(payers) > 0 {
+			result, err := store.CreateTransactionWithPayersTx(r.Context(), db.CreateTransactionWithPayersTxParams{
+				Transaction: createTransactionParams,
+				Payers:      transactionPayerTxParams(payers),
+			})
+			if HandleDBListError(w, err, "An error has occurred", "Failed to create transaction with payers", "group_id", createTransactionReq.GroupID) {
+				return
+			}
+			transaction, createdPayers = result.Transaction, result.Payers
+		} else {
+			var err error
+			transaction, err = store.CreateTransaction(r.Context(), createTransactionParams)
+			if HandleDBListError(w, err, "An error has occurred", "Failed to create transaction", "group_id", createTransactionReq.GroupID) {
+				return
+			}
 		}
 		logger.Debug("Transaction created successfully",
 			slog.Int64("transaction_id", transaction.ID),
@@ -225,9 +317,14 @@ func createTransaction(store db.Store) http.HandlerFunc {
 			Name:            transaction.Name,
 			TransactionDate: transaction.TransactionDate,
 			Amount:          transaction.Amount,
+			Currency:        transaction.Currency,
+			ExchangeRate:    transaction.ExchangeRate,
+			BaseAmount:      services.ConvertAmount(transaction.Amount, transaction.ExchangeRate),
 			Category:        transaction.Category,
 			Note:            transaction.Note,
 			ByUser:          transaction.ByUser,
+			SplitMode:       transaction.SplitMode,
+			Payers:          transactionPayerResponses(transaction, createdPayers),
 			CreatedAt:       transaction.CreatedAt,
 			ModifiedAt:      transaction.ModifiedAt,
 		}
@@ -260,9 +357,9 @@ func updateTransaction(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, transaction.GroupID, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Verify user may edit the transaction, members can only edit the transactions they paid
+		err = auth.CheckTransactionPermission(r.Context(), store, transaction, userID)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can edit other members' transactions") {
 			return
 		}
 
@@ -282,28 +379,100 @@ func updateTransaction(store db.Store) http.HandlerFunc {
 			http.Error(w, "Group ID is required", http.StatusBadRequest)
 			return
 		}
-		if updateTransactionReq.ByUser == 0 {
+		if updateTransactionReq.ByUser == 0 && len(updateTransactionReq.Payers) == 0 {
 			http.Error(w, "ByUser is required", http.StatusBadRequest)
 			return
 		}
 
-		// Verify ByUser is a group member
-		groupMember, err := store.GetGroupMemberByID(r.Context(), updateTransactionReq.ByUser)
-		if err != nil {
-			logger.Warn("Group member not found for ByUser", "by_user", updateTransactionReq.ByUser)
-			http.Error(w, "Group member not found", http.StatusBadRequest)
+		// Get existing payers, transactions without payer rows are paid in full by by_user
+		existingPayers, err := store.ListTransactionPayersByTransactionID(r.Context(), id)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get payers by transaction ID", "transaction_id", id) {
 			return
 		}
-		if groupMember.GroupID != updateTransactionReq.GroupID {
-			logger.Warn("Group member does not belong to this group", "by_user", updateTransactionReq.ByUser, "group_id", updateTransactionReq.GroupID)
-			http.Error(w, "Group member does not belong to this group", http.StatusBadRequest)
-			return
+
+		payerRequests := updateTransactionReq.Payers
+		if len(payerRequests) == 0 && len(existingPayers) > 0 {
+			// Keep existing payers only while the amounts they paid still add up
+			if !updateTransactionReq.Amount.Equal(transaction.Amount) || updateTransactionReq.GroupID != transaction.GroupID {
+				http.Error(w, "Payers are required when changing the amount or group of a transaction with multiple payers", http.StatusBadRequest)
+				return
+			}
+			payerRequests = make([]models.TransactionPayerRequest, len(existingPayers))
+			for i, payer := range existingPayers {
+				payerRequests[i] = models.TransactionPayerRequest{MemberID: payer.MemberID, Amount: payer.Amount}
+			}
 		}
 
-		logger.Debug("Updating transaction", "transaction_id", id, "user_id", userID)
+		var payers []models.TransactionPayerRequest
+		if len(payerRequests) > 0 {
+			// Validate payers, ByUser is one of the payers and a single payer is stored as ByUser only
+			byUser, resolvedPayers, err := services.ResolvePayers(updateTransactionReq.Amount, updateTransactionReq.ByUser, payerRequests)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			updateTransactionReq.ByUser = byUser
+			payers = resolvedPayers
+
+			// Verify all payers are group members
+			groupMembers, err := store.ListGroupMembersByGroupID(r.Context(), db.ListGroupMembersByGroupIDParams{GroupID: updateTransactionReq.GroupID, Limit: 1000, Offset: 0})
+			if HandleDBError(w, err, "Group members not found", "An error has occurred", "Failed to get group members by group ID", "group id", updateTransactionReq.GroupID) {
+				return
+			}
+			if err := ValidatePayerMembersInGroup(payerRequests, groupMembers, updateTransactionReq.GroupID); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			// Verify ByUser is a group member
+			groupMember, err := store.GetGroupMemberByID(r.Context(), updateTransactionReq.ByUser)
+			if err != nil {
+				logger.Warn("Group member not found for ByUser", "by_user", updateTransactionReq.ByUser)
+				http.Error(w, "Group member not found", http.StatusBadRequest)
+				return
+			}
+			if groupMember.GroupID != updateTransactionReq.GroupID {
+				logger.Warn("Group member does not belong to this group", "by_user", updateTransactionReq.ByUser, "group_id", updateTransactionReq.GroupID)
+				http.Error(w, "Group member does not belong to this group", http.StatusBadRequest)
+				return
+			}
+			if groupMember.RemovedAt.Valid && updateTransactionReq.ByUser != transaction.ByUser {
+				http.Error(w, "Group member has been removed from the group", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Resolve currency, the exchange rate is kept unless the currency, date or group changes
+		currency, exchangeRate := transaction.Currency, transaction.ExchangeRate
+		if updateTransactionReq.Currency != "" || !updateTransactionReq.TransactionDate.Equal(transaction.TransactionDate) || updateTransactionReq.GroupID != transaction.GroupID {
+			if updateTransactionReq.Currency == "" {
+				updateTransactionReq.Currency = transaction.Currency
+			}
+
+			// Get group for its base currency
+			group, err := store.GetGroupByID(r.Context(), updateTransactionReq.GroupID)
+			if HandleDBError(w, err, "Group not found", "An error has occurred", "Failed to get group by ID", "group_id", updateTransactionReq.GroupID) {
+				return
+			}
+
+			currency, exchangeRate, ok = resolveTransactionCurrency(w, r, store, group, updateTransactionReq.Currency, updateTransactionReq.TransactionDate)
+			if !ok {
+				return
+			}
+		}
 
-		// Update transaction in database
-		transaction, err = store.UpdateTransaction(r.Context(), db.UpdateTransactionParams{
+		// Rescale splits to a new amount by their split_percent, exact & item-derived splits must be replaced instead
+		var rescaledSplits []db.RescaleSplitTxParams
+		if !updateTransactionReq.Amount.Equal(transaction.Amount) {
+			rescaledSplits, ok = rescaleTransactionSplits(w, r, store, transaction, updateTransactionReq.Amount)
+			if !ok {
+				return
+			}
+		}
+
+		logger.Debug("Updating transaction", "transaction_id", id, "user_id", userID, "payer_count", len(payers), "currency", currency, "rescaled_split_count", len(rescaledSplits))
+
+		updateTransactionParams := db.UpdateTransactionParams{
 			ID:              id,
 			GroupID:         updateTransactionReq.GroupID,
 			Name:            updateTransactionReq.Name,
@@ -312,9 +481,33 @@ func updateTransaction(store db.Store) http.HandlerFunc {
 			Category:        updateTransactionReq.Category,
 			Note:            updateTransactionReq.Note,
 			ByUser:          updateTransactionReq.ByUser,
-		})
-		if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to update transaction", "transaction_id", id) {
-			return
+			Currency:        currency,
+			ExchangeRate:    exchangeRate,
+		}
+
+		// Update transaction in database, replacing payers when it has or had several & rescaling splits
+		var updatedPayers []db.TransactionPayer
+		if len(payers) > 0 || len(existingPayers) > 0 || len(rescaledSplits) > 0 {
+			result, err := store.UpdateTransactionWithPayersTx(r.Context(), db.UpdateTransactionWithPayersTxParams{
+				Transaction: updateTransactionParams,
+				Payers:      transactionPayerTxParams(payers),
+				Splits:      rescaledSplits,
+			})
+			if HandleSplitRescaleError(w, err) {
+				return
+			}
+			if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to update transaction with payers", "transaction_id", id) {
+				return
+			}
+			transaction, updatedPayers = result.Transaction, result.Payers
+		} else {
+			transaction, err = store.UpdateTransaction(r.Context(), updateTransactionParams)
+			if HandleSplitRescaleError(w, err) {
+				return
+			}
+			if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to update transaction", "transaction_id", id) {
+				return
+			}
 		}
 
 		// Convert to response format
@@ -324,9 +517,14 @@ func updateTransaction(store db.Store) http.HandlerFunc {
 			Name:            transaction.Name,
 			TransactionDate: transaction.TransactionDate,
 			Amount:          transaction.Amount,
+			Currency:        transaction.Currency,
+			ExchangeRate:    transaction.ExchangeRate,
+			BaseAmount:      services.ConvertAmount(transaction.Amount, transaction.ExchangeRate),
 			Category:        transaction.Category,
 			Note:            transaction.Note,
 			ByUser:          transaction.ByUser,
+			SplitMode:       transaction.SplitMode,
+			Payers:          transactionPayerResponses(transaction, updatedPayers),
 			CreatedAt:       transaction.CreatedAt,
 			ModifiedAt:      transaction.ModifiedAt,
 		}
@@ -359,15 +557,15 @@ func deleteTransaction(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, transaction.GroupID, userID); err != nil {
-			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+		// Verify user may delete the transaction, members can only delete the transactions they paid
+		err = auth.CheckTransactionPermission(r.Context(), store, transaction, userID)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can delete other members' transactions") {
 			return
 		}
 
 		logger.Debug("Deleting transaction", "transaction_id", id, "user_id", userID)
 
-		// Delete transaction from database
+		// Move transaction to the trash, its splits are kept so it can be restored
 		transaction, err = store.DeleteTransaction(r.Context(), id)
 		if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to delete transaction", "transaction_id", id) {
 			return
@@ -380,11 +578,16 @@ func deleteTransaction(store db.Store) http.HandlerFunc {
 			Name:            transaction.Name,
 			TransactionDate: transaction.TransactionDate,
 			Amount:          transaction.Amount,
+			Currency:        transaction.Currency,
+			ExchangeRate:    transaction.ExchangeRate,
+			BaseAmount:      services.ConvertAmount(transaction.Amount, transaction.ExchangeRate),
 			Category:        transaction.Category,
 			Note:            transaction.Note,
 			ByUser:          transaction.ByUser,
+			SplitMode:       transaction.SplitMode,
 			CreatedAt:       transaction.CreatedAt,
 			ModifiedAt:      transaction.ModifiedAt,
+			DeletedAt:       TimestamptzPtr(transaction.DeletedAt),
 		}
 
 		// Send response with deleted transaction data
@@ -484,14 +687,15 @@ func createTransactionSplitsBatch(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, transaction.GroupID, userID); err != nil {
-			http.Error(w, "Forbidden: User is not a current group member", http.StatusForbidden)
+		// Verify user may edit the transaction, members can only edit the transactions they paid
+		err = auth.CheckTransactionPermission(r.Context(), store, transaction, userID)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can edit other members' transactions") {
 			return
 		}
 
 		// Decode request body
 		var req struct {
+			Mode   string                      `json:"mode"` // Optional: equal, shares, exact or percent
 			Splits []models.CreateSplitRequest `json:"splits"`
 		}
 
@@ -500,7 +704,8 @@ func createTransactionSplitsBatch(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		if len(req.Splits) == 0 {
+		// Equal mode without splits defaults to every member active on the transaction date
+		if len(req.Splits) == 0 && req.Mode != string(services.SplitModeEqual) {
 			http.Error(w, "At least one split is required", http.StatusBadRequest)
 			return
 		}
@@ -511,19 +716,44 @@ func createTransactionSplitsBatch(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Validate split group members are in tx group
-		if err := ValidateSplitMembersInGroup(req.Splits, groupMembers, transaction.GroupID); err != nil {
+		if len(req.Splits) == 0 {
+			for _, member := range ActiveGroupMembers(groupMembers, transaction.TransactionDate) {
+				req.Splits = append(req.Splits, models.CreateSplitRequest{SplitUser: &member.ID})
+			}
+			if len(req.Splits) == 0 {
+				http.Error(w, "No group members were active on the transaction date", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Validate split group members are in tx group and active on the transaction date
+		if err := ValidateSplitMembersInGroup(req.Splits, groupMembers, transaction.GroupID, transaction.TransactionDate); err != nil {
 
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
+		// Calculate missing split percent & amount values from split mode
+		if req.Mode != "" {
+			mode, err := services.ParseSplitMode(req.Mode)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.Splits, err = services.CalculateSplits(mode, transaction.Amount, req.Splits)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
 		// Validate splits total Tx amount & 100%
 		if err := ValidateSplitsTotals(req.Splits, transaction.Amount); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		logger.Debug("Creating transaction splits", "transaction_id", transactionID, "split_count", len(req.Splits))
+		logger.Debug("Creating transaction splits", "transaction_id", transactionID, "split_count", len(req.Splits), "mode", req.Mode)
 
 		// Convert to DB params
 		dbSplits := make([]db.CreateSplitParams, len(req.Splits))
@@ -540,6 +770,7 @@ func createTransactionSplitsBatch(store db.Store) http.HandlerFunc {
 		result, err := store.CreateSplitsTx(r.Context(), db.CreateSplitsTxParams{
 			TransactionID: transactionID,
 			Splits:        dbSplits,
+			SplitMode:     StringPtr(req.Mode),
 		})
 		if err != nil {
 			logger.Error("Failed to create transaction splits", "error", err, "transaction_id", transactionID)
@@ -599,14 +830,15 @@ func updateTransactionSplitsBatch(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Verify user is a member of the group
-		if err := auth.CheckGroupMembership(r.Context(), store, transaction.GroupID, userID); err != nil {
-			http.Error(w, "Forbidden: User is not a current group member", http.StatusForbidden)
+		// Verify user may edit the transaction, members can only edit the transactions they paid
+		err = auth.CheckTransactionPermission(r.Context(), store, transaction, userID)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can edit other members' transactions") {
 			return
 		}
 
 		// Decode request body
 		var req struct {
+			Mode   string                      `json:"mode"` // Optional: equal, shares, exact or percent
 			Splits []models.CreateSplitRequest `json:"splits"`
 		}
 
@@ -615,7 +847,8 @@ func updateTransactionSplitsBatch(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		if len(req.Splits) == 0 {
+		// Equal mode without splits defaults to every member active on the transaction date
+		if len(req.Splits) == 0 && req.Mode != string(services.SplitModeEqual) {
 			http.Error(w, "At least one split is required", http.StatusBadRequest)
 			return
 		}
@@ -626,19 +859,44 @@ func updateTransactionSplitsBatch(store db.Store) http.HandlerFunc {
 			return
 		}
 
-		// Validate split group members are in tx group
-		if err := ValidateSplitMembersInGroup(req.Splits, groupMembers, transaction.GroupID); err != nil {
+		if len(req.Splits) == 0 {
+			for _, member := range ActiveGroupMembers(groupMembers, transaction.TransactionDate) {
+				req.Splits = append(req.Splits, models.CreateSplitRequest{SplitUser: &member.ID})
+			}
+			if len(req.Splits) == 0 {
+				http.Error(w, "No group members were active on the transaction date", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Validate split group members are in tx group and active on the transaction date
+		if err := ValidateSplitMembersInGroup(req.Splits, groupMembers, transaction.GroupID, transaction.TransactionDate); err != nil {
 
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
+		// Calculate missing split percent & amount values from split mode
+		if req.Mode != "" {
+			mode, err := services.ParseSplitMode(req.Mode)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.Splits, err = services.CalculateSplits(mode, transaction.Amount, req.Splits)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
 		// Validate splits total Tx amount & 100%
 		if err := ValidateSplitsTotals(req.Splits, transaction.Amount); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		logger.Debug("Updating transaction splits", "transaction_id", transactionID, "new_split_count", len(req.Splits))
+		logger.Debug("Updating transaction splits", "transaction_id", transactionID, "new_split_count", len(req.Splits), "mode", req.Mode)
 
 		// Convert to DB params
 		dbSplits := make([]db.CreateSplitParams, len(req.Splits))
@@ -655,6 +913,7 @@ func updateTransactionSplitsBatch(store db.Store) http.HandlerFunc {
 		result, err := store.UpdateTransactionSplitsTx(r.Context(), db.UpdateTransactionSplitsTxParams{
 			TransactionID: transactionID,
 			Splits:        dbSplits,
+			SplitMode:     StringPtr(req.Mode),
 		})
 		if err != nil {
 			logger.Error("Failed to update transaction splits", "error", err, "transaction_id", transactionID)
@@ -707,3 +966,118 @@ func updateTransactionSplitsBatch(store db.Store) http.HandlerFunc {
 		}
 	}
 }
+
+// transactionPayerResponses lists the members paying a transaction, a transaction without payer rows is paid in full by by_user
+func transactionPayerResponses(transaction db.Transaction, payers []db.TransactionPayer) []models.TransactionPayerResponse {
+	if len(payers) == 0 {
+		return []models.TransactionPayerResponse{{MemberID: transaction.ByUser, Amount: transaction.Amount}}
+	}
+
+	payerResponses := make([]models.TransactionPayerResponse, len(payers))
+	for i, payer := range payers {
+		payerResponses[i] = models.TransactionPayerResponse{
+			MemberID: payer.MemberID,
+			Amount:   payer.Amount,
+		}
+	}
+	return payerResponses
+}
+
+// transactionPayerTxParams converts payer requests to DB params
+func transactionPayerTxParams(payers []models.TransactionPayerRequest) []db.TransactionPayerTxParams {
+	dbPayers := make([]db.TransactionPayerTxParams, len(payers))
+	for i, payer := range payers {
+		dbPayers[i] = db.TransactionPayerTxParams{
+			MemberID: payer.MemberID,
+			Amount:   payer.Amount,
+		}
+	}
+	return dbPayers
+}
+
+// rescaleTransactionSplits calculates every split's amount for a new transaction amount, keeping their split_percent
+// Splits sent as exact amounts or derived from line items can't be rescaled and must be replaced with the new amount
+// Writes an error response and returns false when the splits can't be rescaled (caller should return immediately)
+func rescaleTransactionSplits(w http.ResponseWriter, r *http.Request, store db.Store, transaction db.Transaction, amount decimal.Decimal) ([]db.RescaleSplitTxParams, bool) {
+	splits, err := store.GetSplitsByTransactionID(r.Context(), transaction.ID)
+	if HandleDBListError(w, err, "An error has occurred", "Failed to get splits by transaction ID", "transaction_id", transaction.ID) {
+		return nil, false
+	}
+	if len(splits) == 0 {
+		return nil, true
+	}
+
+	if transaction.SplitMode != nil {
+		switch *transaction.SplitMode {
+		case db.SplitModeExact:
+			http.Error(w, "Transaction is split by exact amounts, replace its splits to change the amount", http.StatusConflict)
+			return nil, false
+		case db.SplitModeItems:
+			http.Error(w, "Transaction is split by line items, replace its items to change the amount", http.StatusConflict)
+			return nil, false
+		}
+	}
+
+	percents := make([]decimal.Decimal, len(splits))
+	for i, split := range splits {
+		percents[i] = split.SplitPercent
+	}
+	amounts, err := services.RescaleSplitAmounts(amount, percents)
+	if err != nil {
+		http.Error(w, "Cannot rescale splits: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	rescaled := make([]db.RescaleSplitTxParams, len(splits))
+	for i, split := range splits {
+		rescaled[i] = db.RescaleSplitTxParams{ID: split.ID, SplitAmount: amounts[i]}
+	}
+	return rescaled, true
+}
+
+// resolveTransactionCurrency validates a transaction currency, defaulting to the group base currency, and returns its exchange rate to the base currency on date
+// Rates come from the local exchange_rates table, the inverse rate is used when only the opposite direction is loaded
+// Writes an error response and returns false when the currency is invalid or has no rate (caller should return immediately)
+func resolveTransactionCurrency(w http.ResponseWriter, r *http.Request, store db.Store, group db.Group, currency string, date time.Time) (string, decimal.Decimal, bool) {
+	if currency == "" {
+		currency = group.Currency
+	}
+	currency, err := services.NormalizeCurrency(currency)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", decimal.Zero, false
+	}
+	if currency == group.Currency {
+		return currency, decimal.NewFromInt(1), true
+	}
+
+	rate, err := store.GetExchangeRate(r.Context(), db.GetExchangeRateParams{
+		FromCurrency: currency,
+		ToCurrency:   group.Currency,
+		RateDate:     date,
+	})
+	if err == nil {
+		return currency, rate.Rate, true
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		rate, err = store.GetExchangeRate(r.Context(), db.GetExchangeRateParams{
+			FromCurrency: group.Currency,
+			ToCurrency:   currency,
+			RateDate:     date,
+		})
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, fmt.Sprintf("No exchange rate from %s to %s on or before %s", currency, group.Currency, date.Format("2006-01-02")), http.StatusBadRequest)
+		return "", decimal.Zero, false
+	}
+	if HandleDBListError(w, err, "An error has occurred", "Failed to get exchange rate", "from_currency", currency, "to_currency", group.Currency) {
+		return "", decimal.Zero, false
+	}
+
+	inverseRate, err := services.InvertRate(rate.Rate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", decimal.Zero, false
+	}
+	return currency, inverseRate, true
+}
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
+)
+
+// List line items & adjustments for transaction
+// GET /transactions/{transaction_id}/items
+func getTransactionItems(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {transaction_id} from path parameter
+		transactionID, ok := ParsePathInt64(w, r, "transaction_id", "Transaction ID is required")
+		if !ok {
+			return
+		}
+
+		// Get transaction to find its group
+		transaction, err := store.GetTransactionByID(r.Context(), transactionID)
+		if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to get transaction by ID", "transaction_id", transactionID) {
+			return
+		}
+
+		// Verify user is a member of the group
+		if err := auth.CheckGroupMembership(r.Context(), store, transaction.GroupID, userID); err != nil {
+			http.Error(w, "Forbidden: User is not a current group member", http.StatusForbidden)
+			return
+		}
+
+		logger.Debug("Getting items for transaction", "transaction_id", transactionID)
+
+		items, err := store.ListTransactionItemsByTransactionID(r.Context(), transactionID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get items by transaction ID", "transaction_id", transactionID) {
+			return
+		}
+
+		itemMembers, err := store.ListTransactionItemMembersByTransactionID(r.Context(), transactionID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get item members by transaction ID", "transaction_id", transactionID) {
+			return
+		}
+
+		adjustments, err := store.ListTransactionAdjustmentsByTransactionID(r.Context(), transactionID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get adjustments by transaction ID", "transaction_id", transactionID) {
+			return
+		}
+
+		itemResponses := transactionItemResponses(items, itemMembers)
+
+		listItemResponse := models.ListTransactionItemResponse{
+			Items:       itemResponses,
+			Adjustments: transactionAdjustmentResponses(adjustments),
+			Count:       int32(len(itemResponses)),
+		}
+
+		if err := WriteJSONResponseOK(w, listItemResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Create/replace all line items & adjustments for transaction and derive its splits
+// POST /transactions/{transaction_id}/items or PUT /transactions/{transaction_id}/items
+func replaceTransactionItems(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {transaction_id} from path parameter
+		transactionID, ok := ParsePathInt64(w, r, "transaction_id", "Transaction ID is required")
+		if !ok {
+			return
+		}
+
+		// Get transaction to find its group
+		transaction, err := store.GetTransactionByID(r.Context(), transactionID)
+		if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to get transaction by ID", "transaction_id", transactionID) {
+			return
+		}
+
+		// Verify user may edit the transaction, members can only edit the transactions they paid
+		err = auth.CheckTransactionPermission(r.Context(), store, transaction, userID)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can edit other members' transactions") {
+			return
+		}
+
+		// Decode request body
+		var req struct {
+			Items       []models.TransactionItemRequest       `json:"items"`
+			Adjustments []models.TransactionAdjustmentRequest `json:"adjustments"` // Optional: tax, tip, service charges & discounts
+		}
+
+		if err := DecodeJSONBody(r, &req); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if len(req.Items) == 0 {
+			http.Error(w, "At least one item is required", http.StatusBadRequest)
+			return
+		}
+
+		// Get group member list to check all item members
+		groupMembers, err := store.ListGroupMembersByGroupID(r.Context(), db.ListGroupMembersByGroupIDParams{GroupID: transaction.GroupID, Limit: 1000, Offset: 0})
+		if HandleDBError(w, err, "Group members not found", "An error has occurred", "Failed to get group members by group ID", "group id", transaction.GroupID) {
+			return
+		}
+
+		// Validate item members are in tx group and active on the transaction date
+		if err := ValidateItemMembersInGroup(req.Items, groupMembers, transaction.GroupID, transaction.TransactionDate); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Derive one split per member from the items & adjustments
+		itemSplits, err := services.DeriveItemSplits(transaction.Amount, req.Items, req.Adjustments)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Derived splits follow the same rules as explicit ones, a discount can still leave a member at 0
+		if err := ValidateSplitsTotals(itemSplits.Splits, transaction.Amount); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.Debug("Replacing transaction items", "transaction_id", transactionID, "item_count", len(req.Items), "adjustment_count", len(req.Adjustments), "split_count", len(itemSplits.Splits))
+
+		// Convert to DB params
+		dbItems := make([]db.TransactionItemTxParams, len(req.Items))
+		for i, item := range req.Items {
+			dbItems[i] = db.TransactionItemTxParams{
+				Name:      item.Name,
+				Amount:    item.Amount,
+				MemberIDs: item.Members,
+			}
+		}
+
+		dbAdjustments := make([]db.TransactionAdjustmentTxParams, len(req.Adjustments))
+		for i, adjustment := range req.Adjustments {
+			dbAdjustments[i] = db.TransactionAdjustmentTxParams{
+				Name:           adjustment.Name,
+				AdjustmentType: adjustment.Type,
+				Value:          adjustment.Value,
+				Amount:         itemSplits.AdjustmentAmounts[i],
+			}
+		}
+
+		dbSplits := make([]db.CreateSplitParams, len(itemSplits.Splits))
+		for i, split := range itemSplits.Splits {
+			dbSplits[i] = db.CreateSplitParams{
+				TransactionID: transactionID,
+				SplitPercent:  split.SplitPercent,
+				SplitAmount:   split.SplitAmount,
+				SplitUser:     split.SplitUser,
+			}
+		}
+
+		// Execute transaction to replace all items & splits
+		result, err := store.ReplaceTransactionItemsTx(r.Context(), db.ReplaceTransactionItemsTxParams{
+			TransactionID: transactionID,
+			Items:         dbItems,
+			Adjustments:   dbAdjustments,
+			Splits:        dbSplits,
+		})
+		if err != nil {
+			logger.Error("Failed to replace transaction items", "error", err, "transaction_id", transactionID)
+			http.Error(w, fmt.Sprintf("Failed to replace items: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// Convert to response format
+		itemResponses := make([]models.TransactionItemResponse, len(result.Items))
+		for i, item := range result.Items {
+			itemResponses[i] = models.TransactionItemResponse{
+				ID:            item.Item.ID,
+				TransactionID: item.Item.TransactionID,
+				Name:          item.Item.Name,
+				Amount:        item.Item.Amount,
+				Members:       item.MemberIDs,
+				CreatedAt:     item.Item.CreatedAt,
+				ModifiedAt:    item.Item.ModifiedAt,
+			}
+		}
+
+		splitResponses := make([]models.SplitResponse, len(result.NewSplits))
+		for i, split := range result.NewSplits {
+			splitResponses[i] = models.SplitResponse{
+				ID:            split.ID,
+				TransactionID: split.TransactionID,
+				TxAmount:      split.TxAmount,
+				SplitPercent:  split.SplitPercent,
+				SplitAmount:   split.SplitAmount,
+				SplitUser:     split.SplitUser,
+				CreatedAt:     split.CreatedAt,
+				ModifiedAt:    split.ModifiedAt,
+			}
+		}
+
+		response := struct {
+			Items       []models.TransactionItemResponse       `json:"items"`
+			Adjustments []models.TransactionAdjustmentResponse `json:"adjustments"`
+			Splits      []models.SplitResponse                 `json:"splits"`
+			Message     string                                 `json:"message"`
+		}{
+			Items:       itemResponses,
+			Adjustments: transactionAdjustmentResponses(result.Adjustments),
+			Splits:      splitResponses,
+			Message:     fmt.Sprintf("Successfully saved %d items and %d splits", len(result.Items), len(result.NewSplits)),
+		}
+
+		if err := WriteJSONResponseOK(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Delete all line items & adjustments for transaction, existing splits are kept
+// DELETE /transactions/{transaction_id}/items
+func deleteTransactionItems(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {transaction_id} from path parameter
+		transactionID, ok := ParsePathInt64(w, r, "transaction_id", "Transaction ID is required")
+		if !ok {
+			return
+		}
+
+		// Get transaction to find its group
+		transaction, err := store.GetTransactionByID(r.Context(), transactionID)
+		if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to get transaction by ID", "transaction_id", transactionID) {
+			return
+		}
+
+		// Verify user may edit the transaction, members can only edit the transactions they paid
+		err = auth.CheckTransactionPermission(r.Context(), store, transaction, userID)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can edit other members' transactions") {
+			return
+		}
+
+		logger.Debug("Deleting transaction items", "transaction_id", transactionID, "user_id", userID)
+
+		result, err := store.DeleteTransactionItemsTx(r.Context(), transactionID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to delete transaction items", "transaction_id", transactionID) {
+			return
+		}
+
+		response := struct {
+			DeletedItems       []models.TransactionItemResponse       `json:"deleted_items"`
+			DeletedAdjustments []models.TransactionAdjustmentResponse `json:"deleted_adjustments"`
+			Message            string                                 `json:"message"`
+		}{
+			DeletedItems:       transactionItemResponses(result.DeletedItems, nil),
+			DeletedAdjustments: transactionAdjustmentResponses(result.DeletedAdjustments),
+			Message:            fmt.Sprintf("Successfully deleted %d items", len(result.DeletedItems)),
+		}
+
+		if err := WriteJSONResponseOK(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// transactionItemResponses converts items to response format, attaching the member IDs for each item
+func transactionItemResponses(items []db.TransactionItem, itemMembers []db.TransactionItemMember) []models.TransactionItemResponse {
+	membersByItem := make(map[int64][]int64)
+	for _, itemMember := range itemMembers {
+		membersByItem[itemMember.ItemID] = append(membersByItem[itemMember.ItemID], itemMember.MemberID)
+	}
+
+	itemResponses := make([]models.TransactionItemResponse, len(items))
+	for i, item := range items {
+		members, ok := membersByItem[item.ID]
+		if !ok {
+			members = []int64{}
+		}
+		itemResponses[i] = models.TransactionItemResponse{
+			ID:            item.ID,
+			TransactionID: item.TransactionID,
+			Name:          item.Name,
+			Amount:        item.Amount,
+			Members:       members,
+			CreatedAt:     item.CreatedAt,
+			ModifiedAt:    item.ModifiedAt,
+		}
+	}
+	return itemResponses
+}
+
+// transactionAdjustmentResponses converts adjustments to response format
+func transactionAdjustmentResponses(adjustments []db.TransactionAdjustment) []models.TransactionAdjustmentResponse {
+	adjustmentResponses := make([]models.TransactionAdjustmentResponse, len(adjustments))
+	for i, adjustment := range adjustments {
+		adjustmentResponses[i] = models.TransactionAdjustmentResponse{
+			ID:            adjustment.ID,
+			TransactionID: adjustment.TransactionID,
+			Name:          adjustment.Name,
+			Type:          adjustment.AdjustmentType,
+			Value:         adjustment.Value,
+			Amount:        adjustment.Amount,
+			CreatedAt:     adjustment.CreatedAt,
+			ModifiedAt:    adjustment.ModifiedAt,
+		}
+	}
+	return adjustmentResponses
+}
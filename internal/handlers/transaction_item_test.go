@@ -0,0 +1,411 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTransactionItems(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		pathValue      string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "success with items",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(60)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				// Mock group membership check
+				userID := int64Ptr(1)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				items := []db.TransactionItem{
+					{ID: 1, TransactionID: 1, Name: "Steak", Amount: decimal.NewFromInt(40), CreatedAt: time.Now(), ModifiedAt: time.Now()},
+					{ID: 2, TransactionID: 1, Name: "Salad", Amount: decimal.NewFromInt(20), CreatedAt: time.Now(), ModifiedAt: time.Now()},
+				}
+				ms.On("ListTransactionItemsByTransactionID", mock.Anything, int64(1)).Return(items, nil)
+				itemMembers := []db.TransactionItemMember{
+					{ItemID: 1, MemberID: 1},
+					{ItemID: 2, MemberID: 1},
+					{ItemID: 2, MemberID: 2},
+				}
+				ms.On("ListTransactionItemMembersByTransactionID", mock.Anything, int64(1)).Return(itemMembers, nil)
+				adjustments := []db.TransactionAdjustment{
+					{ID: 1, TransactionID: 1, Name: "Tip", AdjustmentType: "fixed", Value: decimal.NewFromInt(10), Amount: decimal.NewFromInt(10)},
+				}
+				ms.On("ListTransactionAdjustmentsByTransactionID", mock.Anything, int64(1)).Return(adjustments, nil)
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "invalid transaction ID format",
+			setupMock:      func(ms *mocks.MockStore) {},
+			pathValue:      "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "transaction not found",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(999)).Return(db.Transaction{}, pgx.ErrNoRows)
+			},
+			pathValue:      "999",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "not a group member",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				// Mock group membership check
+				userID := int64Ptr(1)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListTransactionItemsByTransactionID", mock.Anything, int64(1)).Return(nil, errors.New("database error"))
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", "/transactions/"+tt.pathValue+"/items", nil, 1)
+			req.SetPathValue("transaction_id", tt.pathValue)
+			rr := httptest.NewRecorder()
+
+			handler := getTransactionItems(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, float64(tt.expectedCount), response["count"])
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReplaceTransactionItems(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestBody    interface{}
+		expectedStatus int
+	}{
+		{
+			name: "success derives splits from items",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(100)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				// Mock group membership check & item member validation
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				expectedParams := mock.MatchedBy(func(arg db.ReplaceTransactionItemsTxParams) bool {
+					if arg.TransactionID != 1 || len(arg.Items) != 2 || len(arg.Splits) != 2 {
+						return false
+					}
+					return *arg.Splits[0].SplitUser == 1 && arg.Splits[0].SplitAmount.Equal(decimal.NewFromInt(70)) &&
+						*arg.Splits[1].SplitUser == 2 && arg.Splits[1].SplitAmount.Equal(decimal.NewFromInt(30))
+				})
+				result := db.ReplaceTransactionItemsTxResult{
+					Items: []db.TransactionItemWithMembers{
+						{Item: db.TransactionItem{ID: 1, TransactionID: 1, Name: "Steak", Amount: decimal.NewFromInt(40)}, MemberIDs: []int64{1}},
+						{Item: db.TransactionItem{ID: 2, TransactionID: 1, Name: "Wine", Amount: decimal.NewFromInt(60)}, MemberIDs: []int64{1, 2}},
+					},
+					NewSplits: []db.Split{
+						{ID: 1, TransactionID: 1, SplitAmount: decimal.NewFromInt(70), SplitUser: int64Ptr(1)},
+						{ID: 2, TransactionID: 1, SplitAmount: decimal.NewFromInt(30), SplitUser: int64Ptr(2)},
+					},
+				}
+				ms.On("ReplaceTransactionItemsTx", mock.Anything, expectedParams).Return(result, nil)
+			},
+			requestBody: map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"name": "Steak", "amount": "40.00", "members": []int64{1}},
+					{"name": "Wine", "amount": "60.00", "members": []int64{1, 2}},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "success with adjustments",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(120)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				// Mock group membership check & item member validation
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				expectedParams := mock.MatchedBy(func(arg db.ReplaceTransactionItemsTxParams) bool {
+					if len(arg.Adjustments) != 1 || len(arg.Splits) != 2 {
+						return false
+					}
+					return arg.Adjustments[0].Amount.Equal(decimal.NewFromInt(20)) &&
+						arg.Splits[0].SplitAmount.Equal(decimal.NewFromInt(84)) &&
+						arg.Splits[1].SplitAmount.Equal(decimal.NewFromInt(36))
+				})
+				result := db.ReplaceTransactionItemsTxResult{
+					Items: []db.TransactionItemWithMembers{
+						{Item: db.TransactionItem{ID: 1, TransactionID: 1, Name: "Steak", Amount: decimal.NewFromInt(40)}, MemberIDs: []int64{1}},
+						{Item: db.TransactionItem{ID: 2, TransactionID: 1, Name: "Wine", Amount: decimal.NewFromInt(60)}, MemberIDs: []int64{1, 2}},
+					},
+					Adjustments: []db.TransactionAdjustment{
+						{ID: 1, TransactionID: 1, Name: "Tip", AdjustmentType: "percent", Value: decimal.NewFromFloat(0.2), Amount: decimal.NewFromInt(20)},
+					},
+					NewSplits: []db.Split{
+						{ID: 1, TransactionID: 1, SplitAmount: decimal.NewFromInt(84), SplitUser: int64Ptr(1)},
+						{ID: 2, TransactionID: 1, SplitAmount: decimal.NewFromInt(36), SplitUser: int64Ptr(2)},
+					},
+				}
+				ms.On("ReplaceTransactionItemsTx", mock.Anything, expectedParams).Return(result, nil)
+			},
+			requestBody: map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"name": "Steak", "amount": "40.00", "members": []int64{1}},
+					{"name": "Wine", "amount": "60.00", "members": []int64{1, 2}},
+				},
+				"adjustments": []map[string]interface{}{
+					{"name": "Tip", "type": "percent", "value": "0.20"},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "item member not in group",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(40)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestBody: map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"name": "Steak", "amount": "40.00", "members": []int64{1, 5}},
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "items do not total transaction amount",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(100)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestBody: map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"name": "Steak", "amount": "40.00", "members": []int64{1}},
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "discount leaves a member with a zero split",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(1)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestBody: map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"name": "Mint", "amount": "0.01", "members": []int64{1}},
+					{"name": "Steak", "amount": "9.99", "members": []int64{2}},
+				},
+				"adjustments": []map[string]interface{}{
+					{"name": "Voucher", "type": "fixed", "value": "-9.00"},
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "no items",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(100)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestBody:    map[string]interface{}{"items": []map[string]interface{}{}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid JSON",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(100)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestBody:    "invalid json",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			var bodyBytes []byte
+			if str, ok := tt.requestBody.(string); ok {
+				bodyBytes = []byte(str)
+			} else {
+				var err error
+				bodyBytes, err = json.Marshal(tt.requestBody)
+				require.NoError(t, err)
+			}
+
+			req := createRequestWithUserID("POST", "/transactions/1/items", bodyBytes, 1)
+			req.SetPathValue("transaction_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := replaceTransactionItems(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Len(t, response["items"], 2)
+				assert.Len(t, response["splits"], 2)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReplaceTransactionItemsListsRevision(t *testing.T) {
+	mockStore := mocks.NewMockStore(t)
+
+	transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(100), ExchangeRate: decimal.NewFromInt(1), ByUser: 1}
+	mockStore.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+		{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+	}
+	mockStore.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+	result := db.ReplaceTransactionItemsTxResult{
+		Items: []db.TransactionItemWithMembers{
+			{Item: db.TransactionItem{ID: 1, TransactionID: 1, Name: "Steak", Amount: decimal.NewFromInt(40)}, MemberIDs: []int64{1}},
+			{Item: db.TransactionItem{ID: 2, TransactionID: 1, Name: "Wine", Amount: decimal.NewFromInt(60)}, MemberIDs: []int64{1, 2}},
+		},
+		NewSplits: []db.Split{
+			{ID: 1, TransactionID: 1, SplitAmount: decimal.NewFromInt(70), SplitUser: int64Ptr(1)},
+			{ID: 2, TransactionID: 1, SplitAmount: decimal.NewFromInt(30), SplitUser: int64Ptr(2)},
+		},
+	}
+	mockStore.On("ReplaceTransactionItemsTx", mock.Anything, mock.AnythingOfType("db.ReplaceTransactionItemsTxParams")).Return(result, nil)
+
+	// Replacing the items records the derived splits as revision 2, after the baseline
+	member1, member2 := int64(1), int64(2)
+	splitMode := db.SplitModeItems
+	snapshot, err := json.Marshal(db.TransactionRevisionSnapshot{
+		GroupID:      1,
+		Amount:       decimal.NewFromInt(100),
+		ByUser:       1,
+		ExchangeRate: decimal.NewFromInt(1),
+		Payers:       []db.TransactionRevisionPayer{},
+		Splits: []db.TransactionRevisionSplit{
+			{SplitUser: &member1, SplitPercent: decimal.RequireFromString("0.7"), SplitAmount: decimal.NewFromInt(70)},
+			{SplitUser: &member2, SplitPercent: decimal.RequireFromString("0.3"), SplitAmount: decimal.NewFromInt(30)},
+		},
+		SplitMode: &splitMode,
+	})
+	require.NoError(t, err)
+	revisions := []db.TransactionRevision{
+		{ID: 2, TransactionID: 1, Revision: 2, Snapshot: snapshot, CreatedBy: int64Ptr(1)},
+		revisionTestRevision(t, 1, 100),
+	}
+	mockStore.On("ListTransactionRevisions", mock.Anything, db.ListTransactionRevisionsParams{TransactionID: 1, Limit: 100, Offset: 0}).Return(revisions, nil)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"items": []map[string]interface{}{
+			{"name": "Steak", "amount": "40.00", "members": []int64{1}},
+			{"name": "Wine", "amount": "60.00", "members": []int64{1, 2}},
+		},
+	})
+	require.NoError(t, err)
+	req := createRequestWithUserID("POST", "/transactions/1/items", body, 1)
+	req.SetPathValue("transaction_id", "1")
+	rr := httptest.NewRecorder()
+	replaceTransactionItems(mockStore)(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	req = createRequestWithUserID("GET", "/transactions/1/revisions", nil, 1)
+	req.SetPathValue("transaction_id", "1")
+	rr = httptest.NewRecorder()
+	listTransactionRevisions(mockStore)(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.ListTransactionRevisionResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response.Revisions, 2)
+	latest := response.Revisions[0]
+	assert.Equal(t, int32(2), latest.Revision)
+	require.NotNil(t, latest.Snapshot.SplitMode)
+	assert.Equal(t, db.SplitModeItems, *latest.Snapshot.SplitMode)
+	require.Len(t, latest.Snapshot.Splits, 2)
+	assert.True(t, decimal.NewFromInt(70).Equal(latest.Snapshot.Splits[0].SplitAmount))
+	assert.True(t, decimal.NewFromInt(30).Equal(latest.Snapshot.Splits[1].SplitAmount))
+	mockStore.AssertExpectations(t)
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
+)
+
+// List revisions for transaction, newest first
+// GET /transactions/{transaction_id}/revisions
+func listTransactionRevisions(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {transaction_id} from path parameter
+		transactionID, ok := ParsePathInt64(w, r, "transaction_id", "Transaction ID is required")
+		if !ok {
+			return
+		}
+
+		// Get transaction to find its group
+		transaction, err := store.GetTransactionByID(r.Context(), transactionID)
+		if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to get transaction by ID", "transaction_id", transactionID) {
+			return
+		}
+
+		// Verify user is a member of the group
+		if err := auth.CheckGroupMembership(r.Context(), store, transaction.GroupID, userID); err != nil {
+			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+			return
+		}
+
+		// Parse query parameters
+		limit, offset, err := ParseLimitOffset(r)
+		if err != nil {
+			http.Error(w, "Invalid parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		listParams := db.ListTransactionRevisionsParams{
+			TransactionID: transactionID,
+			Limit:         limit,
+			Offset:        offset,
+		}
+
+		logger.Debug("Listing transaction revisions", "transaction_id", transactionID, "limit", limit, "offset", offset)
+
+		revisions, err := store.ListTransactionRevisions(r.Context(), listParams)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list transaction revisions", "transaction_id", transactionID) {
+			return
+		}
+
+		revisionResponses := make([]models.TransactionRevisionResponse, len(revisions))
+		for i, revision := range revisions {
+			revisionResponses[i], err = transactionRevisionResponse(revision)
+			if err != nil {
+				logger.Error("Failed to decode transaction revision", "error", err, "transaction_id", transactionID, "revision", revision.Revision)
+				http.Error(w, "An error has occurred", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		listRevisionResponse := models.ListTransactionRevisionResponse{
+			Revisions: revisionResponses,
+			Count:     int32(len(revisionResponses)),
+			Limit:     limit,
+			Offset:    offset,
+		}
+
+		if err := WriteJSONResponseOK(w, listRevisionResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Get a transaction revision with the changes since the previous revision
+// GET /transactions/{transaction_id}/revisions/{revision}
+func getTransactionRevision(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {transaction_id} & {revision} from path parameters
+		transactionID, ok := ParsePathInt64(w, r, "transaction_id", "Transaction ID is required")
+		if !ok {
+			return
+		}
+
+		revisionNumber, ok := parsePathRevision(w, r)
+		if !ok {
+			return
+		}
+
+		// Get transaction to find its group
+		transaction, err := store.GetTransactionByID(r.Context(), transactionID)
+		if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to get transaction by ID", "transaction_id", transactionID) {
+			return
+		}
+
+		// Verify user is a member of the group
+		if err := auth.CheckGroupMembership(r.Context(), store, transaction.GroupID, userID); err != nil {
+			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+			return
+		}
+
+		logger.Debug("Getting transaction revision", "transaction_id", transactionID, "revision", revisionNumber)
+
+		revision, err := store.GetTransactionRevision(r.Context(), db.GetTransactionRevisionParams{
+			TransactionID: transactionID,
+			Revision:      revisionNumber,
+		})
+		if HandleDBError(w, err, "Revision not found", "An error has occurred", "Failed to get transaction revision", "transaction_id", transactionID, "revision", revisionNumber) {
+			return
+		}
+
+		revisionResponse, err := transactionRevisionResponse(revision)
+		if err != nil {
+			logger.Error("Failed to decode transaction revision", "error", err, "transaction_id", transactionID, "revision", revisionNumber)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		// Diff against the previous revision, the first revision is the state before any edits
+		revisionResponse.Changes = []models.RevisionChange{}
+		if revisionNumber > 1 {
+			previous, err := store.GetTransactionRevision(r.Context(), db.GetTransactionRevisionParams{
+				TransactionID: transactionID,
+				Revision:      revisionNumber - 1,
+			})
+			if HandleDBError(w, err, "Previous revision not found", "An error has occurred", "Failed to get previous transaction revision", "transaction_id", transactionID, "revision", revisionNumber-1) {
+				return
+			}
+
+			previousResponse, err := transactionRevisionResponse(previous)
+			if err != nil {
+				logger.Error("Failed to decode transaction revision", "error", err, "transaction_id", transactionID, "revision", previous.Revision)
+				http.Error(w, "An error has occurred", http.StatusInternalServerError)
+				return
+			}
+
+			revisionResponse.Changes = services.DiffTransactionRevisions(previousResponse.Snapshot, revisionResponse.Snapshot)
+		}
+
+		if err := WriteJSONResponseOK(w, revisionResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Revert a transaction, its payers & splits to an earlier revision, recorded as a new revision
+// POST /transactions/{transaction_id}/revisions/{revision}/revert
+func revertTransactionRevision(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {transaction_id} & {revision} from path parameters
+		transactionID, ok := ParsePathInt64(w, r, "transaction_id", "Transaction ID is required")
+		if !ok {
+			return
+		}
+
+		revisionNumber, ok := parsePathRevision(w, r)
+		if !ok {
+			return
+		}
+
+		// Get transaction to check permissions
+		transaction, err := store.GetTransactionByID(r.Context(), transactionID)
+		if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to get transaction by ID", "transaction_id", transactionID) {
+			return
+		}
+
+		// Verify user may edit the transaction
+		err = auth.CheckTransactionPermission(r.Context(), store, transaction, userID)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can revert other members' transactions") {
+			return
+		}
+
+		revision, err := store.GetTransactionRevision(r.Context(), db.GetTransactionRevisionParams{
+			TransactionID: transactionID,
+			Revision:      revisionNumber,
+		})
+		if HandleDBError(w, err, "Revision not found", "An error has occurred", "Failed to get transaction revision", "transaction_id", transactionID, "revision", revisionNumber) {
+			return
+		}
+
+		revisionResponse, err := transactionRevisionResponse(revision)
+		if err != nil {
+			logger.Error("Failed to decode transaction revision", "error", err, "transaction_id", transactionID, "revision", revisionNumber)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		// A revision from before the transaction was moved must be revertable by the user in its group too
+		if revisionResponse.Snapshot.GroupID != transaction.GroupID {
+			if err := auth.CheckGroupMembership(r.Context(), store, revisionResponse.Snapshot.GroupID, userID); err != nil {
+				http.Error(w, "Forbidden: you must be a member of the revision's group", http.StatusForbidden)
+				return
+			}
+		}
+
+		// Revisions don't record line items, so item-derived splits can't be restored or replaced
+		snapshot := revisionResponse.Snapshot
+		if isItemsSplitMode(transaction.SplitMode) || isItemsSplitMode(snapshot.SplitMode) {
+			http.Error(w, "Conflict: "+db.ErrRevertItemsMode.Error(), http.StatusConflict)
+			return
+		}
+
+		// The restored payers & splits must still be members of the revision's group, the same as when creating a transaction
+		groupMembers, err := store.ListGroupMembersByGroupID(r.Context(), db.ListGroupMembersByGroupIDParams{GroupID: snapshot.GroupID, Limit: 1000, Offset: 0})
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get group members by group ID", "group_id", snapshot.GroupID) {
+			return
+		}
+		if err := validateRevisionMembers(snapshot, groupMembers); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.Debug("Reverting transaction revision", "transaction_id", transactionID, "revision", revisionNumber, "user_id", userID)
+
+		result, err := store.RevertTransactionRevisionTx(r.Context(), db.RevertTransactionRevisionTxParams{
+			TransactionID: transactionID,
+			Revision:      revisionNumber,
+		})
+		if errors.Is(err, db.ErrRevertItemsMode) {
+			http.Error(w, "Conflict: "+err.Error(), http.StatusConflict)
+			return
+		}
+		if HandleDBError(w, err, "Transaction not found", "An error has occurred", "Failed to revert transaction revision", "transaction_id", transactionID, "revision", revisionNumber) {
+			return
+		}
+
+		revertedResponse, err := transactionRevisionResponse(result.Revision)
+		if err != nil {
+			logger.Error("Failed to decode transaction revision", "error", err, "transaction_id", transactionID, "revision", result.Revision.Revision)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		if err := WriteJSONResponseOK(w, revertedResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// validateRevisionMembers ensures a revision's payer, or its payers, and split members are active members of its group,
+// with split members active on the transaction date
+func validateRevisionMembers(snapshot models.TransactionRevisionSnapshot, groupMembers []db.ListGroupMembersByGroupIDRow) error {
+	if len(snapshot.Payers) == 0 {
+		if !slices.ContainsFunc(groupMembers, func(member db.ListGroupMembersByGroupIDRow) bool { return member.ID == snapshot.ByUser }) {
+			return fmt.Errorf("by_user %d is not a member of this group", snapshot.ByUser)
+		}
+	}
+
+	payers := make([]models.TransactionPayerRequest, len(snapshot.Payers))
+	for i, payer := range snapshot.Payers {
+		payers[i] = models.TransactionPayerRequest{MemberID: payer.MemberID, Amount: payer.Amount}
+	}
+	if err := ValidatePayerMembersInGroup(payers, groupMembers, snapshot.GroupID); err != nil {
+		return err
+	}
+
+	splits := make([]models.CreateSplitRequest, len(snapshot.Splits))
+	for i, split := range snapshot.Splits {
+		splits[i] = models.CreateSplitRequest{SplitUser: split.SplitUser, SplitPercent: split.SplitPercent, SplitAmount: split.SplitAmount}
+	}
+	return ValidateSplitMembersInGroup(splits, groupMembers, snapshot.GroupID, snapshot.TransactionDate)
+}
+
+// isItemsSplitMode reports whether splits are derived from line items
+func isItemsSplitMode(splitMode *string) bool {
+	return splitMode != nil && *splitMode == db.SplitModeItems
+}
+
+// parsePathRevision extracts the {revision} path parameter, revisions are numbered from 1
+func parsePathRevision(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	revision, ok := ParsePathInt64(w, r, "revision", "Revision is required")
+	if !ok {
+		return 0, false
+	}
+
+	if revision < 1 || revision > math.MaxInt32 {
+		http.Error(w, "Invalid revision format", http.StatusBadRequest)
+		return 0, false
+	}
+
+	return int32(revision), true
+}
+
+// transactionRevisionResponse decodes a revision's stored snapshot into a response
+func transactionRevisionResponse(revision db.TransactionRevision) (models.TransactionRevisionResponse, error) {
+	response := models.TransactionRevisionResponse{
+		TransactionID: revision.TransactionID,
+		Revision:      revision.Revision,
+		RevertedFrom:  revision.RevertedFrom,
+		CreatedBy:     revision.CreatedBy,
+		CreatedAt:     revision.CreatedAt,
+	}
+
+	if err := json.Unmarshal(revision.Snapshot, &response.Snapshot); err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
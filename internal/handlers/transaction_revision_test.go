@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func revisionTestTransaction(byUser int64) db.Transaction {
+	return db.Transaction{
+		ID:           1,
+		GroupID:      1,
+		Name:         "Dinner",
+		Amount:       decimal.NewFromInt(100),
+		ExchangeRate: decimal.NewFromInt(1),
+		ByUser:       byUser,
+	}
+}
+
+func revisionTestRevision(t *testing.T, revision int32, amount int64) db.TransactionRevision {
+	member1, member2 := int64(1), int64(2)
+	half := decimal.NewFromInt(amount).Div(decimal.NewFromInt(2))
+	snapshot, err := json.Marshal(db.TransactionRevisionSnapshot{
+		GroupID:      1,
+		Name:         "Dinner",
+		Amount:       decimal.NewFromInt(amount),
+		ByUser:       1,
+		Currency:     "USD",
+		ExchangeRate: decimal.NewFromInt(1),
+		Payers:       []db.TransactionRevisionPayer{},
+		Splits: []db.TransactionRevisionSplit{
+			{SplitUser: &member1, SplitPercent: decimal.RequireFromString("0.5"), SplitAmount: half},
+			{SplitUser: &member2, SplitPercent: decimal.RequireFromString("0.5"), SplitAmount: half},
+		},
+	})
+	require.NoError(t, err)
+
+	return db.TransactionRevision{
+		ID:            int64(revision),
+		TransactionID: 1,
+		Revision:      revision,
+		Snapshot:      snapshot,
+		CreatedBy:     int64Ptr(1),
+		CreatedAt:     time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func revisionTestMembers() []db.ListGroupMembersByGroupIDRow {
+	return []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "member"},
+		{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "owner"},
+	}
+}
+
+func TestListTransactionRevisions(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestURL     string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				revisions := []db.TransactionRevision{revisionTestRevision(t, 2, 120), revisionTestRevision(t, 1, 100)}
+				ms.On("ListTransactionRevisions", mock.Anything, db.ListTransactionRevisionsParams{TransactionID: 1, Limit: 100, Offset: 0}).Return(revisions, nil)
+			},
+			requestURL:     "/transactions/1/revisions",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name: "not a member of the group",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				members := []db.ListGroupMembersByGroupIDRow{{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "owner"}}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestURL:     "/transactions/1/revisions",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "transaction not found",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(db.Transaction{}, pgx.ErrNoRows)
+			},
+			requestURL:     "/transactions/1/revisions",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("ListTransactionRevisions", mock.Anything, db.ListTransactionRevisionsParams{TransactionID: 1, Limit: 100, Offset: 0}).Return(nil, errors.New("database error"))
+			},
+			requestURL:     "/transactions/1/revisions",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", tt.requestURL, nil, 1)
+			req.SetPathValue("transaction_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := listTransactionRevisions(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.ListTransactionRevisionResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedCount, len(response.Revisions))
+				assert.Equal(t, int32(2), response.Revisions[0].Revision)
+				assert.True(t, decimal.NewFromInt(120).Equal(response.Revisions[0].Snapshot.Amount))
+				assert.Len(t, response.Revisions[0].Snapshot.Splits, 2)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTransactionRevision(t *testing.T) {
+	tests := []struct {
+		name            string
+		setupMock       func(*mocks.MockStore)
+		revision        string
+		expectedStatus  int
+		expectedChanges []string
+	}{
+		{
+			name: "diff against previous revision",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 2}).Return(revisionTestRevision(t, 2, 120), nil)
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 1}).Return(revisionTestRevision(t, 1, 100), nil)
+			},
+			revision:        "2",
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"amount", "splits.1.split_amount", "splits.2.split_amount"},
+		},
+		{
+			name: "first revision has no changes",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 1}).Return(revisionTestRevision(t, 1, 100), nil)
+			},
+			revision:        "1",
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{},
+		},
+		{
+			name: "revision not found",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 9}).Return(db.TransactionRevision{}, pgx.ErrNoRows)
+			},
+			revision:       "9",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid revision",
+			setupMock:      func(ms *mocks.MockStore) {},
+			revision:       "0",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", "/transactions/1/revisions/"+tt.revision, nil, 1)
+			req.SetPathValue("transaction_id", "1")
+			req.SetPathValue("revision", tt.revision)
+			rr := httptest.NewRecorder()
+
+			handler := getTransactionRevision(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.TransactionRevisionResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				fields := make([]string, len(response.Changes))
+				for i, change := range response.Changes {
+					fields[i] = change.Field
+				}
+				assert.Equal(t, tt.expectedChanges, fields)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevertTransactionRevision(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name: "member reverts their own transaction",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 1}).Return(revisionTestRevision(t, 1, 100), nil)
+
+				reverted := revisionTestRevision(t, 3, 100)
+				revertedFrom := int32(1)
+				reverted.RevertedFrom = &revertedFrom
+				ms.On("RevertTransactionRevisionTx", mock.Anything, db.RevertTransactionRevisionTxParams{TransactionID: 1, Revision: 1}).Return(db.RevertTransactionRevisionTxResult{
+					Transaction: revisionTestTransaction(1),
+					Revision:    reverted,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "member can't revert another member's transaction",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(2), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return([]db.TransactionPayer{}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "revision not found",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 1}).Return(db.TransactionRevision{}, pgx.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 1}).Return(revisionTestRevision(t, 1, 100), nil)
+				ms.On("RevertTransactionRevisionTx", mock.Anything, db.RevertTransactionRevisionTxParams{TransactionID: 1, Revision: 1}).Return(db.RevertTransactionRevisionTxResult{}, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "transaction split by items",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := revisionTestTransaction(1)
+				itemsMode := db.SplitModeItems
+				transaction.SplitMode = &itemsMode
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 1}).Return(revisionTestRevision(t, 1, 100), nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "split into items during revert",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil)
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 1}).Return(revisionTestRevision(t, 1, 100), nil)
+				ms.On("RevertTransactionRevisionTx", mock.Anything, db.RevertTransactionRevisionTxParams{TransactionID: 1, Revision: 1}).Return(db.RevertTransactionRevisionTxResult{}, db.ErrRevertItemsMode)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "split member removed since the revision",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil).Once()
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 1}).Return(revisionTestRevision(t, 1, 100), nil)
+				// Member 2 has been removed or merged into another member
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers()[:1], nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "split member not active on the transaction date",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(revisionTestTransaction(1), nil)
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(revisionTestMembers(), nil).Once()
+				ms.On("GetTransactionRevision", mock.Anything, db.GetTransactionRevisionParams{TransactionID: 1, Revision: 1}).Return(revisionTestRevision(t, 1, 100), nil)
+				// Member 2 joined after the transaction date
+				members := revisionTestMembers()
+				members[1].JoinedOn = pgtype.Date{Time: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("POST", "/transactions/1/revisions/1/revert", nil, 1)
+			req.SetPathValue("transaction_id", "1")
+			req.SetPathValue("revision", "1")
+			rr := httptest.NewRecorder()
+
+			handler := revertTransactionRevision(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.TransactionRevisionResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int32(3), response.Revision)
+				require.NotNil(t, response.RevertedFrom)
+				assert.Equal(t, int32(1), *response.RevertedFrom)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -99,6 +99,7 @@ func TestGetTransactionByID(t *testing.T) {
 		pathValue         string
 		expectedStatus    int
 		expectTransaction bool
+		expectedPayers    int
 	}{
 		{
 			name: "success",
@@ -117,13 +118,42 @@ func TestGetTransactionByID(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return([]db.TransactionPayer{}, nil)
 			},
 			pathValue:         "1",
 			expectedStatus:    http.StatusOK,
 			expectTransaction: true,
+			expectedPayers:    1,
+		},
+		{
+			name: "success with multiple payers",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{
+					ID:      1,
+					GroupID: 1,
+					Name:    "Dinner",
+					Amount:  decimal.NewFromInt(100),
+					ByUser:  1,
+				}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				// Mock group membership check
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				payers := []db.TransactionPayer{
+					{TransactionID: 1, MemberID: 1, Amount: decimal.NewFromInt(60)},
+					{TransactionID: 1, MemberID: 2, Amount: decimal.NewFromInt(40)},
+				}
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return(payers, nil)
+			},
+			pathValue:         "1",
+			expectedStatus:    http.StatusOK,
+			expectTransaction: true,
+			expectedPayers:    2,
 		},
 		{
 			name:              "invalid ID format",
@@ -161,6 +191,7 @@ func TestGetTransactionByID(t *testing.T) {
 				err := json.Unmarshal(rr.Body.Bytes(), &transactionResponse)
 				require.NoError(t, err)
 				assert.NotNil(t, transactionResponse["id"])
+				assert.Len(t, transactionResponse["payers"], tt.expectedPayers)
 			}
 			mockStore.AssertExpectations(t)
 		})
@@ -181,7 +212,7 @@ func TestCreateTransaction(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				// Mock GetGroupMemberByID for ByUser validation
@@ -191,6 +222,7 @@ func TestCreateTransaction(t *testing.T) {
 					UserID:  userID,
 				}
 				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(groupMember, nil)
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group", Currency: "USD"}, nil)
 				transaction := db.Transaction{
 					ID:              1,
 					GroupID:         1,
@@ -201,7 +233,10 @@ func TestCreateTransaction(t *testing.T) {
 					CreatedAt:       time.Now(),
 					ModifiedAt:      time.Now(),
 				}
-				ms.On("CreateTransaction", mock.Anything, mock.AnythingOfType("db.CreateTransactionParams")).Return(transaction, nil)
+				expectedParams := mock.MatchedBy(func(arg db.CreateTransactionParams) bool {
+					return arg.Currency == "USD" && arg.ExchangeRate.Equal(decimal.NewFromInt(1))
+				})
+				ms.On("CreateTransaction", mock.Anything, expectedParams).Return(transaction, nil)
 			},
 			requestBody: map[string]interface{}{
 				"group_id":         1,
@@ -213,6 +248,207 @@ func TestCreateTransaction(t *testing.T) {
 			expectedStatus:    http.StatusCreated,
 			expectTransaction: true,
 		},
+		{
+			name: "success with multiple payers",
+			setupMock: func(ms *mocks.MockStore) {
+				// Mock group membership check & payer validation
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group", Currency: "USD"}, nil)
+				expectedParams := mock.MatchedBy(func(arg db.CreateTransactionWithPayersTxParams) bool {
+					return arg.Transaction.ByUser == 1 && len(arg.Payers) == 2 &&
+						arg.Payers[0].MemberID == 1 && arg.Payers[0].Amount.Equal(decimal.NewFromInt(70)) &&
+						arg.Payers[1].MemberID == 2 && arg.Payers[1].Amount.Equal(decimal.NewFromInt(30))
+				})
+				result := db.CreateTransactionWithPayersTxResult{
+					Transaction: db.Transaction{ID: 1, GroupID: 1, Name: "Dinner", Amount: decimal.NewFromInt(100), ByUser: 1},
+					Payers: []db.TransactionPayer{
+						{TransactionID: 1, MemberID: 1, Amount: decimal.NewFromInt(70)},
+						{TransactionID: 1, MemberID: 2, Amount: decimal.NewFromInt(30)},
+					},
+				}
+				ms.On("CreateTransactionWithPayersTx", mock.Anything, expectedParams).Return(result, nil)
+			},
+			requestBody: map[string]interface{}{
+				"group_id": 1,
+				"name":     "Dinner",
+				"amount":   "100.00",
+				"payers": []map[string]interface{}{
+					{"member_id": 1, "amount": "70.00"},
+					{"member_id": 2, "amount": "30.00"},
+				},
+			},
+			expectedStatus:    http.StatusCreated,
+			expectTransaction: true,
+		},
+		{
+			name: "single payer is stored as by_user",
+			setupMock: func(ms *mocks.MockStore) {
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group", Currency: "USD"}, nil)
+				expectedParams := mock.MatchedBy(func(arg db.CreateTransactionParams) bool {
+					return arg.ByUser == 2
+				})
+				transaction := db.Transaction{ID: 1, GroupID: 1, Name: "Dinner", Amount: decimal.NewFromInt(100), ByUser: 2}
+				ms.On("CreateTransaction", mock.Anything, expectedParams).Return(transaction, nil)
+			},
+			requestBody: map[string]interface{}{
+				"group_id": 1,
+				"name":     "Dinner",
+				"amount":   "100.00",
+				"payers": []map[string]interface{}{
+					{"member_id": 2, "amount": "100.00"},
+				},
+			},
+			expectedStatus:    http.StatusCreated,
+			expectTransaction: true,
+		},
+		{
+			name: "payers do not total transaction amount",
+			setupMock: func(ms *mocks.MockStore) {
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestBody: map[string]interface{}{
+				"group_id": 1,
+				"name":     "Dinner",
+				"amount":   "100.00",
+				"payers": []map[string]interface{}{
+					{"member_id": 1, "amount": "70.00"},
+					{"member_id": 2, "amount": "20.00"},
+				},
+			},
+			expectedStatus:    http.StatusBadRequest,
+			expectTransaction: false,
+		},
+		{
+			name: "payer not in group",
+			setupMock: func(ms *mocks.MockStore) {
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestBody: map[string]interface{}{
+				"group_id": 1,
+				"name":     "Dinner",
+				"amount":   "100.00",
+				"payers": []map[string]interface{}{
+					{"member_id": 1, "amount": "70.00"},
+					{"member_id": 5, "amount": "30.00"},
+				},
+			},
+			expectedStatus:    http.StatusBadRequest,
+			expectTransaction: false,
+		},
+		{
+			name: "foreign currency uses exchange rate on transaction date",
+			setupMock: func(ms *mocks.MockStore) {
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(db.GetGroupMemberByIDRow{ID: 1, GroupID: 1, UserID: int64Ptr(1)}, nil)
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group", Currency: "USD"}, nil)
+				rateDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+				ms.On("GetExchangeRate", mock.Anything, db.GetExchangeRateParams{FromCurrency: "EUR", ToCurrency: "USD", RateDate: rateDate}).
+					Return(db.ExchangeRate{FromCurrency: "EUR", ToCurrency: "USD", RateDate: rateDate, Rate: decimal.RequireFromString("1.08")}, nil)
+				expectedParams := mock.MatchedBy(func(arg db.CreateTransactionParams) bool {
+					return arg.Currency == "EUR" && arg.ExchangeRate.Equal(decimal.RequireFromString("1.08"))
+				})
+				transaction := db.Transaction{ID: 1, GroupID: 1, Name: "Museum", Amount: decimal.NewFromInt(50), ByUser: 1, Currency: "EUR", ExchangeRate: decimal.RequireFromString("1.08")}
+				ms.On("CreateTransaction", mock.Anything, expectedParams).Return(transaction, nil)
+			},
+			requestBody: map[string]interface{}{
+				"group_id":         1,
+				"name":             "Museum",
+				"transaction_date": "2024-06-01T00:00:00Z",
+				"amount":           "50.00",
+				"currency":         "eur",
+				"by_user":          1,
+			},
+			expectedStatus:    http.StatusCreated,
+			expectTransaction: true,
+		},
+		{
+			name: "foreign currency uses inverse exchange rate",
+			setupMock: func(ms *mocks.MockStore) {
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(db.GetGroupMemberByIDRow{ID: 1, GroupID: 1, UserID: int64Ptr(1)}, nil)
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group", Currency: "USD"}, nil)
+				rateDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+				ms.On("GetExchangeRate", mock.Anything, db.GetExchangeRateParams{FromCurrency: "JPY", ToCurrency: "USD", RateDate: rateDate}).Return(db.ExchangeRate{}, pgx.ErrNoRows)
+				ms.On("GetExchangeRate", mock.Anything, db.GetExchangeRateParams{FromCurrency: "USD", ToCurrency: "JPY", RateDate: rateDate}).
+					Return(db.ExchangeRate{FromCurrency: "USD", ToCurrency: "JPY", RateDate: rateDate, Rate: decimal.NewFromInt(160)}, nil)
+				expectedParams := mock.MatchedBy(func(arg db.CreateTransactionParams) bool {
+					return arg.Currency == "JPY" && arg.ExchangeRate.Equal(decimal.RequireFromString("0.00625"))
+				})
+				transaction := db.Transaction{ID: 1, GroupID: 1, Name: "Ramen", Amount: decimal.NewFromInt(3200), ByUser: 1, Currency: "JPY", ExchangeRate: decimal.RequireFromString("0.00625")}
+				ms.On("CreateTransaction", mock.Anything, expectedParams).Return(transaction, nil)
+			},
+			requestBody: map[string]interface{}{
+				"group_id":         1,
+				"name":             "Ramen",
+				"transaction_date": "2024-06-01T00:00:00Z",
+				"amount":           "3200",
+				"currency":         "JPY",
+				"by_user":          1,
+			},
+			expectedStatus:    http.StatusCreated,
+			expectTransaction: true,
+		},
+		{
+			name: "no exchange rate for currency",
+			setupMock: func(ms *mocks.MockStore) {
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(db.GetGroupMemberByIDRow{ID: 1, GroupID: 1, UserID: int64Ptr(1)}, nil)
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group", Currency: "USD"}, nil)
+				ms.On("GetExchangeRate", mock.Anything, mock.AnythingOfType("db.GetExchangeRateParams")).Return(db.ExchangeRate{}, pgx.ErrNoRows)
+			},
+			requestBody: map[string]interface{}{
+				"group_id":         1,
+				"name":             "Museum",
+				"transaction_date": "2024-06-01T00:00:00Z",
+				"amount":           "50.00",
+				"currency":         "CHF",
+				"by_user":          1,
+			},
+			expectedStatus:    http.StatusBadRequest,
+			expectTransaction: false,
+		},
+		{
+			name: "viewer can't add transactions",
+			setupMock: func(ms *mocks.MockStore) {
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "viewer"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			},
+			requestBody: map[string]interface{}{
+				"group_id":         1,
+				"name":             "New Transaction",
+				"transaction_date": "2024-01-01T00:00:00Z",
+				"amount":           "100.00",
+				"by_user":          1,
+			},
+			expectedStatus:    http.StatusForbidden,
+			expectTransaction: false,
+		},
 		{
 			name:              "missing name",
 			setupMock:         func(ms *mocks.MockStore) {},
@@ -296,7 +532,7 @@ func TestUpdateTransaction(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				// Mock GetGroupMemberByID for ByUser validation
@@ -305,7 +541,10 @@ func TestUpdateTransaction(t *testing.T) {
 					GroupID: 1,
 					UserID:  userID,
 				}
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return([]db.TransactionPayer{}, nil)
 				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(groupMember, nil)
+				// Transaction date changed, exchange rate is looked up again for the group currency
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group", Currency: "USD"}, nil)
 				transaction := db.Transaction{
 					ID:              1,
 					GroupID:         1,
@@ -316,6 +555,8 @@ func TestUpdateTransaction(t *testing.T) {
 					CreatedAt:       time.Now(),
 					ModifiedAt:      time.Now(),
 				}
+				// Amount changed, the transaction has no splits to rescale
+				ms.On("GetSplitsByTransactionID", mock.Anything, int64(1)).Return([]db.Split{}, nil)
 				ms.On("UpdateTransaction", mock.Anything, mock.AnythingOfType("db.UpdateTransactionParams")).Return(transaction, nil)
 			},
 			pathValue: "1",
@@ -329,6 +570,238 @@ func TestUpdateTransaction(t *testing.T) {
 			expectedStatus:    http.StatusOK,
 			expectTransaction: true,
 		},
+		{
+			name: "changing currency looks up exchange rate",
+			setupMock: func(ms *mocks.MockStore) {
+				transactionDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+				existingTransaction := db.Transaction{ID: 1, GroupID: 1, TransactionDate: transactionDate, Amount: decimal.NewFromInt(100), ByUser: 1, Currency: "USD", ExchangeRate: decimal.NewFromInt(1)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(existingTransaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return([]db.TransactionPayer{}, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(db.GetGroupMemberByIDRow{ID: 1, GroupID: 1, UserID: int64Ptr(1)}, nil)
+				ms.On("GetGroupByID", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group", Currency: "USD"}, nil)
+				ms.On("GetExchangeRate", mock.Anything, db.GetExchangeRateParams{FromCurrency: "GBP", ToCurrency: "USD", RateDate: transactionDate}).
+					Return(db.ExchangeRate{FromCurrency: "GBP", ToCurrency: "USD", RateDate: transactionDate, Rate: decimal.RequireFromString("1.27")}, nil)
+				expectedParams := mock.MatchedBy(func(arg db.UpdateTransactionParams) bool {
+					return arg.Currency == "GBP" && arg.ExchangeRate.Equal(decimal.RequireFromString("1.27"))
+				})
+				transaction := db.Transaction{ID: 1, GroupID: 1, Name: "Taxi", TransactionDate: transactionDate, Amount: decimal.NewFromInt(100), ByUser: 1, Currency: "GBP", ExchangeRate: decimal.RequireFromString("1.27")}
+				ms.On("UpdateTransaction", mock.Anything, expectedParams).Return(transaction, nil)
+			},
+			pathValue: "1",
+			requestBody: map[string]interface{}{
+				"group_id":         1,
+				"name":             "Taxi",
+				"transaction_date": "2024-06-01T00:00:00Z",
+				"amount":           "100.00",
+				"currency":         "GBP",
+				"by_user":          1,
+			},
+			expectedStatus:    http.StatusOK,
+			expectTransaction: true,
+		},
+		{
+			name: "keeps existing payers when amount is unchanged",
+			setupMock: func(ms *mocks.MockStore) {
+				existingTransaction := db.Transaction{ID: 1, GroupID: 1, TransactionDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromInt(100), ByUser: 1, Currency: "EUR", ExchangeRate: decimal.RequireFromString("1.1")}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(existingTransaction, nil)
+				// Mock group membership check & payer validation
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				payers := []db.TransactionPayer{
+					{TransactionID: 1, MemberID: 1, Amount: decimal.NewFromInt(60)},
+					{TransactionID: 1, MemberID: 2, Amount: decimal.NewFromInt(40)},
+				}
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return(payers, nil)
+				expectedParams := mock.MatchedBy(func(arg db.UpdateTransactionWithPayersTxParams) bool {
+					return arg.Transaction.Name == "Renamed" && len(arg.Payers) == 2 &&
+						arg.Payers[0].MemberID == 1 && arg.Payers[1].MemberID == 2 &&
+						arg.Transaction.Currency == "EUR" && arg.Transaction.ExchangeRate.Equal(decimal.RequireFromString("1.1"))
+				})
+				result := db.UpdateTransactionWithPayersTxResult{
+					Transaction:   db.Transaction{ID: 1, GroupID: 1, Name: "Renamed", Amount: decimal.NewFromInt(100), ByUser: 1},
+					DeletedPayers: payers,
+					Payers:        payers,
+				}
+				ms.On("UpdateTransactionWithPayersTx", mock.Anything, expectedParams).Return(result, nil)
+			},
+			pathValue: "1",
+			requestBody: map[string]interface{}{
+				"group_id":         1,
+				"name":             "Renamed",
+				"transaction_date": "2024-01-01T00:00:00Z",
+				"amount":           "100.00",
+				"by_user":          1,
+			},
+			expectedStatus:    http.StatusOK,
+			expectTransaction: true,
+		},
+		{
+			name: "amount change requires payers",
+			setupMock: func(ms *mocks.MockStore) {
+				existingTransaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(100), ByUser: 1}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(existingTransaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				payers := []db.TransactionPayer{
+					{TransactionID: 1, MemberID: 1, Amount: decimal.NewFromInt(60)},
+					{TransactionID: 1, MemberID: 2, Amount: decimal.NewFromInt(40)},
+				}
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return(payers, nil)
+			},
+			pathValue: "1",
+			requestBody: map[string]interface{}{
+				"group_id": 1,
+				"name":     "Dinner",
+				"amount":   "120.00",
+				"by_user":  1,
+			},
+			expectedStatus:    http.StatusBadRequest,
+			expectTransaction: false,
+		},
+		{
+			name: "replacing payers with a single payer",
+			setupMock: func(ms *mocks.MockStore) {
+				existingTransaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(100), ByUser: 1}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(existingTransaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				payers := []db.TransactionPayer{
+					{TransactionID: 1, MemberID: 1, Amount: decimal.NewFromInt(60)},
+					{TransactionID: 1, MemberID: 2, Amount: decimal.NewFromInt(40)},
+				}
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return(payers, nil)
+				ms.On("GetSplitsByTransactionID", mock.Anything, int64(1)).Return([]db.Split{}, nil)
+				expectedParams := mock.MatchedBy(func(arg db.UpdateTransactionWithPayersTxParams) bool {
+					return arg.Transaction.ByUser == 2 && len(arg.Payers) == 0
+				})
+				result := db.UpdateTransactionWithPayersTxResult{
+					Transaction:   db.Transaction{ID: 1, GroupID: 1, Name: "Dinner", Amount: decimal.NewFromInt(120), ByUser: 2},
+					DeletedPayers: payers,
+					Payers:        []db.TransactionPayer{},
+				}
+				ms.On("UpdateTransactionWithPayersTx", mock.Anything, expectedParams).Return(result, nil)
+			},
+			pathValue: "1",
+			requestBody: map[string]interface{}{
+				"group_id": 1,
+				"name":     "Dinner",
+				"amount":   "120.00",
+				"payers": []map[string]interface{}{
+					{"member_id": 2, "amount": "120.00"},
+				},
+			},
+			expectedStatus:    http.StatusOK,
+			expectTransaction: true,
+		},
+		{
+			name: "amount change rescales splits by percent",
+			setupMock: func(ms *mocks.MockStore) {
+				transactionDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				existingTransaction := db.Transaction{ID: 1, GroupID: 1, TransactionDate: transactionDate, Amount: decimal.NewFromInt(90), ByUser: 1, Currency: "USD", ExchangeRate: decimal.NewFromInt(1)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(existingTransaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return([]db.TransactionPayer{}, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(db.GetGroupMemberByIDRow{ID: 1, GroupID: 1, UserID: int64Ptr(1)}, nil)
+				splits := []db.Split{
+					{ID: 10, TransactionID: 1, SplitPercent: decimal.RequireFromString("0.333334"), SplitAmount: decimal.NewFromInt(30), SplitUser: int64Ptr(1)},
+					{ID: 11, TransactionID: 1, SplitPercent: decimal.RequireFromString("0.333333"), SplitAmount: decimal.NewFromInt(30), SplitUser: int64Ptr(2)},
+					{ID: 12, TransactionID: 1, SplitPercent: decimal.RequireFromString("0.333333"), SplitAmount: decimal.NewFromInt(30), SplitUser: int64Ptr(3)},
+				}
+				ms.On("GetSplitsByTransactionID", mock.Anything, int64(1)).Return(splits, nil)
+				expectedParams := mock.MatchedBy(func(arg db.UpdateTransactionWithPayersTxParams) bool {
+					return len(arg.Payers) == 0 && len(arg.Splits) == 3 &&
+						arg.Splits[0].ID == 10 && arg.Splits[0].SplitAmount.Equal(decimal.RequireFromString("33.34")) &&
+						arg.Splits[1].ID == 11 && arg.Splits[1].SplitAmount.Equal(decimal.RequireFromString("33.33")) &&
+						arg.Splits[2].ID == 12 && arg.Splits[2].SplitAmount.Equal(decimal.RequireFromString("33.33"))
+				})
+				result := db.UpdateTransactionWithPayersTxResult{
+					Transaction: db.Transaction{ID: 1, GroupID: 1, Name: "Dinner", TransactionDate: transactionDate, Amount: decimal.NewFromInt(100), ByUser: 1},
+					Payers:      []db.TransactionPayer{},
+				}
+				ms.On("UpdateTransactionWithPayersTx", mock.Anything, expectedParams).Return(result, nil)
+			},
+			pathValue: "1",
+			requestBody: map[string]interface{}{
+				"group_id":         1,
+				"name":             "Dinner",
+				"transaction_date": "2024-01-01T00:00:00Z",
+				"amount":           "100.00",
+				"by_user":          1,
+			},
+			expectedStatus:    http.StatusOK,
+			expectTransaction: true,
+		},
+		{
+			name: "amount change rejected for exact splits",
+			setupMock: func(ms *mocks.MockStore) {
+				transactionDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				splitMode := db.SplitModeExact
+				existingTransaction := db.Transaction{ID: 1, GroupID: 1, TransactionDate: transactionDate, Amount: decimal.NewFromInt(90), ByUser: 1, Currency: "USD", ExchangeRate: decimal.NewFromInt(1), SplitMode: &splitMode}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(existingTransaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return([]db.TransactionPayer{}, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(db.GetGroupMemberByIDRow{ID: 1, GroupID: 1, UserID: int64Ptr(1)}, nil)
+				splits := []db.Split{
+					{ID: 10, TransactionID: 1, SplitPercent: decimal.RequireFromString("0.666667"), SplitAmount: decimal.NewFromInt(60), SplitUser: int64Ptr(1)},
+					{ID: 11, TransactionID: 1, SplitPercent: decimal.RequireFromString("0.333333"), SplitAmount: decimal.NewFromInt(30), SplitUser: int64Ptr(2)},
+				}
+				ms.On("GetSplitsByTransactionID", mock.Anything, int64(1)).Return(splits, nil)
+			},
+			pathValue: "1",
+			requestBody: map[string]interface{}{
+				"group_id":         1,
+				"name":             "Dinner",
+				"transaction_date": "2024-01-01T00:00:00Z",
+				"amount":           "100.00",
+				"by_user":          1,
+			},
+			expectedStatus:    http.StatusConflict,
+			expectTransaction: false,
+		},
+		{
+			name: "splits changed during amount change",
+			setupMock: func(ms *mocks.MockStore) {
+				transactionDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				existingTransaction := db.Transaction{ID: 1, GroupID: 1, TransactionDate: transactionDate, Amount: decimal.NewFromInt(90), ByUser: 1, Currency: "USD", ExchangeRate: decimal.NewFromInt(1)}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(existingTransaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return([]db.TransactionPayer{}, nil)
+				ms.On("GetGroupMemberByID", mock.Anything, int64(1)).Return(db.GetGroupMemberByIDRow{ID: 1, GroupID: 1, UserID: int64Ptr(1)}, nil)
+				ms.On("GetSplitsByTransactionID", mock.Anything, int64(1)).Return([]db.Split{}, nil)
+				ms.On("UpdateTransaction", mock.Anything, mock.AnythingOfType("db.UpdateTransactionParams")).Return(db.Transaction{}, db.ErrSplitsChanged)
+			},
+			pathValue: "1",
+			requestBody: map[string]interface{}{
+				"group_id":         1,
+				"name":             "Dinner",
+				"transaction_date": "2024-01-01T00:00:00Z",
+				"amount":           "100.00",
+				"by_user":          1,
+			},
+			expectedStatus:    http.StatusConflict,
+			expectTransaction: false,
+		},
 		{
 			name:              "invalid ID format",
 			setupMock:         func(ms *mocks.MockStore) {},
@@ -412,7 +885,7 @@ func TestDeleteTransaction(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				ms.On("DeleteTransaction", mock.Anything, int64(1)).Return(transaction, nil)
@@ -428,6 +901,91 @@ func TestDeleteTransaction(t *testing.T) {
 			expectedStatus:    http.StatusBadRequest,
 			expectTransaction: false,
 		},
+		{
+			name: "member can't delete another member's transaction",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Name: "Transaction 1", Amount: decimal.NewFromInt(100), ByUser: 2}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "member"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return([]db.TransactionPayer{}, nil)
+			},
+			pathValue:         "1",
+			expectedStatus:    http.StatusForbidden,
+			expectTransaction: false,
+		},
+		{
+			name: "member can delete their own transaction",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Name: "Transaction 1", Amount: decimal.NewFromInt(100), ByUser: 1}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "member"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("DeleteTransaction", mock.Anything, int64(1)).Return(transaction, nil)
+			},
+			pathValue:         "1",
+			expectedStatus:    http.StatusOK,
+			expectTransaction: true,
+		},
+		{
+			name: "co-payer can delete the transaction",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Name: "Transaction 1", Amount: decimal.NewFromInt(100), ByUser: 2}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "member"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				payers := []db.TransactionPayer{
+					{TransactionID: 1, MemberID: 2, Amount: decimal.NewFromInt(60)},
+					{TransactionID: 1, MemberID: 1, Amount: decimal.NewFromInt(40)},
+				}
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return(payers, nil)
+				ms.On("DeleteTransaction", mock.Anything, int64(1)).Return(transaction, nil)
+			},
+			pathValue:         "1",
+			expectedStatus:    http.StatusOK,
+			expectTransaction: true,
+		},
+		{
+			name: "payers lookup fails",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Name: "Transaction 1", Amount: decimal.NewFromInt(100), ByUser: 2}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "member"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return(nil, errors.New("database error"))
+			},
+			pathValue:         "1",
+			expectedStatus:    http.StatusForbidden,
+			expectTransaction: false,
+		},
+		{
+			name: "admin can delete another member's transaction",
+			setupMock: func(ms *mocks.MockStore) {
+				transaction := db.Transaction{ID: 1, GroupID: 1, Name: "Transaction 1", Amount: decimal.NewFromInt(100), ByUser: 2}
+				ms.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "admin"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("DeleteTransaction", mock.Anything, int64(1)).Return(transaction, nil)
+			},
+			pathValue:         "1",
+			expectedStatus:    http.StatusOK,
+			expectTransaction: true,
+		},
 		{
 			name: "transaction not found",
 			setupMock: func(ms *mocks.MockStore) {
@@ -482,7 +1040,7 @@ func TestGetSplitsByTransactionNested(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				splits := []db.Split{
@@ -521,7 +1079,7 @@ func TestGetSplitsByTransactionNested(t *testing.T) {
 				// Mock group membership check
 				userID := int64Ptr(1)
 				members := []db.ListGroupMembersByGroupIDRow{
-					{ID: 1, GroupID: 1, UserID: userID},
+					{ID: 1, GroupID: 1, UserID: userID, Role: "owner"},
 				}
 				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
 				ms.On("GetSplitsByTransactionID", mock.Anything, int64(1)).Return(nil, errors.New("database error"))
@@ -555,3 +1113,72 @@ func TestGetSplitsByTransactionNested(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateTransactionSplitsBatch(t *testing.T) {
+	transactionDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	transaction := db.Transaction{ID: 1, GroupID: 1, Amount: decimal.NewFromInt(90), TransactionDate: transactionDate}
+
+	// Member 2 moved out before the transaction, member 4 moves in after it
+	members := createTestGroupMembers([]int64{1, 2, 3, 4}, 1)
+	members[1].LeftOn = DateFromPtr(timePtr(time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)))
+	members[3].JoinedOn = DateFromPtr(timePtr(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)))
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+	}{
+		{
+			name: "equal mode without splits uses active members",
+			body: `{"mode": "equal"}`,
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("CreateSplitsTx", mock.Anything, mock.MatchedBy(func(arg db.CreateSplitsTxParams) bool {
+					if arg.TransactionID != 1 || len(arg.Splits) != 2 {
+						return false
+					}
+					return *arg.Splits[0].SplitUser == 1 && *arg.Splits[1].SplitUser == 3 &&
+						arg.Splits[0].SplitAmount.Equal(decimal.NewFromInt(45)) && arg.Splits[1].SplitAmount.Equal(decimal.NewFromInt(45))
+				})).Return(db.CreateSplitsTxResult{
+					Transaction: transaction,
+					Splits: []db.Split{
+						{ID: 1, TransactionID: 1, SplitAmount: decimal.NewFromInt(45), SplitUser: int64Ptr(1)},
+						{ID: 2, TransactionID: 1, SplitAmount: decimal.NewFromInt(45), SplitUser: int64Ptr(3)},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "split for member who had left",
+			body:           `{"mode": "equal", "splits": [{"split_user": 1}, {"split_user": 2}]}`,
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "split for member who had not joined",
+			body:           `{"mode": "equal", "splits": [{"split_user": 1}, {"split_user": 4}]}`,
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			mockStore.On("GetTransactionByID", mock.Anything, int64(1)).Return(transaction, nil)
+			mockStore.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("POST", "/transactions/1/splits", []byte(tt.body), 1)
+			req.SetPathValue("transaction_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := createTransactionSplitsBatch(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"net/http"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
+)
+
+// List groups in the trash owned by the authenticated user
+// GET /groups/trash
+func listDeletedGroups(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Parse query parameters
+		limit, offset, err := ParseLimitOffset(r)
+		if err != nil {
+			http.Error(w, "Invalid parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		listParams := db.ListDeletedGroupsByOwnerParams{
+			UserID: &userID,
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		logger.Debug("Listing groups in the trash", "user_id", userID, "limit", limit, "offset", offset)
+
+		groups, err := store.ListDeletedGroupsByOwner(r.Context(), listParams)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list groups in the trash", "user_id", userID) {
+			return
+		}
+
+		groupResponses := make([]models.GroupResponse, len(groups))
+		for i, group := range groups {
+			groupResponses[i] = models.GroupResponse{
+				ID:        group.ID,
+				Name:      group.Name,
+				Currency:  group.Currency,
+				DeletedAt: TimestamptzPtr(group.DeletedAt),
+			}
+		}
+
+		listGroupResponse := models.ListGroupResponse{
+			Groups: groupResponses,
+			Count:  int32(len(groupResponses)),
+			Limit:  limit,
+			Offset: offset,
+		}
+
+		if err := WriteJSONResponseOK(w, listGroupResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Restore a group from the trash, with its members, transactions & settlements
+// POST /groups/{id}/restore
+func restoreGroup(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {id} from path parameter
+		id, ok := ParsePathInt64(w, r, "id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Only the owner who deleted the group can restore it, members of a group in the trash can't be listed
+		_, err := store.GetDeletedGroupByIDForOwner(r.Context(), db.GetDeletedGroupByIDForOwnerParams{
+			ID:     id,
+			UserID: &userID,
+		})
+		if HandleDBError(w, err, "Group not found in the trash", "An error has occurred", "Failed to get group in the trash", "group_id", id, "user_id", userID) {
+			return
+		}
+
+		logger.Debug("Restoring group", "group_id", id, "user_id", userID)
+
+		group, err := store.RestoreGroup(r.Context(), id)
+		if HandleDBError(w, err, "Group not found in the trash", "An error has occurred", "Failed to restore group", "group_id", id) {
+			return
+		}
+
+		groupResponse := models.GroupResponse{
+			ID:       group.ID,
+			Name:     group.Name,
+			Currency: group.Currency,
+		}
+
+		if err := WriteJSONResponseOK(w, groupResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// List transactions in the trash for group
+// GET /groups/{group_id}/trash
+func listDeletedGroupTransactions(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {group_id} from path parameter
+		groupID, ok := ParsePathInt64(w, r, "group_id", "Group ID is required")
+		if !ok {
+			return
+		}
+
+		// Verify user is a member of the group
+		if err := auth.CheckGroupMembership(r.Context(), store, groupID, userID); err != nil {
+			http.Error(w, "Forbidden: you must be a member of this group", http.StatusForbidden)
+			return
+		}
+
+		// Parse query parameters
+		limit, offset, err := ParseLimitOffset(r)
+		if err != nil {
+			http.Error(w, "Invalid parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		listParams := db.ListDeletedTransactionsByGroupIDParams{
+			GroupID: groupID,
+			Limit:   limit,
+			Offset:  offset,
+		}
+
+		logger.Debug("Listing transactions in the trash for group", "group_id", groupID, "limit", limit, "offset", offset)
+
+		transactions, err := store.ListDeletedTransactionsByGroupID(r.Context(), listParams)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to list transactions in the trash", "group_id", groupID) {
+			return
+		}
+
+		transactionResponses := make([]models.TransactionResponse, len(transactions))
+		for i, tx := range transactions {
+			transactionResponses[i] = models.TransactionResponse{
+				ID:              tx.ID,
+				GroupID:         tx.GroupID,
+				Name:            tx.Name,
+				TransactionDate: tx.TransactionDate,
+				Amount:          tx.Amount,
+				Currency:        tx.Currency,
+				ExchangeRate:    tx.ExchangeRate,
+				BaseAmount:      services.ConvertAmount(tx.Amount, tx.ExchangeRate),
+				Category:        tx.Category,
+				Note:            tx.Note,
+				ByUser:          tx.ByUser,
+				SplitMode:       tx.SplitMode,
+				CreatedAt:       tx.CreatedAt,
+				ModifiedAt:      tx.ModifiedAt,
+				DeletedAt:       TimestamptzPtr(tx.DeletedAt),
+			}
+		}
+
+		listTransactionResponse := models.ListTransactionResponse{
+			Transactions: transactionResponses,
+			Count:        int32(len(transactionResponses)),
+			Limit:        limit,
+			Offset:       offset,
+		}
+
+		if err := WriteJSONResponseOK(w, listTransactionResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Restore a transaction from the trash, with its splits, payers & items
+// POST /transactions/{id}/restore
+func restoreTransaction(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Extract {id} from path parameter
+		id, ok := ParsePathInt64(w, r, "id", "Transaction ID is required")
+		if !ok {
+			return
+		}
+
+		// Get transaction in the trash to find its group
+		transaction, err := store.GetDeletedTransactionByID(r.Context(), id)
+		if HandleDBError(w, err, "Transaction not found in the trash", "An error has occurred", "Failed to get transaction in the trash", "transaction_id", id) {
+			return
+		}
+
+		// Verify user may restore the transaction, the same members who may delete it
+		err = auth.CheckTransactionPermission(r.Context(), store, transaction, userID)
+		if HandleGroupPermissionError(w, err, "Forbidden: only the group owner or an admin can restore other members' transactions") {
+			return
+		}
+
+		logger.Debug("Restoring transaction", "transaction_id", id, "user_id", userID)
+
+		transaction, err = store.RestoreTransaction(r.Context(), id)
+		if HandleDBError(w, err, "Transaction not found in the trash", "An error has occurred", "Failed to restore transaction", "transaction_id", id) {
+			return
+		}
+
+		transactionResponse := models.TransactionResponse{
+			ID:              transaction.ID,
+			GroupID:         transaction.GroupID,
+			Name:            transaction.Name,
+			TransactionDate: transaction.TransactionDate,
+			Amount:          transaction.Amount,
+			Currency:        transaction.Currency,
+			ExchangeRate:    transaction.ExchangeRate,
+			BaseAmount:      services.ConvertAmount(transaction.Amount, transaction.ExchangeRate),
+			Category:        transaction.Category,
+			Note:            transaction.Note,
+			ByUser:          transaction.ByUser,
+			SplitMode:       transaction.SplitMode,
+			CreatedAt:       transaction.CreatedAt,
+			ModifiedAt:      transaction.ModifiedAt,
+		}
+
+		if err := WriteJSONResponseOK(w, transactionResponse); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDeletedGroups(t *testing.T) {
+	deletedAt := pgtype.Timestamptz{Time: time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC), Valid: true}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		requestURL     string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				groups := []db.Group{{ID: 1, Name: "Group 1", Currency: "USD", DeletedAt: deletedAt}}
+				ms.On("ListDeletedGroupsByOwner", mock.Anything, db.ListDeletedGroupsByOwnerParams{UserID: int64Ptr(1), Limit: 100, Offset: 0}).Return(groups, nil)
+			},
+			requestURL:     "/groups/trash",
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "invalid limit parameter",
+			setupMock:      func(ms *mocks.MockStore) {},
+			requestURL:     "/groups/trash?limit=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListDeletedGroupsByOwner", mock.Anything, db.ListDeletedGroupsByOwnerParams{UserID: int64Ptr(1), Limit: 100, Offset: 0}).Return(nil, errors.New("database error"))
+			},
+			requestURL:     "/groups/trash",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", tt.requestURL, nil, 1)
+			rr := httptest.NewRecorder()
+
+			handler := listDeletedGroups(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.ListGroupResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedCount, len(response.Groups))
+				require.NotNil(t, response.Groups[0].DeletedAt)
+				assert.True(t, deletedAt.Time.Equal(*response.Groups[0].DeletedAt))
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreGroup(t *testing.T) {
+	deletedGroup := db.Group{ID: 1, Name: "Group 1", Currency: "USD", DeletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		pathValue      string
+		expectedStatus int
+	}{
+		{
+			name: "owner restores group",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetDeletedGroupByIDForOwner", mock.Anything, db.GetDeletedGroupByIDForOwnerParams{ID: 1, UserID: int64Ptr(1)}).Return(deletedGroup, nil)
+				ms.On("RestoreGroup", mock.Anything, int64(1)).Return(db.Group{ID: 1, Name: "Group 1", Currency: "USD"}, nil)
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "group not in the trash or not owned by user",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetDeletedGroupByIDForOwner", mock.Anything, db.GetDeletedGroupByIDForOwnerParams{ID: 1, UserID: int64Ptr(1)}).Return(db.Group{}, pgx.ErrNoRows)
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid ID format",
+			setupMock:      func(ms *mocks.MockStore) {},
+			pathValue:      "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetDeletedGroupByIDForOwner", mock.Anything, db.GetDeletedGroupByIDForOwnerParams{ID: 1, UserID: int64Ptr(1)}).Return(deletedGroup, nil)
+				ms.On("RestoreGroup", mock.Anything, int64(1)).Return(db.Group{}, errors.New("database error"))
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("POST", "/groups/"+tt.pathValue+"/restore", nil, 1)
+			req.SetPathValue("id", tt.pathValue)
+			rr := httptest.NewRecorder()
+
+			handler := restoreGroup(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, float64(1), response["id"])
+				assert.NotContains(t, response, "deleted_at")
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListDeletedGroupTransactions(t *testing.T) {
+	members := []db.ListGroupMembersByGroupIDRow{
+		{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "viewer"},
+	}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "member lists trash",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				transactions := []db.Transaction{
+					{ID: 1, GroupID: 1, Name: "Dinner", Amount: decimal.NewFromInt(60), ExchangeRate: decimal.NewFromInt(1), ByUser: 1, DeletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+					{ID: 2, GroupID: 1, Name: "Taxi", Amount: decimal.NewFromInt(20), ExchangeRate: decimal.NewFromInt(1), ByUser: 1, DeletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+				}
+				ms.On("ListDeletedTransactionsByGroupID", mock.Anything, db.ListDeletedTransactionsByGroupIDParams{GroupID: 1, Limit: 100, Offset: 0}).Return(transactions, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name: "not a group member",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return([]db.ListGroupMembersByGroupIDRow{}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListDeletedTransactionsByGroupID", mock.Anything, db.ListDeletedTransactionsByGroupIDParams{GroupID: 1, Limit: 100, Offset: 0}).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", "/groups/1/trash", nil, 1)
+			req.SetPathValue("group_id", "1")
+			rr := httptest.NewRecorder()
+
+			handler := listDeletedGroupTransactions(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.ListTransactionResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedCount, len(response.Transactions))
+				for _, tx := range response.Transactions {
+					assert.NotNil(t, tx.DeletedAt)
+				}
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreTransaction(t *testing.T) {
+	deletedTransaction := func(byUser int64) db.Transaction {
+		return db.Transaction{
+			ID:           1,
+			GroupID:      1,
+			Name:         "Dinner",
+			Amount:       decimal.NewFromInt(60),
+			ExchangeRate: decimal.NewFromInt(1),
+			ByUser:       byUser,
+			DeletedAt:    pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockStore)
+		pathValue      string
+		expectedStatus int
+	}{
+		{
+			name: "member restores their own transaction",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetDeletedTransactionByID", mock.Anything, int64(1)).Return(deletedTransaction(1), nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "member"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				restored := deletedTransaction(1)
+				restored.DeletedAt = pgtype.Timestamptz{}
+				ms.On("RestoreTransaction", mock.Anything, int64(1)).Return(restored, nil)
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "member can't restore another member's transaction",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetDeletedTransactionByID", mock.Anything, int64(1)).Return(deletedTransaction(2), nil)
+				members := []db.ListGroupMembersByGroupIDRow{
+					{ID: 1, GroupID: 1, UserID: int64Ptr(1), Role: "member"},
+					{ID: 2, GroupID: 1, UserID: int64Ptr(2), Role: "owner"},
+				}
+				ms.On("ListGroupMembersByGroupID", mock.Anything, db.ListGroupMembersByGroupIDParams{GroupID: 1, Limit: 1000, Offset: 0}).Return(members, nil)
+				ms.On("ListTransactionPayersByTransactionID", mock.Anything, int64(1)).Return([]db.TransactionPayer{}, nil)
+			},
+			pathValue:      "1",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "transaction not in the trash",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("GetDeletedTransactionByID", mock.Anything, int64(999)).Return(db.Transaction{}, pgx.ErrNoRows)
+			},
+			pathValue:      "999",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid ID format",
+			setupMock:      func(ms *mocks.MockStore) {},
+			pathValue:      "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("POST", "/transactions/"+tt.pathValue+"/restore", nil, 1)
+			req.SetPathValue("id", tt.pathValue)
+			rr := httptest.NewRecorder()
+
+			handler := restoreTransaction(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, float64(1), response["id"])
+				assert.NotContains(t, response, "deleted_at")
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
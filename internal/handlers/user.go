@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
@@ -10,6 +14,7 @@ import (
 	"github.com/MattSharp0/transaction-split-go/internal/logger"
 	"github.com/MattSharp0/transaction-split-go/internal/models"
 	"github.com/MattSharp0/transaction-split-go/internal/server"
+	"github.com/MattSharp0/transaction-split-go/internal/services"
 	"github.com/shopspring/decimal"
 )
 
@@ -30,6 +35,8 @@ func UserRoutes(s *server.Server, q db.Store) *http.ServeMux {
 	mux.HandleFunc("GET /me/transactions", getTransactionsByUserNested(q)) // GET: List transactions for current user
 	mux.HandleFunc("GET /me/splits", getUserSplits(q))                     // GET: List splits for current user
 	mux.HandleFunc("GET /me/balances", getUserBalances(q))                 // GET: Get balances for current user
+	mux.HandleFunc("GET /me/settlement-plan", getUserSettlementPlan(q))    // GET: Cross-group settlement plan for current user
+	mux.HandleFunc("POST /me/settlements", createCrossGroupPayment(q))     // POST: Record a payment split into per-group settlements
 
 	return mux
 }
@@ -338,9 +345,13 @@ func getTransactionsByUserNested(store db.Store) http.HandlerFunc {
 				Name:            tx.Name,
 				TransactionDate: tx.TransactionDate,
 				Amount:          tx.Amount,
+				Currency:        tx.Currency,
+				ExchangeRate:    tx.ExchangeRate,
+				BaseAmount:      services.ConvertAmount(tx.Amount, tx.ExchangeRate),
 				Category:        tx.Category,
 				Note:            tx.Note,
 				ByUser:          tx.ByUser,
+				SplitMode:       tx.SplitMode,
 				CreatedAt:       tx.CreatedAt,
 				ModifiedAt:      tx.ModifiedAt,
 			}
@@ -474,6 +485,7 @@ func getUserBalances(store db.Store) http.HandlerFunc {
 			balancesByGroup[i] = models.UserBalanceByGroupResponse{
 				GroupID:         bg.GroupID,
 				GroupName:       bg.GroupName,
+				Currency:        bg.Currency,
 				NetBalance:      bg.NetBalance,
 				TotalOwed:       totalOwed,
 				TotalOwedToUser: totalOwedToUser,
@@ -523,3 +535,236 @@ func getUserBalances(store db.Store) http.HandlerFunc {
 		}
 	}
 }
+
+// Get a plan settling the current user's whole network at once, everyone sharing a group with the user
+// Balances between each pair of users are aggregated across groups, so debts in one group cancel out credits in another
+// GET /users/me/settlement-plan
+func getUserSettlementPlan(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		simplify, err := services.ParseSimplifyMethod(r.URL.Query().Get("simplify"))
+		if err != nil {
+			http.Error(w, "Invalid parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.Debug("Getting settlement plan for user", "user_id", userID, "simplify", simplify)
+
+		networkBalances, err := store.UserNetworkBalances(r.Context(), &userID)
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get user network balances", "user_id", userID) {
+			return
+		}
+
+		netBalances, userNames := networkNetBalances(networkBalances)
+
+		var payments []models.SimplifiedPaymentsResponse
+		if simplify == services.SimplifyOptimal {
+			payments, err = services.SimplifyDebtsOptimal(netBalances)
+		} else {
+			payments, err = services.SimplifyDebts(netBalances)
+		}
+		if errors.Is(err, services.ErrTooManyMembersToOptimize) {
+			http.Error(w, "Invalid parameter: "+err.Error()+", use simplify=greedy", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to simplify debts", "error", err, "user_id", userID, "simplify", simplify)
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+
+		paymentResponses := make([]models.SettlementPlanPaymentResponse, len(payments))
+		for i, payment := range payments {
+			paymentResponses[i] = models.SettlementPlanPaymentResponse{
+				FromUserID:   payment.FromUserID,
+				FromUserName: userNames[payment.FromUserID],
+				ToUserID:     payment.ToUserID,
+				ToUserName:   userNames[payment.ToUserID],
+				Amount:       payment.Amount,
+			}
+		}
+
+		response := models.SettlementPlanResponse{
+			UserID:   userID,
+			Simplify: string(simplify),
+			Payments: paymentResponses,
+			Count:    int32(len(paymentResponses)),
+		}
+
+		if err := WriteJSONResponseOK(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Record a payment between the current user and another user, split into settlements in the groups they share
+// Settlements are confirmed when the current user was paid, otherwise pending until the other user confirms them
+// POST /users/me/settlements
+func createCrossGroupPayment(store db.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get authenticated user ID
+		userID, ok := GetAuthenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Decode request body
+		var paymentReq models.CreateCrossGroupPaymentRequest
+		if err := DecodeJSONBody(r, &paymentReq); err != nil {
+			http.Error(w, "Bad request: invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		// Users can only record payments they made or received
+		if paymentReq.FromUserID != userID && paymentReq.ToUserID != userID {
+			http.Error(w, "Forbidden: you can only record payments you made or received", http.StatusForbidden)
+			return
+		}
+
+		sharedBalances, err := store.SharedGroupBalancesNet(r.Context(), db.SharedGroupBalancesNetParams{
+			UserID:      paymentReq.FromUserID,
+			OtherUserID: paymentReq.ToUserID,
+		})
+		if HandleDBListError(w, err, "An error has occurred", "Failed to get shared group balances", "from_user_id", paymentReq.FromUserID, "to_user_id", paymentReq.ToUserID) {
+			return
+		}
+		if len(sharedBalances) == 0 {
+			http.Error(w, fmt.Sprintf("Users %d and %d do not share any groups", paymentReq.FromUserID, paymentReq.ToUserID), http.StatusBadRequest)
+			return
+		}
+
+		// Settlements are in each group's base currency, only split into groups with the payment currency
+		currency := paymentReq.Currency
+		if currency != "" {
+			currency, err = services.NormalizeCurrency(currency)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			var currencies []string
+			for _, balance := range sharedBalances {
+				if !slices.Contains(currencies, balance.Currency) {
+					currencies = append(currencies, balance.Currency)
+				}
+			}
+			if len(currencies) > 1 {
+				http.Error(w, fmt.Sprintf("Currency is required, shared groups use %s", strings.Join(currencies, ", ")), http.StatusBadRequest)
+				return
+			}
+			currency = currencies[0]
+		}
+
+		// Viewers can't record settlements, groups where the user is a viewer are left out
+		viewerGroups := map[int64]bool{}
+		for _, balance := range sharedBalances {
+			if balance.UserID != nil && *balance.UserID == userID && !auth.HasPermission(auth.GroupRole(balance.Role), auth.PermissionAddTransactions) {
+				viewerGroups[balance.GroupID] = true
+			}
+		}
+
+		groupBalances := []services.GroupMemberBalance{}
+		for _, balance := range sharedBalances {
+			if balance.Currency != currency || balance.UserID == nil || viewerGroups[balance.GroupID] {
+				continue
+			}
+			groupBalances = append(groupBalances, services.GroupMemberBalance{
+				GroupID:    balance.GroupID,
+				MemberID:   balance.MemberID,
+				UserID:     *balance.UserID,
+				NetBalance: balance.NetBalance,
+			})
+		}
+
+		groupSettlements, err := services.SplitCrossGroupPayment(paymentReq.FromUserID, paymentReq.ToUserID, paymentReq.Amount, groupBalances)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		settledOn := paymentReq.SettledOn
+		if settledOn.IsZero() {
+			settledOn = time.Now()
+		}
+
+		// Every part of the payment is confirmed together by the user who was paid
+		status := services.SettlementStatusPending
+		if paymentReq.ToUserID == userID {
+			status = services.SettlementStatusConfirmed
+		}
+
+		logger.Debug("Recording cross-group payment", "from_user_id", paymentReq.FromUserID, "to_user_id", paymentReq.ToUserID, "amount", paymentReq.Amount, "currency", currency, "settlement_count", len(groupSettlements), "status", status, "user_id", userID)
+
+		settlementParams := make([]db.CreateSettlementParams, len(groupSettlements))
+		for i, groupSettlement := range groupSettlements {
+			settlementParams[i] = db.CreateSettlementParams{
+				GroupID:    groupSettlement.GroupID,
+				FromMember: groupSettlement.FromMember,
+				ToMember:   groupSettlement.ToMember,
+				Amount:     groupSettlement.Amount,
+				SettledOn:  settledOn,
+				Note:       paymentReq.Note,
+				CreatedBy:  &userID,
+				Status:     string(status),
+			}
+		}
+
+		result, err := store.CreateCrossGroupSettlementsTx(r.Context(), db.CreateCrossGroupSettlementsTxParams{
+			Settlements: settlementParams,
+		})
+		if err != nil {
+			logger.Error("Failed to record cross-group payment", "error", err, "from_user_id", paymentReq.FromUserID, "to_user_id", paymentReq.ToUserID)
+			http.Error(w, fmt.Sprintf("Failed to record payment: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		settlementResponses := settlementResponses(result.Settlements)
+
+		response := models.CrossGroupPaymentResponse{
+			FromUserID:  paymentReq.FromUserID,
+			ToUserID:    paymentReq.ToUserID,
+			Amount:      paymentReq.Amount,
+			Currency:    currency,
+			Settlements: settlementResponses,
+			Count:       int32(len(settlementResponses)),
+		}
+
+		if err := WriteJSONResponseCreated(w, response); err != nil {
+			http.Error(w, "An error has occurred", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// networkNetBalances sums each user's balances with the rest of their network for SimplifyDebts, sorted by user ID
+// Also returns each user's name by ID
+func networkNetBalances(networkBalances []db.UserNetworkBalancesRow) ([]*models.NetBalance, map[int64]string) {
+	owed := make(map[int64]decimal.Decimal)
+	userNames := make(map[int64]string)
+	for _, row := range networkBalances {
+		if row.UserID == nil || row.MemberUserID == nil {
+			continue
+		}
+		owed[*row.UserID] = owed[*row.UserID].Add(row.NetBalance)
+		userNames[*row.UserID] = row.UserName
+	}
+
+	userIDs := make([]int64, 0, len(owed))
+	for id := range owed {
+		userIDs = append(userIDs, id)
+	}
+	slices.Sort(userIDs)
+
+	// SimplifyDebts expects positive balances for users who owe, network balances are positive when owed
+	netBalances := make([]*models.NetBalance, len(userIDs))
+	for i, id := range userIDs {
+		netBalances[i] = &models.NetBalance{UserID: id, NetBalance: owed[id].Neg()}
+	}
+	return netBalances, userNames
+}
@@ -13,6 +13,7 @@ import (
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
 	"github.com/MattSharp0/transaction-split-go/internal/logger"
 	"github.com/MattSharp0/transaction-split-go/internal/mocks"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
 	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -593,3 +594,275 @@ func TestGetTransactionsByUserNested(t *testing.T) {
 func intPtr(i int64) *int64 {
 	return &i
 }
+
+func TestGetUserSettlementPlan(t *testing.T) {
+	// User 1 owes Alice (2) 20 in one group and Alice owes Bob (3) 20 in another,
+	// so user 1 can pay Bob directly and Alice is settled without paying anyone
+	networkBalances := []db.UserNetworkBalancesRow{
+		{UserID: intPtr(1), UserName: "Me", MemberUserID: intPtr(2), NetBalance: decimal.NewFromInt(-20)},
+		{UserID: intPtr(2), UserName: "Alice", MemberUserID: intPtr(1), NetBalance: decimal.NewFromInt(20)},
+		{UserID: intPtr(2), UserName: "Alice", MemberUserID: intPtr(3), NetBalance: decimal.NewFromInt(-20)},
+		{UserID: intPtr(3), UserName: "Bob", MemberUserID: intPtr(2), NetBalance: decimal.NewFromInt(20)},
+	}
+
+	tests := []struct {
+		name             string
+		query            string
+		setupMock        func(*mocks.MockStore)
+		expectedStatus   int
+		expectedPayments []models.SettlementPlanPaymentResponse
+	}{
+		{
+			name: "success",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UserNetworkBalances", mock.Anything, mock.MatchedBy(func(id *int64) bool {
+					return *id == 1
+				})).Return(networkBalances, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedPayments: []models.SettlementPlanPaymentResponse{
+				{FromUserID: 1, FromUserName: "Me", ToUserID: 3, ToUserName: "Bob", Amount: decimal.NewFromInt(20)},
+			},
+		},
+		{
+			name:  "optimal",
+			query: "?simplify=optimal",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UserNetworkBalances", mock.Anything, mock.Anything).Return(networkBalances, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedPayments: []models.SettlementPlanPaymentResponse{
+				{FromUserID: 1, FromUserName: "Me", ToUserID: 3, ToUserName: "Bob", Amount: decimal.NewFromInt(20)},
+			},
+		},
+		{
+			name: "settled up",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UserNetworkBalances", mock.Anything, mock.Anything).Return([]db.UserNetworkBalancesRow{}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedPayments: []models.SettlementPlanPaymentResponse{},
+		},
+		{
+			name:           "invalid simplify method",
+			query:          "?simplify=fastest",
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "database error",
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("UserNetworkBalances", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			req := createRequestWithUserID("GET", "/users/me/settlement-plan"+tt.query, nil, 1)
+			rr := httptest.NewRecorder()
+
+			handler := getUserSettlementPlan(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.SettlementPlanResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int32(len(tt.expectedPayments)), response.Count)
+				require.Len(t, response.Payments, len(tt.expectedPayments))
+				for i, payment := range response.Payments {
+					expected := tt.expectedPayments[i]
+					assert.Equal(t, expected.FromUserID, payment.FromUserID)
+					assert.Equal(t, expected.FromUserName, payment.FromUserName)
+					assert.Equal(t, expected.ToUserID, payment.ToUserID)
+					assert.Equal(t, expected.ToUserName, payment.ToUserName)
+					assert.True(t, expected.Amount.Equal(payment.Amount), "payment[%d]: expected %s, got %s", i, expected.Amount, payment.Amount)
+				}
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateCrossGroupPayment(t *testing.T) {
+	// User 1 owes user 2 30 in group 10 and user 2 owes user 1 10 in group 20
+	sharedBalances := []db.SharedGroupBalancesNetRow{
+		{GroupID: 10, Currency: "USD", MemberID: 101, UserID: intPtr(1), Role: "member", NetBalance: decimal.NewFromInt(-30)},
+		{GroupID: 10, Currency: "USD", MemberID: 102, UserID: intPtr(2), Role: "member", NetBalance: decimal.NewFromInt(30)},
+		{GroupID: 20, Currency: "USD", MemberID: 201, UserID: intPtr(1), Role: "member", NetBalance: decimal.NewFromInt(10)},
+		{GroupID: 20, Currency: "USD", MemberID: 202, UserID: intPtr(2), Role: "member", NetBalance: decimal.NewFromInt(-10)},
+	}
+	eurBalances := []db.SharedGroupBalancesNetRow{
+		{GroupID: 30, Currency: "EUR", MemberID: 301, UserID: intPtr(1), Role: "member", NetBalance: decimal.NewFromInt(-50)},
+		{GroupID: 30, Currency: "EUR", MemberID: 302, UserID: intPtr(2), Role: "member", NetBalance: decimal.NewFromInt(50)},
+	}
+	settlementsMatch := func(status string) func(db.CreateCrossGroupSettlementsTxParams) bool {
+		return func(arg db.CreateCrossGroupSettlementsTxParams) bool {
+			if len(arg.Settlements) != 2 {
+				return false
+			}
+			forward, reverse := arg.Settlements[0], arg.Settlements[1]
+			return forward.GroupID == 10 && forward.FromMember == 101 && forward.ToMember == 102 && forward.Amount.Equal(decimal.NewFromInt(30)) &&
+				reverse.GroupID == 20 && reverse.FromMember == 202 && reverse.ToMember == 201 && reverse.Amount.Equal(decimal.NewFromInt(10)) &&
+				forward.Status == status && reverse.Status == status &&
+				forward.CreatedBy != nil && !forward.SettledOn.IsZero()
+		}
+	}
+	created := db.CreateCrossGroupSettlementsTxResult{
+		Settlements: []db.Settlement{
+			{ID: 1, GroupID: 10, FromMember: 101, ToMember: 102, Amount: decimal.NewFromInt(30)},
+			{ID: 2, GroupID: 20, FromMember: 202, ToMember: 201, Amount: decimal.NewFromInt(10)},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		userID         int64
+		body           interface{}
+		setupMock      func(*mocks.MockStore)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:   "payer records pending settlements",
+			userID: 1,
+			body:   models.CreateCrossGroupPaymentRequest{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromInt(20)},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("SharedGroupBalancesNet", mock.Anything, db.SharedGroupBalancesNetParams{UserID: 1, OtherUserID: 2}).Return(sharedBalances, nil)
+				ms.On("CreateCrossGroupSettlementsTx", mock.Anything, mock.MatchedBy(settlementsMatch("pending"))).Return(created, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedCount:  2,
+		},
+		{
+			name:   "payee records confirmed settlements",
+			userID: 2,
+			body:   models.CreateCrossGroupPaymentRequest{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromInt(20)},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("SharedGroupBalancesNet", mock.Anything, db.SharedGroupBalancesNetParams{UserID: 1, OtherUserID: 2}).Return(sharedBalances, nil)
+				ms.On("CreateCrossGroupSettlementsTx", mock.Anything, mock.MatchedBy(settlementsMatch("confirmed"))).Return(created, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedCount:  2,
+		},
+		{
+			name:   "currency selects groups",
+			userID: 1,
+			body:   models.CreateCrossGroupPaymentRequest{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromInt(50), Currency: "eur"},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("SharedGroupBalancesNet", mock.Anything, mock.Anything).Return(append(sharedBalances, eurBalances...), nil)
+				ms.On("CreateCrossGroupSettlementsTx", mock.Anything, mock.MatchedBy(func(arg db.CreateCrossGroupSettlementsTxParams) bool {
+					return len(arg.Settlements) == 1 && arg.Settlements[0].GroupID == 30 && arg.Settlements[0].Amount.Equal(decimal.NewFromInt(50))
+				})).Return(db.CreateCrossGroupSettlementsTxResult{
+					Settlements: []db.Settlement{{ID: 3, GroupID: 30, FromMember: 301, ToMember: 302, Amount: decimal.NewFromInt(50)}},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedCount:  1,
+		},
+		{
+			name:   "currency required for mixed currency groups",
+			userID: 1,
+			body:   models.CreateCrossGroupPaymentRequest{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromInt(20)},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("SharedGroupBalancesNet", mock.Anything, mock.Anything).Return(append(sharedBalances, eurBalances...), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "groups where the user is a viewer are left out",
+			userID: 1,
+			body:   models.CreateCrossGroupPaymentRequest{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromInt(20)},
+			setupMock: func(ms *mocks.MockStore) {
+				viewerBalances := append([]db.SharedGroupBalancesNetRow{}, sharedBalances...)
+				viewerBalances[2].Role = "viewer"
+				ms.On("SharedGroupBalancesNet", mock.Anything, mock.Anything).Return(viewerBalances, nil)
+				ms.On("CreateCrossGroupSettlementsTx", mock.Anything, mock.MatchedBy(func(arg db.CreateCrossGroupSettlementsTxParams) bool {
+					return len(arg.Settlements) == 1 && arg.Settlements[0].GroupID == 10 && arg.Settlements[0].Amount.Equal(decimal.NewFromInt(20))
+				})).Return(db.CreateCrossGroupSettlementsTxResult{
+					Settlements: []db.Settlement{{ID: 1, GroupID: 10, FromMember: 101, ToMember: 102, Amount: decimal.NewFromInt(20)}},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedCount:  1,
+		},
+		{
+			name:   "more than is owed",
+			userID: 1,
+			body:   models.CreateCrossGroupPaymentRequest{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromInt(40)},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("SharedGroupBalancesNet", mock.Anything, mock.Anything).Return(sharedBalances, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "no shared groups",
+			userID: 1,
+			body:   models.CreateCrossGroupPaymentRequest{FromUserID: 1, ToUserID: 3, Amount: decimal.NewFromInt(20)},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("SharedGroupBalancesNet", mock.Anything, mock.Anything).Return([]db.SharedGroupBalancesNetRow{}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "payment between other users",
+			userID:         3,
+			body:           models.CreateCrossGroupPaymentRequest{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromInt(20)},
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "invalid JSON",
+			userID:         1,
+			body:           "invalid",
+			setupMock:      func(ms *mocks.MockStore) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "settlement transaction fails",
+			userID: 1,
+			body:   models.CreateCrossGroupPaymentRequest{FromUserID: 1, ToUserID: 2, Amount: decimal.NewFromInt(20)},
+			setupMock: func(ms *mocks.MockStore) {
+				ms.On("SharedGroupBalancesNet", mock.Anything, mock.Anything).Return(sharedBalances, nil)
+				ms.On("CreateCrossGroupSettlementsTx", mock.Anything, mock.Anything).Return(db.CreateCrossGroupSettlementsTxResult{}, errors.New("failed to get group 10"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockStore(t)
+			tt.setupMock(mockStore)
+
+			var body []byte
+			if str, ok := tt.body.(string); ok {
+				body = []byte(str)
+			} else {
+				body, _ = json.Marshal(tt.body)
+			}
+
+			req := createRequestWithUserID("POST", "/users/me/settlements", body, tt.userID)
+			rr := httptest.NewRecorder()
+
+			handler := createCrossGroupPayment(mockStore)
+			handler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var response models.CrossGroupPaymentResponse
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, int32(tt.expectedCount), response.Count)
+				assert.Len(t, response.Settlements, tt.expectedCount)
+			}
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
@@ -2,33 +2,187 @@ package handlers
 
 import (
 	"fmt"
+	"net/mail"
+	"strings"
+	"time"
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/MattSharp0/transaction-split-go/internal/auth"
 	"github.com/MattSharp0/transaction-split-go/internal/logger"
 	"github.com/MattSharp0/transaction-split-go/internal/models"
 	"github.com/shopspring/decimal"
 )
 
-// ValidateSplitMembersInGroup ensures all split_user IDs reference group members in the transaction's group.
-func ValidateSplitMembersInGroup(splits []models.CreateSplitRequest, groupMembers []db.ListGroupMembersByGroupIDRow, groupID int64) error {
+// ValidateSplitMembersInGroup ensures all split_user IDs reference group members in the transaction's group
+// who were active on the transaction date.
+func ValidateSplitMembersInGroup(splits []models.CreateSplitRequest, groupMembers []db.ListGroupMembersByGroupIDRow, groupID int64, transactionDate time.Time) error {
 
-	validMemberIDs := make(map[int64]bool)
+	membersByID := make(map[int64]db.ListGroupMembersByGroupIDRow)
 	for _, member := range groupMembers {
-		validMemberIDs[member.ID] = true
+		membersByID[member.ID] = member
 	}
 
 	for i, split := range splits {
 		if split.SplitUser != nil {
-			if !validMemberIDs[*split.SplitUser] {
+			member, ok := membersByID[*split.SplitUser]
+			if !ok {
 				logger.Warn("Split is not a member of this group", "split_user", *split.SplitUser, "group_id", groupID)
 				return fmt.Errorf("split[%d]: split_user %d is not a member of this group", i, *split.SplitUser)
 			}
+			if !MemberActiveOn(member, transactionDate) {
+				logger.Warn("Split is not an active member on transaction date", "split_user", *split.SplitUser, "group_id", groupID, "transaction_date", transactionDate)
+				return fmt.Errorf("split[%d]: split_user %d was not an active member of this group on %s", i, *split.SplitUser, transactionDate.Format(time.DateOnly))
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateItemMembersInGroup ensures all item member IDs reference group members in the transaction's group
+// who were active on the transaction date, as items are split between their members.
+func ValidateItemMembersInGroup(items []models.TransactionItemRequest, groupMembers []db.ListGroupMembersByGroupIDRow, groupID int64, transactionDate time.Time) error {
+
+	membersByID := make(map[int64]db.ListGroupMembersByGroupIDRow)
+	for _, member := range groupMembers {
+		membersByID[member.ID] = member
+	}
+
+	for i, item := range items {
+		for _, memberID := range item.Members {
+			member, ok := membersByID[memberID]
+			if !ok {
+				logger.Warn("Item member is not a member of this group", "member_id", memberID, "group_id", groupID)
+				return fmt.Errorf("item[%d]: member %d is not a member of this group", i, memberID)
+			}
+			if !MemberActiveOn(member, transactionDate) {
+				logger.Warn("Item member is not an active member on transaction date", "member_id", memberID, "group_id", groupID, "transaction_date", transactionDate)
+				return fmt.Errorf("item[%d]: member %d was not an active member of this group on %s", i, memberID, transactionDate.Format(time.DateOnly))
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidatePayerMembersInGroup ensures all payer member IDs reference group members in the transaction's group.
+func ValidatePayerMembersInGroup(payers []models.TransactionPayerRequest, groupMembers []db.ListGroupMembersByGroupIDRow, groupID int64) error {
+
+	validMemberIDs := make(map[int64]bool)
+	for _, member := range groupMembers {
+		validMemberIDs[member.ID] = true
+	}
+
+	for i, payer := range payers {
+		if !validMemberIDs[payer.MemberID] {
+			logger.Warn("Payer is not a member of this group", "member_id", payer.MemberID, "group_id", groupID)
+			return fmt.Errorf("payer[%d]: member %d is not a member of this group", i, payer.MemberID)
+		}
+	}
+
+	return nil
+}
+
+// ValidateSettlementMembersInGroup ensures the paying and paid member IDs are different members of the settlement's group.
+func ValidateSettlementMembersInGroup(fromMember, toMember int64, groupMembers []db.ListGroupMembersByGroupIDRow, groupID int64) error {
+	if fromMember == toMember {
+		return fmt.Errorf("from_member and to_member must be different members")
+	}
+
+	validMemberIDs := make(map[int64]bool)
+	for _, member := range groupMembers {
+		validMemberIDs[member.ID] = true
+	}
+
+	for _, memberID := range []int64{fromMember, toMember} {
+		if !validMemberIDs[memberID] {
+			logger.Warn("Settlement member is not a member of this group", "member_id", memberID, "group_id", groupID)
+			return fmt.Errorf("member %d is not a member of this group", memberID)
 		}
 	}
 
 	return nil
 }
 
+// ValidateMemberDates ensures a member does not leave a group before joining it.
+func ValidateMemberDates(joinedOn, leftOn *time.Time) error {
+	if joinedOn != nil && leftOn != nil && dateOnly(*leftOn).Before(dateOnly(*joinedOn)) {
+		return fmt.Errorf("left_on %s must not be before joined_on %s", leftOn.Format(time.DateOnly), joinedOn.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// ValidateNewMemberRole validates the role of a member being added or changed, defaulting to member when empty
+// The owner role can't be given to a member directly, ownership is transferred instead
+func ValidateNewMemberRole(role string) (auth.GroupRole, error) {
+	if role == "" {
+		return auth.GroupRoleMember, nil
+	}
+	groupRole, err := auth.ParseGroupRole(role)
+	if err != nil {
+		return "", err
+	}
+	if groupRole == auth.GroupRoleOwner {
+		return "", fmt.Errorf("role can't be owner, transfer ownership instead")
+	}
+	return groupRole, nil
+}
+
+// ValidateNewMemberName checks a new member has a user or a name and returns the name without surrounding spaces
+// Members with only a name are placeholders for people without an account, a user can claim them later
+func ValidateNewMemberName(userID *int64, memberName *string) (*string, error) {
+	if memberName != nil {
+		name := strings.TrimSpace(*memberName)
+		if name == "" {
+			return nil, fmt.Errorf("member_name must not be empty")
+		}
+		memberName = &name
+	}
+	if userID == nil && memberName == nil {
+		return nil, fmt.Errorf("user_id or member_name is required")
+	}
+	return memberName, nil
+}
+
+// ValidateInvitationEmail validates the email an invitation is sent to and returns it without surrounding spaces
+func ValidateInvitationEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", fmt.Errorf("email %q is not a valid email address", email)
+	}
+	return email, nil
+}
+
+// MemberActiveOn reports whether a group member was part of the group on date, from joined_on through left_on inclusive.
+// Members without dates are always active.
+func MemberActiveOn(member db.ListGroupMembersByGroupIDRow, date time.Time) bool {
+	day := dateOnly(date)
+	if member.JoinedOn.Valid && day.Before(dateOnly(member.JoinedOn.Time)) {
+		return false
+	}
+	if member.LeftOn.Valid && day.After(dateOnly(member.LeftOn.Time)) {
+		return false
+	}
+	return true
+}
+
+// ActiveGroupMembers returns the group members who were active on date, in their original order.
+func ActiveGroupMembers(groupMembers []db.ListGroupMembersByGroupIDRow, date time.Time) []db.ListGroupMembersByGroupIDRow {
+	active := []db.ListGroupMembersByGroupIDRow{}
+	for _, member := range groupMembers {
+		if MemberActiveOn(member, date) {
+			active = append(active, member)
+		}
+	}
+	return active
+}
+
+// dateOnly drops the time of day so dates compare by calendar day
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 // ValidateSplitsTotals ensures splits add up to exactly 100% and match transaction amount
 func ValidateSplitsTotals(splits []models.CreateSplitRequest, transactionAmount decimal.Decimal) error {
 
@@ -63,10 +217,9 @@ func ValidateSplitsTotals(splits []models.CreateSplitRequest, transactionAmount
 		return fmt.Errorf("split percentages must sum to 1.0 (100%%), got %s", totalPercent.String())
 	}
 
-	// Check amounts sum to transaction amount (within 1 cent tolerance)
-	tolerance := decimal.NewFromFloat(0.01)
-	diff := totalAmount.Sub(transactionAmount).Abs()
-	if diff.GreaterThan(tolerance) {
+	// Check amounts sum to exactly the transaction amount, amounts sent by the client are never rewritten
+	if !totalAmount.Equal(transactionAmount) {
+		diff := totalAmount.Sub(transactionAmount).Abs()
 		logger.Debug("Split percentages do not equal Tx amount", "total_amount", totalAmount, "tx_amount", transactionAmount, "total_splits", len(splits))
 		return fmt.Errorf("split amounts must sum to transaction amount %s, got %s (difference: %s)",
 			transactionAmount.String(), totalAmount.String(), diff.String())
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"testing"
+	"time"
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
 	"github.com/MattSharp0/transaction-split-go/internal/models"
@@ -11,16 +12,22 @@ import (
 )
 
 // Helper function to create test group members
+// Members are linked to users with the same IDs, the first member owns the group
 func createTestGroupMembers(ids []int64, groupID int64) []db.ListGroupMembersByGroupIDRow {
 	members := make([]db.ListGroupMembersByGroupIDRow, len(ids))
 	for i, id := range ids {
 		memberName := "Member " + string(rune('A'+int(i)))
 		userID := int64Ptr(id)
+		role := "member"
+		if i == 0 {
+			role = "owner"
+		}
 		members[i] = db.ListGroupMembersByGroupIDRow{
 			ID:         id,
 			GroupID:    groupID,
 			MemberName: &memberName,
 			UserID:     userID,
+			Role:       role,
 		}
 	}
 	return members
@@ -30,6 +37,12 @@ func TestValidateSplitMembersInGroup(t *testing.T) {
 	groupID := int64(1)
 	validMemberIDs := []int64{1, 2, 3}
 	groupMembers := createTestGroupMembers(validMemberIDs, groupID)
+	transactionDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	// Member 2 moved out on 2024-06-14 and member 3 moved in on 2024-06-15
+	datedMembers := createTestGroupMembers(validMemberIDs, groupID)
+	datedMembers[1].LeftOn = DateFromPtr(timePtr(time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC)))
+	datedMembers[2].JoinedOn = DateFromPtr(timePtr(transactionDate))
 
 	tests := []struct {
 		name        string
@@ -120,11 +133,32 @@ func TestValidateSplitMembersInGroup(t *testing.T) {
 			expectError: true,
 			errorMsg:    "split[1]: split_user 999 is not a member of this group",
 		},
+		{
+			name: "member joined on the transaction date",
+			splits: []models.CreateSplitRequest{
+				{SplitUser: int64Ptr(1), SplitPercent: decimal.NewFromFloat(0.5), SplitAmount: decimal.NewFromInt(50)},
+				{SplitUser: int64Ptr(3), SplitPercent: decimal.NewFromFloat(0.5), SplitAmount: decimal.NewFromInt(50)},
+			},
+			members:     datedMembers,
+			groupID:     groupID,
+			expectError: false,
+		},
+		{
+			name: "member left before the transaction date",
+			splits: []models.CreateSplitRequest{
+				{SplitUser: int64Ptr(1), SplitPercent: decimal.NewFromFloat(0.5), SplitAmount: decimal.NewFromInt(50)},
+				{SplitUser: int64Ptr(2), SplitPercent: decimal.NewFromFloat(0.5), SplitAmount: decimal.NewFromInt(50)},
+			},
+			members:     datedMembers,
+			groupID:     groupID,
+			expectError: true,
+			errorMsg:    "split[1]: split_user 2 was not an active member of this group on 2024-06-15",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateSplitMembersInGroup(tt.splits, tt.members, tt.groupID)
+			err := ValidateSplitMembersInGroup(tt.splits, tt.members, tt.groupID, transactionDate)
 
 			if tt.expectError {
 				require.Error(t, err)
@@ -138,6 +172,141 @@ func TestValidateSplitMembersInGroup(t *testing.T) {
 	}
 }
 
+func TestMemberActiveOn(t *testing.T) {
+	joined := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	left := time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC)
+	member := db.ListGroupMembersByGroupIDRow{ID: 1, JoinedOn: DateFromPtr(&joined), LeftOn: DateFromPtr(&left)}
+
+	tests := []struct {
+		name     string
+		member   db.ListGroupMembersByGroupIDRow
+		date     time.Time
+		expected bool
+	}{
+		{name: "no dates", member: db.ListGroupMembersByGroupIDRow{ID: 2}, date: joined, expected: true},
+		{name: "before joining", member: member, date: time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC), expected: false},
+		{name: "on joined date", member: member, date: joined, expected: true},
+		{name: "on left date", member: member, date: time.Date(2024, 8, 31, 18, 30, 0, 0, time.UTC), expected: true},
+		{name: "after leaving", member: member, date: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MemberActiveOn(tt.member, tt.date))
+		})
+	}
+
+	active := ActiveGroupMembers([]db.ListGroupMembersByGroupIDRow{member, {ID: 2}}, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, active, 1)
+	assert.Equal(t, int64(2), active[0].ID)
+}
+
+func TestValidateMemberDates(t *testing.T) {
+	joined := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, ValidateMemberDates(nil, nil))
+	assert.NoError(t, ValidateMemberDates(&joined, nil))
+	assert.NoError(t, ValidateMemberDates(&joined, &joined))
+	assert.Error(t, ValidateMemberDates(&joined, timePtr(joined.AddDate(0, 0, -1))))
+}
+
+func TestValidatePayerMembersInGroup(t *testing.T) {
+	groupID := int64(1)
+	groupMembers := createTestGroupMembers([]int64{1, 2, 3}, groupID)
+
+	tests := []struct {
+		name        string
+		payers      []models.TransactionPayerRequest
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "all payers are group members",
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 1, Amount: decimal.NewFromInt(60)},
+				{MemberID: 3, Amount: decimal.NewFromInt(40)},
+			},
+			expectError: false,
+		},
+		{
+			name: "payer not in group members",
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 1, Amount: decimal.NewFromInt(60)},
+				{MemberID: 999, Amount: decimal.NewFromInt(40)},
+			},
+			expectError: true,
+			errorMsg:    "payer[1]: member 999 is not a member of this group",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePayerMembersInGroup(tt.payers, groupMembers, groupID)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateSettlementMembersInGroup(t *testing.T) {
+	groupID := int64(1)
+	groupMembers := createTestGroupMembers([]int64{1, 2, 3}, groupID)
+
+	tests := []struct {
+		name        string
+		fromMember  int64
+		toMember    int64
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:        "both members in group",
+			fromMember:  2,
+			toMember:    1,
+			expectError: false,
+		},
+		{
+			name:        "paying member not in group",
+			fromMember:  999,
+			toMember:    1,
+			expectError: true,
+			errorMsg:    "member 999 is not a member of this group",
+		},
+		{
+			name:        "paid member not in group",
+			fromMember:  1,
+			toMember:    999,
+			expectError: true,
+			errorMsg:    "member 999 is not a member of this group",
+		},
+		{
+			name:        "same member",
+			fromMember:  2,
+			toMember:    2,
+			expectError: true,
+			errorMsg:    "from_member and to_member must be different members",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSettlementMembersInGroup(tt.fromMember, tt.toMember, groupMembers, groupID)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidateSplitsTotals(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -184,14 +353,36 @@ func TestValidateSplitsTotals(t *testing.T) {
 			expectError:       false,
 		},
 		{
-			name: "valid splits - with cents tolerance",
+			name: "valid splits - uneven cents",
+			splits: []models.CreateSplitRequest{
+				{SplitPercent: decimal.NewFromFloat(0.333333), SplitAmount: decimal.NewFromFloat(33.33)},
+				{SplitPercent: decimal.NewFromFloat(0.333333), SplitAmount: decimal.NewFromFloat(33.33)},
+				{SplitPercent: decimal.NewFromFloat(0.333334), SplitAmount: decimal.NewFromFloat(33.34)},
+			},
+			transactionAmount: decimal.NewFromFloat(100.00),
+			expectError:       false,
+		},
+		{
+			name: "amounts one cent over are rejected",
 			splits: []models.CreateSplitRequest{
 				{SplitPercent: decimal.NewFromFloat(0.333333), SplitAmount: decimal.NewFromFloat(33.33)},
 				{SplitPercent: decimal.NewFromFloat(0.333333), SplitAmount: decimal.NewFromFloat(33.33)},
 				{SplitPercent: decimal.NewFromFloat(0.333334), SplitAmount: decimal.NewFromFloat(33.35)}, // 0.01 over
 			},
 			transactionAmount: decimal.NewFromFloat(100.00),
-			expectError:       false, // Within 1 cent tolerance
+			expectError:       true,
+			errorMsg:          "split amounts must sum to transaction amount",
+		},
+		{
+			name: "amounts one cent short are rejected",
+			splits: []models.CreateSplitRequest{
+				{SplitPercent: decimal.NewFromFloat(0.333333), SplitAmount: decimal.NewFromFloat(33.33)},
+				{SplitPercent: decimal.NewFromFloat(0.333333), SplitAmount: decimal.NewFromFloat(33.33)},
+				{SplitPercent: decimal.NewFromFloat(0.333334), SplitAmount: decimal.NewFromFloat(33.33)}, // 0.01 short
+			},
+			transactionAmount: decimal.NewFromFloat(100.00),
+			expectError:       true,
+			errorMsg:          "split amounts must sum to transaction amount",
 		},
 		{
 			name:              "empty splits array",
@@ -231,7 +422,7 @@ func TestValidateSplitsTotals(t *testing.T) {
 			errorMsg:          "split amounts must sum to transaction amount",
 		},
 		{
-			name: "amounts exceed transaction amount beyond tolerance",
+			name: "amounts exceed transaction amount",
 			splits: []models.CreateSplitRequest{
 				{SplitPercent: decimal.NewFromFloat(0.5), SplitAmount: decimal.NewFromFloat(50.02)},
 				{SplitPercent: decimal.NewFromFloat(0.5), SplitAmount: decimal.NewFromFloat(50.02)},
@@ -344,3 +535,60 @@ func TestValidateSplitsTotals(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateNewMemberName(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      *int64
+		memberName  *string
+		expected    *string
+		expectError bool
+	}{
+		{name: "user only", userID: int64Ptr(1)},
+		{name: "placeholder", memberName: stringPtr(" Sam "), expected: stringPtr("Sam")},
+		{name: "user with a name", userID: int64Ptr(1), memberName: stringPtr("Sammy"), expected: stringPtr("Sammy")},
+		{name: "neither", expectError: true},
+		{name: "blank name", memberName: stringPtr("   "), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberName, err := ValidateNewMemberName(tt.userID, tt.memberName)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, memberName)
+			}
+		})
+	}
+}
+
+func TestValidateInvitationEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		email       string
+		expected    string
+		expectError bool
+	}{
+		{name: "valid", email: "jane@example.com", expected: "jane@example.com"},
+		{name: "surrounding spaces", email: "  jane@example.com ", expected: "jane@example.com"},
+		{name: "missing domain", email: "jane", expectError: true},
+		{name: "display name", email: "Jane <jane@example.com>", expectError: true},
+		{name: "empty", email: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email, err := ValidateInvitationEmail(tt.email)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, email)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+)
+
+// FileSender writes each message to a .eml file in Dir instead of delivering it
+type FileSender struct {
+	Dir  string
+	From string
+}
+
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	// Name files by time & recipient so they sort in the order they were sent
+	recipient := strings.NewReplacer("/", "_", "\\", "_", "@", "_at_").Replace(msg.To)
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), recipient)
+	path := filepath.Join(s.Dir, name)
+
+	if err := os.WriteFile(path, format(s.From, msg), 0o600); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+
+	logger.Debug("Email written to file", "to", msg.To, "path", path)
+	return nil
+}
+
+// LogSender writes each message to the log instead of delivering it
+type LogSender struct {
+	From string
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	logger.Info("Email", "from", s.From, "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
@@ -0,0 +1,77 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Mail transports set with MAIL_TRANSPORT
+const (
+	TransportSMTP = "smtp" // Deliver through an SMTP server
+	TransportFile = "file" // Write each message to a file in MAIL_DIR, for local development
+	TransportLog  = "log"  // Write each message to the log, the default
+)
+
+// defaultFrom is the sender address used when MAIL_FROM is not set
+const defaultFrom = "no-reply@localhost"
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email, implementations are selected with MAIL_TRANSPORT
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSenderFromEnv creates the Sender for the transport set in MAIL_TRANSPORT
+func NewSenderFromEnv() (Sender, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = defaultFrom
+	}
+
+	switch transport := os.Getenv("MAIL_TRANSPORT"); transport {
+	case "", TransportLog:
+		return &LogSender{From: from}, nil
+	case TransportFile:
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			return nil, fmt.Errorf("MAIL_DIR is required for the %s mail transport", TransportFile)
+		}
+		return &FileSender{Dir: dir, From: from}, nil
+	case TransportSMTP:
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required for the %s mail transport", TransportSMTP)
+		}
+		port := 587
+		if value := os.Getenv("SMTP_PORT"); value != "" {
+			var err error
+			port, err = strconv.Atoi(value)
+			if err != nil || port < 1 {
+				return nil, fmt.Errorf("SMTP_PORT must be a port number, got %q", value)
+			}
+		}
+		return &SMTPSender{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_TRANSPORT %q, expected %s, %s or %s", transport, TransportSMTP, TransportFile, TransportLog)
+	}
+}
+
+// format renders a message with its headers
+func format(from string, msg Message) []byte {
+	return []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		from, msg.To, msg.Subject, msg.Body))
+}
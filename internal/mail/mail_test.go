@@ -0,0 +1,86 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSenderFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expected    Sender
+		expectError bool
+	}{
+		{
+			name:     "defaults to log",
+			env:      map[string]string{},
+			expected: &LogSender{From: defaultFrom},
+		},
+		{
+			name:     "file",
+			env:      map[string]string{"MAIL_TRANSPORT": "file", "MAIL_DIR": "/tmp/mail", "MAIL_FROM": "split@example.com"},
+			expected: &FileSender{Dir: "/tmp/mail", From: "split@example.com"},
+		},
+		{
+			name:        "file without directory",
+			env:         map[string]string{"MAIL_TRANSPORT": "file"},
+			expectError: true,
+		},
+		{
+			name:     "smtp",
+			env:      map[string]string{"MAIL_TRANSPORT": "smtp", "SMTP_HOST": "smtp.example.com", "SMTP_PORT": "2525", "SMTP_USERNAME": "user", "SMTP_PASSWORD": "secret"},
+			expected: &SMTPSender{Host: "smtp.example.com", Port: 2525, Username: "user", Password: "secret", From: defaultFrom},
+		},
+		{
+			name:        "smtp with invalid port",
+			env:         map[string]string{"MAIL_TRANSPORT": "smtp", "SMTP_HOST": "smtp.example.com", "SMTP_PORT": "abc"},
+			expectError: true,
+		},
+		{
+			name:        "unknown transport",
+			env:         map[string]string{"MAIL_TRANSPORT": "pigeon"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"MAIL_TRANSPORT", "MAIL_DIR", "MAIL_FROM", "SMTP_HOST", "SMTP_PORT", "SMTP_USERNAME", "SMTP_PASSWORD"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			sender, err := NewSenderFromEnv()
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sender)
+		})
+	}
+}
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sender := &FileSender{Dir: dir, From: "split@example.com"}
+
+	err := sender.Send(context.Background(), Message{To: "alice@example.com", Subject: "Hello", Body: "Hi Alice"})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Contains(t, files[0].Name(), "alice_at_example.com")
+
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "To: alice@example.com\r\n")
+	assert.Contains(t, string(content), "Subject: Hello\r\n")
+	assert.Contains(t, string(content), "\r\n\r\nHi Alice")
+}
@@ -3,8 +3,10 @@ package mocks
 import (
 	"context"
 	"testing"
+	"time"
 
 	db "github.com/MattSharp0/transaction-split-go/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -23,8 +25,13 @@ var _ db.Store = (*MockStore)(nil)
 
 // Querier interface methods
 
-func (m *MockStore) CreateGroup(ctx context.Context, name string) (db.Group, error) {
-	args := m.Called(ctx, name)
+func (m *MockStore) ClaimGroupMember(ctx context.Context, arg db.ClaimGroupMemberParams) (db.GroupMember, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.GroupMember), args.Error(1)
+}
+
+func (m *MockStore) CreateGroup(ctx context.Context, arg db.CreateGroupParams) (db.Group, error) {
+	args := m.Called(ctx, arg)
 	return args.Get(0).(db.Group), args.Error(1)
 }
 
@@ -38,11 +45,46 @@ func (m *MockStore) CreateSplit(ctx context.Context, arg db.CreateSplitParams) (
 	return args.Get(0).(db.Split), args.Error(1)
 }
 
+func (m *MockStore) CountTransactionRevisions(ctx context.Context, transactionID int64) (int64, error) {
+	args := m.Called(ctx, transactionID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockStore) CreateTransaction(ctx context.Context, arg db.CreateTransactionParams) (db.Transaction, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(db.Transaction), args.Error(1)
 }
 
+func (m *MockStore) CreateTransactionAdjustment(ctx context.Context, arg db.CreateTransactionAdjustmentParams) (db.TransactionAdjustment, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.TransactionAdjustment), args.Error(1)
+}
+
+func (m *MockStore) CreateTransactionItem(ctx context.Context, arg db.CreateTransactionItemParams) (db.TransactionItem, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.TransactionItem), args.Error(1)
+}
+
+func (m *MockStore) CreateTransactionItemMember(ctx context.Context, arg db.CreateTransactionItemMemberParams) (db.TransactionItemMember, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.TransactionItemMember), args.Error(1)
+}
+
+func (m *MockStore) CreateTransactionPayer(ctx context.Context, arg db.CreateTransactionPayerParams) (db.TransactionPayer, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.TransactionPayer), args.Error(1)
+}
+
+func (m *MockStore) CreateTransactionRevision(ctx context.Context, arg db.CreateTransactionRevisionParams) (db.TransactionRevision, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.TransactionRevision), args.Error(1)
+}
+
+func (m *MockStore) CreateSettlement(ctx context.Context, arg db.CreateSettlementParams) (db.Settlement, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Settlement), args.Error(1)
+}
+
 func (m *MockStore) CreateUser(ctx context.Context, name string) (db.User, error) {
 	args := m.Called(ctx, name)
 	return args.Get(0).(db.User), args.Error(1)
@@ -58,16 +100,150 @@ func (m *MockStore) CreateRefreshToken(ctx context.Context, arg db.CreateRefresh
 	return args.Get(0).(db.RefreshToken), args.Error(1)
 }
 
+func (m *MockStore) CreateRotatedRefreshToken(ctx context.Context, arg db.CreateRotatedRefreshTokenParams) (db.RefreshToken, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.RefreshToken), args.Error(1)
+}
+
+func (m *MockStore) DeleteMergedMemberSplits(ctx context.Context, arg db.DeleteMergedMemberSplitsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteMergedMemberTransactionItemMembers(ctx context.Context, arg db.DeleteMergedMemberTransactionItemMembersParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteMergedMemberTransactionPayers(ctx context.Context, arg db.DeleteMergedMemberTransactionPayersParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) DeleteSettlementsBetweenMembers(ctx context.Context, arg db.DeleteSettlementsBetweenMembersParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetDeletedGroupByIDForOwner(ctx context.Context, arg db.GetDeletedGroupByIDForOwnerParams) (db.Group, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Group), args.Error(1)
+}
+
+func (m *MockStore) GetDeletedTransactionByID(ctx context.Context, id int64) (db.Transaction, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Transaction), args.Error(1)
+}
+
 func (m *MockStore) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (db.RefreshToken, error) {
 	args := m.Called(ctx, tokenHash)
 	return args.Get(0).(db.RefreshToken), args.Error(1)
 }
 
+func (m *MockStore) GetRefreshTokenByHashForUpdate(ctx context.Context, tokenHash string) (db.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(db.RefreshToken), args.Error(1)
+}
+
+func (m *MockStore) ListAuditEventsByGroupID(ctx context.Context, arg db.ListAuditEventsByGroupIDParams) ([]db.ListAuditEventsByGroupIDRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.ListAuditEventsByGroupIDRow), args.Error(1)
+}
+
+func (m *MockStore) ListDeletedGroupsByOwner(ctx context.Context, arg db.ListDeletedGroupsByOwnerParams) ([]db.Group, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Group), args.Error(1)
+}
+
+func (m *MockStore) ListDeletedTransactionsByGroupID(ctx context.Context, arg db.ListDeletedTransactionsByGroupIDParams) ([]db.Transaction, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Transaction), args.Error(1)
+}
+
+func (m *MockStore) MergeMemberSplits(ctx context.Context, arg db.MergeMemberSplitsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) MergeMemberTransactionPayers(ctx context.Context, arg db.MergeMemberTransactionPayersParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) PurgeDeletedGroups(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) PurgeDeletedTransactions(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) ReassignMemberSettlements(ctx context.Context, arg db.ReassignMemberSettlementsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) ReassignMemberSplits(ctx context.Context, arg db.ReassignMemberSplitsParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) ReassignMemberTransactionItemMembers(ctx context.Context, arg db.ReassignMemberTransactionItemMembersParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) ReassignMemberTransactionPayers(ctx context.Context, arg db.ReassignMemberTransactionPayersParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) ReassignTransactionsByUser(ctx context.Context, arg db.ReassignTransactionsByUserParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) RestoreGroup(ctx context.Context, id int64) (db.Group, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Group), args.Error(1)
+}
+
+func (m *MockStore) RestoreTransaction(ctx context.Context, id int64) (db.Transaction, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Transaction), args.Error(1)
+}
+
+func (m *MockStore) SetAuditActor(ctx context.Context, actorUserID int64) error {
+	args := m.Called(ctx, actorUserID)
+	return args.Error(0)
+}
+
 func (m *MockStore) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
 	args := m.Called(ctx, tokenHash)
 	return args.Error(0)
 }
 
+func (m *MockStore) RevokeOtherUserRefreshTokens(ctx context.Context, arg db.RevokeOtherUserRefreshTokensParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) RevokeRefreshTokenFamily(ctx context.Context, familyID pgtype.UUID) (int64, error) {
+	args := m.Called(ctx, familyID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockStore) RevokeAllUserTokens(ctx context.Context, userID int64) error {
 	args := m.Called(ctx, userID)
 	return args.Error(0)
@@ -86,6 +262,44 @@ func (m *MockStore) GetUserRefreshTokens(ctx context.Context, userID int64) ([]d
 	return args.Get(0).([]db.RefreshToken), args.Error(1)
 }
 
+func (m *MockStore) CreateGroupInvitation(ctx context.Context, arg db.CreateGroupInvitationParams) (db.GroupInvitation, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.GroupInvitation), args.Error(1)
+}
+
+func (m *MockStore) GetGroupInvitationByID(ctx context.Context, id int64) (db.GroupInvitation, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.GroupInvitation), args.Error(1)
+}
+
+func (m *MockStore) GetGroupInvitationByTokenHash(ctx context.Context, tokenHash string) (db.GroupInvitation, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(db.GroupInvitation), args.Error(1)
+}
+
+func (m *MockStore) GetGroupInvitationByTokenHashForUpdate(ctx context.Context, tokenHash string) (db.GroupInvitation, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(db.GroupInvitation), args.Error(1)
+}
+
+func (m *MockStore) ListGroupInvitationsByGroupID(ctx context.Context, arg db.ListGroupInvitationsByGroupIDParams) ([]db.GroupInvitation, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.GroupInvitation), args.Error(1)
+}
+
+func (m *MockStore) RevokeGroupInvitation(ctx context.Context, id int64) (db.GroupInvitation, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.GroupInvitation), args.Error(1)
+}
+
+func (m *MockStore) UseGroupInvitation(ctx context.Context, id int64) (db.GroupInvitation, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.GroupInvitation), args.Error(1)
+}
+
 func (m *MockStore) DeleteGroup(ctx context.Context, id int64) (db.Group, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(db.Group), args.Error(1)
@@ -96,12 +310,12 @@ func (m *MockStore) DeleteGroupMember(ctx context.Context, id int64) (db.GroupMe
 	return args.Get(0).(db.GroupMember), args.Error(1)
 }
 
-func (m *MockStore) UnlinkGroupMember(ctx context.Context, id int64) (db.GroupMember, error) {
+func (m *MockStore) RemoveGroupMember(ctx context.Context, id int64) (db.GroupMember, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(db.GroupMember), args.Error(1)
 }
 
-func (m *MockStore) DeleteGroupMembersByGroupID(ctx context.Context, groupID int64) ([]db.GroupMember, error) {
+func (m *MockStore) RemoveGroupMembersByGroupID(ctx context.Context, groupID int64) ([]db.GroupMember, error) {
 	args := m.Called(ctx, groupID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -109,6 +323,11 @@ func (m *MockStore) DeleteGroupMembersByGroupID(ctx context.Context, groupID int
 	return args.Get(0).([]db.GroupMember), args.Error(1)
 }
 
+func (m *MockStore) DeleteSettlement(ctx context.Context, id int64) (db.Settlement, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Settlement), args.Error(1)
+}
+
 func (m *MockStore) DeleteSplit(ctx context.Context, id int64) (db.Split, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(db.Split), args.Error(1)
@@ -119,6 +338,30 @@ func (m *MockStore) DeleteTransaction(ctx context.Context, id int64) (db.Transac
 	return args.Get(0).(db.Transaction), args.Error(1)
 }
 
+func (m *MockStore) DeleteTransactionAdjustments(ctx context.Context, transactionID int64) ([]db.TransactionAdjustment, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.TransactionAdjustment), args.Error(1)
+}
+
+func (m *MockStore) DeleteTransactionItems(ctx context.Context, transactionID int64) ([]db.TransactionItem, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.TransactionItem), args.Error(1)
+}
+
+func (m *MockStore) DeleteTransactionPayers(ctx context.Context, transactionID int64) ([]db.TransactionPayer, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.TransactionPayer), args.Error(1)
+}
+
 func (m *MockStore) DeleteTransactionSplits(ctx context.Context, transactionID int64) ([]db.Split, error) {
 	args := m.Called(ctx, transactionID)
 	if args.Get(0) == nil {
@@ -132,6 +375,11 @@ func (m *MockStore) DeleteUser(ctx context.Context, id int64) (db.User, error) {
 	return args.Get(0).(db.User), args.Error(1)
 }
 
+func (m *MockStore) GetExchangeRate(ctx context.Context, arg db.GetExchangeRateParams) (db.ExchangeRate, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ExchangeRate), args.Error(1)
+}
+
 func (m *MockStore) GetGroupByID(ctx context.Context, id int64) (db.Group, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(db.Group), args.Error(1)
@@ -147,6 +395,16 @@ func (m *MockStore) GetGroupMemberByID(ctx context.Context, id int64) (db.GetGro
 	return args.Get(0).(db.GetGroupMemberByIDRow), args.Error(1)
 }
 
+func (m *MockStore) GetSettlementByID(ctx context.Context, id int64) (db.Settlement, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Settlement), args.Error(1)
+}
+
+func (m *MockStore) GetSettlementByIDForUpdate(ctx context.Context, id int64) (db.Settlement, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Settlement), args.Error(1)
+}
+
 func (m *MockStore) GetSplitByID(ctx context.Context, id int64) (db.Split, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(db.Split), args.Error(1)
@@ -199,6 +457,11 @@ func (m *MockStore) GetTransactionByIDForUpdate(ctx context.Context, id int64) (
 	return args.Get(0).(db.Transaction), args.Error(1)
 }
 
+func (m *MockStore) GetTransactionRevision(ctx context.Context, arg db.GetTransactionRevisionParams) (db.TransactionRevision, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.TransactionRevision), args.Error(1)
+}
+
 func (m *MockStore) GetTransactionsByGroupInPeriod(ctx context.Context, arg db.GetTransactionsByGroupInPeriodParams) ([]db.Transaction, error) {
 	args := m.Called(ctx, arg)
 	if args.Get(0) == nil {
@@ -249,6 +512,14 @@ func (m *MockStore) GroupBalancesNet(ctx context.Context, groupID int64) ([]db.G
 	return args.Get(0).([]db.GroupBalancesNetRow), args.Error(1)
 }
 
+func (m *MockStore) SharedGroupBalancesNet(ctx context.Context, arg db.SharedGroupBalancesNetParams) ([]db.SharedGroupBalancesNetRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.SharedGroupBalancesNetRow), args.Error(1)
+}
+
 func (m *MockStore) ListGroupMembersByGroupID(ctx context.Context, arg db.ListGroupMembersByGroupIDParams) ([]db.ListGroupMembersByGroupIDRow, error) {
 	args := m.Called(ctx, arg)
 	if args.Get(0) == nil {
@@ -273,6 +544,14 @@ func (m *MockStore) ListGroupsByUser(ctx context.Context, arg db.ListGroupsByUse
 	return args.Get(0).([]db.Group), args.Error(1)
 }
 
+func (m *MockStore) ListSettlementsByGroupID(ctx context.Context, arg db.ListSettlementsByGroupIDParams) ([]db.Settlement, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Settlement), args.Error(1)
+}
+
 func (m *MockStore) ListSplits(ctx context.Context, arg db.ListSplitsParams) ([]db.Split, error) {
 	args := m.Called(ctx, arg)
 	if args.Get(0) == nil {
@@ -297,6 +576,54 @@ func (m *MockStore) ListSplitsForTransaction(ctx context.Context, transactionID
 	return args.Get(0).([]db.Split), args.Error(1)
 }
 
+func (m *MockStore) ListTransactionAdjustmentsByTransactionID(ctx context.Context, transactionID int64) ([]db.TransactionAdjustment, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.TransactionAdjustment), args.Error(1)
+}
+
+func (m *MockStore) ListTransactionItemMembersByTransactionID(ctx context.Context, transactionID int64) ([]db.TransactionItemMember, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.TransactionItemMember), args.Error(1)
+}
+
+func (m *MockStore) ListTransactionItemsByTransactionID(ctx context.Context, transactionID int64) ([]db.TransactionItem, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.TransactionItem), args.Error(1)
+}
+
+func (m *MockStore) ListTransactionPayersByTransactionID(ctx context.Context, transactionID int64) ([]db.TransactionPayer, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.TransactionPayer), args.Error(1)
+}
+
+func (m *MockStore) ListTransactionRevisions(ctx context.Context, arg db.ListTransactionRevisionsParams) ([]db.TransactionRevision, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.TransactionRevision), args.Error(1)
+}
+
+func (m *MockStore) ListInconsistentTransactionsByGroupID(ctx context.Context, arg db.ListInconsistentTransactionsByGroupIDParams) ([]db.ListInconsistentTransactionsByGroupIDRow, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.ListInconsistentTransactionsByGroupIDRow), args.Error(1)
+}
+
 func (m *MockStore) ListTransactions(ctx context.Context, arg db.ListTransactionsParams) ([]db.Transaction, error) {
 	args := m.Called(ctx, arg)
 	if args.Get(0) == nil {
@@ -331,11 +658,31 @@ func (m *MockStore) UpdateGroupMember(ctx context.Context, arg db.UpdateGroupMem
 	return args.Get(0).(db.GroupMember), args.Error(1)
 }
 
+func (m *MockStore) UpdateGroupMemberRole(ctx context.Context, arg db.UpdateGroupMemberRoleParams) (db.GroupMember, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.GroupMember), args.Error(1)
+}
+
+func (m *MockStore) UpdateSettlementStatus(ctx context.Context, arg db.UpdateSettlementStatusParams) (db.Settlement, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Settlement), args.Error(1)
+}
+
 func (m *MockStore) UpdateSplit(ctx context.Context, arg db.UpdateSplitParams) (db.Split, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(db.Split), args.Error(1)
 }
 
+func (m *MockStore) RescaleSplit(ctx context.Context, arg db.RescaleSplitParams) (db.Split, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.Split), args.Error(1)
+}
+
+func (m *MockStore) SetTransactionSplitMode(ctx context.Context, arg db.SetTransactionSplitModeParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
 func (m *MockStore) UpdateTransaction(ctx context.Context, arg db.UpdateTransactionParams) (db.Transaction, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(db.Transaction), args.Error(1)
@@ -346,6 +693,41 @@ func (m *MockStore) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db
 	return args.Get(0).(db.User), args.Error(1)
 }
 
+func (m *MockStore) VerifyUserEmail(ctx context.Context, arg db.VerifyUserEmailParams) (db.User, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.User), args.Error(1)
+}
+
+func (m *MockStore) CreateOneTimeToken(ctx context.Context, arg db.CreateOneTimeTokenParams) (db.OneTimeToken, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.OneTimeToken), args.Error(1)
+}
+
+func (m *MockStore) GetOneTimeTokenByHashForUpdate(ctx context.Context, arg db.GetOneTimeTokenByHashForUpdateParams) (db.OneTimeToken, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.OneTimeToken), args.Error(1)
+}
+
+func (m *MockStore) InvalidateUserOneTimeTokens(ctx context.Context, arg db.InvalidateUserOneTimeTokensParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *MockStore) UpdateUserPassword(ctx context.Context, arg db.UpdateUserPasswordParams) (db.User, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.User), args.Error(1)
+}
+
+func (m *MockStore) UseOneTimeToken(ctx context.Context, id int64) (db.OneTimeToken, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.OneTimeToken), args.Error(1)
+}
+
+func (m *MockStore) UpsertExchangeRate(ctx context.Context, arg db.UpsertExchangeRateParams) (db.ExchangeRate, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ExchangeRate), args.Error(1)
+}
+
 func (m *MockStore) UserBalancesByGroup(ctx context.Context, userID *int64) ([]db.UserBalancesByGroupRow, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
@@ -367,6 +749,14 @@ func (m *MockStore) UserBalancesSummary(ctx context.Context, userID *int64) (db.
 	return args.Get(0).(db.UserBalancesSummaryRow), args.Error(1)
 }
 
+func (m *MockStore) UserNetworkBalances(ctx context.Context, userID *int64) ([]db.UserNetworkBalancesRow, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.UserNetworkBalancesRow), args.Error(1)
+}
+
 // Transaction methods
 
 func (m *MockStore) CreateSplitsTx(ctx context.Context, arg db.CreateSplitsTxParams) (db.CreateSplitsTxResult, error) {
@@ -384,6 +774,26 @@ func (m *MockStore) DeleteTransactionWithSplitsTx(ctx context.Context, transacti
 	return args.Error(0)
 }
 
+func (m *MockStore) CreateTransactionWithPayersTx(ctx context.Context, arg db.CreateTransactionWithPayersTxParams) (db.CreateTransactionWithPayersTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.CreateTransactionWithPayersTxResult), args.Error(1)
+}
+
+func (m *MockStore) UpdateTransactionWithPayersTx(ctx context.Context, arg db.UpdateTransactionWithPayersTxParams) (db.UpdateTransactionWithPayersTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.UpdateTransactionWithPayersTxResult), args.Error(1)
+}
+
+func (m *MockStore) ReplaceTransactionItemsTx(ctx context.Context, arg db.ReplaceTransactionItemsTxParams) (db.ReplaceTransactionItemsTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ReplaceTransactionItemsTxResult), args.Error(1)
+}
+
+func (m *MockStore) DeleteTransactionItemsTx(ctx context.Context, transactionID int64) (db.DeleteTransactionItemsTxResult, error) {
+	args := m.Called(ctx, transactionID)
+	return args.Get(0).(db.DeleteTransactionItemsTxResult), args.Error(1)
+}
+
 func (m *MockStore) CreateGroupMembersTx(ctx context.Context, arg db.CreateGroupMemberTxParams) (db.CreateGroupMemberTxResult, error) {
 	args := m.Called(ctx, arg)
 	return args.Get(0).(db.CreateGroupMemberTxResult), args.Error(1)
@@ -398,3 +808,68 @@ func (m *MockStore) DeleteGroupMembersTx(ctx context.Context, groupID int64) err
 	args := m.Called(ctx, groupID)
 	return args.Error(0)
 }
+
+func (m *MockStore) TransferGroupOwnershipTx(ctx context.Context, arg db.TransferGroupOwnershipTxParams) (db.TransferGroupOwnershipTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.TransferGroupOwnershipTxResult), args.Error(1)
+}
+
+func (m *MockStore) CreateSettlementsTx(ctx context.Context, arg db.CreateSettlementsTxParams) (db.CreateSettlementsTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.CreateSettlementsTxResult), args.Error(1)
+}
+
+func (m *MockStore) CreateCrossGroupSettlementsTx(ctx context.Context, arg db.CreateCrossGroupSettlementsTxParams) (db.CreateCrossGroupSettlementsTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.CreateCrossGroupSettlementsTxResult), args.Error(1)
+}
+
+func (m *MockStore) UpdateSettlementStatusTx(ctx context.Context, arg db.UpdateSettlementStatusTxParams) (db.UpdateSettlementStatusTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.UpdateSettlementStatusTxResult), args.Error(1)
+}
+
+func (m *MockStore) LoadExchangeRatesTx(ctx context.Context, arg db.LoadExchangeRatesTxParams) (db.LoadExchangeRatesTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.LoadExchangeRatesTxResult), args.Error(1)
+}
+
+func (m *MockStore) AcceptGroupInvitationTx(ctx context.Context, arg db.AcceptGroupInvitationTxParams) (db.AcceptGroupInvitationTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.AcceptGroupInvitationTxResult), args.Error(1)
+}
+
+func (m *MockStore) MergeGroupMembersTx(ctx context.Context, arg db.MergeGroupMembersTxParams) (db.MergeGroupMembersTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.MergeGroupMembersTxResult), args.Error(1)
+}
+
+func (m *MockStore) PurgeTrashTx(ctx context.Context, arg db.PurgeTrashTxParams) (db.PurgeTrashTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.PurgeTrashTxResult), args.Error(1)
+}
+
+func (m *MockStore) RevertTransactionRevisionTx(ctx context.Context, arg db.RevertTransactionRevisionTxParams) (db.RevertTransactionRevisionTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.RevertTransactionRevisionTxResult), args.Error(1)
+}
+
+func (m *MockStore) IssueOneTimeTokenTx(ctx context.Context, arg db.CreateOneTimeTokenParams) (db.OneTimeToken, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.OneTimeToken), args.Error(1)
+}
+
+func (m *MockStore) ResetPasswordTx(ctx context.Context, arg db.ResetPasswordTxParams) (db.ResetPasswordTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.ResetPasswordTxResult), args.Error(1)
+}
+
+func (m *MockStore) UseOneTimeTokenTx(ctx context.Context, arg db.UseOneTimeTokenTxParams) (db.OneTimeToken, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.OneTimeToken), args.Error(1)
+}
+
+func (m *MockStore) RotateRefreshTokenTx(ctx context.Context, arg db.RotateRefreshTokenTxParams) (db.RotateRefreshTokenTxResult, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(db.RotateRefreshTokenTxResult), args.Error(1)
+}
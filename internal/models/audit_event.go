@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEventResponse struct {
+	ID          int64           `json:"id"`
+	ActorUserID *int64          `json:"actor_user_id"` // Null for changes made without a user, e.g. purging the trash
+	ActorName   *string         `json:"actor_name"`
+	Entity      string          `json:"entity"` // group, group_member, transaction or split
+	EntityID    int64           `json:"entity_id"`
+	Action      string          `json:"action"` // create, update, delete, trash, restore or remove
+	Before      json.RawMessage `json:"before"` // Row before the change, null on create
+	After       json.RawMessage `json:"after"`  // Row after the change, null on delete
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type ListAuditEventResponse struct {
+	Events []AuditEventResponse `json:"events"`
+	Count  int32                `json:"count"`
+	Limit  int32                `json:"limit"`
+	Offset int32                `json:"offset"`
+}
@@ -31,6 +31,8 @@ type SimplifiedPaymentsResponse struct {
 
 type GroupBalancesResponse struct {
 	GroupID                 int64                        `json:"group_id"`
+	Currency                string                       `json:"currency"` // Group base currency, all amounts are converted to it
+	Simplify                string                       `json:"simplify"` // Solver used for simplified_payments, greedy or optimal
 	Balances                []BalanceResponse            `json:"balances"`
 	NetBalances             []NetBalanceResponse         `json:"net_balances"`
 	SimplifiedPayments      []SimplifiedPaymentsResponse `json:"simplified_payments"`
@@ -49,6 +51,7 @@ type UserBalancesSummaryResponse struct {
 type UserBalanceByGroupResponse struct {
 	GroupID         int64           `json:"group_id"`
 	GroupName       string          `json:"group_name"`
+	Currency        string          `json:"currency"` // Group base currency of the balances
 	NetBalance      decimal.Decimal `json:"net_balance"`
 	TotalOwed       decimal.Decimal `json:"total_owed"`         // negative net_balance amounts
 	TotalOwedToUser decimal.Decimal `json:"total_owed_to_user"` // positive net_balance amounts
@@ -70,3 +73,19 @@ type UserBalancesResponse struct {
 	GroupCount       int32                         `json:"group_count"`
 	MemberCount      int32                         `json:"member_count"`
 }
+
+// Cross-group settlement plan for a user's network, everyone sharing a group with the user
+type SettlementPlanPaymentResponse struct {
+	FromUserID   int64           `json:"from_user_id"`
+	FromUserName string          `json:"from_user_name"`
+	ToUserID     int64           `json:"to_user_id"`
+	ToUserName   string          `json:"to_user_name"`
+	Amount       decimal.Decimal `json:"amount"`
+}
+
+type SettlementPlanResponse struct {
+	UserID   int64                           `json:"user_id"`
+	Simplify string                          `json:"simplify"` // Solver used for payments, greedy or optimal
+	Payments []SettlementPlanPaymentResponse `json:"payments"`
+	Count    int32                           `json:"count"`
+}
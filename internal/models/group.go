@@ -1,8 +1,12 @@
 package models
 
+import "time"
+
 type GroupResponse struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Currency  string     `json:"currency"`             // Base currency for balances
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // Set while the group is in the trash
 }
 
 type ListGroupResponse struct {
@@ -13,7 +17,8 @@ type ListGroupResponse struct {
 }
 
 type CreateGroupRequest struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"` // Optional: defaults to USD, can't be changed later
 }
 
 type UpdateGroupRequest struct {
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+type GroupInvitationResponse struct {
+	ID        int64      `json:"id"`
+	GroupID   int64      `json:"group_id"`
+	Email     *string    `json:"email"` // Null for link invitations anyone can accept
+	Role      string     `json:"role"`  // Role given to the new member: admin, member or viewer
+	SingleUse bool       `json:"single_use"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	UseCount  int32      `json:"use_count"`
+	Status    string     `json:"status"`     // pending, accepted, expired or revoked
+	CreatedBy *int64     `json:"created_by"` // User ID who sent the invitation
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	MemberID  *int64     `json:"member_id"` // Placeholder member the invitation claims, null when it adds a new member
+}
+
+type ListGroupInvitationResponse struct {
+	Invitations []GroupInvitationResponse `json:"invitations"`
+	Count       int32                     `json:"count"`
+	Limit       int32                     `json:"limit"`
+	Offset      int32                     `json:"offset"`
+}
+
+type CreateGroupInvitationRequest struct {
+	Email          *string `json:"email"`            // Optional: only a user with this email can accept, omit for a link invitation
+	Role           string  `json:"role"`             // Optional: admin, member or viewer, defaults to member
+	SingleUse      *bool   `json:"single_use"`       // Optional: defaults to true, email invitations are always single use
+	ExpiresInHours *int    `json:"expires_in_hours"` // Optional: defaults to 168 (7 days), 0 for single use invitations that don't expire
+	MemberID       *int64  `json:"member_id"`        // Optional: placeholder member to claim, its transactions and balances carry over to the user who accepts
+}
+
+// CreateGroupInvitationResponse includes the invite token, it is only returned when the invitation is created
+type CreateGroupInvitationResponse struct {
+	Invitation GroupInvitationResponse `json:"invitation"`
+	Token      string                  `json:"token"`
+}
+
+type AcceptGroupInvitationRequest struct {
+	Token string `json:"token"`
+}
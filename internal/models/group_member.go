@@ -3,13 +3,17 @@ package models
 import "time"
 
 type GroupMemberResponse struct {
-	ID         int64     `json:"id"`
-	GroupID    int64     `json:"group_id"`
-	GroupName  string    `json:"group_name,omitempty"`
-	MemberName *string   `json:"member_name"`
-	UserID     *int64    `json:"user_id"`
-	UserName   string    `json:"user_name,omitempty"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID         int64      `json:"id"`
+	GroupID    int64      `json:"group_id"`
+	GroupName  string     `json:"group_name,omitempty"`
+	MemberName *string    `json:"member_name"`
+	UserID     *int64     `json:"user_id"` // Null for placeholder members without an account
+	UserName   *string    `json:"user_name,omitempty"`
+	JoinedOn   *time.Time `json:"joined_on"`  // Null when a member since the group started
+	LeftOn     *time.Time `json:"left_on"`    // Null while still a member
+	Role       string     `json:"role"`       // owner, admin, member or viewer
+	RemovedAt  *time.Time `json:"removed_at"` // Null while the member is in the group
+	CreatedAt  time.Time  `json:"created_at"`
 }
 
 type ListGroupMemberResponse struct {
@@ -20,13 +24,19 @@ type ListGroupMemberResponse struct {
 }
 
 type CreateGroupMemberRequest struct {
-	GroupID int64  `json:"group_id"`
-	UserID  *int64 `json:"user_id"`
+	GroupID    int64      `json:"group_id"`
+	UserID     *int64     `json:"user_id"`     // Optional if member_name is given
+	MemberName *string    `json:"member_name"` // Optional if user_id is given: name of a placeholder member without an account, defaults to the user's name
+	JoinedOn   *time.Time `json:"joined_on"`   // Optional: first day the member shares transactions
+	LeftOn     *time.Time `json:"left_on"`     // Optional: last day the member shares transactions
+	Role       string     `json:"role"`        // Optional: admin, member or viewer, defaults to member
 }
 
 type UpdateGroupMemberRequest struct {
-	GroupID int64  `json:"group_id"`
-	UserID  *int64 `json:"user_id"`
+	GroupID  int64      `json:"group_id"`
+	UserID   *int64     `json:"user_id"`
+	JoinedOn *time.Time `json:"joined_on"` // Optional: first day the member shares transactions
+	LeftOn   *time.Time `json:"left_on"`   // Optional: last day the member shares transactions
 }
 
 // Batch operation models
@@ -35,7 +45,11 @@ type BatchCreateGroupMemberRequest struct {
 }
 
 type BatchGroupMemberItem struct {
-	UserID *int64 `json:"user_id"`
+	UserID     *int64     `json:"user_id"`     // Optional if member_name is given
+	MemberName *string    `json:"member_name"` // Optional if user_id is given: name of a placeholder member without an account
+	JoinedOn   *time.Time `json:"joined_on"`   // Optional: first day the member shares transactions
+	LeftOn     *time.Time `json:"left_on"`     // Optional: last day the member shares transactions
+	Role       string     `json:"role"`        // Optional: admin, member or viewer, defaults to member
 }
 
 type BatchUpdateGroupMemberRequest struct {
@@ -50,8 +64,10 @@ type BatchCreateGroupMemberResponse struct {
 
 type BatchUpdateGroupMemberResponse struct {
 	Group          GroupResponse         `json:"group"`
+	KeptMembers    []GroupMemberResponse `json:"kept_members"`
 	DeletedMembers []GroupMemberResponse `json:"deleted_members"`
 	NewMembers     []GroupMemberResponse `json:"new_members"`
+	KeptCount      int32                 `json:"kept_count"`
 	DeletedCount   int32                 `json:"deleted_count"`
 	NewCount       int32                 `json:"new_count"`
 }
@@ -60,3 +76,26 @@ type BatchDeleteGroupMemberResponse struct {
 	GroupID int64  `json:"group_id"`
 	Message string `json:"message"`
 }
+
+type UpdateGroupMemberRoleRequest struct {
+	Role string `json:"role"` // admin, member or viewer
+}
+
+type TransferGroupOwnershipRequest struct {
+	MemberID int64 `json:"member_id"` // Group Member ID of the new owner
+}
+
+type TransferGroupOwnershipResponse struct {
+	PreviousOwner GroupMemberResponse `json:"previous_owner"`
+	NewOwner      GroupMemberResponse `json:"new_owner"`
+}
+
+type MergeGroupMembersRequest struct {
+	MemberID int64 `json:"member_id"` // Group Member ID of the duplicate, merged into the member in the path and removed
+}
+
+type MergeGroupMembersResponse struct {
+	Member        GroupMemberResponse   `json:"member"`
+	RemovedMember GroupMemberResponse   `json:"removed_member"`
+	Balances      GroupBalancesResponse `json:"balances"` // Group balances after the merge, simplified with the greedy method
+}
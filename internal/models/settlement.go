@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type SettlementResponse struct {
+	ID         int64           `json:"id"`
+	GroupID    int64           `json:"group_id"`
+	FromMember int64           `json:"from_member"` // Group Member ID who paid
+	ToMember   int64           `json:"to_member"`   // Group Member ID who was paid
+	Amount     decimal.Decimal `json:"amount"`
+	SettledOn  time.Time       `json:"settled_on"`
+	Note       *string         `json:"note"`
+	Status     string          `json:"status"`     // pending, confirmed, disputed or cancelled. Only confirmed settlements count towards balances
+	CreatedBy  *int64          `json:"created_by"` // User ID who recorded the settlement
+	CreatedAt  time.Time       `json:"created_at"`
+	ModifiedAt time.Time       `json:"modified_at"`
+}
+
+type ListSettlementResponse struct {
+	Settlements []SettlementResponse `json:"settlements"`
+	Count       int32                `json:"count"`
+	Limit       int32                `json:"limit"`
+	Offset      int32                `json:"offset"`
+}
+
+type CreateSettlementRequest struct {
+	FromMember int64           `json:"from_member"` // Group Member ID, not User ID
+	ToMember   int64           `json:"to_member"`   // Group Member ID, not User ID
+	Amount     decimal.Decimal `json:"amount"`
+	SettledOn  time.Time       `json:"settled_on"` // Optional: defaults to today
+	Note       *string         `json:"note"`
+}
+
+type SettleAllResponse struct {
+	GroupID     int64                `json:"group_id"`
+	Settlements []SettlementResponse `json:"settlements"`
+	Count       int32                `json:"count"`
+}
+
+// CreateCrossGroupPaymentRequest records a payment between two users, split into settlements in the groups they share
+type CreateCrossGroupPaymentRequest struct {
+	FromUserID int64           `json:"from_user_id"` // User ID, not Group Member ID
+	ToUserID   int64           `json:"to_user_id"`   // User ID, not Group Member ID
+	Amount     decimal.Decimal `json:"amount"`
+	Currency   string          `json:"currency"`   // Optional: only split into groups with this base currency, required when shared groups use more than one
+	SettledOn  time.Time       `json:"settled_on"` // Optional: defaults to today
+	Note       *string         `json:"note"`
+}
+
+type CrossGroupPaymentResponse struct {
+	FromUserID  int64                `json:"from_user_id"`
+	ToUserID    int64                `json:"to_user_id"`
+	Amount      decimal.Decimal      `json:"amount"`
+	Currency    string               `json:"currency"`
+	Settlements []SettlementResponse `json:"settlements"`
+	Count       int32                `json:"count"`
+}
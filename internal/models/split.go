@@ -28,6 +28,7 @@ type CreateSplitRequest struct {
 	TransactionID int64           `json:"transaction_id"`
 	SplitPercent  decimal.Decimal `json:"split_percent"`
 	SplitAmount   decimal.Decimal `json:"split_amount"`
+	Shares        decimal.Decimal `json:"shares"` // Only used with "shares" split mode
 	SplitUser     *int64          `json:"split_user"`
 }
 
@@ -7,16 +7,27 @@ import (
 )
 
 type TransactionResponse struct {
-	ID              int64           `json:"id"`
-	GroupID         int64           `json:"group_id"`
-	Name            string          `json:"name"`
-	TransactionDate time.Time       `json:"transaction_date"`
-	Amount          decimal.Decimal `json:"amount"`
-	Category        *string         `json:"category"`
-	Note            *string         `json:"note"`
-	ByUser          int64           `json:"by_user"`
-	CreatedAt       time.Time       `json:"created_at"`
-	ModifiedAt      time.Time       `json:"modified_at"`
+	ID              int64                      `json:"id"`
+	GroupID         int64                      `json:"group_id"`
+	Name            string                     `json:"name"`
+	TransactionDate time.Time                  `json:"transaction_date"`
+	Amount          decimal.Decimal            `json:"amount"` // In the transaction currency
+	Currency        string                     `json:"currency"`
+	ExchangeRate    decimal.Decimal            `json:"exchange_rate"` // Transaction currency to group base currency on transaction_date
+	BaseAmount      decimal.Decimal            `json:"base_amount"`   // Amount in the group base currency
+	Category        *string                    `json:"category"`
+	Note            *string                    `json:"note"`
+	ByUser          int64                      `json:"by_user"` // Primary payer
+	Payers          []TransactionPayerResponse `json:"payers,omitempty"`
+	SplitMode       *string                    `json:"split_mode"` // How the splits were calculated, null for splits sent without a mode
+	CreatedAt       time.Time                  `json:"created_at"`
+	ModifiedAt      time.Time                  `json:"modified_at"`
+	DeletedAt       *time.Time                 `json:"deleted_at,omitempty"` // Set while the transaction is in the trash
+}
+
+type TransactionPayerResponse struct {
+	MemberID int64           `json:"member_id"`
+	Amount   decimal.Decimal `json:"amount"`
 }
 
 type ListTransactionResponse struct {
@@ -27,21 +38,49 @@ type ListTransactionResponse struct {
 }
 
 type CreateTransactionRequest struct {
-	GroupID         int64           `json:"group_id"`
-	Name            string          `json:"name"`
-	TransactionDate time.Time       `json:"transaction_date"`
-	Amount          decimal.Decimal `json:"amount"`
-	Category        *string         `json:"category"`
-	Note            *string         `json:"note"`
-	ByUser          int64           `json:"by_user"`
+	GroupID         int64                     `json:"group_id"`
+	Name            string                    `json:"name"`
+	TransactionDate time.Time                 `json:"transaction_date"`
+	Amount          decimal.Decimal           `json:"amount"`
+	Currency        string                    `json:"currency"` // Optional: defaults to the group base currency
+	Category        *string                   `json:"category"`
+	Note            *string                   `json:"note"`
+	ByUser          int64                     `json:"by_user"`
+	Payers          []TransactionPayerRequest `json:"payers"` // Optional: members paying part of the amount, by_user defaults to the first payer
 }
 
 type UpdateTransactionRequest struct {
-	GroupID         int64           `json:"group_id"`
+	GroupID         int64                     `json:"group_id"`
+	Name            string                    `json:"name"`
+	TransactionDate time.Time                 `json:"transaction_date"`
+	Amount          decimal.Decimal           `json:"amount"`
+	Currency        string                    `json:"currency"` // Optional: defaults to the current transaction currency
+	Category        *string                   `json:"category"`
+	Note            *string                   `json:"note"`
+	ByUser          int64                     `json:"by_user"`
+	Payers          []TransactionPayerRequest `json:"payers"` // Optional: members paying part of the amount, by_user defaults to the first payer
+}
+
+type TransactionPayerRequest struct {
+	MemberID int64           `json:"member_id"` // Group Member ID, not User ID
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+type InconsistentTransactionResponse struct {
+	TransactionID   int64           `json:"transaction_id"`
 	Name            string          `json:"name"`
 	TransactionDate time.Time       `json:"transaction_date"`
 	Amount          decimal.Decimal `json:"amount"`
-	Category        *string         `json:"category"`
-	Note            *string         `json:"note"`
-	ByUser          int64           `json:"by_user"`
+	SplitMode       *string         `json:"split_mode"`
+	SplitCount      int64           `json:"split_count"`
+	SplitTotal      decimal.Decimal `json:"split_total"`
+	Difference      decimal.Decimal `json:"difference"`        // Amount minus split_total
+	StaleSplitCount int64           `json:"stale_split_count"` // Splits calculated from a different transaction amount
+}
+
+type ListInconsistentTransactionResponse struct {
+	Transactions []InconsistentTransactionResponse `json:"transactions"`
+	Count        int32                             `json:"count"`
+	Limit        int32                             `json:"limit"`
+	Offset       int32                             `json:"offset"`
 }
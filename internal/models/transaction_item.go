@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type TransactionItemResponse struct {
+	ID            int64           `json:"id"`
+	TransactionID int64           `json:"transaction_id"`
+	Name          string          `json:"name"`
+	Amount        decimal.Decimal `json:"amount"`
+	Members       []int64         `json:"members"` // Group Member IDs sharing this item
+	CreatedAt     time.Time       `json:"created_at"`
+	ModifiedAt    time.Time       `json:"modified_at"`
+}
+
+type TransactionAdjustmentResponse struct {
+	ID            int64           `json:"id"`
+	TransactionID int64           `json:"transaction_id"`
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	Value         decimal.Decimal `json:"value"`
+	Amount        decimal.Decimal `json:"amount"` // Resolved amount applied to the transaction
+	CreatedAt     time.Time       `json:"created_at"`
+	ModifiedAt    time.Time       `json:"modified_at"`
+}
+
+type ListTransactionItemResponse struct {
+	Items       []TransactionItemResponse       `json:"items"`
+	Adjustments []TransactionAdjustmentResponse `json:"adjustments"`
+	Count       int32                           `json:"count"`
+}
+
+type TransactionItemRequest struct {
+	Name    string          `json:"name"`
+	Amount  decimal.Decimal `json:"amount"`
+	Members []int64         `json:"members"` // Group Member IDs sharing this item (not User IDs)
+}
+
+type TransactionAdjustmentRequest struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`  // fixed or percent
+	Value decimal.Decimal `json:"value"` // Fixed amount or percent of item subtotal (0.0 to 1.0), negative for discounts
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type TransactionRevisionResponse struct {
+	TransactionID int64                       `json:"transaction_id"`
+	Revision      int32                       `json:"revision"`
+	RevertedFrom  *int32                      `json:"reverted_from"` // Revision restored by a revert, null for edits
+	CreatedBy     *int64                      `json:"created_by"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	Snapshot      TransactionRevisionSnapshot `json:"snapshot"`
+	Changes       []RevisionChange            `json:"changes,omitempty"` // Diff against the previous revision, only when getting a single revision
+}
+
+// TransactionRevisionSnapshot is a transaction with its payers & splits as of a revision
+type TransactionRevisionSnapshot struct {
+	GroupID         int64                      `json:"group_id"`
+	Name            string                     `json:"name"`
+	TransactionDate time.Time                  `json:"transaction_date"`
+	Amount          decimal.Decimal            `json:"amount"`
+	Category        *string                    `json:"category"`
+	Note            *string                    `json:"note"`
+	ByUser          int64                      `json:"by_user"`
+	Currency        string                     `json:"currency"`
+	ExchangeRate    decimal.Decimal            `json:"exchange_rate"`
+	Payers          []TransactionPayerResponse `json:"payers"`
+	Splits          []RevisionSplit            `json:"splits"`
+	SplitMode       *string                    `json:"split_mode"` // How the splits were calculated, null for splits sent without a mode
+}
+
+type RevisionSplit struct {
+	SplitUser    *int64          `json:"split_user"`
+	SplitPercent decimal.Decimal `json:"split_percent"`
+	SplitAmount  decimal.Decimal `json:"split_amount"`
+}
+
+// RevisionChange is a field that differs between two revisions, payers & splits are keyed by member, e.g. splits.12.split_amount
+type RevisionChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"` // Null when added
+	After  interface{} `json:"after"`  // Null when removed
+}
+
+type ListTransactionRevisionResponse struct {
+	Revisions []TransactionRevisionResponse `json:"revisions"`
+	Count     int32                         `json:"count"`
+	Limit     int32                         `json:"limit"`
+	Offset    int32                         `json:"offset"`
+}
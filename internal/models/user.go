@@ -9,6 +9,15 @@ type UserResponse struct {
 	ModifiedAt time.Time `json:"modified_at"`
 }
 
+type CurrentUserResponse struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	ModifiedAt    time.Time `json:"modified_at"`
+}
+
 type ListUserResponse struct {
 	Users  []UserResponse `json:"users"`
 	Count  int32          `json:"count"`
@@ -45,3 +54,44 @@ type RefreshResponse struct {
 	Token     string `json:"token"`
 	CSRFToken string `json:"csrf_token,omitempty"`
 }
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email"`
+}
+
+// SessionResponse is a login on one device, refreshing keeps the same session ID
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	DeviceInfo *string   `json:"device_info"` // User-Agent of the client, null if it didn't send one
+	IPAddress  *string   `json:"ip_address"`
+	LastUsedAt time.Time `json:"last_used_at"` // When the session last logged in or refreshed
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // The session making the request
+}
+
+type ListSessionResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+	Count    int32             `json:"count"`
+}
+
+type RevokeOtherSessionsResponse struct {
+	Revoked int64 `json:"revoked"` // Number of sessions logged out
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Allocate divides total between weights, rounding each share to the given number of decimal places.
+// The results always sum exactly to total (after rounding total itself to places).
+//
+// Allocation uses the largest remainder method:
+//  1. Each share is truncated to places, in units of 10^-places (i.e. pennies for places = 2)
+//  2. Leftover units are handed out one at a time to the shares with the largest truncated remainder
+//  3. Ties on remainder go to the share with the lowest index, so the earliest split in the request wins
+//
+// Remainders are compared exactly using integer division, the result is stable for the same inputs.
+func Allocate(total decimal.Decimal, weights []decimal.Decimal, places int32) ([]decimal.Decimal, error) {
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("at least one weight is required")
+	}
+	if total.IsNegative() {
+		return nil, fmt.Errorf("total must not be negative, got %s", total.String())
+	}
+
+	totalWeight := decimal.Zero
+	for i, w := range weights {
+		if w.IsNegative() {
+			return nil, fmt.Errorf("weight[%d] must not be negative, got %s", i, w.String())
+		}
+		totalWeight = totalWeight.Add(w)
+	}
+	if !totalWeight.IsPositive() {
+		return nil, fmt.Errorf("weights must sum to more than 0")
+	}
+
+	// Work in whole units of the smallest representable value
+	unit := decimal.New(1, -places)
+	totalUnits := total.Round(places).Div(unit)
+
+	results := make([]decimal.Decimal, len(weights))
+	remainders := make([]decimal.Decimal, len(weights))
+	allocatedUnits := decimal.Zero
+	for i, w := range weights {
+		q, r := totalUnits.Mul(w).QuoRem(totalWeight, 0)
+		results[i] = q
+		remainders[i] = r
+		allocatedUnits = allocatedUnits.Add(q)
+	}
+
+	// Leftover units are always fewer than the number of weights
+	leftover := totalUnits.Sub(allocatedUnits).IntPart()
+	for _, i := range largestRemainderOrder(remainders)[:leftover] {
+		results[i] = results[i].Add(decimal.NewFromInt(1))
+	}
+
+	for i := range results {
+		results[i] = results[i].Mul(unit)
+	}
+	return results, nil
+}
+
+// largestRemainderOrder returns indexes sorted by remainder descending, ties broken by lowest index.
+func largestRemainderOrder(remainders []decimal.Decimal) []int {
+	order := make([]int, len(remainders))
+	for i := range order {
+		order[i] = i
+	}
+	// Stable sort keeps equal remainders in index order
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].GreaterThan(remainders[order[b]])
+	})
+	return order
+}
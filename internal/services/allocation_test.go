@@ -0,0 +1,160 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decimals(values ...string) []decimal.Decimal {
+	result := make([]decimal.Decimal, len(values))
+	for i, v := range values {
+		result[i] = decimal.RequireFromString(v)
+	}
+	return result
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       decimal.Decimal
+		weights     []decimal.Decimal
+		places      int32
+		expected    []decimal.Decimal
+		expectError bool
+	}{
+		{
+			name:     "even split",
+			total:    decimal.NewFromInt(100),
+			weights:  decimals("1", "1"),
+			places:   2,
+			expected: decimals("50", "50"),
+		},
+		{
+			name:     "$100 three ways gives first split the extra penny",
+			total:    decimal.NewFromInt(100),
+			weights:  decimals("1", "1", "1"),
+			places:   2,
+			expected: decimals("33.34", "33.33", "33.33"),
+		},
+		{
+			name:     "two leftover pennies go to first two splits on tie",
+			total:    decimal.RequireFromString("0.05"),
+			weights:  decimals("1", "1", "1"),
+			places:   2,
+			expected: decimals("0.02", "0.02", "0.01"),
+		},
+		{
+			name:     "leftover penny goes to largest remainder not first index",
+			total:    decimal.NewFromInt(10),
+			weights:  decimals("1", "2"),
+			places:   2,
+			expected: decimals("3.33", "6.67"),
+		},
+		{
+			name:     "weighted shares",
+			total:    decimal.RequireFromString("125.50"),
+			weights:  decimals("3", "1"),
+			places:   2,
+			expected: decimals("94.13", "31.37"),
+		},
+		{
+			name:     "percent weights",
+			total:    decimal.NewFromInt(200),
+			weights:  decimals("0.333333", "0.333333", "0.333334"),
+			places:   2,
+			expected: decimals("66.67", "66.66", "66.67"),
+		},
+		{
+			name:     "percent places",
+			total:    decimal.NewFromInt(1),
+			weights:  decimals("1", "1", "1"),
+			places:   6,
+			expected: decimals("0.333334", "0.333333", "0.333333"),
+		},
+		{
+			name:     "zero weight receives nothing",
+			total:    decimal.NewFromInt(10),
+			weights:  decimals("1", "0", "2"),
+			places:   2,
+			expected: decimals("3.33", "0", "6.67"),
+		},
+		{
+			name:     "single weight receives total",
+			total:    decimal.RequireFromString("42.42"),
+			weights:  decimals("7"),
+			places:   2,
+			expected: decimals("42.42"),
+		},
+		{
+			name:     "zero total",
+			total:    decimal.Zero,
+			weights:  decimals("1", "1"),
+			places:   2,
+			expected: decimals("0", "0"),
+		},
+		{
+			name:        "no weights",
+			total:       decimal.NewFromInt(100),
+			weights:     []decimal.Decimal{},
+			places:      2,
+			expectError: true,
+		},
+		{
+			name:        "all zero weights",
+			total:       decimal.NewFromInt(100),
+			weights:     decimals("0", "0"),
+			places:      2,
+			expectError: true,
+		},
+		{
+			name:        "negative weight",
+			total:       decimal.NewFromInt(100),
+			weights:     decimals("1", "-1"),
+			places:      2,
+			expectError: true,
+		},
+		{
+			name:        "negative total",
+			total:       decimal.NewFromInt(-100),
+			weights:     decimals("1", "1"),
+			places:      2,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Allocate(tt.total, tt.weights, tt.places)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, len(tt.expected))
+
+			sum := decimal.Zero
+			for i := range result {
+				assert.True(t, result[i].Equal(tt.expected[i]), "result[%d]: expected %s, got %s", i, tt.expected[i], result[i])
+				sum = sum.Add(result[i])
+			}
+			assert.True(t, sum.Equal(tt.total), "expected sum %s, got %s", tt.total, sum)
+		})
+	}
+}
+
+func TestAllocateDeterministic(t *testing.T) {
+	weights := decimals("1", "1", "1", "1", "1", "1", "1")
+	total := decimal.RequireFromString("99.99")
+
+	first, err := Allocate(total, weights, 2)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		result, err := Allocate(total, weights, 2)
+		require.NoError(t, err)
+		assert.Equal(t, first, result)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// GroupMemberBalance is a user's net balance in one group, positive when the member is owed
+type GroupMemberBalance struct {
+	GroupID    int64
+	MemberID   int64
+	UserID     int64
+	NetBalance decimal.Decimal
+}
+
+// GroupSettlement is the part of a cross-group payment recorded as a settlement in one group
+type GroupSettlement struct {
+	GroupID    int64
+	FromMember int64 // Group Member ID who paid
+	ToMember   int64 // Group Member ID who was paid
+	Amount     decimal.Decimal
+}
+
+// SplitCrossGroupPayment splits a payment between two users into settlements in the groups they share.
+// Groups where the payee owes the payer are settled first with settlements in the other direction,
+// so the payer can pay off more in the groups where they owe the payee. The forward settlements less
+// the reverse settlements always equal amount.
+// e.g. A owes B 30 in "Apartment" and B owes A 10 in "Ski Trip", a payment of 20 from A to B records
+// 30 from A to B in "Apartment" and 10 from B to A in "Ski Trip", settling both groups.
+// Groups are used in the order given, an error is returned when the payer does not owe the payee enough in the shared groups.
+func SplitCrossGroupPayment(fromUserID, toUserID int64, amount decimal.Decimal, balances []GroupMemberBalance) ([]GroupSettlement, error) {
+	if fromUserID == toUserID {
+		return nil, fmt.Errorf("a payment must be between two different users")
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be greater than 0")
+	}
+	if !amount.Equal(amount.Round(splitAmountPlaces)) {
+		return nil, fmt.Errorf("amount must have at most %d decimal places", splitAmountPlaces)
+	}
+
+	// Pair the payer and payee in each group they are both members of
+	type sharedGroup struct {
+		groupID  int64
+		from, to *GroupMemberBalance
+	}
+	var groups []*sharedGroup
+	byGroupID := make(map[int64]*sharedGroup)
+	for i := range balances {
+		balance := &balances[i]
+		if balance.UserID != fromUserID && balance.UserID != toUserID {
+			continue
+		}
+		group, ok := byGroupID[balance.GroupID]
+		if !ok {
+			group = &sharedGroup{groupID: balance.GroupID}
+			byGroupID[balance.GroupID] = group
+			groups = append(groups, group)
+		}
+		if balance.UserID == fromUserID {
+			group.from = balance
+		} else {
+			group.to = balance
+		}
+	}
+
+	// Forward capacity is what the payer can pay the payee in a group without overpaying either,
+	// reverse capacity is what the payee can pay back in groups where they owe the payer
+	forward := make([]decimal.Decimal, len(groups))
+	reverse := make([]decimal.Decimal, len(groups))
+	forwardTotal := decimal.Zero
+	reverseTotal := decimal.Zero
+	for i, group := range groups {
+		forward[i] = decimal.Zero
+		reverse[i] = decimal.Zero
+		if group.from == nil || group.to == nil {
+			continue
+		}
+		if group.from.NetBalance.IsNegative() && group.to.NetBalance.IsPositive() {
+			forward[i] = decimal.Min(group.from.NetBalance.Neg(), group.to.NetBalance)
+			forwardTotal = forwardTotal.Add(forward[i])
+		}
+		if group.from.NetBalance.IsPositive() && group.to.NetBalance.IsNegative() {
+			reverse[i] = decimal.Min(group.from.NetBalance, group.to.NetBalance.Neg())
+			reverseTotal = reverseTotal.Add(reverse[i])
+		}
+	}
+
+	if amount.GreaterThan(forwardTotal) {
+		return nil, fmt.Errorf("payment of %s is more than the %s owed in shared groups", amount.String(), forwardTotal.String())
+	}
+
+	// Offset as much of the reverse debt as the forward capacity allows
+	reverseRemaining := decimal.Min(reverseTotal, forwardTotal.Sub(amount))
+	forwardRemaining := amount.Add(reverseRemaining)
+
+	settlements := []GroupSettlement{}
+	for i, group := range groups {
+		if forward[i].IsPositive() && forwardRemaining.IsPositive() {
+			paid := decimal.Min(forward[i], forwardRemaining)
+			forwardRemaining = forwardRemaining.Sub(paid)
+			settlements = append(settlements, GroupSettlement{GroupID: group.groupID, FromMember: group.from.MemberID, ToMember: group.to.MemberID, Amount: paid})
+		}
+		if reverse[i].IsPositive() && reverseRemaining.IsPositive() {
+			paid := decimal.Min(reverse[i], reverseRemaining)
+			reverseRemaining = reverseRemaining.Sub(paid)
+			settlements = append(settlements, GroupSettlement{GroupID: group.groupID, FromMember: group.to.MemberID, ToMember: group.from.MemberID, Amount: paid})
+		}
+	}
+
+	return settlements, nil
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCrossGroupPayment(t *testing.T) {
+	// User 1 owes user 2 30 in group 10 and user 2 owes user 1 10 in group 20
+	pairBalances := []GroupMemberBalance{
+		{GroupID: 10, MemberID: 101, UserID: 1, NetBalance: decimal.NewFromInt(-30)},
+		{GroupID: 10, MemberID: 102, UserID: 2, NetBalance: decimal.NewFromInt(30)},
+		{GroupID: 20, MemberID: 201, UserID: 1, NetBalance: decimal.NewFromInt(10)},
+		{GroupID: 20, MemberID: 202, UserID: 2, NetBalance: decimal.NewFromInt(-10)},
+	}
+
+	tests := []struct {
+		name                string
+		fromUserID          int64
+		toUserID            int64
+		amount              decimal.Decimal
+		balances            []GroupMemberBalance
+		expectError         bool
+		expectedSettlements []GroupSettlement
+	}{
+		{
+			name:       "net payment settles both groups",
+			fromUserID: 1,
+			toUserID:   2,
+			amount:     decimal.NewFromInt(20),
+			balances:   pairBalances,
+			expectedSettlements: []GroupSettlement{
+				{GroupID: 10, FromMember: 101, ToMember: 102, Amount: decimal.NewFromInt(30)},
+				{GroupID: 20, FromMember: 202, ToMember: 201, Amount: decimal.NewFromInt(10)},
+			},
+		},
+		{
+			name:       "partial payment offsets reverse debt first",
+			fromUserID: 1,
+			toUserID:   2,
+			amount:     decimal.NewFromInt(5),
+			balances:   pairBalances,
+			expectedSettlements: []GroupSettlement{
+				{GroupID: 10, FromMember: 101, ToMember: 102, Amount: decimal.NewFromInt(15)},
+				{GroupID: 20, FromMember: 202, ToMember: 201, Amount: decimal.NewFromInt(10)},
+			},
+		},
+		{
+			name:       "overpaying the forward debt",
+			fromUserID: 1,
+			toUserID:   2,
+			amount:     decimal.NewFromInt(31),
+			balances:   pairBalances,
+			// Only 30 is owed in group 10, the reverse debt can't absorb more
+			expectError: true,
+		},
+		{
+			name:        "payee owes payer overall",
+			fromUserID:  2,
+			toUserID:    1,
+			amount:      decimal.NewFromInt(20),
+			balances:    pairBalances,
+			expectError: true,
+		},
+		{
+			name:       "limited by what the payee is owed in a group",
+			fromUserID: 1,
+			toUserID:   2,
+			amount:     decimal.RequireFromString("12.50"),
+			balances: []GroupMemberBalance{
+				// User 1 owes 20 in group 10 but user 2 is only owed 5, user 3 is owed the rest
+				{GroupID: 10, MemberID: 101, UserID: 1, NetBalance: decimal.NewFromInt(-20)},
+				{GroupID: 10, MemberID: 102, UserID: 2, NetBalance: decimal.NewFromInt(5)},
+				{GroupID: 30, MemberID: 301, UserID: 1, NetBalance: decimal.NewFromInt(-40)},
+				{GroupID: 30, MemberID: 302, UserID: 2, NetBalance: decimal.NewFromInt(40)},
+			},
+			expectedSettlements: []GroupSettlement{
+				{GroupID: 10, FromMember: 101, ToMember: 102, Amount: decimal.NewFromInt(5)},
+				{GroupID: 30, FromMember: 301, ToMember: 302, Amount: decimal.RequireFromString("7.50")},
+			},
+		},
+		{
+			name:       "groups with only one of the users are ignored",
+			fromUserID: 1,
+			toUserID:   2,
+			amount:     decimal.NewFromInt(10),
+			balances: []GroupMemberBalance{
+				{GroupID: 10, MemberID: 101, UserID: 1, NetBalance: decimal.NewFromInt(-50)},
+				{GroupID: 20, MemberID: 202, UserID: 2, NetBalance: decimal.NewFromInt(50)},
+			},
+			expectError: true,
+		},
+		{
+			name:        "same user",
+			fromUserID:  1,
+			toUserID:    1,
+			amount:      decimal.NewFromInt(10),
+			balances:    pairBalances,
+			expectError: true,
+		},
+		{
+			name:        "zero amount",
+			fromUserID:  1,
+			toUserID:    2,
+			amount:      decimal.Zero,
+			balances:    pairBalances,
+			expectError: true,
+		},
+		{
+			name:        "fractional cents",
+			fromUserID:  1,
+			toUserID:    2,
+			amount:      decimal.RequireFromString("10.005"),
+			balances:    pairBalances,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settlements, err := SplitCrossGroupPayment(tt.fromUserID, tt.toUserID, tt.amount, tt.balances)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, settlements)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, settlements, len(tt.expectedSettlements))
+
+			net := decimal.Zero
+			for i, settlement := range settlements {
+				expected := tt.expectedSettlements[i]
+				assert.Equal(t, expected.GroupID, settlement.GroupID)
+				assert.Equal(t, expected.FromMember, settlement.FromMember)
+				assert.Equal(t, expected.ToMember, settlement.ToMember)
+				assert.True(t, expected.Amount.Equal(settlement.Amount), "settlement[%d]: expected %s, got %s", i, expected.Amount, settlement.Amount)
+				if settlement.FromMember%100 == tt.fromUserID {
+					net = net.Add(settlement.Amount)
+				} else {
+					net = net.Sub(settlement.Amount)
+				}
+			}
+			assert.True(t, net.Equal(tt.amount), "settlements should net to %s, got %s", tt.amount, net)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency is the base currency for groups created without one
+const DefaultCurrency = "USD"
+
+// exchangeRatePlaces matches exchange_rates.rate numeric(18,8)
+const exchangeRatePlaces = 8
+
+// ExchangeRate converts 1 unit of From into Rate units of To on Date
+type ExchangeRate struct {
+	From string
+	To   string
+	Date time.Time
+	Rate decimal.Decimal
+}
+
+// NormalizeCurrency upper cases and validates a 3 letter ISO 4217 currency code
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("currency must be a 3 letter code, got %q", code)
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", fmt.Errorf("currency must be a 3 letter code, got %q", code)
+		}
+	}
+	return code, nil
+}
+
+// InvertRate returns the rate for the opposite direction, rounded to the stored precision
+func InvertRate(rate decimal.Decimal) (decimal.Decimal, error) {
+	if !rate.IsPositive() {
+		return decimal.Zero, fmt.Errorf("exchange rate must be greater than 0, got %s", rate.String())
+	}
+	return decimal.NewFromInt(1).DivRound(rate, exchangeRatePlaces), nil
+}
+
+// ConvertAmount converts an amount with rate, rounded to cents
+func ConvertAmount(amount, rate decimal.Decimal) decimal.Decimal {
+	return amount.Mul(rate).Round(splitAmountPlaces)
+}
+
+// ParseExchangeRates reads exchange rates from CSV with the columns date,from_currency,to_currency,rate
+// e.g. 2025-06-01,EUR,USD,1.08 means 1 EUR = 1.08 USD on 2025-06-01.
+// A header row starting with "date" and lines starting with # are skipped.
+func ParseExchangeRates(r io.Reader) ([]ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	rates := []ExchangeRate{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(rates) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "date") {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q, expected YYYY-MM-DD", line, record[0])
+		}
+		from, err := NormalizeCurrency(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: from %w", line, err)
+		}
+		to, err := NormalizeCurrency(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: to %w", line, err)
+		}
+		if from == to {
+			return nil, fmt.Errorf("line %d: from and to currency are both %s", line, from)
+		}
+		rate, err := decimal.NewFromString(strings.TrimSpace(record[3]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, record[3])
+		}
+		if !rate.IsPositive() {
+			return nil, fmt.Errorf("line %d: rate must be greater than 0", line)
+		}
+		if !rate.Equal(rate.Round(exchangeRatePlaces)) {
+			return nil, fmt.Errorf("line %d: rate must have at most %d decimal places", line, exchangeRatePlaces)
+		}
+
+		rates = append(rates, ExchangeRate{From: from, To: to, Date: date, Rate: rate})
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        string
+		expected    string
+		expectError bool
+	}{
+		{name: "upper case", code: "EUR", expected: "EUR"},
+		{name: "lower case with spaces", code: " usd ", expected: "USD"},
+		{name: "too long", code: "EURO", expectError: true},
+		{name: "too short", code: "EU", expectError: true},
+		{name: "digits", code: "E1R", expectError: true},
+		{name: "empty", code: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := NormalizeCurrency(tt.code)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, code)
+		})
+	}
+}
+
+func TestInvertRate(t *testing.T) {
+	rate, err := InvertRate(decimal.NewFromInt(160))
+	require.NoError(t, err)
+	assert.True(t, rate.Equal(decimal.RequireFromString("0.00625")), "got %s", rate)
+
+	rate, err = InvertRate(decimal.NewFromInt(3))
+	require.NoError(t, err)
+	assert.True(t, rate.Equal(decimal.RequireFromString("0.33333333")), "got %s", rate)
+
+	_, err = InvertRate(decimal.Zero)
+	assert.Error(t, err)
+}
+
+func TestConvertAmount(t *testing.T) {
+	amount := ConvertAmount(decimal.RequireFromString("33.33"), decimal.RequireFromString("1.0875"))
+	assert.True(t, amount.Equal(decimal.RequireFromString("36.25")), "got %s", amount)
+}
+
+func TestParseExchangeRates(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedRates []ExchangeRate
+		expectError   bool
+	}{
+		{
+			name: "header, comments and rates",
+			input: `date,from_currency,to_currency,rate
+# ECB reference rates
+2024-06-01,EUR,USD,1.0875
+2024-06-02, gbp, usd, 1.27
+`,
+			expectedRates: []ExchangeRate{
+				{From: "EUR", To: "USD", Date: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("1.0875")},
+				{From: "GBP", To: "USD", Date: time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("1.27")},
+			},
+		},
+		{
+			name:          "without header",
+			input:         "2024-06-01,USD,JPY,160\n",
+			expectedRates: []ExchangeRate{{From: "USD", To: "JPY", Date: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Rate: decimal.NewFromInt(160)}},
+		},
+		{
+			name:          "empty file",
+			input:         "",
+			expectedRates: []ExchangeRate{},
+		},
+		{
+			name:        "invalid date",
+			input:       "06/01/2024,EUR,USD,1.08\n",
+			expectError: true,
+		},
+		{
+			name:        "invalid currency",
+			input:       "2024-06-01,EURO,USD,1.08\n",
+			expectError: true,
+		},
+		{
+			name:        "same currency",
+			input:       "2024-06-01,USD,USD,1\n",
+			expectError: true,
+		},
+		{
+			name:        "zero rate",
+			input:       "2024-06-01,EUR,USD,0\n",
+			expectError: true,
+		},
+		{
+			name:        "rate with too many decimal places",
+			input:       "2024-06-01,EUR,USD,1.123456789\n",
+			expectError: true,
+		},
+		{
+			name:        "missing column",
+			input:       "2024-06-01,EUR,1.08\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := ParseExchangeRates(strings.NewReader(tt.input))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, rates, len(tt.expectedRates))
+			for i, rate := range rates {
+				assert.Equal(t, tt.expectedRates[i].From, rate.From)
+				assert.Equal(t, tt.expectedRates[i].To, rate.To)
+				assert.True(t, tt.expectedRates[i].Date.Equal(rate.Date), "rate[%d] date: expected %s, got %s", i, tt.expectedRates[i].Date, rate.Date)
+				assert.True(t, tt.expectedRates[i].Rate.Equal(rate.Rate), "rate[%d]: expected %s, got %s", i, tt.expectedRates[i].Rate, rate.Rate)
+			}
+		})
+	}
+}
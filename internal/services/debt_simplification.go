@@ -3,6 +3,8 @@ package services
 import (
 	"container/heap"
 	"errors"
+	"fmt"
+	"math/bits"
 
 	"github.com/MattSharp0/transaction-split-go/internal/logger"
 	"github.com/MattSharp0/transaction-split-go/internal/models"
@@ -42,7 +44,7 @@ func SimplifyDebts(balances []*models.NetBalance) ([]models.SimplifiedPaymentsRe
 	heap.Init(debtorHeap)
 	heap.Init(creditorHeap)
 
-	payments := make([]models.SimplifiedPaymentsResponse, debtorHeap.Len())
+	payments := make([]models.SimplifiedPaymentsResponse, 0, debtorHeap.Len())
 	var paymentCount int
 
 	for debtorHeap.Len() > 0 {
@@ -56,11 +58,11 @@ func SimplifyDebts(balances []*models.NetBalance) ([]models.SimplifiedPaymentsRe
 			pa = debtor.NetBalance
 		}
 		if delta.IsNegative() {
-			creditorHeap.Push(&models.NetBalance{UserID: creditor.UserID, NetBalance: delta})
+			heap.Push(creditorHeap, &models.NetBalance{UserID: creditor.UserID, NetBalance: delta})
 			pa = decimal.Min(creditor.NetBalance.Abs(), debtor.NetBalance)
 		}
 		if delta.IsPositive() {
-			debtorHeap.Push(&models.NetBalance{UserID: debtor.UserID, NetBalance: delta})
+			heap.Push(debtorHeap, &models.NetBalance{UserID: debtor.UserID, NetBalance: delta})
 			pa = decimal.Min(creditor.NetBalance.Abs(), debtor.NetBalance)
 		}
 
@@ -71,6 +73,121 @@ func SimplifyDebts(balances []*models.NetBalance) ([]models.SimplifiedPaymentsRe
 	}
 	logger.Debug("Simplified Payments count", "payment count", paymentCount)
 
-	payments = payments[paymentCount:]
+	return payments, nil
+}
+
+// SimplifyMethod selects the debt simplification solver
+type SimplifyMethod string
+
+const (
+	SimplifyGreedy  SimplifyMethod = "greedy"  // Largest debtor pays largest creditor, fast but not always the fewest payments
+	SimplifyOptimal SimplifyMethod = "optimal" // Fewest payments, exponential in the number of members with a balance
+)
+
+// ParseSimplifyMethod parses the simplify query parameter, defaulting to greedy when empty
+func ParseSimplifyMethod(method string) (SimplifyMethod, error) {
+	switch SimplifyMethod(method) {
+	case "", SimplifyGreedy:
+		return SimplifyGreedy, nil
+	case SimplifyOptimal:
+		return SimplifyOptimal, nil
+	default:
+		return "", fmt.Errorf("simplify must be %s or %s, got %q", SimplifyOptimal, SimplifyGreedy, method)
+	}
+}
+
+// MaxOptimalSimplifyMembers caps the members with a non-zero balance SimplifyDebtsOptimal accepts,
+// its running time and memory grow with 2^members
+const MaxOptimalSimplifyMembers = 20
+
+// ErrTooManyMembersToOptimize is returned by SimplifyDebtsOptimal when more than MaxOptimalSimplifyMembers have a balance
+var ErrTooManyMembersToOptimize = fmt.Errorf("optimal simplification supports at most %d members with a balance", MaxOptimalSimplifyMembers)
+
+// SimplifyDebtsOptimal returns the fewest payments that settle all balances.
+// Balances are partitioned into as many zero-sum subsets as possible, each subset of k members
+// then settles with k-1 payments, so n members in s subsets need n-s payments in total.
+// The greedy match in SimplifyDebts can miss these subsets, e.g. 90, 50, 70, -30, -40, -140 settles
+// as {70, -30, -40} & {90, 50, -140} in 4 payments instead of 5.
+func SimplifyDebtsOptimal(balances []*models.NetBalance) ([]models.SimplifiedPaymentsResponse, error) {
+	if sum := utils.SumNetBalances(balances); !sum.IsZero() {
+		logger.Warn("Net balances do not sum to zero", "sum", sum)
+		return nil, errors.New("net Balances do not sum to zero")
+	}
+
+	// Only members with a balance take part, amounts in cents so subset sums are exact
+	var members []*models.NetBalance
+	var cents []int64
+	for _, balance := range balances {
+		if balance.NetBalance.IsZero() {
+			continue
+		}
+		shifted := balance.NetBalance.Shift(splitAmountPlaces)
+		if !shifted.IsInteger() {
+			return nil, fmt.Errorf("net balance %s has more than %d decimal places", balance.NetBalance.String(), splitAmountPlaces)
+		}
+		members = append(members, balance)
+		cents = append(cents, shifted.IntPart())
+	}
+	if len(members) > MaxOptimalSimplifyMembers {
+		return nil, ErrTooManyMembersToOptimize
+	}
+
+	n := len(members)
+	full := 1<<n - 1
+
+	// subsetCount[mask] is the most zero-sum subsets the members in mask can be partitioned into
+	sums := make([]int64, full+1)
+	subsetCount := make([]int8, full+1)
+	for mask := 1; mask <= full; mask++ {
+		lowest := bits.TrailingZeros(uint(mask))
+		sums[mask] = sums[mask&^(1<<lowest)] + cents[lowest]
+
+		best := int8(0)
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && subsetCount[mask&^(1<<i)] > best {
+				best = subsetCount[mask&^(1<<i)]
+			}
+		}
+		if sums[mask] == 0 {
+			best++
+		}
+		subsetCount[mask] = best
+	}
+
+	// Walk back from all members removing one member at a time along the best partition,
+	// members removed between two zero-sum masks form one subset
+	payments := []models.SimplifiedPaymentsResponse{}
+	var subset []*models.NetBalance
+	for mask := full; mask != 0; {
+		next := -1
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) == 0 {
+				continue
+			}
+			candidate := mask &^ (1 << i)
+			expected := subsetCount[mask]
+			if sums[mask] == 0 {
+				expected--
+			}
+			if subsetCount[candidate] == expected {
+				next = i
+				break
+			}
+		}
+
+		subset = append(subset, members[next])
+		mask &^= 1 << next
+
+		if sums[mask] == 0 {
+			subsetPayments, err := SimplifyDebts(subset)
+			if err != nil {
+				return nil, err
+			}
+			payments = append(payments, subsetPayments...)
+			subset = nil
+		}
+	}
+	logger.Debug("Optimal simplified payments count", "payment count", len(payments), "members", n, "subsets", subsetCount[full])
+
 	return payments, nil
 }
@@ -247,3 +247,146 @@ func TestSimplifyDebts(t *testing.T) {
 		})
 	}
 }
+
+func TestSimplifyDebtsOptimal(t *testing.T) {
+	tests := []struct {
+		name             string
+		balances         []*models.NetBalance
+		expectError      bool
+		expectedPayments int
+		greedyPayments   int // Payments SimplifyDebts needs for the same balances, 0 to skip
+	}{
+		{
+			name: "simple two-person debt",
+			balances: []*models.NetBalance{
+				{UserID: 1, NetBalance: decimal.NewFromInt(100)},
+				{UserID: 2, NetBalance: decimal.NewFromInt(-100)},
+			},
+			expectedPayments: 1,
+			greedyPayments:   1,
+		},
+		{
+			name: "zero-sum subsets missed by greedy",
+			balances: []*models.NetBalance{
+				{UserID: 1, NetBalance: decimal.NewFromInt(90)},
+				{UserID: 2, NetBalance: decimal.NewFromInt(50)},
+				{UserID: 3, NetBalance: decimal.NewFromInt(70)},
+				{UserID: 4, NetBalance: decimal.NewFromInt(-30)},
+				{UserID: 5, NetBalance: decimal.NewFromInt(-40)},
+				{UserID: 6, NetBalance: decimal.NewFromInt(-140)},
+			},
+			expectedPayments: 4,
+			greedyPayments:   5,
+		},
+		{
+			name: "independent pairs",
+			balances: []*models.NetBalance{
+				{UserID: 1, NetBalance: decimal.RequireFromString("12.34")},
+				{UserID: 2, NetBalance: decimal.RequireFromString("-12.34")},
+				{UserID: 3, NetBalance: decimal.RequireFromString("56.78")},
+				{UserID: 4, NetBalance: decimal.RequireFromString("-56.78")},
+				{UserID: 5, NetBalance: decimal.Zero},
+			},
+			expectedPayments: 2,
+		},
+		{
+			name: "one debtor many creditors",
+			balances: []*models.NetBalance{
+				{UserID: 1, NetBalance: decimal.NewFromInt(300)},
+				{UserID: 2, NetBalance: decimal.NewFromInt(-100)},
+				{UserID: 3, NetBalance: decimal.NewFromInt(-150)},
+				{UserID: 4, NetBalance: decimal.NewFromInt(-50)},
+			},
+			expectedPayments: 3,
+		},
+		{
+			name:             "empty balances",
+			balances:         []*models.NetBalance{},
+			expectedPayments: 0,
+		},
+		{
+			name: "zero balances",
+			balances: []*models.NetBalance{
+				{UserID: 1, NetBalance: decimal.Zero},
+				{UserID: 2, NetBalance: decimal.Zero},
+			},
+			expectedPayments: 0,
+		},
+		{
+			name: "non-zero sum error",
+			balances: []*models.NetBalance{
+				{UserID: 1, NetBalance: decimal.NewFromInt(100)},
+				{UserID: 2, NetBalance: decimal.NewFromInt(-50)},
+			},
+			expectError: true,
+		},
+		{
+			name: "fractional cents error",
+			balances: []*models.NetBalance{
+				{UserID: 1, NetBalance: decimal.RequireFromString("0.005")},
+				{UserID: 2, NetBalance: decimal.RequireFromString("-0.005")},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payments, err := SimplifyDebtsOptimal(tt.balances)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, payments)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, payments, tt.expectedPayments)
+
+			// Applying the payments must settle every balance
+			remaining := make(map[int64]decimal.Decimal)
+			for _, b := range tt.balances {
+				remaining[b.UserID] = remaining[b.UserID].Add(b.NetBalance)
+			}
+			for _, p := range payments {
+				assert.True(t, p.Amount.IsPositive(), "Payment amount should be positive")
+				remaining[p.FromUserID] = remaining[p.FromUserID].Sub(p.Amount)
+				remaining[p.ToUserID] = remaining[p.ToUserID].Add(p.Amount)
+			}
+			for userID, balance := range remaining {
+				assert.True(t, balance.IsZero(), "user %d should be settled, %s remaining", userID, balance)
+			}
+
+			if tt.greedyPayments > 0 {
+				greedy, err := SimplifyDebts(tt.balances)
+				require.NoError(t, err)
+				assert.Len(t, greedy, tt.greedyPayments)
+				assert.LessOrEqual(t, len(payments), len(greedy))
+			}
+		})
+	}
+}
+
+func TestSimplifyDebtsOptimalMemberCap(t *testing.T) {
+	balances := make([]*models.NetBalance, 0, MaxOptimalSimplifyMembers+1)
+	for i := 1; i <= MaxOptimalSimplifyMembers; i++ {
+		balances = append(balances, &models.NetBalance{UserID: int64(i), NetBalance: decimal.NewFromInt(1)})
+	}
+	balances = append(balances, &models.NetBalance{UserID: MaxOptimalSimplifyMembers + 1, NetBalance: decimal.NewFromInt(-MaxOptimalSimplifyMembers)})
+
+	payments, err := SimplifyDebtsOptimal(balances)
+	assert.ErrorIs(t, err, ErrTooManyMembersToOptimize)
+	assert.Nil(t, payments)
+
+	// At the cap the solver still runs, pairing each debtor with a creditor
+	balances = make([]*models.NetBalance, 0, MaxOptimalSimplifyMembers)
+	for i := 1; i <= MaxOptimalSimplifyMembers; i++ {
+		amount := decimal.NewFromInt(int64(i+1) / 2)
+		if i%2 == 0 {
+			amount = amount.Neg()
+		}
+		balances = append(balances, &models.NetBalance{UserID: int64(i), NetBalance: amount})
+	}
+
+	payments, err = SimplifyDebtsOptimal(balances)
+	require.NoError(t, err)
+	assert.Len(t, payments, MaxOptimalSimplifyMembers/2)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// AdjustmentType determines how a transaction adjustment value is applied
+type AdjustmentType string
+
+const (
+	AdjustmentTypeFixed   AdjustmentType = "fixed"   // Value is an amount
+	AdjustmentTypePercent AdjustmentType = "percent" // Value is a percent of the item subtotal (0.0 to 1.0)
+)
+
+// ItemSplits is the result of deriving splits from a transaction's line items and adjustments
+type ItemSplits struct {
+	Splits            []models.CreateSplitRequest
+	AdjustmentAmounts []decimal.Decimal // Resolved amount of each adjustment, in request order
+}
+
+// DeriveItemSplits builds one split per group member from a transaction's line items and adjustments.
+// Each item amount is divided equally between its members with Allocate, then totalled per member.
+// Adjustments (tax, tip, discounts) are resolved against the item subtotal and spread across members
+// in proportion to their item subtotals, by allocating the transaction amount weighted by member subtotals.
+// Splits are returned in order of each member's first appearance in the items.
+// Items plus adjustments must sum exactly to the transaction amount.
+func DeriveItemSplits(transactionAmount decimal.Decimal, items []models.TransactionItemRequest, adjustments []models.TransactionAdjustmentRequest) (ItemSplits, error) {
+	if len(items) == 0 {
+		return ItemSplits{}, fmt.Errorf("at least one item is required")
+	}
+
+	subtotal := decimal.Zero
+	for i, item := range items {
+		if err := validateItem(i, item); err != nil {
+			return ItemSplits{}, err
+		}
+		subtotal = subtotal.Add(item.Amount)
+	}
+
+	adjustmentAmounts, err := ResolveAdjustments(subtotal, adjustments)
+	if err != nil {
+		return ItemSplits{}, err
+	}
+
+	total := subtotal
+	for _, amount := range adjustmentAmounts {
+		total = total.Add(amount)
+	}
+	if !total.Equal(transactionAmount) {
+		return ItemSplits{}, fmt.Errorf("item and adjustment amounts must sum to transaction amount %s, got %s",
+			transactionAmount.String(), total.String())
+	}
+
+	memberOrder := []int64{}
+	memberSubtotals := make(map[int64]decimal.Decimal)
+	for _, item := range items {
+		weights := make([]decimal.Decimal, len(item.Members))
+		for i := range weights {
+			weights[i] = decimal.NewFromInt(1)
+		}
+		shares, err := Allocate(item.Amount, weights, splitAmountPlaces)
+		if err != nil {
+			return ItemSplits{}, err
+		}
+		for i, memberID := range item.Members {
+			if _, ok := memberSubtotals[memberID]; !ok {
+				memberOrder = append(memberOrder, memberID)
+			}
+			memberSubtotals[memberID] = memberSubtotals[memberID].Add(shares[i])
+		}
+	}
+
+	subtotals := make([]decimal.Decimal, len(memberOrder))
+	for i, memberID := range memberOrder {
+		subtotals[i] = memberSubtotals[memberID]
+	}
+	// Without adjustments the transaction amount equals the subtotal, so each member keeps their subtotal
+	amounts, err := Allocate(transactionAmount, subtotals, splitAmountPlaces)
+	if err != nil {
+		return ItemSplits{}, err
+	}
+	percents, err := Allocate(decimal.NewFromInt(1), amounts, splitPercentPlaces)
+	if err != nil {
+		return ItemSplits{}, err
+	}
+
+	splits := make([]models.CreateSplitRequest, len(memberOrder))
+	for i, memberID := range memberOrder {
+		splits[i] = models.CreateSplitRequest{
+			SplitPercent: percents[i],
+			SplitAmount:  amounts[i],
+			SplitUser:    &memberID,
+		}
+	}
+
+	logger.Debug("Derived splits from items", "item_count", len(items), "adjustment_count", len(adjustments), "split_count", len(splits), "transaction_amount", transactionAmount)
+	return ItemSplits{Splits: splits, AdjustmentAmounts: adjustmentAmounts}, nil
+}
+
+// ResolveAdjustments returns the amount of each adjustment applied to the item subtotal.
+// Fixed adjustments are used as is, percent adjustments are a percent of the subtotal rounded to cents.
+// Negative values are discounts.
+func ResolveAdjustments(subtotal decimal.Decimal, adjustments []models.TransactionAdjustmentRequest) ([]decimal.Decimal, error) {
+	amounts := make([]decimal.Decimal, len(adjustments))
+	for i, adjustment := range adjustments {
+		if adjustment.Name == "" {
+			return nil, fmt.Errorf("adjustment[%d]: name is required", i)
+		}
+
+		switch AdjustmentType(adjustment.Type) {
+		case AdjustmentTypeFixed:
+			if !adjustment.Value.Equal(adjustment.Value.Round(splitAmountPlaces)) {
+				return nil, fmt.Errorf("adjustment[%d]: fixed value must have at most %d decimal places", i, splitAmountPlaces)
+			}
+			amounts[i] = adjustment.Value
+
+		case AdjustmentTypePercent:
+			if adjustment.Value.LessThan(decimal.NewFromInt(-1)) || adjustment.Value.GreaterThan(decimal.NewFromInt(1)) {
+				return nil, fmt.Errorf("adjustment[%d]: percent value must be between -1.0 and 1.0", i)
+			}
+			amounts[i] = subtotal.Mul(adjustment.Value).Round(splitAmountPlaces)
+
+		default:
+			return nil, fmt.Errorf("adjustment[%d]: invalid type %q, must be one of: fixed, percent", i, adjustment.Type)
+		}
+	}
+	return amounts, nil
+}
+
+// validateItem checks a single line item has a name, a positive amount, unique members
+// and at least one cent for each member
+func validateItem(index int, item models.TransactionItemRequest) error {
+	if item.Name == "" {
+		return fmt.Errorf("item[%d]: name is required", index)
+	}
+	if !item.Amount.IsPositive() {
+		return fmt.Errorf("item[%d]: amount must be greater than 0", index)
+	}
+	if len(item.Members) == 0 {
+		return fmt.Errorf("item[%d]: at least one member is required", index)
+	}
+	seen := make(map[int64]bool, len(item.Members))
+	for _, memberID := range item.Members {
+		if seen[memberID] {
+			return fmt.Errorf("item[%d]: member %d is listed more than once", index, memberID)
+		}
+		seen[memberID] = true
+	}
+	if minAmount := decimal.New(int64(len(item.Members)), -splitAmountPlaces); item.Amount.LessThan(minAmount) {
+		return fmt.Errorf("item[%d]: amount %s is less than one cent per member", index, item.Amount.String())
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveItemSplits(t *testing.T) {
+	tests := []struct {
+		name             string
+		amount           decimal.Decimal
+		items            []models.TransactionItemRequest
+		adjustments      []models.TransactionAdjustmentRequest
+		expectError      bool
+		expectedMembers  []int64
+		expectedAmounts  []string
+		expectedPercents []string
+	}{
+		{
+			name:   "single shared item",
+			amount: decimal.NewFromInt(30),
+			items: []models.TransactionItemRequest{
+				{Name: "Pizza", Amount: decimal.NewFromInt(30), Members: []int64{1, 2, 3}},
+			},
+			expectedMembers:  []int64{1, 2, 3},
+			expectedAmounts:  []string{"10", "10", "10"},
+			expectedPercents: []string{"0.333334", "0.333333", "0.333333"},
+		},
+		{
+			name:   "items shared by different members",
+			amount: decimal.NewFromInt(100),
+			items: []models.TransactionItemRequest{
+				{Name: "Steak", Amount: decimal.NewFromInt(40), Members: []int64{1}},
+				{Name: "Salad", Amount: decimal.NewFromInt(20), Members: []int64{2}},
+				{Name: "Wine", Amount: decimal.NewFromInt(40), Members: []int64{1, 2}},
+			},
+			expectedMembers:  []int64{1, 2},
+			expectedAmounts:  []string{"60", "40"},
+			expectedPercents: []string{"0.6", "0.4"},
+		},
+		{
+			name:   "member only in later item",
+			amount: decimal.NewFromInt(25),
+			items: []models.TransactionItemRequest{
+				{Name: "Milk", Amount: decimal.NewFromInt(5), Members: []int64{2}},
+				{Name: "Bread", Amount: decimal.NewFromInt(20), Members: []int64{3, 2}},
+			},
+			expectedMembers:  []int64{2, 3},
+			expectedAmounts:  []string{"15", "10"},
+			expectedPercents: []string{"0.6", "0.4"},
+		},
+		{
+			name:   "item rounding pennies",
+			amount: decimal.NewFromInt(10),
+			items: []models.TransactionItemRequest{
+				{Name: "Snacks", Amount: decimal.NewFromInt(10), Members: []int64{1, 2, 3}},
+			},
+			expectedMembers:  []int64{1, 2, 3},
+			expectedAmounts:  []string{"3.34", "3.33", "3.33"},
+			expectedPercents: []string{"0.334", "0.333", "0.333"},
+		},
+		{
+			name:   "percent tax and fixed tip spread by subtotal",
+			amount: decimal.RequireFromString("123.00"),
+			items: []models.TransactionItemRequest{
+				{Name: "Steak", Amount: decimal.NewFromInt(60), Members: []int64{1}},
+				{Name: "Salad", Amount: decimal.NewFromInt(40), Members: []int64{2}},
+			},
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Tax", Type: "percent", Value: decimal.RequireFromString("0.08")},
+				{Name: "Tip", Type: "fixed", Value: decimal.NewFromInt(15)},
+			},
+			expectedMembers:  []int64{1, 2},
+			expectedAmounts:  []string{"73.80", "49.20"},
+			expectedPercents: []string{"0.6", "0.4"},
+		},
+		{
+			name:   "discount reduces each share proportionally",
+			amount: decimal.NewFromInt(90),
+			items: []models.TransactionItemRequest{
+				{Name: "Shoes", Amount: decimal.NewFromInt(75), Members: []int64{1}},
+				{Name: "Socks", Amount: decimal.NewFromInt(25), Members: []int64{2}},
+			},
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Coupon", Type: "fixed", Value: decimal.NewFromInt(-10)},
+			},
+			expectedMembers:  []int64{1, 2},
+			expectedAmounts:  []string{"67.50", "22.50"},
+			expectedPercents: []string{"0.75", "0.25"},
+		},
+		{
+			name:   "adjustment rounding reconciles to transaction amount",
+			amount: decimal.NewFromInt(11),
+			items: []models.TransactionItemRequest{
+				{Name: "Coffee", Amount: decimal.NewFromInt(5), Members: []int64{1}},
+				{Name: "Coffee", Amount: decimal.NewFromInt(5), Members: []int64{2}},
+				{Name: "Coffee", Amount: decimal.NewFromInt(5), Members: []int64{3}},
+			},
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Discount", Type: "fixed", Value: decimal.NewFromInt(-4)},
+			},
+			expectedMembers:  []int64{1, 2, 3},
+			expectedAmounts:  []string{"3.67", "3.67", "3.66"},
+			expectedPercents: []string{"0.333637", "0.333636", "0.332727"},
+		},
+		{
+			name:   "adjustments do not total transaction amount",
+			amount: decimal.NewFromInt(100),
+			items: []models.TransactionItemRequest{
+				{Name: "Steak", Amount: decimal.NewFromInt(100), Members: []int64{1}},
+			},
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Tip", Type: "fixed", Value: decimal.NewFromInt(15)},
+			},
+			expectError: true,
+		},
+		{
+			name:   "invalid adjustment type",
+			amount: decimal.NewFromInt(110),
+			items: []models.TransactionItemRequest{
+				{Name: "Steak", Amount: decimal.NewFromInt(100), Members: []int64{1}},
+			},
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Tip", Type: "weight", Value: decimal.NewFromInt(10)},
+			},
+			expectError: true,
+		},
+		{
+			name:   "items do not total transaction amount",
+			amount: decimal.NewFromInt(100),
+			items: []models.TransactionItemRequest{
+				{Name: "Steak", Amount: decimal.NewFromInt(40), Members: []int64{1}},
+			},
+			expectError: true,
+		},
+		{
+			name:   "item without members",
+			amount: decimal.NewFromInt(40),
+			items: []models.TransactionItemRequest{
+				{Name: "Steak", Amount: decimal.NewFromInt(40), Members: []int64{}},
+			},
+			expectError: true,
+		},
+		{
+			name:   "item without name",
+			amount: decimal.NewFromInt(40),
+			items: []models.TransactionItemRequest{
+				{Amount: decimal.NewFromInt(40), Members: []int64{1}},
+			},
+			expectError: true,
+		},
+		{
+			name:   "item with zero amount",
+			amount: decimal.NewFromInt(40),
+			items: []models.TransactionItemRequest{
+				{Name: "Steak", Amount: decimal.NewFromInt(40), Members: []int64{1}},
+				{Name: "Water", Amount: decimal.Zero, Members: []int64{1}},
+			},
+			expectError: true,
+		},
+		{
+			name:   "item smaller than a cent per member",
+			amount: decimal.RequireFromString("0.01"),
+			items: []models.TransactionItemRequest{
+				{Name: "Mint", Amount: decimal.RequireFromString("0.01"), Members: []int64{1, 2, 3}},
+			},
+			expectError: true,
+		},
+		{
+			name:   "duplicate member on item",
+			amount: decimal.NewFromInt(40),
+			items: []models.TransactionItemRequest{
+				{Name: "Steak", Amount: decimal.NewFromInt(40), Members: []int64{1, 1}},
+			},
+			expectError: true,
+		},
+		{
+			name:        "no items",
+			amount:      decimal.NewFromInt(40),
+			items:       []models.TransactionItemRequest{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DeriveItemSplits(tt.amount, tt.items, tt.adjustments)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result.Splits)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.Splits, len(tt.expectedMembers))
+			require.Len(t, result.AdjustmentAmounts, len(tt.adjustments))
+			splits := result.Splits
+
+			totalAmount := decimal.Zero
+			totalPercent := decimal.Zero
+			for i, split := range splits {
+				require.NotNil(t, split.SplitUser)
+				assert.Equal(t, tt.expectedMembers[i], *split.SplitUser)
+				expectedAmount := decimal.RequireFromString(tt.expectedAmounts[i])
+				expectedPercent := decimal.RequireFromString(tt.expectedPercents[i])
+				assert.True(t, split.SplitAmount.Equal(expectedAmount), "split[%d] amount: expected %s, got %s", i, expectedAmount, split.SplitAmount)
+				assert.True(t, split.SplitPercent.Equal(expectedPercent), "split[%d] percent: expected %s, got %s", i, expectedPercent, split.SplitPercent)
+				totalAmount = totalAmount.Add(split.SplitAmount)
+				totalPercent = totalPercent.Add(split.SplitPercent)
+			}
+			assert.True(t, totalAmount.Equal(tt.amount), "amounts should total %s, got %s", tt.amount, totalAmount)
+			assert.True(t, totalPercent.Equal(decimal.NewFromInt(1)), "percents should total 1, got %s", totalPercent)
+		})
+	}
+}
+
+func TestResolveAdjustments(t *testing.T) {
+	tests := []struct {
+		name        string
+		subtotal    decimal.Decimal
+		adjustments []models.TransactionAdjustmentRequest
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "fixed and percent",
+			subtotal: decimal.NewFromInt(100),
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Tax", Type: "percent", Value: decimal.RequireFromString("0.0825")},
+				{Name: "Tip", Type: "fixed", Value: decimal.RequireFromString("18.50")},
+			},
+			expected: []string{"8.25", "18.50"},
+		},
+		{
+			name:     "percent rounded to cents",
+			subtotal: decimal.RequireFromString("33.33"),
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Service", Type: "percent", Value: decimal.RequireFromString("0.18")},
+			},
+			expected: []string{"6"},
+		},
+		{
+			name:     "percent discount",
+			subtotal: decimal.NewFromInt(80),
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Coupon", Type: "percent", Value: decimal.RequireFromString("-0.25")},
+			},
+			expected: []string{"-20"},
+		},
+		{
+			name:        "no adjustments",
+			subtotal:    decimal.NewFromInt(80),
+			adjustments: []models.TransactionAdjustmentRequest{},
+			expected:    []string{},
+		},
+		{
+			name:     "fixed value with fractional cents",
+			subtotal: decimal.NewFromInt(80),
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Tip", Type: "fixed", Value: decimal.RequireFromString("1.005")},
+			},
+			expectError: true,
+		},
+		{
+			name:     "percent out of range",
+			subtotal: decimal.NewFromInt(80),
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Name: "Tip", Type: "percent", Value: decimal.NewFromInt(15)},
+			},
+			expectError: true,
+		},
+		{
+			name:     "missing name",
+			subtotal: decimal.NewFromInt(80),
+			adjustments: []models.TransactionAdjustmentRequest{
+				{Type: "fixed", Value: decimal.NewFromInt(5)},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amounts, err := ResolveAdjustments(tt.subtotal, tt.adjustments)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, amounts, len(tt.expected))
+			for i := range amounts {
+				expected := decimal.RequireFromString(tt.expected[i])
+				assert.True(t, amounts[i].Equal(expected), "adjustment[%d]: expected %s, got %s", i, expected, amounts[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ResolvePayers validates the members paying a transaction and returns the by_user member and the payer rows to store.
+// Payer amounts must be positive, have at most 2 decimal places and sum exactly to the transaction amount.
+// by_user must be one of the payers and defaults to the first payer when 0.
+// A single payer is recorded as by_user only, so no payer rows are returned for it.
+func ResolvePayers(transactionAmount decimal.Decimal, byUser int64, payers []models.TransactionPayerRequest) (int64, []models.TransactionPayerRequest, error) {
+	if len(payers) == 0 {
+		return 0, nil, fmt.Errorf("at least one payer is required")
+	}
+
+	total := decimal.Zero
+	seen := make(map[int64]bool, len(payers))
+	for i, payer := range payers {
+		if payer.MemberID == 0 {
+			return 0, nil, fmt.Errorf("payer[%d]: member_id is required", i)
+		}
+		if seen[payer.MemberID] {
+			return 0, nil, fmt.Errorf("payer[%d]: member %d is listed more than once", i, payer.MemberID)
+		}
+		seen[payer.MemberID] = true
+
+		if !payer.Amount.IsPositive() {
+			return 0, nil, fmt.Errorf("payer[%d]: amount must be greater than 0", i)
+		}
+		if !payer.Amount.Equal(payer.Amount.Round(splitAmountPlaces)) {
+			return 0, nil, fmt.Errorf("payer[%d]: amount must have at most %d decimal places", i, splitAmountPlaces)
+		}
+		total = total.Add(payer.Amount)
+	}
+
+	if !total.Equal(transactionAmount) {
+		return 0, nil, fmt.Errorf("payer amounts must sum to transaction amount %s, got %s",
+			transactionAmount.String(), total.String())
+	}
+
+	if byUser == 0 {
+		byUser = payers[0].MemberID
+	} else if !seen[byUser] {
+		return 0, nil, fmt.Errorf("by_user %d must be one of the payers", byUser)
+	}
+
+	if len(payers) == 1 {
+		return byUser, nil, nil
+	}
+	return byUser, payers, nil
+}
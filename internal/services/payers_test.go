@@ -0,0 +1,126 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePayers(t *testing.T) {
+	tests := []struct {
+		name           string
+		amount         decimal.Decimal
+		byUser         int64
+		payers         []models.TransactionPayerRequest
+		expectError    bool
+		expectedByUser int64
+		expectedPayers int
+	}{
+		{
+			name:   "multiple payers default by_user to first payer",
+			amount: decimal.NewFromInt(100),
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 2, Amount: decimal.NewFromInt(60)},
+				{MemberID: 1, Amount: decimal.NewFromInt(40)},
+			},
+			expectedByUser: 2,
+			expectedPayers: 2,
+		},
+		{
+			name:   "by_user is one of the payers",
+			amount: decimal.RequireFromString("100.01"),
+			byUser: 1,
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 2, Amount: decimal.RequireFromString("50.01")},
+				{MemberID: 1, Amount: decimal.NewFromInt(50)},
+			},
+			expectedByUser: 1,
+			expectedPayers: 2,
+		},
+		{
+			name:   "single payer is stored as by_user only",
+			amount: decimal.NewFromInt(100),
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 3, Amount: decimal.NewFromInt(100)},
+			},
+			expectedByUser: 3,
+			expectedPayers: 0,
+		},
+		{
+			name:   "by_user is not a payer",
+			amount: decimal.NewFromInt(100),
+			byUser: 5,
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 1, Amount: decimal.NewFromInt(60)},
+				{MemberID: 2, Amount: decimal.NewFromInt(40)},
+			},
+			expectError: true,
+		},
+		{
+			name:   "payers do not total transaction amount",
+			amount: decimal.NewFromInt(100),
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 1, Amount: decimal.NewFromInt(60)},
+				{MemberID: 2, Amount: decimal.RequireFromString("39.99")},
+			},
+			expectError: true,
+		},
+		{
+			name:   "duplicate payer",
+			amount: decimal.NewFromInt(100),
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 1, Amount: decimal.NewFromInt(60)},
+				{MemberID: 1, Amount: decimal.NewFromInt(40)},
+			},
+			expectError: true,
+		},
+		{
+			name:   "zero payer amount",
+			amount: decimal.NewFromInt(100),
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 1, Amount: decimal.NewFromInt(100)},
+				{MemberID: 2, Amount: decimal.Zero},
+			},
+			expectError: true,
+		},
+		{
+			name:   "payer amount with fractional cents",
+			amount: decimal.NewFromInt(100),
+			payers: []models.TransactionPayerRequest{
+				{MemberID: 1, Amount: decimal.RequireFromString("49.995")},
+				{MemberID: 2, Amount: decimal.RequireFromString("50.005")},
+			},
+			expectError: true,
+		},
+		{
+			name:   "missing member_id",
+			amount: decimal.NewFromInt(100),
+			payers: []models.TransactionPayerRequest{
+				{Amount: decimal.NewFromInt(100)},
+			},
+			expectError: true,
+		},
+		{
+			name:        "no payers",
+			amount:      decimal.NewFromInt(100),
+			payers:      []models.TransactionPayerRequest{},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			byUser, payers, err := ResolvePayers(tt.amount, tt.byUser, tt.payers)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedByUser, byUser)
+			assert.Len(t, payers, tt.expectedPayers)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+)
+
+// DiffTransactionRevisions returns the fields that changed from prev to cur.
+// Transaction fields are listed first, then payers & splits keyed by member ID in ascending order.
+// A payer or split present in only one revision is reported with a null before or after value.
+func DiffTransactionRevisions(prev, cur models.TransactionRevisionSnapshot) []models.RevisionChange {
+	changes := []models.RevisionChange{}
+
+	add := func(field string, before, after interface{}) {
+		changes = append(changes, models.RevisionChange{Field: field, Before: before, After: after})
+	}
+
+	if prev.GroupID != cur.GroupID {
+		add("group_id", prev.GroupID, cur.GroupID)
+	}
+	if prev.Name != cur.Name {
+		add("name", prev.Name, cur.Name)
+	}
+	if !prev.TransactionDate.Equal(cur.TransactionDate) {
+		add("transaction_date", prev.TransactionDate, cur.TransactionDate)
+	}
+	if !prev.Amount.Equal(cur.Amount) {
+		add("amount", prev.Amount, cur.Amount)
+	}
+	if !stringPtrEqual(prev.Category, cur.Category) {
+		add("category", prev.Category, cur.Category)
+	}
+	if !stringPtrEqual(prev.Note, cur.Note) {
+		add("note", prev.Note, cur.Note)
+	}
+	if prev.ByUser != cur.ByUser {
+		add("by_user", prev.ByUser, cur.ByUser)
+	}
+	if prev.Currency != cur.Currency {
+		add("currency", prev.Currency, cur.Currency)
+	}
+	if !prev.ExchangeRate.Equal(cur.ExchangeRate) {
+		add("exchange_rate", prev.ExchangeRate, cur.ExchangeRate)
+	}
+
+	if !stringPtrEqual(prev.SplitMode, cur.SplitMode) {
+		add("split_mode", prev.SplitMode, cur.SplitMode)
+	}
+
+	// Payers, keyed by member
+	prevPayers := make(map[int64]models.TransactionPayerResponse, len(prev.Payers))
+	for _, payer := range prev.Payers {
+		prevPayers[payer.MemberID] = payer
+	}
+	curPayers := make(map[int64]models.TransactionPayerResponse, len(cur.Payers))
+	for _, payer := range cur.Payers {
+		curPayers[payer.MemberID] = payer
+	}
+	for _, memberID := range unionKeys(prevPayers, curPayers) {
+		before, inPrev := prevPayers[memberID]
+		after, inCur := curPayers[memberID]
+		field := fmt.Sprintf("payers.%d", memberID)
+		switch {
+		case !inPrev:
+			add(field, nil, after.Amount)
+		case !inCur:
+			add(field, before.Amount, nil)
+		case !before.Amount.Equal(after.Amount):
+			add(field, before.Amount, after.Amount)
+		}
+	}
+
+	// Splits, keyed by member, splits without a member can't be matched and are keyed as 0
+	prevSplits := make(map[int64]models.RevisionSplit, len(prev.Splits))
+	for _, split := range prev.Splits {
+		prevSplits[splitMemberKey(split)] = split
+	}
+	curSplits := make(map[int64]models.RevisionSplit, len(cur.Splits))
+	for _, split := range cur.Splits {
+		curSplits[splitMemberKey(split)] = split
+	}
+	for _, memberID := range unionKeys(prevSplits, curSplits) {
+		before, inPrev := prevSplits[memberID]
+		after, inCur := curSplits[memberID]
+		field := fmt.Sprintf("splits.%d", memberID)
+		switch {
+		case !inPrev:
+			add(field, nil, after)
+		case !inCur:
+			add(field, before, nil)
+		default:
+			if !before.SplitPercent.Equal(after.SplitPercent) {
+				add(field+".split_percent", before.SplitPercent, after.SplitPercent)
+			}
+			if !before.SplitAmount.Equal(after.SplitAmount) {
+				add(field+".split_amount", before.SplitAmount, after.SplitAmount)
+			}
+		}
+	}
+
+	return changes
+}
+
+func stringPtrEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func splitMemberKey(split models.RevisionSplit) int64 {
+	if split.SplitUser == nil {
+		return 0
+	}
+	return *split.SplitUser
+}
+
+// unionKeys returns the member IDs in either map in ascending order
+func unionKeys[V any](a, b map[int64]V) []int64 {
+	keys := make([]int64, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTransactionRevisions(t *testing.T) {
+	member1, member2, member3 := int64(1), int64(2), int64(3)
+	category := "Food"
+
+	base := models.TransactionRevisionSnapshot{
+		GroupID:         1,
+		Name:            "Dinner",
+		TransactionDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Amount:          decimal.NewFromInt(90),
+		ByUser:          1,
+		Currency:        "USD",
+		ExchangeRate:    decimal.NewFromInt(1),
+		Splits: []models.RevisionSplit{
+			{SplitUser: &member1, SplitPercent: decimal.RequireFromString("0.5"), SplitAmount: decimal.NewFromInt(45)},
+			{SplitUser: &member2, SplitPercent: decimal.RequireFromString("0.5"), SplitAmount: decimal.NewFromInt(45)},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		modify         func(s *models.TransactionRevisionSnapshot)
+		expectedFields []string
+	}{
+		{
+			name:           "no changes",
+			modify:         func(s *models.TransactionRevisionSnapshot) {},
+			expectedFields: []string{},
+		},
+		{
+			name: "transaction fields changed",
+			modify: func(s *models.TransactionRevisionSnapshot) {
+				s.Name = "Lunch"
+				s.Category = &category
+			},
+			expectedFields: []string{"name", "category"},
+		},
+		{
+			name: "equal amounts with different scale are unchanged",
+			modify: func(s *models.TransactionRevisionSnapshot) {
+				s.Amount = decimal.RequireFromString("90.00")
+			},
+			expectedFields: []string{},
+		},
+		{
+			name: "split amounts changed",
+			modify: func(s *models.TransactionRevisionSnapshot) {
+				s.Amount = decimal.NewFromInt(100)
+				s.Splits = []models.RevisionSplit{
+					{SplitUser: &member1, SplitPercent: decimal.RequireFromString("0.5"), SplitAmount: decimal.NewFromInt(50)},
+					{SplitUser: &member2, SplitPercent: decimal.RequireFromString("0.5"), SplitAmount: decimal.NewFromInt(50)},
+				}
+			},
+			expectedFields: []string{"amount", "splits.1.split_amount", "splits.2.split_amount"},
+		},
+		{
+			name: "split member replaced",
+			modify: func(s *models.TransactionRevisionSnapshot) {
+				s.Splits = []models.RevisionSplit{
+					{SplitUser: &member1, SplitPercent: decimal.RequireFromString("0.5"), SplitAmount: decimal.NewFromInt(45)},
+					{SplitUser: &member3, SplitPercent: decimal.RequireFromString("0.5"), SplitAmount: decimal.NewFromInt(45)},
+				}
+			},
+			expectedFields: []string{"splits.2", "splits.3"},
+		},
+		{
+			name: "payers added",
+			modify: func(s *models.TransactionRevisionSnapshot) {
+				s.Payers = []models.TransactionPayerResponse{
+					{MemberID: 2, Amount: decimal.NewFromInt(40)},
+					{MemberID: 1, Amount: decimal.NewFromInt(50)},
+				}
+			},
+			expectedFields: []string{"payers.1", "payers.2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur := base
+			cur.Splits = append([]models.RevisionSplit(nil), base.Splits...)
+			tt.modify(&cur)
+
+			changes := DiffTransactionRevisions(base, cur)
+
+			fields := make([]string, len(changes))
+			for i, change := range changes {
+				fields[i] = change.Field
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
+
+func TestDiffTransactionRevisionsAddedAndRemoved(t *testing.T) {
+	member1, member2 := int64(1), int64(2)
+	split1 := models.RevisionSplit{SplitUser: &member1, SplitPercent: decimal.NewFromInt(1), SplitAmount: decimal.NewFromInt(10)}
+	split2 := models.RevisionSplit{SplitUser: &member2, SplitPercent: decimal.NewFromInt(1), SplitAmount: decimal.NewFromInt(10)}
+
+	prev := models.TransactionRevisionSnapshot{Splits: []models.RevisionSplit{split1}}
+	cur := models.TransactionRevisionSnapshot{Splits: []models.RevisionSplit{split2}}
+
+	changes := DiffTransactionRevisions(prev, cur)
+
+	assert.Equal(t, []models.RevisionChange{
+		{Field: "splits.1", Before: split1, After: nil},
+		{Field: "splits.2", Before: nil, After: split2},
+	}, changes)
+}
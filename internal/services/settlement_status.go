@@ -0,0 +1,31 @@
+package services
+
+// SettlementStatus is the confirmation state of a settlement, only confirmed settlements count towards balances
+type SettlementStatus string
+
+const (
+	SettlementStatusPending   SettlementStatus = "pending"   // Recorded by the payer, waiting for the recipient
+	SettlementStatusConfirmed SettlementStatus = "confirmed" // Recipient confirmed the payment was received
+	SettlementStatusDisputed  SettlementStatus = "disputed"  // Recipient says the payment was not received
+	SettlementStatusCancelled SettlementStatus = "cancelled" // Withdrawn, never counts towards balances
+)
+
+// settlementTransitions lists the statuses a settlement can move to from each status
+// Confirmed and cancelled settlements are final
+var settlementTransitions = map[SettlementStatus][]SettlementStatus{
+	SettlementStatusPending:  {SettlementStatusConfirmed, SettlementStatusDisputed, SettlementStatusCancelled},
+	SettlementStatusDisputed: {SettlementStatusConfirmed, SettlementStatusCancelled},
+}
+
+// SettlementStatusesBefore returns the statuses a settlement can move to status from, in a fixed order
+func SettlementStatusesBefore(status SettlementStatus) []string {
+	statuses := []string{}
+	for _, from := range []SettlementStatus{SettlementStatusPending, SettlementStatusConfirmed, SettlementStatusDisputed, SettlementStatusCancelled} {
+		for _, to := range settlementTransitions[from] {
+			if to == status {
+				statuses = append(statuses, string(from))
+			}
+		}
+	}
+	return statuses
+}
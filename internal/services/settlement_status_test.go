@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettlementStatusesBefore(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   SettlementStatus
+		expected []string
+	}{
+		{
+			name:     "confirm pending or disputed",
+			status:   SettlementStatusConfirmed,
+			expected: []string{"pending", "disputed"},
+		},
+		{
+			name:     "dispute pending only",
+			status:   SettlementStatusDisputed,
+			expected: []string{"pending"},
+		},
+		{
+			name:     "cancel pending or disputed",
+			status:   SettlementStatusCancelled,
+			expected: []string{"pending", "disputed"},
+		},
+		{
+			name:     "nothing moves back to pending",
+			status:   SettlementStatusPending,
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SettlementStatusesBefore(tt.status))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/MattSharp0/transaction-split-go/internal/logger"
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// SplitMode determines which split fields the client provides and which are calculated by the server
+type SplitMode string
+
+const (
+	SplitModeEqual   SplitMode = "equal"   // Transaction amount divided evenly, only split_user required
+	SplitModeShares  SplitMode = "shares"  // Transaction amount divided by weight, shares required
+	SplitModeExact   SplitMode = "exact"   // split_amount required, split_percent calculated
+	SplitModePercent SplitMode = "percent" // split_percent required, split_amount calculated
+)
+
+const (
+	splitAmountPlaces  int32 = 2 // splits.split_amount numeric(10,2)
+	splitPercentPlaces int32 = 6 // splits.split_percent decimal(7,6)
+)
+
+// ParseSplitMode validates a split mode string from an API request
+func ParseSplitMode(mode string) (SplitMode, error) {
+	switch SplitMode(mode) {
+	case SplitModeEqual, SplitModeShares, SplitModeExact, SplitModePercent:
+		return SplitMode(mode), nil
+	}
+	return "", fmt.Errorf("invalid split mode %q, must be one of: equal, shares, exact, percent", mode)
+}
+
+// CalculateSplits fills in the split_percent and split_amount of each split based on the split mode.
+// Only the inputs required by the mode are read, any other values on the request are overwritten.
+// Rounding remainders are distributed with Allocate so percentages total 1.0 and amounts total the transaction amount.
+func CalculateSplits(mode SplitMode, transactionAmount decimal.Decimal, splits []models.CreateSplitRequest) ([]models.CreateSplitRequest, error) {
+	if len(splits) == 0 {
+		return nil, fmt.Errorf("at least one split is required")
+	}
+	if !transactionAmount.IsPositive() {
+		return nil, fmt.Errorf("transaction amount must be greater than 0 to calculate splits")
+	}
+
+	result := make([]models.CreateSplitRequest, len(splits))
+	copy(result, splits)
+
+	switch mode {
+	case SplitModeEqual:
+		weights := make([]decimal.Decimal, len(result))
+		for i := range weights {
+			weights[i] = decimal.NewFromInt(1)
+		}
+		if err := applyWeights(result, weights, transactionAmount); err != nil {
+			return nil, err
+		}
+
+	case SplitModeShares:
+		weights := make([]decimal.Decimal, len(result))
+		for i, split := range result {
+			if !split.Shares.IsPositive() {
+				return nil, fmt.Errorf("split[%d]: shares must be greater than 0", i)
+			}
+			weights[i] = split.Shares
+		}
+		if err := applyWeights(result, weights, transactionAmount); err != nil {
+			return nil, err
+		}
+
+	case SplitModeExact:
+		totalAmount := decimal.Zero
+		for i, split := range result {
+			if !split.SplitAmount.IsPositive() {
+				return nil, fmt.Errorf("split[%d]: split_amount must be greater than 0", i)
+			}
+			totalAmount = totalAmount.Add(split.SplitAmount)
+		}
+		if !totalAmount.Equal(transactionAmount) {
+			return nil, fmt.Errorf("split amounts must sum to transaction amount %s, got %s",
+				transactionAmount.String(), totalAmount.String())
+		}
+		amounts := make([]decimal.Decimal, len(result))
+		for i, split := range result {
+			amounts[i] = split.SplitAmount
+		}
+		percents, err := Allocate(decimal.NewFromInt(1), amounts, splitPercentPlaces)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result {
+			result[i].SplitPercent = percents[i]
+		}
+
+	case SplitModePercent:
+		totalPercent := decimal.Zero
+		for i, split := range result {
+			if !split.SplitPercent.IsPositive() || split.SplitPercent.GreaterThan(decimal.NewFromInt(1)) {
+				return nil, fmt.Errorf("split[%d]: split_percent must be greater than 0.0 and at most 1.0", i)
+			}
+			totalPercent = totalPercent.Add(split.SplitPercent)
+		}
+		if !totalPercent.Equal(decimal.NewFromInt(1)) {
+			return nil, fmt.Errorf("split percentages must sum to 1.0 (100%%), got %s", totalPercent.String())
+		}
+		percents := make([]decimal.Decimal, len(result))
+		for i, split := range result {
+			percents[i] = split.SplitPercent
+		}
+		amounts, err := Allocate(transactionAmount, percents, splitAmountPlaces)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result {
+			result[i].SplitAmount = amounts[i]
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported split mode %q", mode)
+	}
+
+	logger.Debug("Calculated splits", "mode", mode, "split_count", len(result), "transaction_amount", transactionAmount)
+	return result, nil
+}
+
+// RescaleSplitAmounts re-allocates a new transaction amount using each split's split_percent as its weight.
+// Used when a transaction's amount changes, the split percentages are kept and the amounts are
+// rounded with Allocate so they sum exactly to the new amount.
+func RescaleSplitAmounts(transactionAmount decimal.Decimal, splitPercents []decimal.Decimal) ([]decimal.Decimal, error) {
+	if !transactionAmount.IsPositive() {
+		return nil, fmt.Errorf("transaction amount must be greater than 0 to rescale splits")
+	}
+	return Allocate(transactionAmount, splitPercents, splitAmountPlaces)
+}
+
+// applyWeights sets split_percent and split_amount on each split proportional to its weight
+func applyWeights(splits []models.CreateSplitRequest, weights []decimal.Decimal, transactionAmount decimal.Decimal) error {
+	percents, err := Allocate(decimal.NewFromInt(1), weights, splitPercentPlaces)
+	if err != nil {
+		return err
+	}
+	amounts, err := Allocate(transactionAmount, weights, splitAmountPlaces)
+	if err != nil {
+		return err
+	}
+	for i := range splits {
+		splits[i].SplitPercent = percents[i]
+		splits[i].SplitAmount = amounts[i]
+	}
+	return nil
+}
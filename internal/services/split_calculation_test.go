@@ -0,0 +1,242 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/MattSharp0/transaction-split-go/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSplitMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		expected    SplitMode
+		expectError bool
+	}{
+		{name: "equal", mode: "equal", expected: SplitModeEqual},
+		{name: "shares", mode: "shares", expected: SplitModeShares},
+		{name: "exact", mode: "exact", expected: SplitModeExact},
+		{name: "percent", mode: "percent", expected: SplitModePercent},
+		{name: "unknown mode", mode: "weights", expectError: true},
+		{name: "empty mode", mode: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, err := ParseSplitMode(tt.mode)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mode)
+		})
+	}
+}
+
+func TestCalculateSplits(t *testing.T) {
+	user := func(id int64) *int64 { return &id }
+
+	tests := []struct {
+		name             string
+		mode             SplitMode
+		amount           decimal.Decimal
+		splits           []models.CreateSplitRequest
+		expectError      bool
+		expectedAmounts  []string
+		expectedPercents []string
+	}{
+		{
+			name:   "equal split two ways",
+			mode:   SplitModeEqual,
+			amount: decimal.NewFromInt(100),
+			splits: []models.CreateSplitRequest{
+				{SplitUser: user(1)},
+				{SplitUser: user(2)},
+			},
+			expectedAmounts:  []string{"50", "50"},
+			expectedPercents: []string{"0.5", "0.5"},
+		},
+		{
+			name:   "equal split three ways",
+			mode:   SplitModeEqual,
+			amount: decimal.NewFromInt(100),
+			splits: []models.CreateSplitRequest{
+				{SplitUser: user(1)},
+				{SplitUser: user(2)},
+				{SplitUser: user(3)},
+			},
+			expectedAmounts:  []string{"33.34", "33.33", "33.33"},
+			expectedPercents: []string{"0.333334", "0.333333", "0.333333"},
+		},
+		{
+			name:   "shares split",
+			mode:   SplitModeShares,
+			amount: decimal.NewFromInt(90),
+			splits: []models.CreateSplitRequest{
+				{SplitUser: user(1), Shares: decimal.NewFromInt(2)},
+				{SplitUser: user(2), Shares: decimal.NewFromInt(1)},
+			},
+			expectedAmounts:  []string{"60", "30"},
+			expectedPercents: []string{"0.666667", "0.333333"},
+		},
+		{
+			name:   "shares split missing shares",
+			mode:   SplitModeShares,
+			amount: decimal.NewFromInt(90),
+			splits: []models.CreateSplitRequest{
+				{SplitUser: user(1), Shares: decimal.NewFromInt(2)},
+				{SplitUser: user(2)},
+			},
+			expectError: true,
+		},
+		{
+			name:   "exact split",
+			mode:   SplitModeExact,
+			amount: decimal.NewFromFloat(125.50),
+			splits: []models.CreateSplitRequest{
+				{SplitUser: user(1), SplitAmount: decimal.NewFromFloat(100.40)},
+				{SplitUser: user(2), SplitAmount: decimal.NewFromFloat(25.10)},
+			},
+			expectedAmounts:  []string{"100.4", "25.1"},
+			expectedPercents: []string{"0.8", "0.2"},
+		},
+		{
+			name:   "exact split does not total transaction amount",
+			mode:   SplitModeExact,
+			amount: decimal.NewFromInt(100),
+			splits: []models.CreateSplitRequest{
+				{SplitUser: user(1), SplitAmount: decimal.NewFromInt(60)},
+				{SplitUser: user(2), SplitAmount: decimal.NewFromInt(30)},
+			},
+			expectError: true,
+		},
+		{
+			name:   "percent split",
+			mode:   SplitModePercent,
+			amount: decimal.NewFromInt(200),
+			splits: []models.CreateSplitRequest{
+				{SplitUser: user(1), SplitPercent: decimal.NewFromFloat(0.75)},
+				{SplitUser: user(2), SplitPercent: decimal.NewFromFloat(0.25)},
+			},
+			expectedAmounts:  []string{"150", "50"},
+			expectedPercents: []string{"0.75", "0.25"},
+		},
+		{
+			name:   "percent split does not total 100%",
+			mode:   SplitModePercent,
+			amount: decimal.NewFromInt(200),
+			splits: []models.CreateSplitRequest{
+				{SplitUser: user(1), SplitPercent: decimal.NewFromFloat(0.5)},
+				{SplitUser: user(2), SplitPercent: decimal.NewFromFloat(0.25)},
+			},
+			expectError: true,
+		},
+		{
+			name:        "no splits",
+			mode:        SplitModeEqual,
+			amount:      decimal.NewFromInt(100),
+			splits:      []models.CreateSplitRequest{},
+			expectError: true,
+		},
+		{
+			name:   "zero transaction amount",
+			mode:   SplitModeEqual,
+			amount: decimal.Zero,
+			splits: []models.CreateSplitRequest{
+				{SplitUser: user(1)},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splits, err := CalculateSplits(tt.mode, tt.amount, tt.splits)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, splits)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, splits, len(tt.splits))
+
+			totalAmount := decimal.Zero
+			totalPercent := decimal.Zero
+			for i, split := range splits {
+				expectedAmount := decimal.RequireFromString(tt.expectedAmounts[i])
+				expectedPercent := decimal.RequireFromString(tt.expectedPercents[i])
+				assert.True(t, split.SplitAmount.Equal(expectedAmount), "split[%d] amount: expected %s, got %s", i, expectedAmount, split.SplitAmount)
+				assert.True(t, split.SplitPercent.Equal(expectedPercent), "split[%d] percent: expected %s, got %s", i, expectedPercent, split.SplitPercent)
+				assert.Equal(t, tt.splits[i].SplitUser, split.SplitUser)
+				totalAmount = totalAmount.Add(split.SplitAmount)
+				totalPercent = totalPercent.Add(split.SplitPercent)
+			}
+			assert.True(t, totalAmount.Equal(tt.amount), "amounts should total %s, got %s", tt.amount, totalAmount)
+			assert.True(t, totalPercent.Equal(decimal.NewFromInt(1)), "percents should total 1, got %s", totalPercent)
+		})
+	}
+}
+
+func TestRescaleSplitAmounts(t *testing.T) {
+	tests := []struct {
+		name            string
+		amount          decimal.Decimal
+		percents        []string
+		expectError     bool
+		expectedAmounts []string
+	}{
+		{
+			name:            "half and half",
+			amount:          decimal.NewFromInt(120),
+			percents:        []string{"0.5", "0.5"},
+			expectedAmounts: []string{"60", "60"},
+		},
+		{
+			name:            "equal thirds give the leftover penny to the largest percent",
+			amount:          decimal.NewFromInt(100),
+			percents:        []string{"0.333333", "0.333334", "0.333333"},
+			expectedAmounts: []string{"33.33", "33.34", "33.33"},
+		},
+		{
+			name:            "uneven percents",
+			amount:          decimal.RequireFromString("45.67"),
+			percents:        []string{"0.25", "0.75"},
+			expectedAmounts: []string{"11.42", "34.25"},
+		},
+		{
+			name:        "zero amount",
+			amount:      decimal.Zero,
+			percents:    []string{"0.5", "0.5"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percents := make([]decimal.Decimal, len(tt.percents))
+			for i, percent := range tt.percents {
+				percents[i] = decimal.RequireFromString(percent)
+			}
+
+			amounts, err := RescaleSplitAmounts(tt.amount, percents)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, amounts, len(tt.expectedAmounts))
+
+			total := decimal.Zero
+			for i, amount := range amounts {
+				expected := decimal.RequireFromString(tt.expectedAmounts[i])
+				assert.True(t, amount.Equal(expected), "split[%d] amount: expected %s, got %s", i, expected, amount)
+				total = total.Add(amount)
+			}
+			assert.True(t, total.Equal(tt.amount), "amounts should total %s, got %s", tt.amount, total)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+/*
+audit event queries
+Table structure:
+CREATE TABLE "audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "group_id" bigint,
+  "actor_user_id" bigint,
+  "entity" varchar NOT NULL, -- group, group_member, transaction or split
+  "entity_id" bigint NOT NULL,
+  "action" varchar NOT NULL, -- create, update, delete, trash, restore or remove
+  "before" jsonb,
+  "after" jsonb,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+Events are written by triggers, see 019_audit_events.up.sql
+*/
+
+-- name: SetAuditActor :exec
+-- Sets the user recorded on audit events for the rest of the DB transaction
+SELECT set_config('audit.actor_user_id', @actor_user_id::bigint::text, true);
+
+-- name: ListAuditEventsByGroupID :many
+-- Newest events first
+SELECT 
+  ae.*,
+  u.name AS actor_name
+FROM audit_events ae
+LEFT JOIN users u ON ae.actor_user_id = u.id
+WHERE ae.group_id = $1
+ORDER BY ae.id desc
+LIMIT $2
+OFFSET $3;
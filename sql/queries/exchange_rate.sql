@@ -0,0 +1,30 @@
+/*
+Exchange rate queries
+Table structure:
+from_currency varchar(3) NOT NULL,
+to_currency varchar(3) NOT NULL,
+rate_date date NOT NULL,
+rate numeric(18,8) NOT NULL,
+created_at timestamptz NOT NULL DEFAULT (now()),
+modified_at timestamptz NOT NULL DEFAULT (now()),
+PRIMARY KEY (from_currency, to_currency, rate_date)
+*/
+
+-- name: GetExchangeRate :one
+-- Returns the most recent rate on or before rate_date
+SELECT
+    *
+FROM exchange_rates
+WHERE
+    from_currency = $1
+    AND to_currency = $2
+    AND rate_date <= @rate_date::date
+ORDER BY rate_date desc
+LIMIT 1;
+
+-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (from_currency, to_currency, rate_date, rate)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (from_currency, to_currency, rate_date)
+DO UPDATE SET rate = EXCLUDED.rate
+RETURNING *;
@@ -2,25 +2,27 @@
 Group queries
 Table structure:
 id bigserial PRIMARY KEY,
-name varchar NOT NULL
+name varchar NOT NULL,
+currency varchar(3) NOT NULL DEFAULT 'USD',
+deleted_at timestamptz
 */
 
 -- name: CreateGroup :one
-INSERT INTO "groups" (name)
-VALUES ($1)
+INSERT INTO "groups" (name, currency)
+VALUES ($1, $2)
 RETURNING *;
 
 -- name: GetGroupByID :one
 SELECT 
   *
 FROM "groups"
-WHERE id = $1 LIMIT 1;  
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;  
 
 -- name: GetGroupByIDForUpdate :one
 SELECT 
   *
 FROM "groups"
-WHERE id = $1 
+WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1
 FOR UPDATE;
 
@@ -28,6 +30,7 @@ FOR UPDATE;
 SELECT 
   *
 FROM "groups"
+WHERE deleted_at IS NULL
 ORDER BY name
 LIMIT $1
 OFFSET $2;
@@ -37,7 +40,7 @@ SELECT
   g.*
 FROM "groups" g
 INNER JOIN group_members gm ON g.id = gm.group_id
-WHERE gm.user_id = $1
+WHERE gm.user_id = $1 AND gm.removed_at IS NULL AND g.deleted_at IS NULL
 ORDER BY g.name
 LIMIT $2
 OFFSET $3;
@@ -49,6 +52,39 @@ WHERE id = $2
 RETURNING *;    
 
 -- name: DeleteGroup :one
+-- Moves a group to the trash, it is purged after the retention period
+UPDATE "groups"
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetDeletedGroupByIDForOwner :one
+-- Returns a group in the trash, only if the user owns it
+SELECT 
+  g.*
+FROM "groups" g
+INNER JOIN group_members gm ON g.id = gm.group_id
+WHERE g.id = $1 AND g.deleted_at IS NOT NULL
+  AND gm.user_id = $2 AND gm.role = 'owner' AND gm.removed_at IS NULL
+LIMIT 1;
+
+-- name: ListDeletedGroupsByOwner :many
+SELECT 
+  g.*
+FROM "groups" g
+INNER JOIN group_members gm ON g.id = gm.group_id
+WHERE gm.user_id = $1 AND gm.role = 'owner' AND gm.removed_at IS NULL AND g.deleted_at IS NOT NULL
+ORDER BY g.deleted_at desc
+LIMIT $2
+OFFSET $3;
+
+-- name: RestoreGroup :one
+UPDATE "groups"
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedGroups :execrows
+-- Permanently deletes groups in the trash since before the given time, with their members, transactions & settlements
 DELETE FROM "groups"
-WHERE id = $1
-RETURNING *;
\ No newline at end of file
+WHERE deleted_at < @deleted_before::timestamptz;
\ No newline at end of file
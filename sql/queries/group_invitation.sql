@@ -0,0 +1,63 @@
+/*
+group invitation queries
+Table structure:
+CREATE TABLE "group_invitations" (
+  "id" bigserial PRIMARY KEY,
+  "group_id" bigint NOT NULL,
+  "token_hash" varchar NOT NULL,
+  "email" varchar,
+  "role" varchar NOT NULL DEFAULT 'member', -- admin, member or viewer
+  "single_use" boolean NOT NULL DEFAULT true,
+  "expires_at" timestamptz,
+  "use_count" integer NOT NULL DEFAULT 0,
+  "created_by" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "revoked_at" timestamptz,
+  "group_member_id" bigint -- placeholder member the invitation claims
+);
+*/
+
+-- name: CreateGroupInvitation :one
+INSERT INTO "group_invitations" (group_id, token_hash, email, role, single_use, expires_at, created_by, group_member_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetGroupInvitationByID :one
+SELECT
+    *
+FROM "group_invitations"
+WHERE id = $1 LIMIT 1;
+
+-- name: GetGroupInvitationByTokenHash :one
+SELECT
+    *
+FROM "group_invitations"
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: GetGroupInvitationByTokenHashForUpdate :one
+SELECT
+    *
+FROM "group_invitations"
+WHERE token_hash = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListGroupInvitationsByGroupID :many
+SELECT
+    *
+FROM "group_invitations"
+WHERE group_id = $1
+ORDER BY created_at desc, id desc
+LIMIT $2
+OFFSET $3;
+
+-- name: RevokeGroupInvitation :one
+UPDATE "group_invitations"
+SET revoked_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UseGroupInvitation :one
+UPDATE "group_invitations"
+SET use_count = use_count + 1
+WHERE id = $1
+RETURNING *;
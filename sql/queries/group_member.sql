@@ -1,6 +1,6 @@
 -- name: CreateGroupMember :one
-INSERT INTO group_members (group_id, user_id)
-VALUES ($1, $2)
+INSERT INTO group_members (group_id, member_name, user_id, joined_on, left_on, role)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: GetGroupMemberByID :one
@@ -10,40 +10,58 @@ SELECT
   u.name AS user_name
 FROM group_members gm
 JOIN groups g ON gm.group_id = g.id 
-JOIN users u ON gm.user_id = u.id
+LEFT JOIN users u ON gm.user_id = u.id
 WHERE gm.id = $1 LIMIT 1;
 
 -- name: ListGroupMembersByGroupID :many
+-- Removed members and members of deleted groups are not listed
 SELECT 
   gm.*,
   g.name AS group_name,
   u.name AS user_name
 FROM group_members gm
 JOIN groups g ON gm.group_id = g.id
-JOIN users u ON gm.user_id = u.id
-WHERE gm.group_id = $1
+LEFT JOIN users u ON gm.user_id = u.id
+WHERE gm.group_id = $1 AND gm.removed_at IS NULL AND g.deleted_at IS NULL
 ORDER BY gm.id
 LIMIT $2
 OFFSET $3;
 
 -- name: UpdateGroupMember :one
 UPDATE group_members
-SET group_id = $1, user_id = $2
-WHERE id = $3
+SET group_id = $1, user_id = $2, joined_on = $3, left_on = $4
+WHERE id = $5
 RETURNING *;    
 
--- name: UnlinkGroupMember :one
+-- name: UpdateGroupMemberRole :one
 UPDATE group_members
-SET user_id = NULL
+SET role = $2
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteGroupMember :one
-DELETE FROM group_members
-WHERE id = $1
+-- name: ClaimGroupMember :one
+-- Links a user to a placeholder member, only if no user has claimed it yet and it hasn't been removed
+UPDATE group_members
+SET user_id = $2, role = $3
+WHERE id = $1 AND user_id IS NULL AND removed_at IS NULL
+RETURNING *;
+
+-- name: RemoveGroupMember :one
+-- Marks a member as removed, their transactions, splits & settlements are kept
+UPDATE group_members
+SET removed_at = now()
+WHERE id = $1 AND removed_at IS NULL
+RETURNING *;
+
+-- name: RemoveGroupMembersByGroupID :many
+-- The owner is kept, a group always has an owner
+UPDATE group_members
+SET removed_at = now()
+WHERE group_id = $1 AND removed_at IS NULL AND role <> 'owner'
 RETURNING *;
 
--- name: DeleteGroupMembersByGroupID :many
+-- name: DeleteGroupMember :one
+-- Only members without transactions, splits or settlements can be deleted
 DELETE FROM group_members
-WHERE group_id = $1
+WHERE id = $1
 RETURNING *;
@@ -0,0 +1,38 @@
+/*
+one time token queries
+Table structure:
+CREATE TABLE "one_time_tokens" (
+  "id" bigserial PRIMARY KEY,
+  "purpose" varchar NOT NULL,
+  "token_hash" varchar NOT NULL,
+  "user_id" bigint NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "used_at" timestamptz,
+  CONSTRAINT one_time_tokens_purpose_check CHECK ("purpose" IN ('password_reset', 'magic_link')),
+  CONSTRAINT one_time_tokens_user_id_fkey FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+*/
+
+-- name: CreateOneTimeToken :one
+INSERT INTO "one_time_tokens" (purpose, token_hash, user_id, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetOneTimeTokenByHashForUpdate :one
+SELECT * FROM "one_time_tokens"
+WHERE token_hash = $1 AND purpose = $2
+LIMIT 1
+FOR UPDATE;
+
+-- name: InvalidateUserOneTimeTokens :exec
+-- Uses up every unused token of a purpose for a user, so only the newest token or none works
+UPDATE "one_time_tokens"
+SET used_at = now()
+WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL;
+
+-- name: UseOneTimeToken :one
+UPDATE "one_time_tokens"
+SET used_at = now()
+WHERE id = $1 AND used_at IS NULL
+RETURNING *;
@@ -0,0 +1,68 @@
+/*
+settlement queries
+Table structure:
+CREATE TABLE "settlements" (
+  "id" bigserial PRIMARY KEY,
+  "group_id" bigint NOT NULL,
+  "from_member" bigint NOT NULL,
+  "to_member" bigint NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "settled_on" date NOT NULL DEFAULT (CURRENT_DATE),
+  "note" varchar,
+  "created_by" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "modified_at" timestamptz NOT NULL DEFAULT (now()),
+  "status" varchar NOT NULL DEFAULT 'pending' -- pending, confirmed, disputed or cancelled
+);
+*/
+
+-- name: CreateSettlement :one
+INSERT INTO "settlements" (group_id, from_member, to_member, amount, settled_on, note, created_by, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetSettlementByID :one
+SELECT
+    *
+FROM "settlements"
+WHERE id = $1 LIMIT 1;
+
+-- name: GetSettlementByIDForUpdate :one
+SELECT
+    *
+FROM "settlements"
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListSettlementsByGroupID :many
+SELECT
+    *
+FROM "settlements"
+WHERE group_id = $1
+ORDER BY settled_on desc, id desc
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateSettlementStatus :one
+UPDATE "settlements"
+SET status = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSettlement :one
+DELETE FROM "settlements"
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSettlementsBetweenMembers :exec
+-- Deletes settlements between two members in either direction
+DELETE FROM "settlements"
+WHERE (from_member = @member_id::bigint AND to_member = @other_member_id::bigint)
+   OR (from_member = @other_member_id::bigint AND to_member = @member_id::bigint);
+
+-- name: ReassignMemberSettlements :exec
+-- Moves every settlement paid or received by a group member to another member
+UPDATE "settlements"
+SET from_member = CASE WHEN from_member = @from_member_id::bigint THEN @into_member_id::bigint ELSE from_member END,
+    to_member = CASE WHEN to_member = @from_member_id::bigint THEN @into_member_id::bigint ELSE to_member END
+WHERE from_member = @from_member_id::bigint OR to_member = @from_member_id::bigint;
@@ -59,7 +59,8 @@ FOR UPDATE;
 SELECT s.* FROM "splits" s
 INNER JOIN transactions t ON s.transaction_id = t.id
 INNER JOIN group_members gm ON t.group_id = gm.group_id
-WHERE s.split_user = $1 AND gm.user_id = $2
+INNER JOIN "groups" g ON t.group_id = g.id
+WHERE s.split_user = $1 AND gm.user_id = $2 AND gm.removed_at IS NULL AND t.deleted_at IS NULL AND g.deleted_at IS NULL
 ORDER BY s.created_at desc
 LIMIT $3
 OFFSET $4;
@@ -78,7 +79,8 @@ SELECT
 FROM "splits" s
 INNER JOIN transactions t ON s.transaction_id = t.id
 INNER JOIN group_members gm ON t.group_id = gm.group_id
-WHERE gm.user_id = $1
+INNER JOIN "groups" g ON t.group_id = g.id
+WHERE gm.user_id = $1 AND gm.removed_at IS NULL AND t.deleted_at IS NULL AND g.deleted_at IS NULL
 ORDER BY s.transaction_id, s.created_at desc
 LIMIT $2
 OFFSET $3;
@@ -96,6 +98,13 @@ SET split_percent = $2, split_amount = $3, split_user = $4
 WHERE id = $1
 RETURNING *;
 
+-- name: RescaleSplit :one
+-- Sets a split's amount for a new transaction amount, split_percent is kept
+UPDATE "splits"
+SET tx_amount = $3, split_amount = $4
+WHERE id = $1 AND transaction_id = $2
+RETURNING *;
+
 -- name: DeleteSplit :one
 DELETE FROM "splits" 
 WHERE id = $1
@@ -104,4 +113,25 @@ RETURNING *;
 -- name: DeleteTransactionSplits :many
 DELETE from "splits"
 WHERE transaction_id = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: MergeMemberSplits :exec
+-- Adds a member's splits to another member's splits of the same transactions
+UPDATE splits s
+SET split_amount = s.split_amount + f.split_amount, split_percent = s.split_percent + f.split_percent
+FROM splits f
+WHERE f.transaction_id = s.transaction_id
+  AND s.split_user = @into_member_id::bigint
+  AND f.split_user = @from_member_id::bigint;
+
+-- name: DeleteMergedMemberSplits :exec
+-- Deletes a member's splits of transactions the other member is also split into, run after MergeMemberSplits
+DELETE FROM splits f
+WHERE f.split_user = @from_member_id::bigint
+  AND EXISTS (SELECT 1 FROM splits s WHERE s.transaction_id = f.transaction_id AND s.split_user = @into_member_id::bigint);
+
+-- name: ReassignMemberSplits :exec
+-- Moves every split of a group member to another member
+UPDATE splits
+SET split_user = @into_member_id::bigint
+WHERE split_user = @from_member_id::bigint;
@@ -9,13 +9,22 @@ CREATE TABLE "refresh_tokens" (
   "created_at" timestamptz NOT NULL DEFAULT (now()),
   "revoked_at" timestamptz,
   "device_info" varchar,
+  "family_id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "ip_address" varchar,
+  "last_used_at" timestamptz NOT NULL DEFAULT (now()),
   CONSTRAINT refresh_tokens_user_id_fkey FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
 );
 */
 
 -- name: CreateRefreshToken :one
-INSERT INTO "refresh_tokens" (token_hash, user_id, expires_at, device_info)
-VALUES ($1, $2, $3, $4)
+INSERT INTO "refresh_tokens" (token_hash, user_id, expires_at, device_info, ip_address)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CreateRotatedRefreshToken :one
+-- Creates the token replacing a rotated token, in the same family
+INSERT INTO "refresh_tokens" (token_hash, user_id, expires_at, device_info, ip_address, family_id)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: GetRefreshTokenByHash :one
@@ -23,11 +32,28 @@ SELECT * FROM "refresh_tokens"
 WHERE token_hash = $1
 LIMIT 1;
 
+-- name: GetRefreshTokenByHashForUpdate :one
+SELECT * FROM "refresh_tokens"
+WHERE token_hash = $1
+LIMIT 1
+FOR UPDATE;
+
 -- name: RevokeRefreshToken :exec
 UPDATE "refresh_tokens"
 SET revoked_at = now()
 WHERE token_hash = $1 AND revoked_at IS NULL;
 
+-- name: RevokeRefreshTokenFamily :execrows
+UPDATE "refresh_tokens"
+SET revoked_at = now()
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeOtherUserRefreshTokens :execrows
+-- Revokes every session of a user except the one with the given family
+UPDATE "refresh_tokens"
+SET revoked_at = now()
+WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL;
+
 -- name: RevokeAllUserTokens :exec
 UPDATE "refresh_tokens"
 SET revoked_at = now()
@@ -42,5 +68,5 @@ SELECT * FROM "refresh_tokens"
 WHERE user_id = $1
   AND revoked_at IS NULL
   AND expires_at > now()
-ORDER BY created_at DESC;
+ORDER BY last_used_at DESC;
 
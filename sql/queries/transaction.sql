@@ -10,27 +10,31 @@ category varchar,
 note varchar,
 by_user bigint NOT NULL,
 created_at timestamptz NOT NULL DEFAULT (now()),
-modified_at timestamptz NOT NULL DEFAULT (now())
+modified_at timestamptz NOT NULL DEFAULT (now()),
+currency varchar(3) NOT NULL DEFAULT 'USD',
+exchange_rate numeric(18,8) NOT NULL DEFAULT 1,
+deleted_at timestamptz,
+split_mode varchar
 */
 
 
 -- name: CreateTransaction :one
-INSERT INTO "transactions" (group_id, name, transaction_date, amount, category, note, by_user)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO "transactions" (group_id, name, transaction_date, amount, category, note, by_user, currency, exchange_rate)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- name: GetTransactionByID :one
 SELECT 
     * 
 FROM "transactions"
-WHERE id = $1 
+WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1;
 
 -- name: GetTransactionByIDForUpdate :one
 SELECT 
     * 
 FROM "transactions"
-WHERE id = $1 
+WHERE id = $1 AND deleted_at IS NULL
 LIMIT 1
 FOR UPDATE;
 
@@ -38,7 +42,7 @@ FOR UPDATE;
 SELECT 
     * 
 FROM "transactions"
-WHERE by_user = $1
+WHERE by_user = $1 AND deleted_at IS NULL
 ORDER BY transaction_date desc
 LIMIT $2
 OFFSET $3;
@@ -50,6 +54,7 @@ FROM "transactions"
 WHERE 
     group_id = $1
     and transaction_date between @start_date::date and @end_date::date
+    and deleted_at IS NULL
 ORDER BY transaction_date desc
 LIMIT $2
 OFFSET $3;
@@ -59,6 +64,7 @@ SELECT * FROM "transactions"
 WHERE 
     by_user = $1 
     AND transaction_date between @start_date::date and @end_date::date
+    AND deleted_at IS NULL
 ORDER BY transaction_date desc
 LIMIT $2
 OFFSET $3;
@@ -67,6 +73,7 @@ OFFSET $3;
 SELECT 
     * 
 FROM "transactions"
+WHERE deleted_at IS NULL
 ORDER BY transaction_date desc
 LIMIT $1
 OFFSET $2;
@@ -76,7 +83,8 @@ SELECT
     t.* 
 FROM "transactions" t
 INNER JOIN group_members gm ON t.group_id = gm.group_id
-WHERE gm.user_id = $1
+INNER JOIN "groups" g ON t.group_id = g.id
+WHERE gm.user_id = $1 AND gm.removed_at IS NULL AND t.deleted_at IS NULL AND g.deleted_at IS NULL
 ORDER BY t.transaction_date desc
 LIMIT $2
 OFFSET $3;
@@ -90,11 +98,75 @@ SET
     amount = $5,
     category = $6,
     note = $7,
-    by_user = $8
+    by_user = $8,
+    currency = $9,
+    exchange_rate = $10
 WHERE id = $1
 RETURNING *;
 
+-- name: SetTransactionSplitMode :exec
+-- Records how the transaction's splits were calculated, NULL for splits sent without a mode
+UPDATE "transactions"
+SET split_mode = $2
+WHERE id = $1;
+
+-- name: ListInconsistentTransactionsByGroupID :many
+-- Transactions whose splits don't sum to the transaction amount, or were split from a different amount
+-- Transactions without splits aren't listed
+SELECT
+    t.id,
+    t.name,
+    t.transaction_date,
+    t.amount,
+    t.split_mode,
+    COUNT(s.id) AS split_count,
+    SUM(s.split_amount)::numeric AS split_total,
+    COUNT(s.id) FILTER (WHERE s.tx_amount != t.amount) AS stale_split_count
+FROM "transactions" t
+INNER JOIN splits s ON s.transaction_id = t.id
+WHERE t.group_id = $1 AND t.deleted_at IS NULL
+GROUP BY t.id
+HAVING SUM(s.split_amount) != t.amount OR COUNT(s.id) FILTER (WHERE s.tx_amount != t.amount) > 0
+ORDER BY t.transaction_date desc, t.id
+LIMIT $2
+OFFSET $3;
+
 -- name: DeleteTransaction :one
-DELETE FROM "transactions" 
-WHERE id = $1
-RETURNING *; 
\ No newline at end of file
+-- Moves a transaction to the trash, its splits, payers & items are kept until it is purged
+UPDATE "transactions"
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetDeletedTransactionByID :one
+SELECT 
+    * 
+FROM "transactions"
+WHERE id = $1 AND deleted_at IS NOT NULL
+LIMIT 1;
+
+-- name: ListDeletedTransactionsByGroupID :many
+SELECT 
+    * 
+FROM "transactions"
+WHERE group_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at desc
+LIMIT $2
+OFFSET $3;
+
+-- name: RestoreTransaction :one
+UPDATE "transactions"
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedTransactions :execrows
+-- Permanently deletes transactions in the trash since before the given time, with their splits, payers & items
+DELETE FROM "transactions"
+WHERE deleted_at < @deleted_before::timestamptz;
+
+-- name: ReassignTransactionsByUser :exec
+-- Moves every transaction paid by a group member to another member
+UPDATE transactions
+SET by_user = @into_member_id::bigint
+WHERE by_user = @from_member_id::bigint;
@@ -0,0 +1,88 @@
+/*
+transaction item queries
+Table structure:
+CREATE TABLE "transaction_items" (
+  "id" bigserial PRIMARY KEY,
+  "transaction_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "modified_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "transaction_item_members" (
+  "item_id" bigint NOT NULL,
+  "member_id" bigint NOT NULL,
+  PRIMARY KEY ("item_id", "member_id")
+);
+
+CREATE TABLE "transaction_adjustments" (
+  "id" bigserial PRIMARY KEY,
+  "transaction_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "adjustment_type" varchar NOT NULL,
+  "value" numeric(14,6) NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "modified_at" timestamptz NOT NULL DEFAULT (now())
+);
+*/
+
+-- name: CreateTransactionItem :one
+INSERT INTO "transaction_items" (transaction_id, name, amount)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: CreateTransactionItemMember :one
+INSERT INTO "transaction_item_members" (item_id, member_id)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: ListTransactionItemsByTransactionID :many
+SELECT
+    *
+FROM "transaction_items"
+WHERE transaction_id = $1
+ORDER BY id;
+
+-- name: ListTransactionItemMembersByTransactionID :many
+SELECT
+    tim.*
+FROM "transaction_item_members" tim
+INNER JOIN "transaction_items" ti ON tim.item_id = ti.id
+WHERE ti.transaction_id = $1
+ORDER BY tim.item_id, tim.member_id;
+
+-- name: DeleteTransactionItems :many
+DELETE FROM "transaction_items"
+WHERE transaction_id = $1
+RETURNING *;
+
+-- name: CreateTransactionAdjustment :one
+INSERT INTO "transaction_adjustments" (transaction_id, name, adjustment_type, value, amount)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListTransactionAdjustmentsByTransactionID :many
+SELECT
+    *
+FROM "transaction_adjustments"
+WHERE transaction_id = $1
+ORDER BY id;
+
+-- name: DeleteTransactionAdjustments :many
+DELETE FROM "transaction_adjustments"
+WHERE transaction_id = $1
+RETURNING *;
+
+-- name: DeleteMergedMemberTransactionItemMembers :exec
+-- Deletes a member's shares of line items the other member also shares
+DELETE FROM "transaction_item_members" f
+WHERE f.member_id = @from_member_id::bigint
+  AND EXISTS (SELECT 1 FROM "transaction_item_members" im WHERE im.item_id = f.item_id AND im.member_id = @into_member_id::bigint);
+
+-- name: ReassignMemberTransactionItemMembers :exec
+-- Moves every line item share of a group member to another member
+UPDATE "transaction_item_members"
+SET member_id = @into_member_id::bigint
+WHERE member_id = @from_member_id::bigint;
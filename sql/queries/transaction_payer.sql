@@ -0,0 +1,50 @@
+/*
+transaction payer queries
+Table structure:
+CREATE TABLE "transaction_payers" (
+  "transaction_id" bigint NOT NULL,
+  "member_id" bigint NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "modified_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("transaction_id", "member_id")
+);
+*/
+
+-- name: CreateTransactionPayer :one
+INSERT INTO "transaction_payers" (transaction_id, member_id, amount)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListTransactionPayersByTransactionID :many
+SELECT
+    *
+FROM "transaction_payers"
+WHERE transaction_id = $1
+ORDER BY amount desc, member_id;
+
+-- name: DeleteTransactionPayers :many
+DELETE FROM "transaction_payers"
+WHERE transaction_id = $1
+RETURNING *;
+
+-- name: MergeMemberTransactionPayers :exec
+-- Adds a member's payments to another member's payments of the same transactions
+UPDATE "transaction_payers" p
+SET amount = p.amount + f.amount
+FROM "transaction_payers" f
+WHERE f.transaction_id = p.transaction_id
+  AND p.member_id = @into_member_id::bigint
+  AND f.member_id = @from_member_id::bigint;
+
+-- name: DeleteMergedMemberTransactionPayers :exec
+-- Deletes a member's payments of transactions the other member also paid, run after MergeMemberTransactionPayers
+DELETE FROM "transaction_payers" f
+WHERE f.member_id = @from_member_id::bigint
+  AND EXISTS (SELECT 1 FROM "transaction_payers" p WHERE p.transaction_id = f.transaction_id AND p.member_id = @into_member_id::bigint);
+
+-- name: ReassignMemberTransactionPayers :exec
+-- Moves every payment of a group member to another member
+UPDATE "transaction_payers"
+SET member_id = @into_member_id::bigint
+WHERE member_id = @from_member_id::bigint;
@@ -0,0 +1,41 @@
+/*
+transaction revision queries
+Table structure:
+CREATE TABLE "transaction_revisions" (
+  "id" bigserial PRIMARY KEY,
+  "transaction_id" bigint NOT NULL,
+  "revision" integer NOT NULL,
+  "snapshot" jsonb NOT NULL, -- transaction fields, payers & splits
+  "reverted_from" integer,
+  "created_by" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+*/
+
+-- name: CreateTransactionRevision :one
+-- Numbers the revision after the latest revision of the transaction
+INSERT INTO "transaction_revisions" (transaction_id, revision, snapshot, reverted_from, created_by)
+VALUES ($1, (SELECT COALESCE(MAX(revision), 0) + 1 FROM "transaction_revisions" WHERE transaction_id = $1), $2, $3, $4)
+RETURNING *;
+
+-- name: CountTransactionRevisions :one
+SELECT 
+    count(*) 
+FROM "transaction_revisions"
+WHERE transaction_id = $1;
+
+-- name: GetTransactionRevision :one
+SELECT 
+    * 
+FROM "transaction_revisions"
+WHERE transaction_id = $1 AND revision = $2
+LIMIT 1;
+
+-- name: ListTransactionRevisions :many
+SELECT 
+    * 
+FROM "transaction_revisions"
+WHERE transaction_id = $1
+ORDER BY revision desc
+LIMIT $2
+OFFSET $3;
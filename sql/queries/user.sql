@@ -48,6 +48,19 @@ SET name = $1
 WHERE id = $2
 RETURNING *;
 
+-- name: UpdateUserPassword :one
+UPDATE "users"
+SET password_hash = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: VerifyUserEmail :one
+-- Marks the user's email verified, only while the email is still the one the verification token was issued for
+UPDATE "users"
+SET email_verified = true
+WHERE id = $1 AND email = $2
+RETURNING *;
+
 -- name: DeleteUser :one
 DELETE FROM "users"
 WHERE id = $1
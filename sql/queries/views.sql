@@ -13,6 +13,7 @@ ORDER BY c.member_name, d.member_name;
 
 -- name: GroupBalancesNet :many
 SELECT
+    gm.id as member_id,
     gm.user_id as user_id,
     gm.member_name as user_name,
     gbn.net_balance::numeric(10,2) as net_balance -- sum returns unconstrained numeric
@@ -43,6 +44,7 @@ SELECT
 SELECT
     g.id as group_id,
     g.name as group_name,
+    g.currency as currency,
     gbn.net_balance::numeric(10,2) as net_balance
 FROM group_balances_net gbn
 JOIN group_members gm on gm.id = gbn.user_id
@@ -59,3 +61,44 @@ FROM user_balances_by_member ubm
 JOIN users u on u.id = ubm.member_user_id
 WHERE ubm.user_id = $1
 ORDER BY u.name;
+
+-- name: SharedGroupBalancesNet :many
+-- Returns the net balance of two users in every group they are both members of
+-- Members without ledger entries in a group have a net balance of 0
+SELECT
+    gm.group_id as group_id,
+    g.currency as currency,
+    gm.id as member_id,
+    gm.user_id as user_id,
+    gm.role as role,
+    COALESCE(gbn.net_balance, 0)::numeric(10,2) as net_balance
+FROM group_members gm
+JOIN groups g on g.id = gm.group_id
+LEFT JOIN group_balances_net gbn on gbn.user_id = gm.id
+WHERE gm.user_id IN (@user_id::bigint, @other_user_id::bigint)
+  AND g.deleted_at IS NULL
+  AND gm.group_id IN (SELECT group_id FROM group_members WHERE user_id = @user_id::bigint)
+  AND gm.group_id IN (SELECT group_id FROM group_members WHERE user_id = @other_user_id::bigint)
+ORDER BY gm.group_id, gm.id;
+
+-- name: UserNetworkBalances :many
+-- Returns the balance between every pair of users in a user's network, everyone sharing a group with the user, including the user
+-- Built on user_balances_by_member, so balances are aggregated across all groups the pair shares
+-- Positive values mean member_user_id owes user_id
+WITH network AS (
+    SELECT DISTINCT gm.user_id
+    FROM group_members gm
+    WHERE gm.user_id IS NOT NULL
+      AND gm.group_id IN (SELECT group_id FROM group_members WHERE user_id = $1)
+)
+SELECT
+    ubm.user_id as user_id,
+    u.name as user_name,
+    ubm.member_user_id as member_user_id,
+    ubm.net_balance::numeric(10,2) as net_balance
+FROM user_balances_by_member ubm
+JOIN users u on u.id = ubm.user_id
+WHERE ubm.user_id IN (SELECT user_id FROM network)
+  AND ubm.member_user_id IN (SELECT user_id FROM network)
+  AND ubm.user_id <> ubm.member_user_id
+ORDER BY ubm.user_id, ubm.member_user_id;